@@ -0,0 +1,40 @@
+package commands
+
+import (
+	"os"
+
+	"github.com/DrSkyle/cloudslash/internal/graph"
+	"github.com/DrSkyle/cloudslash/internal/report"
+	"github.com/spf13/cobra"
+)
+
+var diffFormat string
+
+var diffCmd = &cobra.Command{
+	Use:   "diff <old-snapshot> <new-snapshot>",
+	Short: "Compare two saved scans (new, resolved and changed waste)",
+	Long: `Compare two snapshots written by 'cloudslash scan --save' and report
+waste that appeared, waste that was resolved, resources whose cost or risk
+changed, and the net monthly-cost delta.
+
+Example:
+  cloudslash diff last-week.json today.json --format markdown`,
+	Args: cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		older, err := graph.LoadSnapshot(args[0])
+		if err != nil {
+			return err
+		}
+		newer, err := graph.LoadSnapshot(args[1])
+		if err != nil {
+			return err
+		}
+
+		return report.WriteDiff(os.Stdout, graph.Diff(older, newer), diffFormat)
+	},
+}
+
+func init() {
+	diffCmd.Flags().StringVar(&diffFormat, "format", "text", "Output format (text, json, markdown)")
+	rootCmd.AddCommand(diffCmd)
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	
 	"github.com/DrSkyle/cloudslash/internal/app"
+	"github.com/DrSkyle/cloudslash/internal/graph"
 	"github.com/spf13/cobra"
 )
 
@@ -15,6 +16,9 @@ var ExportCmd = &cobra.Command{
 	Short: "Export forensic data (CSV, JSON)",
 	Long: `Run a scan and export the results to a specified format.
     
+Use --from to export a saved snapshot offline instead of rescanning, and
+--where to export only resources matching a query (see 'cloudslash query').
+
 Default output directory: ./cloudslash-out/`,
 	Run: func(cmd *cobra.Command, args []string) {
         fmt.Println("🚀 Initializing Forensic Export...")
@@ -32,18 +36,19 @@ Default output directory: ./cloudslash-out/`,
             fmt.Println("   📂 CSV:  ./cloudslash-out/waste_report.csv")
             fmt.Println("   xxxxx JSON: ./cloudslash-out/waste_report.json")
             fmt.Println("   📊 HTML: ./cloudslash-out/dashboard.html")
+            fmt.Println("   🔎 Coverage: ./cloudslash-out/coverage.json")
         } else {
             // Calculate Potential Cost Savings
             var monthlyWaste float64
-            g.Mu.RLock()
             wasteCount := 0
-            for _, node := range g.Nodes {
-                if node.IsWaste {
-                    monthlyWaste += node.Cost
-                    wasteCount++
-                }
-            }
-            g.Mu.RUnlock()
+            g.Read(func(v *graph.View) {
+                v.Each(func(node *graph.Node) {
+                    if node.HasWasteFinding() {
+                        monthlyWaste += node.Savings
+                        wasteCount++
+                    }
+                })
+            })
 
             fmt.Printf("\n⚠️  Export Skipped [Community Edition]\n")
             fmt.Printf("\n   🔥 YOU ARE BURNING $%.2f / MONTH\n", monthlyWaste)
@@ -58,6 +63,8 @@ Default output directory: ./cloudslash-out/`,
 }
 
 func init() {
+    ExportCmd.Flags().StringVar(&config.FromSnapshot, "from", "", "Export from a saved snapshot instead of scanning")
+    ExportCmd.Flags().StringVar(&config.Where, "where", "", "Only export resources matching this query to CSV/JSON")
     // Future expansion: --format
     // ExportCmd.Flags().StringVar(&exportFormat, "format", "csv", "Export format (csv, json)")
 }
@@ -0,0 +1,74 @@
+package commands
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/DrSkyle/cloudslash/internal/app"
+	"github.com/DrSkyle/cloudslash/internal/graph"
+	"github.com/spf13/cobra"
+)
+
+var (
+	graphFormat string
+	graphRoot   string
+	graphOutput string
+)
+
+var graphCmd = &cobra.Command{
+	Use:   "graph",
+	Short: "Export the infrastructure graph (DOT, GraphML, Cypher)",
+	Long: `Export the resource graph with its typed, weighted edges for Graphviz,
+yEd/Gephi (GraphML) or Neo4j (Cypher CREATE statements).
+
+Example:
+  cloudslash graph --from scan.json --format dot -o infra.dot && dot -Tsvg infra.dot > infra.svg
+  cloudslash graph --from scan.json --format cypher --root arn:aws:ec2:us-east-1:123456789012:vpc/vpc-0abc | cypher-shell`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		g, err := loadGraph(graphOutput == "")
+		if err != nil {
+			return err
+		}
+
+		var w io.Writer = os.Stdout
+		if graphOutput != "" {
+			f, err := os.Create(graphOutput)
+			if err != nil {
+				return fmt.Errorf("failed to create %s: %v", graphOutput, err)
+			}
+			defer f.Close()
+			w = f
+		}
+		return g.Export(w, graphFormat, graphRoot)
+	},
+}
+
+// loadGraph reads the --from snapshot, or runs a headless scan that leaves
+// cloudslash-out/ alone. Commands writing their result to stdout pass
+// toStdout, so the scan's progress and warnings go to stderr instead.
+func loadGraph(toStdout bool) (*graph.Graph, error) {
+	if config.FromSnapshot != "" {
+		return graph.LoadSnapshot(config.FromSnapshot)
+	}
+	cfg := config
+	cfg.Headless = true
+	cfg.SkipOutputs = true
+	if toStdout {
+		// Scanners and heuristics print with fmt, so redirect for the whole run.
+		stdout := os.Stdout
+		os.Stdout = os.Stderr
+		defer func() { os.Stdout = stdout }()
+	}
+	_, g, err := app.Run(cfg)
+	return g, err
+}
+
+func init() {
+	graphCmd.Flags().StringVar(&graphFormat, "format", "dot", "Output format (dot, graphml, cypher)")
+	graphCmd.Flags().StringVar(&graphRoot, "root", "", "Only export the connected component of this ARN")
+	graphCmd.Flags().StringVarP(&graphOutput, "output", "o", "", "Write to a file instead of stdout")
+	graphCmd.Flags().StringVar(&config.FromSnapshot, "from", "", "Export a saved snapshot instead of scanning")
+	rootCmd.AddCommand(graphCmd)
+}
@@ -0,0 +1,60 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/DrSkyle/cloudslash/internal/heuristics"
+	"github.com/DrSkyle/cloudslash/internal/report"
+	"github.com/spf13/cobra"
+)
+
+var (
+	listAccount string
+	listTags    map[string]string
+)
+
+var heuristicsCmd = &cobra.Command{
+	Use:   "heuristics",
+	Short: "Inspect the built-in heuristics",
+}
+
+var heuristicsListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List heuristics with their effective thresholds",
+	Long: `List every built-in heuristic with the thresholds it uses, after applying the
+heuristics section of the config file (--config, default $HOME/.cloudslash.yaml).
+
+Overrides are scoped by account, region and tags; pass --account, --region and
+--tag to see the values for resources in that scope.
+
+Example:
+  cloudslash heuristics list --account 111111111111 --region us-west-2 --tag env=sandbox`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		settings, err := heuristics.LoadSettings(config.ConfigPath)
+		if err != nil {
+			return err
+		}
+
+		target := heuristics.Target{Account: listAccount, Tags: listTags}
+		if cmd.Flags().Changed("region") {
+			target.Region = config.Region
+		}
+
+		if err := report.WriteSettings(os.Stdout, heuristics.EffectiveSettings(heuristics.Catalog(), settings, target)); err != nil {
+			return err
+		}
+		if settings != nil {
+			fmt.Printf("\nSettings from %s, %d overrides\n", settings.Path, len(settings.Overrides))
+		}
+		return nil
+	},
+}
+
+func init() {
+	heuristicsListCmd.Flags().StringVar(&listAccount, "account", "", "Show values for resources in this account")
+	heuristicsListCmd.Flags().StringToStringVar(&listTags, "tag", nil, "Show values for resources with this tag (key=value, repeatable)")
+	heuristicsCmd.AddCommand(heuristicsListCmd)
+	rootCmd.AddCommand(heuristicsCmd)
+}
@@ -48,6 +48,11 @@ var NukeCmd = &cobra.Command{
 			fmt.Printf("Error: %v\n", err)
 			return
 		}
+		identity, err := client.VerifyIdentity(ctx)
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			return
+		}
 		
 		// Setup minimal heuristics
 		hEngine := heuristics.NewEngine()
@@ -56,19 +61,23 @@ var NukeCmd = &cobra.Command{
 
 		// RUN SCAN (Simplified for Nuke - just EBS for now to prove concept safely)
 		// Full scan is heavy. Let's just do EBS Nuke for v1.2
-		ec2 := aws.NewEC2Scanner(client.Config, g)
+		ec2 := aws.NewEC2Scanner(client.Config, identity, g)
 		ec2.ScanVolumes(ctx)
 		hEngine.Run(ctx, g)
 
 		// 2. Iterate and Destroy
-		g.Mu.RLock()
+		// Walk targets in dependency order so nothing is deleted while still in use.
+		plan := g.PlanWasteDeletion()
+		if err := plan.CycleError(); err != nil {
+			fmt.Printf("⚠️  %v\n   Resources in or behind the cycle are skipped; delete them manually.\n", err)
+		}
+
 		var waste []*graph.Node
-		for _, node := range g.Nodes {
-			if node.IsWaste {
+		for _, id := range plan.Order {
+			if node, ok := g.Node(id); ok {
 				waste = append(waste, node)
 			}
 		}
-		g.Mu.RUnlock()
 
 		if len(waste) == 0 {
 			fmt.Println("No waste found to nuke. You are clean.")
@@ -81,7 +90,10 @@ var NukeCmd = &cobra.Command{
 
 		for _, item := range waste {
 			fmt.Printf("\n[TARGET] %s (%s)\n", item.ID, item.Type)
-			fmt.Printf(" Reason: %s\n", item.Properties["Reason"])
+			for _, f := range item.Findings {
+				fmt.Printf(" Reason: [%s] %s\n", f.Category, f.Reason)
+			}
+			printBlastRadius(g.AnalyzeImpact(item.ID))
 			fmt.Print(" 💀 Delete this resource? [y/N]: ")
 			
 			if scanner.Scan() {
@@ -104,3 +116,18 @@ var NukeCmd = &cobra.Command{
 		fmt.Println("\nNuke complete.")
 	},
 }
+
+// printBlastRadius lists what breaks if the target goes away.
+func printBlastRadius(r *graph.ImpactReport) {
+	if r == nil || (len(r.Orphaned) == 0 && len(r.Shared) == 0) {
+		fmt.Println(" Blast Radius: none")
+		return
+	}
+	fmt.Printf(" Blast Radius: %.2f (%d orphaned, %d shared)\n", r.BlastRadius, len(r.Orphaned), len(r.Shared))
+	for _, imp := range r.Orphaned {
+		fmt.Printf("   ✗ %s (%s) via %s, strength %.2f\n", imp.Node.ID, imp.Node.Type, imp.Via, imp.Strength)
+	}
+	for _, imp := range r.Shared {
+		fmt.Printf("   ~ %s (%s) still reachable elsewhere\n", imp.Node.ID, imp.Node.Type)
+	}
+}
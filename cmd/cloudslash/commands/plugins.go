@@ -0,0 +1,49 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/DrSkyle/cloudslash/internal/heuristics"
+	"github.com/DrSkyle/cloudslash/internal/report"
+	"github.com/spf13/cobra"
+)
+
+var pluginsCmd = &cobra.Command{
+	Use:   "plugins",
+	Short: "Inspect external heuristic plugins",
+}
+
+var pluginsListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List plugins with their declared capabilities",
+	Long: `Run the describe command of every executable in ~/.cloudslash/plugins (or
+--plugin-dir) and list the resource types each plugin analyzes, the
+heuristics it runs after, the environment variables it is given and its
+time limit. Plugins that fail to describe themselves are reported.
+
+Example:
+  cloudslash plugins list --plugin-dir ./plugins`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		dir := config.PluginDir
+		if dir == "" {
+			dir = heuristics.DefaultPluginDir()
+		}
+		plugins, loadErr := heuristics.DiscoverPlugins(context.Background(), dir)
+		if len(plugins) == 0 && loadErr == nil {
+			fmt.Printf("No plugins found in %s\n", dir)
+			return nil
+		}
+		if err := report.WritePlugins(os.Stdout, plugins); err != nil {
+			return err
+		}
+		return loadErr
+	},
+}
+
+func init() {
+	pluginsCmd.AddCommand(pluginsListCmd)
+	rootCmd.AddCommand(pluginsCmd)
+}
@@ -0,0 +1,53 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/DrSkyle/cloudslash/internal/graph"
+	"github.com/DrSkyle/cloudslash/internal/policy"
+	"github.com/DrSkyle/cloudslash/internal/report"
+	"github.com/spf13/cobra"
+)
+
+var policyCmd = &cobra.Command{
+	Use:   "policy",
+	Short: "Validate and list suppression policy rules",
+	Long: `Validate .cloudslash-policy.yaml (or --policy) and list its rules with owner,
+expiry and status. With --from, the rules are evaluated against a saved scan
+and each rule's hit count is shown, so unused rules can be cleaned up.
+
+Example:
+  cloudslash policy --policy .cloudslash-policy.yaml --from scan.json`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		pol, err := policy.LoadDefault(config.PolicyPath)
+		if err != nil {
+			return err
+		}
+		if pol == nil {
+			return fmt.Errorf("no policy found: create %s or pass --policy", policy.DefaultFile)
+		}
+
+		if config.FromSnapshot == "" {
+			return report.WritePolicy(os.Stdout, pol, false)
+		}
+
+		g, err := graph.LoadSnapshot(config.FromSnapshot)
+		if err != nil {
+			return err
+		}
+		g.Suppressor = pol
+		removed := g.ApplySuppressor()
+		if err := report.WritePolicy(os.Stdout, pol, true); err != nil {
+			return err
+		}
+		fmt.Printf("%d findings in %s would be suppressed\n", removed, config.FromSnapshot)
+		return nil
+	},
+}
+
+func init() {
+	policyCmd.Flags().StringVar(&config.FromSnapshot, "from", "", "Evaluate the rules against a saved snapshot")
+	rootCmd.AddCommand(policyCmd)
+}
@@ -0,0 +1,48 @@
+package commands
+
+import (
+	"os"
+
+	"github.com/DrSkyle/cloudslash/internal/query"
+	"github.com/DrSkyle/cloudslash/internal/report"
+	"github.com/spf13/cobra"
+)
+
+var queryFormat string
+
+var queryCmd = &cobra.Command{
+	Use:   "query <expression>",
+	Short: "Filter resources with a query expression",
+	Long: `Select resources from a scan (or a saved snapshot) with a filter expression.
+
+Fields:    id, type, cost, savings, risk, waste, justified, justification,
+           source, age (compare with durations like 36h or 14d), props.<Property>, tags.<Key>
+Operators: = != < <= > >= ~ !~ in (...) not in (...), and, or, not, has
+Edges:     out [EdgeType] (expr), in [EdgeType] (expr)
+
+Examples:
+  cloudslash query 'type = AWS::EC2::Volume and props.Size > 500 and tags.Team = data and cost > $20' --from scan.json
+  cloudslash query 'type = AWS::EC2::Volume and out AttachedTo (props.State = stopped)'
+  cloudslash query 'waste and not justified and risk >= 50' --format json
+  cloudslash query 'type = AWS::EC2::Snapshot and age > 180d'`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		q, err := query.Parse(args[0])
+		if err != nil {
+			return err
+		}
+
+		g, err := loadGraph(true)
+		if err != nil {
+			return err
+		}
+
+		return report.WriteNodes(os.Stdout, q.Select(g), queryFormat)
+	},
+}
+
+func init() {
+	queryCmd.Flags().StringVar(&queryFormat, "format", "table", "Output format (table, json)")
+	queryCmd.Flags().StringVar(&config.FromSnapshot, "from", "", "Query a saved snapshot instead of scanning")
+	rootCmd.AddCommand(queryCmd)
+}
@@ -3,6 +3,7 @@ package commands
 import (
 	"fmt"
 	"os"
+    "path/filepath"
     "strings"
 
     "github.com/DrSkyle/cloudslash/internal/app"
@@ -27,7 +28,10 @@ Identify. Audit. Slash.`,
 	Run: func(cmd *cobra.Command, args []string) {
         // Default action: Run TUI
         config.Headless = false
-		_, _, _ = app.Run(config)
+		if _, _, err := app.Run(config); err != nil {
+            fmt.Printf("❌ %v\n", err)
+            os.Exit(1)
+        }
 	},
 }
 
@@ -42,12 +46,18 @@ func init() {
 	cobra.OnInitialize(initConfig)
 
 	// Persistent Flags
+	rootCmd.PersistentFlags().StringVar(&cfgFile, "config", "", "Config file (default $HOME/.cloudslash.yaml)")
 	rootCmd.PersistentFlags().StringVar(&config.LicenseKey, "license", "", "License Key")
 	rootCmd.PersistentFlags().StringVar(&config.Region, "region", "us-east-1", "AWS Region")
 	rootCmd.PersistentFlags().StringVar(&config.TFStatePath, "tfstate", "terraform.tfstate", "Path to web.tfstate")
 	rootCmd.PersistentFlags().BoolVar(&config.AllProfiles, "all-profiles", false, "Scan all AWS profiles")
     rootCmd.PersistentFlags().StringVar(&config.RequiredTags, "required-tags", "", "Required tags (comma-separated)")
     rootCmd.PersistentFlags().StringVar(&config.SlackWebhook, "slack-webhook", "", "Slack Webhook URL")
+    rootCmd.PersistentFlags().StringVar(&config.PolicyPath, "policy", "", "Suppression policy file (default .cloudslash-policy.yaml if present)")
+    rootCmd.PersistentFlags().StringVar(&config.RulesDir, "rules", "", "Directory of user-defined rule files (default .cloudslash-rules if present)")
+    rootCmd.PersistentFlags().StringVar(&config.PluginDir, "plugin-dir", "", "Directory of external heuristic plugins (default ~/.cloudslash/plugins)")
+    rootCmd.PersistentFlags().Float64Var(&config.MinConfidence, "min-confidence", 0, "Only report findings with at least this confidence (0-1)")
+    rootCmd.Flags().StringVar(&config.FromSnapshot, "from", "", "Open a saved snapshot in the TUI (offline)")
 
     // Hidden Flags
     rootCmd.PersistentFlags().BoolVar(&config.MockMode, "mock", false, "Run in Mock Mode")
@@ -89,7 +99,13 @@ func initConfig() {
 		}
 	}
 	viper.AutomaticEnv()
-    viper.ReadInConfig()
+    if err := viper.ReadInConfig(); err == nil {
+        // Heuristic thresholds are read from YAML/JSON config files only.
+        switch strings.ToLower(filepath.Ext(viper.ConfigFileUsed())) {
+        case ".yaml", ".yml", ".json":
+            config.ConfigPath = viper.ConfigFileUsed()
+        }
+    }
 }
 
 func renderFutureGlassHelp(cmd *cobra.Command) {
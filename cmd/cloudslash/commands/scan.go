@@ -1,6 +1,9 @@
 package commands
 
 import (
+	"fmt"
+	"os"
+
 	"github.com/DrSkyle/cloudslash/internal/app"
 	"github.com/spf13/cobra"
 )
@@ -12,13 +15,18 @@ var scanCmd = &cobra.Command{
 	Long: `Run CloudSlash in headless mode. Useful for CI/CD pipelines or cron jobs.
     
 Example:
-  cloudslash scan --region us-west-2`,
+  cloudslash scan --region us-west-2
+  cloudslash scan --save scan-2024-06-01.json`,
 	Run: func(cmd *cobra.Command, args []string) {
         config.Headless = true
-		_, _, _ = app.Run(config)
+		if _, _, err := app.Run(config); err != nil {
+            fmt.Printf("❌ %v\n", err)
+            os.Exit(1)
+        }
 	},
 }
 
 func init() {
+	scanCmd.Flags().StringVar(&config.SavePath, "save", "", "Save the analyzed graph to a snapshot file")
 	rootCmd.AddCommand(scanCmd)
 }
@@ -6,6 +6,7 @@ import (
 	"os"
 	"strings"
 	"sync"
+	"time"
 	
 	"github.com/DrSkyle/cloudslash/internal/aws"
 	"github.com/DrSkyle/cloudslash/internal/graph"
@@ -13,9 +14,12 @@ import (
 	"github.com/DrSkyle/cloudslash/internal/forensics"
 	"github.com/DrSkyle/cloudslash/internal/license"
 	"github.com/DrSkyle/cloudslash/internal/notifier"
+	"github.com/DrSkyle/cloudslash/internal/policy"
 	"github.com/DrSkyle/cloudslash/internal/pricing"
+	"github.com/DrSkyle/cloudslash/internal/query"
 	"github.com/DrSkyle/cloudslash/internal/remediation"
 	"github.com/DrSkyle/cloudslash/internal/report"
+	"github.com/DrSkyle/cloudslash/internal/rules"
 	"github.com/DrSkyle/cloudslash/internal/swarm"
 	"github.com/DrSkyle/cloudslash/internal/tf"
 	"github.com/DrSkyle/cloudslash/internal/ui"
@@ -23,15 +27,27 @@ import (
 	tea "github.com/charmbracelet/bubbletea"
 )
 
+
 type Config struct {
-	LicenseKey   string
-	Region       string
-	TFStatePath  string
-	MockMode     bool
-	AllProfiles  bool
-	RequiredTags string
-	SlackWebhook string
-	Headless     bool // New: Don't run TUI
+	LicenseKey    string
+	Region        string
+	TFStatePath   string
+	MockMode      bool
+	AllProfiles   bool
+	RequiredTags  string
+	SlackWebhook  string
+	Headless      bool    // New: Don't run TUI
+	SkipOutputs   bool    // Don't write cloudslash-out/, for commands that print their own result
+	SavePath      string  // Write the analyzed graph to this snapshot file
+	FromSnapshot  string  // Load a saved snapshot instead of scanning AWS
+	PolicyPath    string  // Suppression policy; defaults to .cloudslash-policy.yaml if present
+	MinConfidence float64 // Drop findings below this confidence (0-1) before reporting
+	ConfigPath    string  // Config file whose heuristics section tunes thresholds
+	RulesDir      string  // User-defined rules; defaults to .cloudslash-rules if present
+	PluginDir     string  // External heuristic plugins; defaults to ~/.cloudslash/plugins
+	Where         string  // Only export resources matching this query to CSV/JSON
+
+	where *query.Query // Compiled Where, set by Run
 }
 
 func Run(cfg Config) (bool, *graph.Graph, error) {
@@ -50,26 +66,96 @@ func Run(cfg Config) (bool, *graph.Graph, error) {
 		}
 	}
 
+	if cfg.MinConfidence < 0 || cfg.MinConfidence > 1 {
+		return !isTrial, nil, fmt.Errorf("--min-confidence must be between 0 and 1, got %g", cfg.MinConfidence)
+	}
+
+	if cfg.Where != "" {
+		where, err := query.Parse(cfg.Where)
+		if err != nil {
+			return !isTrial, nil, fmt.Errorf("--where: %w", err)
+		}
+		cfg.where = where
+	}
+
+	pol, err := policy.LoadDefault(cfg.PolicyPath)
+	if err != nil {
+		return !isTrial, nil, err
+	}
+	warnPolicy(pol, false)
+
+	settings, err := heuristics.LoadSettings(cfg.ConfigPath)
+	if err != nil {
+		return !isTrial, nil, err
+	}
+
+	userRules, err := rules.LoadDefault(cfg.RulesDir)
+	if err != nil {
+		return !isTrial, nil, err
+	}
+
+	// A broken plugin is reported and left out; the others still run.
+	pluginDir := cfg.PluginDir
+	if pluginDir == "" {
+		pluginDir = heuristics.DefaultPluginDir()
+	}
+	plugins, err := heuristics.DiscoverPlugins(context.Background(), pluginDir)
+	if err != nil {
+		fmt.Printf("Warning: %v\n", err)
+	}
+	extra := heuristicsFrom(userRules, plugins)
+
 	// 2. Initialize Components
 	ctx := context.Background()
 	var g *graph.Graph
 	var engine *swarm.Engine
 
 	g = graph.NewGraph()
+	g.Metadata.ScannedAt = time.Now()
+	g.Metadata.Mock = cfg.MockMode
+	if pol != nil {
+		g.Suppressor = pol
+	}
 	engine = swarm.NewEngine()
 	engine.Start(ctx)
 
 	var doneChan <-chan struct{}
+	offline := cfg.FromSnapshot != ""
 
-	if cfg.MockMode {
-		runMockMode(ctx, g, engine, cfg.Headless) // Mock mode is synchronous
+	if offline {
+		// Offline: reuse a previous scan, no AWS calls.
+		loaded, err := graph.LoadSnapshot(cfg.FromSnapshot)
+		if err != nil {
+			return !isTrial, nil, err
+		}
+		g = loaded
+		if !cfg.Headless {
+			fmt.Printf("Loaded snapshot %s (scanned %s)\n", cfg.FromSnapshot, g.Metadata.ScannedAt.Format(time.RFC822))
+		}
+		if pol != nil {
+			// Re-check the saved findings against the current policy.
+			g.Suppressor = pol
+			g.ApplySuppressor()
+		}
+		g.FilterConfidence(cfg.MinConfidence)
+		printCoverage(cfg, g)
+		if !isTrial {
+			generateOutputs(ctx, cfg, g, nil)
+		}
+	} else if cfg.MockMode {
+		runMockMode(ctx, cfg, g, engine, settings, extra) // Mock mode is synchronous
+		if cfg.Headless {
+			warnPolicy(pol, true)
+		}
+		printCoverage(cfg, g)
+		saveSnapshot(cfg, g)
 	} else {
-		doneChan = runRealMode(ctx, cfg, g, engine, isTrial)
+		doneChan = runRealMode(ctx, cfg, g, engine, isTrial, pol, settings, extra)
 	}
 
     // 3. Start Interface (TUI vs Headless)
     if !cfg.Headless {
-        model := ui.NewModel(engine, g, isTrial, cfg.MockMode)
+        model := ui.NewModel(engine, g, isTrial, cfg.MockMode || offline)
         p := tea.NewProgram(model)
         if _, err := p.Run(); err != nil {
             fmt.Printf("Alas, there's been an error: %v", err)
@@ -87,8 +173,8 @@ func Run(cfg Config) (bool, *graph.Graph, error) {
 }
 
 // Logic extracted from original main.go
-func runMockMode(ctx context.Context, g *graph.Graph, engine *swarm.Engine, headless bool) {
-		if !headless {
+func runMockMode(ctx context.Context, cfg Config, g *graph.Graph, engine *swarm.Engine, settings *heuristics.Settings, extra []heuristics.WeightedHeuristic) {
+		if !cfg.Headless {
             // TUI model handles starting the mock scan? 
             // Original main.go: mockScanner.Scan(ctx) was called in main thread.
         }
@@ -98,26 +184,36 @@ func runMockMode(ctx context.Context, g *graph.Graph, engine *swarm.Engine, head
 
 		// Synchronous Heuristics for Demo
 		heuristicEngine := heuristics.NewEngine()
+		heuristicEngine.Settings = settings
 		heuristicEngine.Register(&heuristics.ZombieEBSHeuristic{})
+		heuristicEngine.Register(&heuristics.StoppedInstanceHeuristic{})
 		heuristicEngine.Register(&heuristics.S3MultipartHeuristic{})
+		heuristicEngine.Register(&heuristics.UnusedSecurityGroupHeuristic{})
+		heuristicEngine.Register(&heuristics.EmptyVPCHeuristic{})
+		heuristicEngine.Register(&heuristics.EmptySubnetHeuristic{})
+		heuristicEngine.Register(&heuristics.OrphanedENIHeuristic{})
+		heuristicEngine.Register(&heuristics.RDSSnapshotHeuristic{})
+		heuristicEngine.Register(&heuristics.SnapshotChildrenHeuristic{}) // Runs after volume waste is known
+		registerExtra(heuristicEngine, extra)
 		if err := heuristicEngine.Run(ctx, g); err != nil {
 			fmt.Printf("Heuristic run failed: %v\n", err)
 		}
 
-        // Second Pass (Time Machine)
-        hEngine2 := heuristics.NewEngine()
-        hEngine2.Register(&heuristics.SnapshotChildrenHeuristic{})
-        hEngine2.Run(ctx, g)
+		g.FilterConfidence(cfg.MinConfidence)
+		if cfg.SkipOutputs {
+			return
+		}
 
 		os.Mkdir("cloudslash-out", 0755)
 		if err := report.GenerateHTML(g, "cloudslash-out/dashboard.html"); err != nil {
 			fmt.Printf("Failed to generate mock dashboard: %v\n", err)
 		}
-		report.GenerateCSV(g, "cloudslash-out/waste_report.csv")
-		report.GenerateJSON(g, "cloudslash-out/waste_report.json")
+		report.GenerateCSV(g, "cloudslash-out/waste_report.csv", cfg.where)
+		report.GenerateJSON(g, "cloudslash-out/waste_report.json", cfg.where)
+		report.GenerateCoverageJSON(g, "cloudslash-out/coverage.json")
 }
 
-func runRealMode(ctx context.Context, cfg Config, g *graph.Graph, engine *swarm.Engine, isTrial bool) <-chan struct{} {
+func runRealMode(ctx context.Context, cfg Config, g *graph.Graph, engine *swarm.Engine, isTrial bool, pol *policy.Policy, settings *heuristics.Settings, extra []heuristics.WeightedHeuristic) <-chan struct{} {
 		done := make(chan struct{})
 		
 		var pricingClient *pricing.Client
@@ -198,27 +294,29 @@ func runRealMode(ctx context.Context, cfg Config, g *graph.Graph, engine *swarm.
 					cwd, _ := os.Getwd() // Default to current directory
 					auditor := tf.NewCodeAuditor(state)
 					
-					g.Mu.Lock()
-					for _, node := range g.Nodes {
-						if node.IsWaste {
-							file, line, err := auditor.FindSource(node.ID, cwd)
-							if err == nil {
-								node.SourceLocation = fmt.Sprintf("%s:%d", file, line)
+					g.Write(func(v *graph.View) {
+						v.Each(func(node *graph.Node) {
+							if node.IsWaste {
+								file, line, err := auditor.FindSource(node.ID, cwd)
+								if err == nil {
+									node.SourceLocation = fmt.Sprintf("%s:%d", file, line)
+								}
 							}
-						}
-					}
-					g.Mu.Unlock()
+						})
+					})
 				}
 			}
 
 			// Run Genius Heuristic Engine
 			hEngine := heuristics.NewEngine()
+			hEngine.Settings = settings
 			if pricingClient != nil {
 				hEngine.Register(&heuristics.ElasticIPHeuristic{Pricing: pricingClient})
 			} else {
 				hEngine.Register(&heuristics.ElasticIPHeuristic{})
 			}
 			hEngine.Register(&heuristics.S3MultipartHeuristic{})
+			hEngine.Register(&heuristics.RDSSnapshotHeuristic{})
 
 			if cwClient != nil {
 				if pricingClient != nil {
@@ -227,16 +325,29 @@ func runRealMode(ctx context.Context, cfg Config, g *graph.Graph, engine *swarm.
 					hEngine.Register(&heuristics.NATGatewayHeuristic{CW: cwClient})
 				}
 				hEngine.Register(&heuristics.RDSHeuristic{CW: cwClient})
+				if pricingClient != nil {
+					hEngine.Register(&heuristics.IdleVPCEndpointHeuristic{CW: cwClient, Pricing: pricingClient})
+				} else {
+					hEngine.Register(&heuristics.IdleVPCEndpointHeuristic{CW: cwClient})
+				}
 				hEngine.Register(&heuristics.ELBHeuristic{CW: cwClient})
 				if pricingClient != nil {
 					hEngine.Register(&heuristics.UnderutilizedInstanceHeuristic{CW: cwClient, Pricing: pricingClient})
+					hEngine.Register(&heuristics.ProvisionedIOPSHeuristic{CW: cwClient, Pricing: pricingClient})
+					hEngine.Register(&heuristics.RDSRightsizingHeuristic{CW: cwClient, Pricing: pricingClient})
 				}
 			}
 
 			if pricingClient != nil {
 				hEngine.Register(&heuristics.ZombieEBSHeuristic{Pricing: pricingClient})
+				hEngine.Register(&heuristics.StoppedInstanceHeuristic{Pricing: pricingClient})
+				hEngine.Register(&heuristics.OrphanedENIHeuristic{Pricing: pricingClient})
+				hEngine.Register(&heuristics.ModernizationHeuristic{Pricing: pricingClient})
+				hEngine.Register(&heuristics.GravitonHeuristic{Pricing: pricingClient})
 			} else {
 				hEngine.Register(&heuristics.ZombieEBSHeuristic{})
+				hEngine.Register(&heuristics.StoppedInstanceHeuristic{})
+				hEngine.Register(&heuristics.OrphanedENIHeuristic{})
 			}
 
 			if cfg.RequiredTags != "" {
@@ -252,6 +363,9 @@ func runRealMode(ctx context.Context, cfg Config, g *graph.Graph, engine *swarm.
 			hEngine.Register(&heuristics.FossilAMIHeuristic{})
 			hEngine.Register(&heuristics.ZombieEKSHeuristic{})
 			hEngine.Register(&heuristics.GhostNodeGroupHeuristic{})
+			hEngine.Register(&heuristics.UnusedSecurityGroupHeuristic{})
+			hEngine.Register(&heuristics.EmptyVPCHeuristic{})
+			hEngine.Register(&heuristics.EmptySubnetHeuristic{})
             
             // v1.2.5 Fargate Analysis
             if k8sClient, err := k8s.NewClient(); err == nil {
@@ -261,21 +375,20 @@ func runRealMode(ctx context.Context, cfg Config, g *graph.Graph, engine *swarm.
                  hEngine.Register(&heuristics.AbandonedFargateHeuristic{K8sClient: nil})
             }
 
-			// Execute Forensics
-			if err := hEngine.Run(ctx, g); err != nil {
-				fmt.Printf("Deep Analysis failed: %v\n", err)
-			}
-
-			// SECOND PASS (Dependent Heuristics)
-			// "The Time Machine" needs Volume Waste to be identified first.
-			hEngine2 := heuristics.NewEngine()
+			// "The Time Machine" declares a dependency on volume waste, so the
+			// engine runs it in a later wave than ZombieEBS.
 			if pricingClient != nil {
-				hEngine2.Register(&heuristics.SnapshotChildrenHeuristic{Pricing: pricingClient})
+				hEngine.Register(&heuristics.SnapshotChildrenHeuristic{Pricing: pricingClient})
 			} else {
-				hEngine2.Register(&heuristics.SnapshotChildrenHeuristic{})
+				hEngine.Register(&heuristics.SnapshotChildrenHeuristic{})
 			}
-			if err := hEngine2.Run(ctx, g); err != nil {
-				fmt.Printf("Time Machine Analysis failed: %v\n", err)
+
+			// User-defined rules and plugins run alongside the built-ins.
+			registerExtra(hEngine, extra)
+
+			// Execute Forensics
+			if err := hEngine.Run(ctx, g); err != nil {
+				fmt.Printf("Deep Analysis failed: %v\n", err)
 			}
 			
 			// Execute Forensics (Pro Feature Check implied by binary, but logic runs for graph data)
@@ -290,42 +403,121 @@ func runRealMode(ctx context.Context, cfg Config, g *graph.Graph, engine *swarm.
                 detective.InvestigateGraph(ctx, g)
             }
 
+			g.FilterConfidence(cfg.MinConfidence)
+
+			if cfg.Headless {
+				warnPolicy(pol, true)
+			}
+			printCoverage(cfg, g)
+			saveSnapshot(cfg, g)
+
 			// Generate Output
 			if !isTrial {
-				os.Mkdir("cloudslash-out", 0755)
-				gen := tf.NewGenerator(g, state)
-				gen.GenerateWasteTF("cloudslash-out/waste.tf")
-				gen.GenerateImportScript("cloudslash-out/import.sh")
-				gen.GenerateDestroyPlan("cloudslash-out/destroy_plan.out")
-				gen.GenerateFixScript("cloudslash-out/fix_terraform.sh")
-				os.Chmod("cloudslash-out/fix_terraform.sh", 0755)
-
-				remGen := remediation.NewGenerator(g)
-				remGen.GenerateSafeDeleteScript("cloudslash-out/safe_cleanup.sh")
-				os.Chmod("cloudslash-out/safe_cleanup.sh", 0755)
-
-				remGen.GenerateIgnoreScript("cloudslash-out/ignore_resources.sh")
-				os.Chmod("cloudslash-out/ignore_resources.sh", 0755)
-
-				if err := report.GenerateHTML(g, "cloudslash-out/dashboard.html"); err != nil {
-					fmt.Printf("Failed to generate dashboard: %v\n", err)
-				}
-				
-				// Generate data export artifacts for external processing.
-				report.GenerateCSV(g, "cloudslash-out/waste_report.csv")
-				report.GenerateJSON(g, "cloudslash-out/waste_report.json")
-
-				if cfg.SlackWebhook != "" {
-					if err := notifier.SendSlackReport(cfg.SlackWebhook, g); err != nil {
-						fmt.Printf("Failed to send Slack report: %v\n", err)
-					}
-				}
+				generateOutputs(ctx, cfg, g, state)
 			}
 		}()
         
         return done
 }
 
+// generateOutputs writes the Pro report and remediation artifacts to cloudslash-out/.
+// state is optional; without it Terraform addresses are left as suggestions.
+func generateOutputs(ctx context.Context, cfg Config, g *graph.Graph, state *tf.State) {
+	if cfg.SkipOutputs {
+		return
+	}
+	os.Mkdir("cloudslash-out", 0755)
+	gen := tf.NewGenerator(g, state)
+	gen.GenerateWasteTF("cloudslash-out/waste.tf")
+	gen.GenerateImportScript("cloudslash-out/import.sh")
+	gen.GenerateDestroyPlan("cloudslash-out/destroy_plan.out")
+	gen.GenerateFixScript("cloudslash-out/fix_terraform.sh")
+	os.Chmod("cloudslash-out/fix_terraform.sh", 0755)
+
+	remGen := remediation.NewGenerator(g)
+	remGen.GenerateSafeDeleteScript("cloudslash-out/safe_cleanup.sh")
+	os.Chmod("cloudslash-out/safe_cleanup.sh", 0755)
+
+	remGen.GenerateIgnoreScript("cloudslash-out/ignore_resources.sh")
+	os.Chmod("cloudslash-out/ignore_resources.sh", 0755)
+
+	if err := report.GenerateHTML(g, "cloudslash-out/dashboard.html"); err != nil {
+		fmt.Printf("Failed to generate dashboard: %v\n", err)
+	}
+
+	// Generate data export artifacts for external processing.
+	report.GenerateCSV(g, "cloudslash-out/waste_report.csv", cfg.where)
+	report.GenerateJSON(g, "cloudslash-out/waste_report.json", cfg.where)
+	report.GenerateCoverageJSON(g, "cloudslash-out/coverage.json")
+	if err := report.GenerateGravitonCSV(g, "cloudslash-out/graviton_backlog.csv"); err != nil {
+		fmt.Printf("Failed to generate Graviton backlog: %v\n", err)
+	}
+
+	if cfg.SlackWebhook != "" {
+		if err := notifier.SendSlackReport(cfg.SlackWebhook, g); err != nil {
+			fmt.Printf("Failed to send Slack report: %v\n", err)
+		}
+	}
+}
+
+// heuristicsFrom lists user-defined rules and plugins as heuristics.
+func heuristicsFrom(userRules []*rules.Rule, plugins []*heuristics.Plugin) []heuristics.WeightedHeuristic {
+	var out []heuristics.WeightedHeuristic
+	for _, r := range userRules {
+		out = append(out, r)
+	}
+	for _, p := range plugins {
+		out = append(out, p)
+	}
+	return out
+}
+
+// registerExtra adds rules and plugins to e, warning about any it rejects.
+func registerExtra(e *heuristics.Engine, extra []heuristics.WeightedHeuristic) {
+	for _, h := range extra {
+		if err := e.Register(h); err != nil {
+			fmt.Printf("Warning: %s not registered: %v\n", h.Name(), err)
+		}
+	}
+}
+
+// warnPolicy prints expired policy rules, and after a scan the rules that matched nothing.
+func warnPolicy(pol *policy.Policy, afterScan bool) {
+	if pol == nil {
+		return
+	}
+	if !afterScan {
+		for _, r := range pol.Expired() {
+			fmt.Printf("Warning: policy rule %s (owner %s) expired on %s and no longer applies\n", r.ID, r.Owner, r.Expires)
+		}
+		return
+	}
+	for _, r := range pol.Unused() {
+		fmt.Printf("Warning: policy rule %s (owner %s) matched no findings; consider removing it\n", r.ID, r.Owner)
+	}
+}
+
+// printCoverage reports how each heuristic fared after a headless scan.
+func printCoverage(cfg Config, g *graph.Graph) {
+	if !cfg.Headless {
+		return
+	}
+	fmt.Println("\nHeuristic coverage:")
+	report.WriteCoverage(os.Stdout, g.HeuristicRuns())
+}
+
+// saveSnapshot persists the analyzed graph when --save was given.
+func saveSnapshot(cfg Config, g *graph.Graph) {
+	if cfg.SavePath == "" {
+		return
+	}
+	if err := g.SaveSnapshot(cfg.SavePath); err != nil {
+		fmt.Printf("Failed to save snapshot: %v\n", err)
+		return
+	}
+	fmt.Printf("Snapshot saved to %s\n", cfg.SavePath)
+}
+
 
 func runScanForProfile(ctx context.Context, region, profile string, g *graph.Graph, engine *swarm.Engine, scanWg *sync.WaitGroup) (*aws.Client, error) {
 	awsClient, err := aws.NewClient(ctx, region, profile)
@@ -342,11 +534,12 @@ func runScanForProfile(ctx context.Context, region, profile string, g *graph.Gra
         }
 		return nil, fmt.Errorf("failed to verify identity: %v", err)
 	}
-	fmt.Printf(" [Profile: %s] Connected to AWS Account: %s\n", profile, identity)
+	fmt.Printf(" [Profile: %s] Connected to AWS Account: %s (%s)\n", profile, identity.AccountID, identity.Region)
+	g.AddScanScope(identity.AccountID, identity.Region)
 
 	// Scanners
-	ec2Scanner := aws.NewEC2Scanner(awsClient.Config, g)
-	s3Scanner := aws.NewS3Scanner(awsClient.Config, g)
+	ec2Scanner := aws.NewEC2Scanner(awsClient.Config, identity, g)
+	s3Scanner := aws.NewS3Scanner(awsClient.Config, identity, g)
 	rdsScanner := aws.NewRDSScanner(awsClient.Config, g)
 	elbScanner := aws.NewELBScanner(awsClient.Config, g)
 	eksScanner := aws.NewEKSScanner(awsClient.Config, g)
@@ -364,22 +557,46 @@ func runScanForProfile(ctx context.Context, region, profile string, g *graph.Gra
 	submitTask(func(ctx context.Context) error { return ec2Scanner.ScanNatGateways(ctx) })
 	submitTask(func(ctx context.Context) error { return ec2Scanner.ScanAddresses(ctx) })
 	submitTask(func(ctx context.Context) error { return s3Scanner.ScanBuckets(ctx) })
-	submitTask(func(ctx context.Context) error { return rdsScanner.ScanInstances(ctx) })
+	// Snapshots are judged against the databases they came from, so those are scanned first.
+	submitTask(func(ctx context.Context) error {
+		if err := rdsScanner.ScanInstances(ctx); err != nil {
+			return err
+		}
+		if err := rdsScanner.ScanClusters(ctx); err != nil {
+			return err
+		}
+		if err := rdsScanner.ScanSnapshots(ctx); err != nil {
+			return err
+		}
+		return rdsScanner.ScanClusterSnapshots(ctx)
+	})
 	submitTask(func(ctx context.Context) error { return elbScanner.ScanLoadBalancers(ctx) })
 	// New Scans
 	submitTask(func(ctx context.Context) error { return ec2Scanner.ScanSnapshots(ctx, "self") })
 	submitTask(func(ctx context.Context) error { return ec2Scanner.ScanImages(ctx) })
-	submitTask(func(ctx context.Context) error { return eksScanner.ScanClusters(ctx) })
-
 	// K8s Scanner (Ghost Detector v1.2.4)
-	// Only run if we can create a client
+	// Only run if we can create a client. It annotates the node groups the EKS
+	// scan finds, so it runs after it.
+	var k8sScanner *k8s.Scanner
 	if k8sClient, err := k8s.NewClient(); err == nil {
-		k8sScanner := k8s.NewScanner(k8sClient, g)
-		submitTask(func(ctx context.Context) error { return k8sScanner.Scan(ctx) })
-	} else {
-		// Log warning but don't fail, as this might be running outside k8s context
-		// fmt.Printf("Skipping K8s Scan (Ghost Detector): %v\n", err)
+		k8sScanner = k8s.NewScanner(k8sClient, g)
 	}
+	submitTask(func(ctx context.Context) error {
+		if err := eksScanner.ScanClusters(ctx); err != nil {
+			return err
+		}
+		if k8sScanner == nil {
+			return nil
+		}
+		return k8sScanner.Scan(ctx)
+	})
+	submitTask(func(ctx context.Context) error { return ec2Scanner.ScanVpcs(ctx) })
+	submitTask(func(ctx context.Context) error { return ec2Scanner.ScanSubnets(ctx) })
+	submitTask(func(ctx context.Context) error { return ec2Scanner.ScanRouteTables(ctx) })
+	submitTask(func(ctx context.Context) error { return ec2Scanner.ScanInternetGateways(ctx) })
+	submitTask(func(ctx context.Context) error { return ec2Scanner.ScanSecurityGroups(ctx) })
+	submitTask(func(ctx context.Context) error { return ec2Scanner.ScanNetworkInterfaces(ctx) })
+	submitTask(func(ctx context.Context) error { return ec2Scanner.ScanVpcEndpoints(ctx) })
 
 	return awsClient, nil
 }
@@ -3,6 +3,7 @@ package aws
 import (
 	"context"
 	"fmt"
+	"math"
 	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
@@ -73,3 +74,71 @@ func (c *CloudWatchClient) GetMetricSum(ctx context.Context, namespace, metricNa
 
 	return sumVal, nil
 }
+
+// GetPeakRate adds up the given metrics per period and returns the highest
+// per-second rate, e.g. VolumeReadOps and VolumeWriteOps give peak IOPS. The
+// period is the shortest multiple of five minutes that fits the window in a
+// single request, so the peak is an average over that period.
+func (c *CloudWatchClient) GetPeakRate(ctx context.Context, namespace string, metricNames []string, dimensions []types.Dimension, startTime, endTime time.Time) (rate float64, period time.Duration, err error) {
+	seconds := int32(math.Ceil(endTime.Sub(startTime).Seconds()/1440/300)) * 300
+	if seconds < 300 {
+		seconds = 300
+	}
+
+	sums := make(map[time.Time]float64)
+	for _, name := range metricNames {
+		input := &cloudwatch.GetMetricStatisticsInput{
+			Namespace:  aws.String(namespace),
+			MetricName: aws.String(name),
+			Dimensions: dimensions,
+			StartTime:  aws.Time(startTime),
+			EndTime:    aws.Time(endTime),
+			Period:     aws.Int32(seconds),
+			Statistics: []types.Statistic{types.StatisticSum},
+		}
+
+		result, err := c.Client.GetMetricStatistics(ctx, input)
+		if err != nil {
+			return 0, 0, fmt.Errorf("failed to get metric statistics: %v", err)
+		}
+		for _, dp := range result.Datapoints {
+			if dp.Sum != nil && dp.Timestamp != nil {
+				sums[*dp.Timestamp] += *dp.Sum
+			}
+		}
+	}
+
+	peak := 0.0
+	for _, sum := range sums {
+		peak = max(peak, sum)
+	}
+	return peak / float64(seconds), time.Duration(seconds) * time.Second, nil
+}
+
+// GetMetricPercentile returns a percentile of a metric over the whole window,
+// e.g. percentile 95 of CPUUtilization, or 5 of FreeableMemory for its low point.
+func (c *CloudWatchClient) GetMetricPercentile(ctx context.Context, namespace, metricName string, dimensions []types.Dimension, startTime, endTime time.Time, percentile float64) (float64, error) {
+	// One period spanning the window, so the percentile covers every sample.
+	period := int32(math.Ceil(endTime.Sub(startTime).Minutes())) * 60
+	stat := fmt.Sprintf("p%g", percentile)
+	input := &cloudwatch.GetMetricStatisticsInput{
+		Namespace:          aws.String(namespace),
+		MetricName:         aws.String(metricName),
+		Dimensions:         dimensions,
+		StartTime:          aws.Time(startTime),
+		EndTime:            aws.Time(endTime),
+		Period:             aws.Int32(period),
+		ExtendedStatistics: []string{stat},
+	}
+
+	result, err := c.Client.GetMetricStatistics(ctx, input)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get metric statistics: %v", err)
+	}
+	for _, dp := range result.Datapoints {
+		if v, ok := dp.ExtendedStatistics[stat]; ok {
+			return v, nil
+		}
+	}
+	return 0, fmt.Errorf("no %s datapoints for %s", stat, metricName)
+}
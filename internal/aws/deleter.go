@@ -2,8 +2,8 @@ package aws
 
 import (
 	"context"
-	"strings"
 
+	"github.com/DrSkyle/cloudslash/internal/resource"
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/ec2"
 )
@@ -22,11 +22,8 @@ func NewDeleter(cfg aws.Config) *Deleter {
 // DeleteVolume deletes an EBS volume.
 func (d *Deleter) DeleteVolume(ctx context.Context, id string) error {
 	// Parse ID from ARN if needed
-	// arn:aws:ec2:region:account:volume/vol-123
-	if strings.HasPrefix(id, "arn:") {
-		parts := strings.Split(id, "/")
-		id = parts[len(parts)-1]
-	}
+	// arn:aws:ec2:us-east-1:123456789012:volume/vol-123
+	id = resource.ResourceID(id)
 
 	_, err := d.EC2.DeleteVolume(ctx, &ec2.DeleteVolumeInput{
 		VolumeId: aws.String(id),
@@ -3,8 +3,11 @@ package aws
 import (
 	"context"
 	"fmt"
+	"strings"
+	"time"
 
 	"github.com/DrSkyle/cloudslash/internal/graph"
+	"github.com/DrSkyle/cloudslash/internal/resource"
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/ec2"
 	"github.com/aws/aws-sdk-go-v2/service/ec2/types"
@@ -17,17 +20,26 @@ type EC2Client interface {
 	DescribeAddresses(ctx context.Context, params *ec2.DescribeAddressesInput, optFns ...func(*ec2.Options)) (*ec2.DescribeAddressesOutput, error)
 	DescribeSnapshots(ctx context.Context, params *ec2.DescribeSnapshotsInput, optFns ...func(*ec2.Options)) (*ec2.DescribeSnapshotsOutput, error)
 	DescribeImages(ctx context.Context, params *ec2.DescribeImagesInput, optFns ...func(*ec2.Options)) (*ec2.DescribeImagesOutput, error)
+	DescribeVpcs(ctx context.Context, params *ec2.DescribeVpcsInput, optFns ...func(*ec2.Options)) (*ec2.DescribeVpcsOutput, error)
+	DescribeSubnets(ctx context.Context, params *ec2.DescribeSubnetsInput, optFns ...func(*ec2.Options)) (*ec2.DescribeSubnetsOutput, error)
+	DescribeRouteTables(ctx context.Context, params *ec2.DescribeRouteTablesInput, optFns ...func(*ec2.Options)) (*ec2.DescribeRouteTablesOutput, error)
+	DescribeInternetGateways(ctx context.Context, params *ec2.DescribeInternetGatewaysInput, optFns ...func(*ec2.Options)) (*ec2.DescribeInternetGatewaysOutput, error)
+	DescribeSecurityGroups(ctx context.Context, params *ec2.DescribeSecurityGroupsInput, optFns ...func(*ec2.Options)) (*ec2.DescribeSecurityGroupsOutput, error)
+	DescribeNetworkInterfaces(ctx context.Context, params *ec2.DescribeNetworkInterfacesInput, optFns ...func(*ec2.Options)) (*ec2.DescribeNetworkInterfacesOutput, error)
+	DescribeVpcEndpoints(ctx context.Context, params *ec2.DescribeVpcEndpointsInput, optFns ...func(*ec2.Options)) (*ec2.DescribeVpcEndpointsOutput, error)
 }
 
 type EC2Scanner struct {
-	Client EC2Client
-	Graph  *graph.Graph
+	Client   EC2Client
+	Identity resource.Identity // Account/region used to build node ARNs
+	Graph    *graph.Graph
 }
 
-func NewEC2Scanner(cfg aws.Config, g *graph.Graph) *EC2Scanner {
+func NewEC2Scanner(cfg aws.Config, identity resource.Identity, g *graph.Graph) *EC2Scanner {
 	return &EC2Scanner{
-		Client: ec2.NewFromConfig(cfg),
-		Graph:  g,
+		Client:   ec2.NewFromConfig(cfg),
+		Identity: identity,
+		Graph:    g,
 	}
 }
 
@@ -39,39 +51,63 @@ func (s *EC2Scanner) ScanInstances(ctx context.Context) error {
 			return fmt.Errorf("failed to describe instances: %v", err)
 		}
 
+		batch := s.Graph.NewBatch()
+
 		for _, reservation := range page.Reservations {
 			for _, instance := range reservation.Instances {
 				id := *instance.InstanceId
-				arn := fmt.Sprintf("arn:aws:ec2:region:account:instance/%s", id) // Simplified ARN construction
+				arn := s.Identity.EC2("instance", id)
 
 				props := map[string]interface{}{
-					"State":      string(instance.State.Name),
-					"Type":       string(instance.InstanceType),
-					"LaunchTime": instance.LaunchTime,
-					"Tags":       parseTags(instance.Tags),
+					"State":        string(instance.State.Name),
+					"Type":         string(instance.InstanceType),
+					"InstanceType": string(instance.InstanceType),
+					"LaunchTime":   instance.LaunchTime,
+					"Tags":         parseTags(instance.Tags),
+				}
+				if instance.Architecture != "" {
+					props["Architecture"] = string(instance.Architecture)
+				}
+				if instance.PlatformDetails != nil {
+					props["PlatformDetails"] = *instance.PlatformDetails // e.g. "Linux/UNIX", "Windows"
+				}
+				if instance.ImageId != nil {
+					props["ImageId"] = *instance.ImageId
+				}
+				if instance.PublicIpAddress != nil {
+					props["PublicIpAddress"] = *instance.PublicIpAddress
+				}
+				if reason := aws.ToString(instance.StateTransitionReason); reason != "" {
+					props["StateTransitionReason"] = reason
+					if instance.State.Name == types.InstanceStateNameStopped {
+						if t, ok := parseTransitionTime(reason); ok {
+							props["StoppedAt"] = t
+						}
+					}
 				}
 
-				s.Graph.AddNode(arn, "AWS::EC2::Instance", props)
+				batch.AddNode(arn, "AWS::EC2::Instance", props)
 
 				// Link to VPC
 				if instance.VpcId != nil {
-					vpcARN := fmt.Sprintf("arn:aws:ec2:region:account:vpc/%s", *instance.VpcId)
-					s.Graph.AddTypedEdge(vpcARN, arn, graph.EdgeTypeContains, 100)
+					vpcARN := s.Identity.EC2("vpc", *instance.VpcId)
+					batch.AddTypedEdge(vpcARN, arn, graph.EdgeTypeContains, 100)
 				}
 
 				// Link to Subnet
 				if instance.SubnetId != nil {
-					subnetARN := fmt.Sprintf("arn:aws:ec2:region:account:subnet/%s", *instance.SubnetId)
-					s.Graph.AddTypedEdge(subnetARN, arn, graph.EdgeTypeContains, 100)
+					subnetARN := s.Identity.EC2("subnet", *instance.SubnetId)
+					batch.AddTypedEdge(subnetARN, arn, graph.EdgeTypeContains, 100)
 				}
 
 				// Link to Security Groups
 				for _, sg := range instance.SecurityGroups {
-					sgARN := fmt.Sprintf("arn:aws:ec2:region:account:security-group/%s", *sg.GroupId)
-					s.Graph.AddTypedEdge(arn, sgARN, graph.EdgeTypeSecuredBy, 100)
+					sgARN := s.Identity.EC2("security-group", *sg.GroupId)
+					batch.AddTypedEdge(arn, sgARN, graph.EdgeTypeSecuredBy, 100)
 				}
 			}
 		}
+		batch.Flush()
 	}
 	return nil
 }
@@ -84,31 +120,45 @@ func (s *EC2Scanner) ScanVolumes(ctx context.Context) error {
 			return fmt.Errorf("failed to describe volumes: %v", err)
 		}
 
+		batch := s.Graph.NewBatch()
+
 		for _, volume := range page.Volumes {
 			id := *volume.VolumeId
-			arn := fmt.Sprintf("arn:aws:ec2:region:account:volume/%s", id)
+			arn := s.Identity.EC2("volume", id)
 
 			props := map[string]interface{}{
 				"State":      string(volume.State),
 				"Size":       *volume.Size,
+				"VolumeType": string(volume.VolumeType),
 				"CreateTime": volume.CreateTime,
 				"Tags":       parseTags(volume.Tags),
 			}
 
-			s.Graph.AddNode(arn, "AWS::EC2::Volume", props)
+			if volume.Iops != nil {
+				props["Iops"] = *volume.Iops
+			}
+			if volume.Throughput != nil {
+				props["Throughput"] = *volume.Throughput
+			}
+			if aws.ToBool(volume.MultiAttachEnabled) {
+				props["MultiAttachEnabled"] = true
+			}
+
+			batch.AddNode(arn, "AWS::EC2::Volume", props)
 
 			// Link to Attachments
 			for _, att := range volume.Attachments {
 				if att.InstanceId != nil {
-					instanceARN := fmt.Sprintf("arn:aws:ec2:region:account:instance/%s", *att.InstanceId)
-					s.Graph.AddTypedEdge(arn, instanceARN, graph.EdgeTypeAttachedTo, 100)
+					instanceARN := s.Identity.EC2("instance", *att.InstanceId)
+					batch.AddTypedEdge(arn, instanceARN, graph.EdgeTypeAttachedTo, 100)
 
 					// Store attachment info in properties for heuristics
-					props["DeleteOnTermination"] = att.DeleteOnTermination
+					props["DeleteOnTermination"] = aws.ToBool(att.DeleteOnTermination)
 					props["AttachedInstanceId"] = *att.InstanceId // Store ID for easy lookup
 				}
 			}
 		}
+		batch.Flush()
 	}
 	return nil
 }
@@ -121,17 +171,20 @@ func (s *EC2Scanner) ScanNatGateways(ctx context.Context) error {
 			return fmt.Errorf("failed to describe nat gateways: %v", err)
 		}
 
+		batch := s.Graph.NewBatch()
+
 		for _, ngw := range page.NatGateways {
 			id := *ngw.NatGatewayId
-			arn := fmt.Sprintf("arn:aws:ec2:region:account:natgateway/%s", id)
+			arn := s.Identity.EC2("natgateway", id)
 			
 			props := map[string]interface{}{
 				"State": string(ngw.State),
 				"Tags":  parseTags(ngw.Tags),
 			}
 
-			s.Graph.AddNode(arn, "AWS::EC2::NatGateway", props)
+			batch.AddNode(arn, "AWS::EC2::NatGateway", props)
 		}
+		batch.Flush()
 	}
 	return nil
 }
@@ -142,9 +195,12 @@ func (s *EC2Scanner) ScanAddresses(ctx context.Context) error {
 		return fmt.Errorf("failed to describe addresses: %v", err)
 	}
 
+	batch := s.Graph.NewBatch()
+	defer batch.Flush()
+
 	for _, addr := range result.Addresses {
 		id := *addr.AllocationId
-		arn := fmt.Sprintf("arn:aws:ec2:region:account:eip/%s", id)
+		arn := s.Identity.EC2("elastic-ip", id)
 		
 		props := map[string]interface{}{
 			"PublicIp": *addr.PublicIp,
@@ -153,11 +209,11 @@ func (s *EC2Scanner) ScanAddresses(ctx context.Context) error {
 
 		if addr.InstanceId != nil {
 			props["InstanceId"] = *addr.InstanceId
-			instanceARN := fmt.Sprintf("arn:aws:ec2:region:account:instance/%s", *addr.InstanceId)
-			s.Graph.AddEdge(arn, instanceARN)
+			instanceARN := s.Identity.EC2("instance", *addr.InstanceId)
+			batch.AddTypedEdge(arn, instanceARN, graph.EdgeTypeAttachedTo, 100)
 		}
 
-		s.Graph.AddNode(arn, "AWS::EC2::EIP", props)
+		batch.AddNode(arn, "AWS::EC2::EIP", props)
 	}
 	return nil
 }
@@ -176,19 +232,23 @@ func (s *EC2Scanner) ScanSnapshots(ctx context.Context, ownerID string) error {
 		if err != nil {
 			return fmt.Errorf("failed to scan snapshots: %v", err)
 		}
+
+		batch := s.Graph.NewBatch()
 		for _, snap := range page.Snapshots {
 			id := *snap.SnapshotId
-			arn := fmt.Sprintf("arn:aws:ec2:region:account:snapshot/%s", id)
+			arn := s.Identity.EC2("snapshot", id)
 			
 			props := map[string]interface{}{
 				"State":       string(snap.State),
 				"VolumeSize":  *snap.VolumeSize,
 				"Description": *snap.Description,
 				"VolumeId":    *snap.VolumeId, // Original volume
+				"OwnerId":     aws.ToString(snap.OwnerId),
 				"Tags":        parseTags(snap.Tags),
 			}
-			s.Graph.AddNode(arn, "AWS::EC2::Snapshot", props)
+			batch.AddNode(arn, "AWS::EC2::Snapshot", props)
 		}
+		batch.Flush()
 	}
 	return nil
 }
@@ -202,29 +262,47 @@ func (s *EC2Scanner) ScanImages(ctx context.Context) error {
 		return fmt.Errorf("failed to scan images: %v", err)
 	}
 
+	batch := s.Graph.NewBatch()
+	defer batch.Flush()
+
 	for _, img := range result.Images {
 		id := *img.ImageId
-		arn := fmt.Sprintf("arn:aws:ec2:region:account:image/%s", id)
+		arn := s.Identity.EC2("image", id)
 
 		props := map[string]interface{}{
 			"State": string(img.State),
 			"Name":  *img.Name,
 			"Tags":  parseTags(img.Tags),
 		}
-		s.Graph.AddNode(arn, "AWS::EC2::AMI", props)
+		batch.AddNode(arn, "AWS::EC2::AMI", props)
 
 		// Link AMI to its Snapshots
 		for _, bdm := range img.BlockDeviceMappings {
 			if bdm.Ebs != nil && bdm.Ebs.SnapshotId != nil {
-				snapARN := fmt.Sprintf("arn:aws:ec2:region:account:snapshot/%s", *bdm.Ebs.SnapshotId)
-				// AMI -> Snapshot (AMI contains/uses Snapshot)
-				s.Graph.AddTypedEdge(arn, snapARN, graph.EdgeTypeContains, 100)
+				snapARN := s.Identity.EC2("snapshot", *bdm.Ebs.SnapshotId)
+				// Snapshot -> AMI (Snapshot backs the AMI, like a volume attached to an instance)
+				batch.AddTypedEdge(snapARN, arn, graph.EdgeTypeAttachedTo, 100)
 			}
 		}
 	}
 	return nil
 }
 
+// parseTransitionTime extracts the time from a state transition reason such
+// as "User initiated (2024-05-04 18:45:06 GMT)".
+func parseTransitionTime(reason string) (time.Time, bool) {
+	start := strings.LastIndex(reason, "(")
+	end := strings.LastIndex(reason, ")")
+	if start < 0 || end < start {
+		return time.Time{}, false
+	}
+	t, err := time.Parse("2006-01-02 15:04:05 MST", reason[start+1:end])
+	if err != nil {
+		return time.Time{}, false
+	}
+	return t, true
+}
+
 func parseTags(tags []types.Tag) map[string]string {
 	out := make(map[string]string)
 	for _, t := range tags {
@@ -74,7 +74,7 @@ func (s *EKSScanner) processCluster(ctx context.Context, name string) error {
 	arn := *cluster.Arn
 	
 	// 1. Check Managed Node Groups
-	hasManagedNodes, err := s.checkManagedNodes(ctx, name)
+	hasManagedNodes, err := s.checkManagedNodes(ctx, name, arn)
 	if err != nil {
 		return err
 	}
@@ -114,11 +114,12 @@ func (s *EKSScanner) processCluster(ctx context.Context, name string) error {
 	return nil
 }
 
-func (s *EKSScanner) checkManagedNodes(ctx context.Context, clusterName string) (bool, error) {
-	// If any nodegroup exists with DesiredSize > 0, return true.
-	// Actually, listing nodegroups is paginated too.
+// checkManagedNodes ingests the cluster's managed node groups and reports
+// whether any of them has a desired size above zero.
+func (s *EKSScanner) checkManagedNodes(ctx context.Context, clusterName, clusterARN string) (bool, error) {
 	paginator := eks.NewListNodegroupsPaginator(s.Client, &eks.ListNodegroupsInput{ClusterName: &clusterName})
-	
+
+	hasNodes := false
 	for paginator.HasMorePages() {
 		page, err := paginator.NextPage(ctx)
 		if err != nil {
@@ -126,22 +127,44 @@ func (s *EKSScanner) checkManagedNodes(ctx context.Context, clusterName string)
 		}
 
 		for _, ngName := range page.Nodegroups {
-			ng, err := s.Client.DescribeNodegroup(ctx, &eks.DescribeNodegroupInput{
+			resp, err := s.Client.DescribeNodegroup(ctx, &eks.DescribeNodegroupInput{
 				ClusterName:   &clusterName,
 				NodegroupName: &ngName,
 			})
 			if err != nil {
 				return false, err
 			}
-			
-			if ng.Nodegroup != nil && ng.Nodegroup.ScalingConfig != nil {
-				if ng.Nodegroup.ScalingConfig.DesiredSize != nil && *ng.Nodegroup.ScalingConfig.DesiredSize > 0 {
-					return true, nil
-				}
+			ng := resp.Nodegroup
+			if ng == nil || ng.NodegroupArn == nil {
+				continue
+			}
+
+			desired := int32(0)
+			if ng.ScalingConfig != nil {
+				desired = aws.ToInt32(ng.ScalingConfig.DesiredSize)
+			}
+			if desired > 0 {
+				hasNodes = true
 			}
+
+			props := map[string]interface{}{
+				"NodeGroupName": ngName,
+				"ClusterName":   clusterName,
+				"Status":        string(ng.Status),
+				"InstanceTypes": ng.InstanceTypes,
+				"AmiType":       string(ng.AmiType), // e.g. AL2_x86_64, AL2_ARM_64
+				"CapacityType":  string(ng.CapacityType),
+				"DesiredSize":   desired,
+				"CreatedAt":     ng.CreatedAt,
+				"Tags":          ng.Tags,
+			}
+			s.Graph.AddNode(*ng.NodegroupArn, "AWS::EKS::NodeGroup", props)
+
+			// Link to Cluster
+			s.Graph.AddEdge(*ng.NodegroupArn, clusterARN)
 		}
 	}
-	return false, nil
+	return hasNodes, nil
 }
 
 func (s *EKSScanner) scanFargateProfiles(ctx context.Context, clusterName, clusterARN string) (bool, error) {
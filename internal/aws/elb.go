@@ -29,6 +29,8 @@ func (s *ELBScanner) ScanLoadBalancers(ctx context.Context) error {
 			return fmt.Errorf("failed to describe load balancers: %v", err)
 		}
 
+		batch := s.Graph.NewBatch()
+
 		for _, lb := range page.LoadBalancers {
 			arn := *lb.LoadBalancerArn
 			name := *lb.LoadBalancerName
@@ -39,8 +41,9 @@ func (s *ELBScanner) ScanLoadBalancers(ctx context.Context) error {
 				"Type":  string(lb.Type),
 			}
 
-			s.Graph.AddNode(arn, "AWS::ElasticLoadBalancingV2::LoadBalancer", props)
+			batch.AddNode(arn, "AWS::ElasticLoadBalancingV2::LoadBalancer", props)
 		}
+		batch.Flush()
 	}
 	return nil
 }
@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/DrSkyle/cloudslash/internal/graph"
+	"github.com/DrSkyle/cloudslash/internal/resource"
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/config"
 	"github.com/aws/aws-sdk-go-v2/service/ec2"
@@ -67,8 +68,9 @@ func TestIntegrationScanVolumes(t *testing.T) {
 	// 4. Run Scanner
 	g := graph.NewGraph()
 	scanner := &EC2Scanner{
-		Client: client, // The real client pointing to LocalStack implements the interface
-		Graph:  g,
+		Client:   client, // The real client pointing to LocalStack implements the interface
+		Identity: resource.NewIdentity("000000000000", "us-east-1"), // LocalStack default account
+		Graph:    g,
 	}
 
 	if err := scanner.ScanVolumes(ctx); err != nil {
@@ -76,18 +78,17 @@ func TestIntegrationScanVolumes(t *testing.T) {
 	}
 
 	// 5. Assert
-	// ARN format used in scanner: arn:aws:ec2:region:account:volume/ID
-	// LocalStack account ID is usually 000000000000 but scanner uses "account" placeholder in current impl
-	// Update: In internal/aws/ec2.go, I used "account" literal in fmt.Sprintf.
-	// arn := fmt.Sprintf("arn:aws:ec2:region:account:volume/%s", id)
-	targetARN := "arn:aws:ec2:region:account:volume/" + volID
+	// ARN format used in scanner: arn:aws:ec2:<region>:<account>:volume/ID
+	targetARN := "arn:aws:ec2:us-east-1:000000000000:volume/" + volID
 
-	node, ok := g.Nodes[targetARN]
+	node, ok := g.Node(targetARN)
 	if !ok {
 		// Debug dump
-		for k := range g.Nodes {
-			t.Logf("Found node: %s", k)
-		}
+		g.Read(func(v *graph.View) {
+			v.Each(func(n *graph.Node) {
+				t.Logf("Found node: %s", n.ID)
+			})
+		})
 		t.Fatalf("Scanner failed to find volume %s in graph", volID)
 	}
 
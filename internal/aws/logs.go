@@ -31,6 +31,8 @@ func (c *CloudWatchLogsClient) ScanLogGroups(ctx context.Context) error {
 			return fmt.Errorf("failed to describe log groups: %v", err)
 		}
 
+		batch := c.Graph.NewBatch()
+
 		for _, group := range page.LogGroups {
 			arn := *group.Arn
 			// Strip trailing :* if present (sometimes ARN has :*)
@@ -45,8 +47,9 @@ func (c *CloudWatchLogsClient) ScanLogGroups(ctx context.Context) error {
 				props["Retention"] = *group.RetentionInDays
 			}
 
-			c.Graph.AddNode(arn, "AWS::Logs::LogGroup", props)
+			batch.AddNode(arn, "AWS::Logs::LogGroup", props)
 		}
+		batch.Flush()
 	}
 	return nil
 }
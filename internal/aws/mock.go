@@ -22,7 +22,9 @@ func (s *MockScanner) Scan(ctx context.Context) error {
 	// 1. Stopped Instance (Zombie)
 	s.Graph.AddNode("arn:aws:ec2:us-east-1:123456789012:instance/i-0mock1234567890", "AWS::EC2::Instance", map[string]interface{}{
 		"State":      "stopped",
-		"LaunchTime": time.Now().Add(-60 * 24 * time.Hour), // 60 days old
+		"Type":       "m5.large",
+		"LaunchTime": time.Now().Add(-180 * 24 * time.Hour),
+		"StoppedAt":  time.Now().Add(-60 * 24 * time.Hour), // Stopped 60 days ago
 	})
 
 	// 2. Unattached Volume (v1.2 Auditor Test)
@@ -30,7 +32,7 @@ func (s *MockScanner) Scan(ctx context.Context) error {
 		"State": "available",
 		"Size":  100, // GB
 	})
-    if node, ok := s.Graph.Nodes["arn:aws:ec2:us-east-1:123456789012:volume/vol-0mock1234567890"]; ok {
+    if node, ok := s.Graph.Node("arn:aws:ec2:us-east-1:123456789012:volume/vol-0mock1234567890"); ok {
         node.Cost = 8.00 // Manually set cost to test TUI
         node.SourceLocation = "terraform/storage.tf:24" // Manually set source to test TUI
     }
@@ -38,33 +40,105 @@ func (s *MockScanner) Scan(ctx context.Context) error {
 	// 3. Zombie Volume (Attached to stopped instance)
 	s.Graph.AddNode("arn:aws:ec2:us-east-1:123456789012:volume/vol-0mockZombie", "AWS::EC2::Volume", map[string]interface{}{
 		"State":               "in-use",
+		"Size":                50,
 		"AttachedInstanceId":  "i-0mock1234567890",
 		"DeleteOnTermination": false,
 	})
+	if node, ok := s.Graph.Node("arn:aws:ec2:us-east-1:123456789012:volume/vol-0mockZombie"); ok {
+		node.Cost = 4.00
+	}
+	s.Graph.AddTypedEdge("arn:aws:ec2:us-east-1:123456789012:volume/vol-0mockZombie", "arn:aws:ec2:us-east-1:123456789012:instance/i-0mock1234567890", graph.EdgeTypeAttachedTo, 100)
+
+	// Elastic IP still associated with the stopped instance
+	s.Graph.AddNode("arn:aws:ec2:us-east-1:123456789012:elastic-ip/eipalloc-0mock", "AWS::EC2::EIP", map[string]interface{}{
+		"PublicIp":   "203.0.113.25",
+		"InstanceId": "i-0mock1234567890",
+	})
+	if node, ok := s.Graph.Node("arn:aws:ec2:us-east-1:123456789012:elastic-ip/eipalloc-0mock"); ok {
+		node.Cost = 3.65
+	}
+	s.Graph.AddTypedEdge("arn:aws:ec2:us-east-1:123456789012:elastic-ip/eipalloc-0mock", "arn:aws:ec2:us-east-1:123456789012:instance/i-0mock1234567890", graph.EdgeTypeAttachedTo, 100)
 
 	// 4. Unused NAT Gateway (Marked as waste manually for demo since we skip CW)
 	s.Graph.AddNode("arn:aws:ec2:us-east-1:123456789012:natgateway/nat-0mock12345", "AWS::EC2::NatGateway", map[string]interface{}{
 		"State": "available",
 	})
-	s.Graph.MarkWaste("arn:aws:ec2:us-east-1:123456789012:natgateway/nat-0mock12345", 80)
-	if node, ok := s.Graph.Nodes["arn:aws:ec2:us-east-1:123456789012:natgateway/nat-0mock12345"]; ok {
-		node.Properties["Reason"] = "Unused NAT Gateway (Mocked)"
-		node.Cost = 32.85 // v1.2 Fixed Rate
-	}
+	s.Graph.AddFinding("arn:aws:ec2:us-east-1:123456789012:natgateway/nat-0mock12345", graph.Finding{
+		Heuristic:      "NATGatewayHeuristic",
+		Category:       graph.CategoryWaste,
+		Confidence:     0.9,
+		RiskScore:      80,
+		MonthlySavings: 32.85, // v1.2 Fixed Rate
+		Reason:         "Unused NAT Gateway (Mocked)",
+		Action:         "Delete the NAT Gateway and release its Elastic IP",
+	})
 
     // 5. Mock Snapshot (Time Machine Test)
     // Parent volume is vol-0mock1234567890 (which is waste)
-    s.Graph.AddNode("arn:aws:ec2:us-east-1:123456789012:snapshot/snap-0mockChild", "AWS::EC2::Snapshot", map[string]interface{}{
+    s.Graph.AddNode("arn:aws:ec2:us-east-1::snapshot/snap-0mockChild", "AWS::EC2::Snapshot", map[string]interface{}{
         "State": "completed",
         "VolumeId": "vol-0mock1234567890", // Links to waste vol
+        "OwnerId": "123456789012",
         "VolumeSize": 100,
     })
 
 	// 5. Stale S3 Multipart Upload
-	s.Graph.AddNode("arn:aws:s3:::mock-bucket/upload-1", "AWS::S3::MultipartUpload", map[string]interface{}{
+	s.Graph.AddNode("arn:aws:s3:::mock-bucket/multipart/upload-1", "AWS::S3::MultipartUpload", map[string]interface{}{
 		"Initiated": time.Now().Add(-10 * 24 * time.Hour), // 10 days old
 	})
 
+	// Network: the stopped instance's VPC, a group nothing uses, and a VPC left empty
+	const (
+		appVPC    = "arn:aws:ec2:us-east-1:123456789012:vpc/vpc-0mockApp"
+		appSubnet = "arn:aws:ec2:us-east-1:123456789012:subnet/subnet-0mockApp"
+		appENI    = "arn:aws:ec2:us-east-1:123456789012:network-interface/eni-0mockApp"
+		webSG     = "arn:aws:ec2:us-east-1:123456789012:security-group/sg-0mockWeb"
+		oldSG     = "arn:aws:ec2:us-east-1:123456789012:security-group/sg-0mockOld"
+		oldVPC    = "arn:aws:ec2:us-east-1:123456789012:vpc/vpc-0mockLegacy"
+		oldSubnet = "arn:aws:ec2:us-east-1:123456789012:subnet/subnet-0mockLegacy"
+		instance  = "arn:aws:ec2:us-east-1:123456789012:instance/i-0mock1234567890"
+	)
+	s.Graph.AddNode(appVPC, "AWS::EC2::VPC", map[string]interface{}{"State": "available", "CidrBlock": "10.0.0.0/16"})
+	s.Graph.AddNode(appSubnet, "AWS::EC2::Subnet", map[string]interface{}{"State": "available", "CidrBlock": "10.0.1.0/24", "AvailabilityZone": "us-east-1a"})
+	s.Graph.AddNode(appENI, "AWS::EC2::NetworkInterface", map[string]interface{}{"Status": "in-use", "InterfaceType": "interface", "AttachedInstanceId": "i-0mock1234567890"})
+	s.Graph.AddNode(webSG, "AWS::EC2::SecurityGroup", map[string]interface{}{"GroupName": "web"})
+	s.Graph.AddNode(oldSG, "AWS::EC2::SecurityGroup", map[string]interface{}{"GroupName": "old-bastion"})
+	s.Graph.AddTypedEdge(appVPC, appSubnet, graph.EdgeTypeContains, 100)
+	s.Graph.AddTypedEdge(appVPC, webSG, graph.EdgeTypeContains, 100)
+	s.Graph.AddTypedEdge(appVPC, oldSG, graph.EdgeTypeContains, 100)
+	s.Graph.AddTypedEdge(appSubnet, appENI, graph.EdgeTypeContains, 100)
+	s.Graph.AddTypedEdge(appSubnet, instance, graph.EdgeTypeContains, 100)
+	s.Graph.AddTypedEdge(appENI, instance, graph.EdgeTypeAttachedTo, 100)
+	s.Graph.AddTypedEdge(appENI, webSG, graph.EdgeTypeSecuredBy, 100)
+	s.Graph.AddTypedEdge(instance, webSG, graph.EdgeTypeSecuredBy, 100)
+
+	// Interface left behind by a deleted Lambda function
+	const orphanENI = "arn:aws:ec2:us-east-1:123456789012:network-interface/eni-0mockOrphan"
+	s.Graph.AddNode(orphanENI, "AWS::EC2::NetworkInterface", map[string]interface{}{
+		"Status":           "available",
+		"InterfaceType":    "lambda",
+		"Description":      "AWS Lambda VPC ENI-orders-worker",
+		"PrivateIpAddress": "10.0.1.57",
+	})
+	s.Graph.AddTypedEdge(appSubnet, orphanENI, graph.EdgeTypeContains, 100)
+	s.Graph.AddTypedEdge(orphanENI, webSG, graph.EdgeTypeSecuredBy, 100)
+
+	s.Graph.AddNode(oldVPC, "AWS::EC2::VPC", map[string]interface{}{"State": "available", "CidrBlock": "10.9.0.0/16"})
+	s.Graph.AddNode(oldSubnet, "AWS::EC2::Subnet", map[string]interface{}{"State": "available", "CidrBlock": "10.9.0.0/24", "AvailabilityZone": "us-east-1b"})
+	s.Graph.AddTypedEdge(oldVPC, oldSubnet, graph.EdgeTypeContains, 100)
+
+	// Final snapshot of a deleted database
+	const finalSnap = "arn:aws:rds:us-east-1:123456789012:snapshot:orders-final"
+	s.Graph.AddNode(finalSnap, "AWS::RDS::DBSnapshot", map[string]interface{}{
+		"SnapshotType":         "manual",
+		"Status":               "available",
+		"Engine":               "postgres",
+		"AllocatedStorage":     int32(200),
+		"DBInstanceIdentifier": "orders",
+		"CreateTime":           time.Now().Add(-400 * 24 * time.Hour),
+	})
+	s.Graph.AddTypedEdge(finalSnap, "arn:aws:rds:us-east-1:123456789012:db:orders", graph.EdgeTypeUnknown, 1)
+
     // 6. Ignored Resource (Should NOT appear in TUI)
     s.Graph.AddNode("arn:aws:ec2:us-east-1:123456789012:volume/vol-0mockIGNORED", "AWS::EC2::Volume", map[string]interface{}{
         "State": "available",
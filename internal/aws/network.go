@@ -0,0 +1,282 @@
+package aws
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/DrSkyle/cloudslash/internal/graph"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	"github.com/aws/aws-sdk-go-v2/service/ec2/types"
+)
+
+// The network scanners fill in the VPC, subnet and security group nodes that
+// instances and load balancers point at. Edges follow deletion order: a VPC
+// Contains its subnets, route tables, gateways and groups, a subnet Contains
+// its network interfaces, and an interface is SecuredBy its groups.
+
+func (s *EC2Scanner) ScanVpcs(ctx context.Context) error {
+	paginator := ec2.NewDescribeVpcsPaginator(s.Client, &ec2.DescribeVpcsInput{})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to describe vpcs: %v", err)
+		}
+
+		batch := s.Graph.NewBatch()
+		for _, vpc := range page.Vpcs {
+			arn := s.Identity.EC2("vpc", *vpc.VpcId)
+			batch.AddNode(arn, "AWS::EC2::VPC", map[string]interface{}{
+				"State":     string(vpc.State),
+				"CidrBlock": aws.ToString(vpc.CidrBlock),
+				"IsDefault": aws.ToBool(vpc.IsDefault),
+				"Tags":      parseTags(vpc.Tags),
+			})
+		}
+		batch.Flush()
+	}
+	return nil
+}
+
+func (s *EC2Scanner) ScanSubnets(ctx context.Context) error {
+	paginator := ec2.NewDescribeSubnetsPaginator(s.Client, &ec2.DescribeSubnetsInput{})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to describe subnets: %v", err)
+		}
+
+		batch := s.Graph.NewBatch()
+		for _, subnet := range page.Subnets {
+			arn := s.Identity.EC2("subnet", *subnet.SubnetId)
+			batch.AddNode(arn, "AWS::EC2::Subnet", map[string]interface{}{
+				"State":                   string(subnet.State),
+				"CidrBlock":               aws.ToString(subnet.CidrBlock),
+				"AvailabilityZone":        aws.ToString(subnet.AvailabilityZone),
+				"AvailableIpAddressCount": aws.ToInt32(subnet.AvailableIpAddressCount),
+				"DefaultForAz":            aws.ToBool(subnet.DefaultForAz),
+				"VpcId":                   aws.ToString(subnet.VpcId),
+				"Tags":                    parseTags(subnet.Tags),
+			})
+
+			if subnet.VpcId != nil {
+				batch.AddTypedEdge(s.Identity.EC2("vpc", *subnet.VpcId), arn, graph.EdgeTypeContains, 100)
+			}
+		}
+		batch.Flush()
+	}
+	return nil
+}
+
+func (s *EC2Scanner) ScanRouteTables(ctx context.Context) error {
+	paginator := ec2.NewDescribeRouteTablesPaginator(s.Client, &ec2.DescribeRouteTablesInput{})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to describe route tables: %v", err)
+		}
+
+		batch := s.Graph.NewBatch()
+		for _, rt := range page.RouteTables {
+			arn := s.Identity.EC2("route-table", *rt.RouteTableId)
+
+			isMain := false
+			for _, assoc := range rt.Associations {
+				if aws.ToBool(assoc.Main) {
+					isMain = true
+				}
+				// Route table -> Subnet (the association goes before the table can)
+				if assoc.SubnetId != nil {
+					batch.AddTypedEdge(arn, s.Identity.EC2("subnet", *assoc.SubnetId), graph.EdgeTypeAttachedTo, 100)
+				}
+			}
+			batch.AddNode(arn, "AWS::EC2::RouteTable", map[string]interface{}{
+				"Main":   isMain,
+				"Routes": len(rt.Routes),
+				"VpcId":  aws.ToString(rt.VpcId),
+				"Tags":   parseTags(rt.Tags),
+			})
+
+			if rt.VpcId != nil {
+				batch.AddTypedEdge(s.Identity.EC2("vpc", *rt.VpcId), arn, graph.EdgeTypeContains, 100)
+			}
+
+			// Route table -> Gateway (traffic leaves through it)
+			for _, route := range rt.Routes {
+				switch {
+				case route.GatewayId != nil && *route.GatewayId != "local":
+					batch.AddTypedEdge(arn, s.Identity.EC2("internet-gateway", *route.GatewayId), graph.EdgeTypeFlowsTo, 100)
+				case route.NatGatewayId != nil:
+					batch.AddTypedEdge(arn, s.Identity.EC2("natgateway", *route.NatGatewayId), graph.EdgeTypeFlowsTo, 100)
+				}
+			}
+		}
+		batch.Flush()
+	}
+	return nil
+}
+
+func (s *EC2Scanner) ScanInternetGateways(ctx context.Context) error {
+	paginator := ec2.NewDescribeInternetGatewaysPaginator(s.Client, &ec2.DescribeInternetGatewaysInput{})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to describe internet gateways: %v", err)
+		}
+
+		batch := s.Graph.NewBatch()
+		for _, igw := range page.InternetGateways {
+			arn := s.Identity.EC2("internet-gateway", *igw.InternetGatewayId)
+			props := map[string]interface{}{
+				"State": "detached",
+				"Tags":  parseTags(igw.Tags),
+			}
+			for _, att := range igw.Attachments {
+				if att.VpcId == nil {
+					continue
+				}
+				props["State"] = string(att.State)
+				props["VpcId"] = *att.VpcId
+				batch.AddTypedEdge(s.Identity.EC2("vpc", *att.VpcId), arn, graph.EdgeTypeContains, 100)
+			}
+			batch.AddNode(arn, "AWS::EC2::InternetGateway", props)
+		}
+		batch.Flush()
+	}
+	return nil
+}
+
+func (s *EC2Scanner) ScanSecurityGroups(ctx context.Context) error {
+	paginator := ec2.NewDescribeSecurityGroupsPaginator(s.Client, &ec2.DescribeSecurityGroupsInput{})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to describe security groups: %v", err)
+		}
+
+		batch := s.Graph.NewBatch()
+		for _, sg := range page.SecurityGroups {
+			id := *sg.GroupId
+			arn := s.Identity.EC2("security-group", id)
+
+			name := aws.ToString(sg.GroupName)
+			batch.AddNode(arn, "AWS::EC2::SecurityGroup", map[string]interface{}{
+				"GroupName":        name,
+				"Description":      aws.ToString(sg.Description),
+				"IsDefault":        name == "default",
+				"IngressRules":     len(sg.IpPermissions),
+				"EgressRules":      len(sg.IpPermissionsEgress),
+				"ReferencedGroups": referencedGroups(id, sg.IpPermissions, sg.IpPermissionsEgress),
+				"VpcId":            aws.ToString(sg.VpcId),
+				"Tags":             parseTags(sg.Tags),
+			})
+
+			if sg.VpcId != nil {
+				batch.AddTypedEdge(s.Identity.EC2("vpc", *sg.VpcId), arn, graph.EdgeTypeContains, 100)
+			}
+		}
+		batch.Flush()
+	}
+	return nil
+}
+
+func (s *EC2Scanner) ScanNetworkInterfaces(ctx context.Context) error {
+	paginator := ec2.NewDescribeNetworkInterfacesPaginator(s.Client, &ec2.DescribeNetworkInterfacesInput{})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to describe network interfaces: %v", err)
+		}
+
+		batch := s.Graph.NewBatch()
+		for _, eni := range page.NetworkInterfaces {
+			arn := s.Identity.EC2("network-interface", *eni.NetworkInterfaceId)
+
+			props := map[string]interface{}{
+				"Status":           string(eni.Status),
+				"InterfaceType":    string(eni.InterfaceType),
+				"Description":      aws.ToString(eni.Description),
+				"RequesterManaged": aws.ToBool(eni.RequesterManaged),
+				"RequesterId":      aws.ToString(eni.RequesterId),
+				"PrivateIpAddress": aws.ToString(eni.PrivateIpAddress),
+				"VpcId":            aws.ToString(eni.VpcId),
+				"SubnetId":         aws.ToString(eni.SubnetId),
+				"Tags":             parseTags(eni.TagSet),
+			}
+			if eni.Association != nil && eni.Association.PublicIp != nil {
+				props["PublicIp"] = *eni.Association.PublicIp
+			}
+			if eni.Attachment != nil && eni.Attachment.InstanceId != nil {
+				props["AttachedInstanceId"] = *eni.Attachment.InstanceId
+				batch.AddTypedEdge(arn, s.Identity.EC2("instance", *eni.Attachment.InstanceId), graph.EdgeTypeAttachedTo, 100)
+			}
+			batch.AddNode(arn, "AWS::EC2::NetworkInterface", props)
+
+			if eni.SubnetId != nil {
+				batch.AddTypedEdge(s.Identity.EC2("subnet", *eni.SubnetId), arn, graph.EdgeTypeContains, 100)
+			}
+			for _, sg := range eni.Groups {
+				if sg.GroupId != nil {
+					batch.AddTypedEdge(arn, s.Identity.EC2("security-group", *sg.GroupId), graph.EdgeTypeSecuredBy, 100)
+				}
+			}
+		}
+		batch.Flush()
+	}
+	return nil
+}
+
+func (s *EC2Scanner) ScanVpcEndpoints(ctx context.Context) error {
+	paginator := ec2.NewDescribeVpcEndpointsPaginator(s.Client, &ec2.DescribeVpcEndpointsInput{})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to describe vpc endpoints: %v", err)
+		}
+
+		batch := s.Graph.NewBatch()
+		for _, ep := range page.VpcEndpoints {
+			arn := s.Identity.EC2("vpc-endpoint", *ep.VpcEndpointId)
+			batch.AddNode(arn, "AWS::EC2::VPCEndpoint", map[string]interface{}{
+				"State":               string(ep.State),
+				"VpcEndpointType":     string(ep.VpcEndpointType),
+				"ServiceName":         aws.ToString(ep.ServiceName),
+				"VpcId":               aws.ToString(ep.VpcId),
+				"SubnetIds":           ep.SubnetIds,
+				"NetworkInterfaceIds": ep.NetworkInterfaceIds,
+				"CreateTime":          ep.CreationTimestamp,
+				"Tags":                parseTags(ep.Tags),
+			})
+
+			if ep.VpcId != nil {
+				batch.AddTypedEdge(s.Identity.EC2("vpc", *ep.VpcId), arn, graph.EdgeTypeContains, 100)
+			}
+			// Interface -> Endpoint (the endpoint owns and removes its interfaces)
+			for _, eniID := range ep.NetworkInterfaceIds {
+				batch.AddTypedEdge(s.Identity.EC2("network-interface", eniID), arn, graph.EdgeTypeAttachedTo, 100)
+			}
+		}
+		batch.Flush()
+	}
+	return nil
+}
+
+// referencedGroups lists the other security groups a group's rules refer to.
+// A referenced group cannot be deleted while the reference exists.
+func referencedGroups(self string, rules ...[]types.IpPermission) []string {
+	seen := make(map[string]bool)
+	var out []string
+	for _, perms := range rules {
+		for _, p := range perms {
+			for _, pair := range p.UserIdGroupPairs {
+				id := aws.ToString(pair.GroupId)
+				if id == "" || id == self || seen[id] {
+					continue
+				}
+				seen[id] = true
+				out = append(out, id)
+			}
+		}
+	}
+	return out
+}
@@ -6,7 +6,9 @@ import (
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/rds"
+	"github.com/aws/aws-sdk-go-v2/service/rds/types"
 	"github.com/DrSkyle/cloudslash/internal/graph"
+	"github.com/DrSkyle/cloudslash/internal/resource"
 )
 
 type RDSScanner struct {
@@ -29,6 +31,8 @@ func (s *RDSScanner) ScanInstances(ctx context.Context) error {
 			return fmt.Errorf("failed to describe rds instances: %v", err)
 		}
 
+		batch := s.Graph.NewBatch()
+
 		for _, instance := range page.DBInstances {
 			// id := *instance.DBInstanceIdentifier // Unused
 			arn := *instance.DBInstanceArn
@@ -37,10 +41,142 @@ func (s *RDSScanner) ScanInstances(ctx context.Context) error {
 				"Status":        *instance.DBInstanceStatus,
 				"InstanceClass": *instance.DBInstanceClass,
 				"Engine":        *instance.Engine,
+				"EngineVersion": aws.ToString(instance.EngineVersion),
+				"MultiAZ":       aws.ToBool(instance.MultiAZ),
+				"StorageType":   aws.ToString(instance.StorageType),     // gp2, gp3, io1, io2, standard
+				"Storage":       aws.ToInt32(instance.AllocatedStorage), // GB
+				"Iops":          aws.ToInt32(instance.Iops),
+				"CreateTime":    instance.InstanceCreateTime,
+				"Tags":          parseRDSTags(instance.TagList),
+			}
+			if instance.DBClusterIdentifier != nil {
+				props["DBClusterIdentifier"] = *instance.DBClusterIdentifier
+			}
+
+			batch.AddNode(arn, "AWS::RDS::DBInstance", props)
+		}
+		batch.Flush()
+	}
+	return nil
+}
+
+// ScanClusters ingests Aurora and Multi-AZ DB clusters. A cluster Contains its
+// member instances, which must be deleted before it.
+func (s *RDSScanner) ScanClusters(ctx context.Context) error {
+	paginator := rds.NewDescribeDBClustersPaginator(s.Client, &rds.DescribeDBClustersInput{})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to describe rds clusters: %v", err)
+		}
+
+		batch := s.Graph.NewBatch()
+		for _, cluster := range page.DBClusters {
+			arn := aws.ToString(cluster.DBClusterArn)
+			if arn == "" {
+				continue
+			}
+			batch.AddNode(arn, "AWS::RDS::DBCluster", map[string]interface{}{
+				"Status":        aws.ToString(cluster.Status),
+				"Engine":        aws.ToString(cluster.Engine),
+				"EngineVersion": aws.ToString(cluster.EngineVersion),
+				"MultiAZ":       aws.ToBool(cluster.MultiAZ),
+				"CreateTime":    cluster.ClusterCreateTime,
+				"Tags":          parseRDSTags(cluster.TagList),
+			})
+
+			id, err := resource.ParseARN(arn)
+			if err != nil {
+				continue
+			}
+			for _, member := range cluster.DBClusterMembers {
+				if member.DBInstanceIdentifier != nil {
+					batch.AddTypedEdge(arn, id.Identity().RDS("db", *member.DBInstanceIdentifier), graph.EdgeTypeContains, 100)
+				}
+			}
+		}
+		batch.Flush()
+	}
+	return nil
+}
+
+// ScanSnapshots ingests DB snapshots, linked to the instance they were taken
+// from. The link does not constrain deletion order; when the instance is gone
+// the target stays an "Unknown" placeholder.
+func (s *RDSScanner) ScanSnapshots(ctx context.Context) error {
+	paginator := rds.NewDescribeDBSnapshotsPaginator(s.Client, &rds.DescribeDBSnapshotsInput{})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to describe rds snapshots: %v", err)
+		}
+
+		batch := s.Graph.NewBatch()
+		for _, snap := range page.DBSnapshots {
+			arn := aws.ToString(snap.DBSnapshotArn)
+			if arn == "" {
+				continue
 			}
+			batch.AddNode(arn, "AWS::RDS::DBSnapshot", map[string]interface{}{
+				"SnapshotType":         aws.ToString(snap.SnapshotType), // manual, automated, shared, public
+				"Status":               aws.ToString(snap.Status),
+				"Engine":               aws.ToString(snap.Engine),
+				"AllocatedStorage":     aws.ToInt32(snap.AllocatedStorage), // GB
+				"DBInstanceIdentifier": aws.ToString(snap.DBInstanceIdentifier),
+				"CreateTime":           snap.SnapshotCreateTime,
+				"Tags":                 parseRDSTags(snap.TagList),
+			})
 
-			s.Graph.AddNode(arn, "AWS::RDS::DBInstance", props)
+			if id, err := resource.ParseARN(arn); err == nil && snap.DBInstanceIdentifier != nil {
+				batch.AddTypedEdge(arn, id.Identity().RDS("db", *snap.DBInstanceIdentifier), graph.EdgeTypeUnknown, 1)
+			}
 		}
+		batch.Flush()
 	}
 	return nil
 }
+
+// ScanClusterSnapshots ingests Aurora cluster snapshots, linked to their
+// cluster the same way ScanSnapshots links instance snapshots.
+func (s *RDSScanner) ScanClusterSnapshots(ctx context.Context) error {
+	paginator := rds.NewDescribeDBClusterSnapshotsPaginator(s.Client, &rds.DescribeDBClusterSnapshotsInput{})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to describe rds cluster snapshots: %v", err)
+		}
+
+		batch := s.Graph.NewBatch()
+		for _, snap := range page.DBClusterSnapshots {
+			arn := aws.ToString(snap.DBClusterSnapshotArn)
+			if arn == "" {
+				continue
+			}
+			batch.AddNode(arn, "AWS::RDS::DBClusterSnapshot", map[string]interface{}{
+				"SnapshotType":        aws.ToString(snap.SnapshotType),
+				"Status":              aws.ToString(snap.Status),
+				"Engine":              aws.ToString(snap.Engine),
+				"AllocatedStorage":    aws.ToInt32(snap.AllocatedStorage), // GB
+				"DBClusterIdentifier": aws.ToString(snap.DBClusterIdentifier),
+				"CreateTime":          snap.SnapshotCreateTime,
+				"Tags":                parseRDSTags(snap.TagList),
+			})
+
+			if id, err := resource.ParseARN(arn); err == nil && snap.DBClusterIdentifier != nil {
+				batch.AddTypedEdge(arn, id.Identity().RDS("cluster", *snap.DBClusterIdentifier), graph.EdgeTypeUnknown, 1)
+			}
+		}
+		batch.Flush()
+	}
+	return nil
+}
+
+func parseRDSTags(tags []types.Tag) map[string]string {
+	out := make(map[string]string, len(tags))
+	for _, t := range tags {
+		if t.Key != nil && t.Value != nil {
+			out[*t.Key] = *t.Value
+		}
+	}
+	return out
+}
@@ -7,17 +7,20 @@ import (
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/s3"
 	"github.com/DrSkyle/cloudslash/internal/graph"
+	"github.com/DrSkyle/cloudslash/internal/resource"
 )
 
 type S3Scanner struct {
-	Client *s3.Client
-	Graph  *graph.Graph
+	Client   *s3.Client
+	Identity resource.Identity
+	Graph    *graph.Graph
 }
 
-func NewS3Scanner(cfg aws.Config, g *graph.Graph) *S3Scanner {
+func NewS3Scanner(cfg aws.Config, identity resource.Identity, g *graph.Graph) *S3Scanner {
 	return &S3Scanner{
-		Client: s3.NewFromConfig(cfg),
-		Graph:  g,
+		Client:   s3.NewFromConfig(cfg),
+		Identity: identity,
+		Graph:    g,
 	}
 }
 
@@ -29,7 +32,7 @@ func (s *S3Scanner) ScanBuckets(ctx context.Context) error {
 
 	for _, bucket := range result.Buckets {
 		name := *bucket.Name
-		arn := fmt.Sprintf("arn:aws:s3:::bucket/%s", name)
+		arn := s.Identity.S3Bucket(name)
 
 		props := map[string]interface{}{
 			"Name":         name,
@@ -58,10 +61,12 @@ func (s *S3Scanner) scanMultipartUploads(ctx context.Context, bucketName, bucket
 			return err
 		}
 
+		batch := s.Graph.NewBatch()
+
 		for _, upload := range page.Uploads {
 			key := *upload.Key
 			uploadId := *upload.UploadId
-			arn := fmt.Sprintf("arn:aws:s3:::multipart/%s/%s", bucketName, uploadId)
+			arn := s.Identity.S3MultipartUpload(bucketName, uploadId)
 
 			props := map[string]interface{}{
 				"Bucket":    bucketName,
@@ -70,9 +75,10 @@ func (s *S3Scanner) scanMultipartUploads(ctx context.Context, bucketName, bucket
 				"Initiated": upload.Initiated,
 			}
 
-			s.Graph.AddNode(arn, "AWS::S3::MultipartUpload", props)
-			s.Graph.AddEdge(arn, bucketARN) // Link to bucket
+			batch.AddNode(arn, "AWS::S3::MultipartUpload", props)
+			batch.AddEdge(arn, bucketARN) // Link to bucket
 		}
+		batch.Flush()
 	}
 	return nil
 }
@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/DrSkyle/cloudslash/internal/graph"
+	"github.com/DrSkyle/cloudslash/internal/resource"
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/ec2"
 	"github.com/aws/aws-sdk-go-v2/service/ec2/types"
@@ -13,7 +14,10 @@ import (
 
 // MockEC2Client implements EC2Client for testing.
 type MockEC2Client struct {
-	DescribeVolumesFunc func(ctx context.Context, params *ec2.DescribeVolumesInput, optFns ...func(*ec2.Options)) (*ec2.DescribeVolumesOutput, error)
+	DescribeVolumesFunc           func(ctx context.Context, params *ec2.DescribeVolumesInput, optFns ...func(*ec2.Options)) (*ec2.DescribeVolumesOutput, error)
+	DescribeInstancesFunc         func(ctx context.Context, params *ec2.DescribeInstancesInput, optFns ...func(*ec2.Options)) (*ec2.DescribeInstancesOutput, error)
+	DescribeSecurityGroupsFunc    func(ctx context.Context, params *ec2.DescribeSecurityGroupsInput, optFns ...func(*ec2.Options)) (*ec2.DescribeSecurityGroupsOutput, error)
+	DescribeNetworkInterfacesFunc func(ctx context.Context, params *ec2.DescribeNetworkInterfacesInput, optFns ...func(*ec2.Options)) (*ec2.DescribeNetworkInterfacesOutput, error)
 	// Add other mock functions if needed
 }
 
@@ -26,6 +30,9 @@ func (m *MockEC2Client) DescribeVolumes(ctx context.Context, params *ec2.Describ
 
 // Stubs for other interface methods
 func (m *MockEC2Client) DescribeInstances(ctx context.Context, params *ec2.DescribeInstancesInput, optFns ...func(*ec2.Options)) (*ec2.DescribeInstancesOutput, error) {
+	if m.DescribeInstancesFunc != nil {
+		return m.DescribeInstancesFunc(ctx, params, optFns...)
+	}
 	return &ec2.DescribeInstancesOutput{}, nil
 }
 func (m *MockEC2Client) DescribeNatGateways(ctx context.Context, params *ec2.DescribeNatGatewaysInput, optFns ...func(*ec2.Options)) (*ec2.DescribeNatGatewaysOutput, error) {
@@ -43,6 +50,40 @@ func (m *MockEC2Client) DescribeSnapshots(ctx context.Context, params *ec2.Descr
 	return &ec2.DescribeSnapshotsOutput{}, nil
 }
 
+func (m *MockEC2Client) DescribeVpcs(ctx context.Context, params *ec2.DescribeVpcsInput, optFns ...func(*ec2.Options)) (*ec2.DescribeVpcsOutput, error) {
+	return &ec2.DescribeVpcsOutput{}, nil
+}
+
+func (m *MockEC2Client) DescribeSubnets(ctx context.Context, params *ec2.DescribeSubnetsInput, optFns ...func(*ec2.Options)) (*ec2.DescribeSubnetsOutput, error) {
+	return &ec2.DescribeSubnetsOutput{}, nil
+}
+
+func (m *MockEC2Client) DescribeRouteTables(ctx context.Context, params *ec2.DescribeRouteTablesInput, optFns ...func(*ec2.Options)) (*ec2.DescribeRouteTablesOutput, error) {
+	return &ec2.DescribeRouteTablesOutput{}, nil
+}
+
+func (m *MockEC2Client) DescribeInternetGateways(ctx context.Context, params *ec2.DescribeInternetGatewaysInput, optFns ...func(*ec2.Options)) (*ec2.DescribeInternetGatewaysOutput, error) {
+	return &ec2.DescribeInternetGatewaysOutput{}, nil
+}
+
+func (m *MockEC2Client) DescribeSecurityGroups(ctx context.Context, params *ec2.DescribeSecurityGroupsInput, optFns ...func(*ec2.Options)) (*ec2.DescribeSecurityGroupsOutput, error) {
+	if m.DescribeSecurityGroupsFunc != nil {
+		return m.DescribeSecurityGroupsFunc(ctx, params, optFns...)
+	}
+	return &ec2.DescribeSecurityGroupsOutput{}, nil
+}
+
+func (m *MockEC2Client) DescribeNetworkInterfaces(ctx context.Context, params *ec2.DescribeNetworkInterfacesInput, optFns ...func(*ec2.Options)) (*ec2.DescribeNetworkInterfacesOutput, error) {
+	if m.DescribeNetworkInterfacesFunc != nil {
+		return m.DescribeNetworkInterfacesFunc(ctx, params, optFns...)
+	}
+	return &ec2.DescribeNetworkInterfacesOutput{}, nil
+}
+
+func (m *MockEC2Client) DescribeVpcEndpoints(ctx context.Context, params *ec2.DescribeVpcEndpointsInput, optFns ...func(*ec2.Options)) (*ec2.DescribeVpcEndpointsOutput, error) {
+	return &ec2.DescribeVpcEndpointsOutput{}, nil
+}
+
 func TestScanVolumes(t *testing.T) {
 	tests := []struct {
 		name          string
@@ -62,9 +103,7 @@ func TestScanVolumes(t *testing.T) {
 			},
 			wantNodeCount: 1,
 			checkNode: func(t *testing.T, g *graph.Graph) {
-				g.Mu.RLock()
-				defer g.Mu.RUnlock()
-				node, ok := g.Nodes["arn:aws:ec2:region:account:volume/vol-zombie"]
+				node, ok := g.Node("arn:aws:ec2:us-east-1:123456789012:volume/vol-zombie")
 				if !ok {
 					t.Fatal("Zombie volume not found in graph")
 				}
@@ -92,17 +131,15 @@ func TestScanVolumes(t *testing.T) {
 			},
 			wantNodeCount: 2, // Volume + attached Instance placeholder
 			checkNode: func(t *testing.T, g *graph.Graph) {
-				g.Mu.RLock()
-				defer g.Mu.RUnlock()
-				node, ok := g.Nodes["arn:aws:ec2:region:account:volume/vol-inuse"]
+				node, ok := g.Node("arn:aws:ec2:us-east-1:123456789012:volume/vol-inuse")
 				if !ok {
 					t.Fatal("Clean volume not found in graph")
 				}
 				// Verify edge to instance exists
 				foundEdge := false
-				edges, _ := g.Edges[node.ID]
+				edges := edgesOf(g, node.ID)
 				for _, edge := range edges {
-					if edge.TargetID == "arn:aws:ec2:region:account:instance/i-12345" {
+					if edge.TargetID == "arn:aws:ec2:us-east-1:123456789012:instance/i-12345" {
 						foundEdge = true
 						break
 					}
@@ -126,8 +163,9 @@ func TestScanVolumes(t *testing.T) {
 			}
 
 			scanner := &EC2Scanner{
-				Client: mock,
-				Graph:  g,
+				Client:   mock,
+				Identity: resource.NewIdentity("123456789012", "us-east-1"),
+				Graph:    g,
 			}
 
 			err := scanner.ScanVolumes(context.Background())
@@ -135,8 +173,8 @@ func TestScanVolumes(t *testing.T) {
 				t.Fatalf("ScanVolumes failed: %v", err)
 			}
 
-			if len(g.Nodes) != tt.wantNodeCount {
-				t.Errorf("Expected %d nodes, got %d", tt.wantNodeCount, len(g.Nodes))
+			if g.Len() != tt.wantNodeCount {
+				t.Errorf("Expected %d nodes, got %d", tt.wantNodeCount, g.Len())
 			}
 
 			if tt.checkNode != nil {
@@ -145,3 +183,144 @@ func TestScanVolumes(t *testing.T) {
 		})
 	}
 }
+
+func TestScanInstances_StopTime(t *testing.T) {
+	mockClient := &MockEC2Client{
+		DescribeInstancesFunc: func(ctx context.Context, params *ec2.DescribeInstancesInput, optFns ...func(*ec2.Options)) (*ec2.DescribeInstancesOutput, error) {
+			return &ec2.DescribeInstancesOutput{Reservations: []types.Reservation{{Instances: []types.Instance{
+				{
+					InstanceId:            aws.String("i-stopped"),
+					State:                 &types.InstanceState{Name: types.InstanceStateNameStopped},
+					LaunchTime:            aws.Time(time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)),
+					StateTransitionReason: aws.String("User initiated (2024-05-04 18:45:06 GMT)"),
+				},
+				{
+					InstanceId:            aws.String("i-running"),
+					State:                 &types.InstanceState{Name: types.InstanceStateNameRunning},
+					StateTransitionReason: aws.String(""),
+					PublicIpAddress:       aws.String("203.0.113.10"),
+				},
+			}}}}, nil
+		},
+	}
+
+	g := graph.NewGraph()
+	scanner := &EC2Scanner{Client: mockClient, Identity: resource.NewIdentity("123456789012", "us-east-1"), Graph: g}
+	if err := scanner.ScanInstances(context.Background()); err != nil {
+		t.Fatalf("ScanInstances failed: %v", err)
+	}
+
+	stopped := nodeByID(g, "arn:aws:ec2:us-east-1:123456789012:instance/i-stopped")
+	want := time.Date(2024, 5, 4, 18, 45, 6, 0, time.UTC)
+	if got, ok := stopped.Properties["StoppedAt"].(time.Time); !ok || !got.Equal(want) {
+		t.Errorf("StoppedAt = %v, want %v", stopped.Properties["StoppedAt"], want)
+	}
+	running := nodeByID(g, "arn:aws:ec2:us-east-1:123456789012:instance/i-running")
+	if _, ok := running.Properties["StoppedAt"]; ok {
+		t.Error("running instances have no stop time")
+	}
+	if running.Properties["PublicIpAddress"] != "203.0.113.10" {
+		t.Errorf("PublicIpAddress = %v", running.Properties["PublicIpAddress"])
+	}
+}
+
+func TestScanSecurityGroups_ReplacesPlaceholders(t *testing.T) {
+	mockClient := &MockEC2Client{
+		DescribeInstancesFunc: func(ctx context.Context, params *ec2.DescribeInstancesInput, optFns ...func(*ec2.Options)) (*ec2.DescribeInstancesOutput, error) {
+			return &ec2.DescribeInstancesOutput{Reservations: []types.Reservation{{Instances: []types.Instance{
+				{
+					InstanceId:     aws.String("i-web"),
+					State:          &types.InstanceState{Name: types.InstanceStateNameRunning},
+					SecurityGroups: []types.GroupIdentifier{{GroupId: aws.String("sg-web")}},
+				},
+			}}}}, nil
+		},
+		DescribeSecurityGroupsFunc: func(ctx context.Context, params *ec2.DescribeSecurityGroupsInput, optFns ...func(*ec2.Options)) (*ec2.DescribeSecurityGroupsOutput, error) {
+			return &ec2.DescribeSecurityGroupsOutput{SecurityGroups: []types.SecurityGroup{
+				{
+					GroupId:   aws.String("sg-web"),
+					GroupName: aws.String("web"),
+					VpcId:     aws.String("vpc-1"),
+					IpPermissions: []types.IpPermission{
+						{UserIdGroupPairs: []types.UserIdGroupPair{{GroupId: aws.String("sg-lb")}, {GroupId: aws.String("sg-web")}}},
+					},
+				},
+			}}, nil
+		},
+		DescribeNetworkInterfacesFunc: func(ctx context.Context, params *ec2.DescribeNetworkInterfacesInput, optFns ...func(*ec2.Options)) (*ec2.DescribeNetworkInterfacesOutput, error) {
+			return &ec2.DescribeNetworkInterfacesOutput{NetworkInterfaces: []types.NetworkInterface{
+				{
+					NetworkInterfaceId: aws.String("eni-web"),
+					Status:             types.NetworkInterfaceStatusInUse,
+					SubnetId:           aws.String("subnet-1"),
+					Groups:             []types.GroupIdentifier{{GroupId: aws.String("sg-web")}},
+					Attachment:         &types.NetworkInterfaceAttachment{InstanceId: aws.String("i-web")},
+				},
+			}}, nil
+		},
+	}
+
+	g := graph.NewGraph()
+	scanner := &EC2Scanner{Client: mockClient, Identity: resource.NewIdentity("123456789012", "us-east-1"), Graph: g}
+	ctx := context.Background()
+	if err := scanner.ScanInstances(ctx); err != nil {
+		t.Fatalf("ScanInstances failed: %v", err)
+	}
+	sgARN := "arn:aws:ec2:us-east-1:123456789012:security-group/sg-web"
+	if nodeByID(g, sgARN).Type != "Unknown" {
+		t.Fatalf("before the group scan, %s should be a placeholder", sgARN)
+	}
+	if err := scanner.ScanSecurityGroups(ctx); err != nil {
+		t.Fatalf("ScanSecurityGroups failed: %v", err)
+	}
+	if err := scanner.ScanNetworkInterfaces(ctx); err != nil {
+		t.Fatalf("ScanNetworkInterfaces failed: %v", err)
+	}
+
+	sg := nodeByID(g, sgARN)
+	if sg.Type != "AWS::EC2::SecurityGroup" || sg.Properties["GroupName"] != "web" {
+		t.Errorf("security group = %s %v", sg.Type, sg.Properties)
+	}
+	refs, _ := sg.Properties["ReferencedGroups"].([]string)
+	if len(refs) != 1 || refs[0] != "sg-lb" {
+		t.Errorf("ReferencedGroups = %v, want [sg-lb]", refs)
+	}
+
+	secured := map[string]bool{}
+	for _, e := range reverseEdgesOf(g, sgARN) {
+		if e.Type == graph.EdgeTypeSecuredBy {
+			secured[e.TargetID] = true
+		}
+	}
+	eniARN := "arn:aws:ec2:us-east-1:123456789012:network-interface/eni-web"
+	if !secured[eniARN] || !secured["arn:aws:ec2:us-east-1:123456789012:instance/i-web"] {
+		t.Errorf("group should be referenced by the instance and its interface, got %v", secured)
+	}
+	found := false
+	for _, e := range edgesOf(g, "arn:aws:ec2:us-east-1:123456789012:subnet/subnet-1") {
+		found = found || (e.TargetID == eniARN && e.Type == graph.EdgeTypeContains)
+	}
+	if !found {
+		t.Error("subnet should contain the interface")
+	}
+}
+
+// nodeByID returns the node with id, or nil when the graph has none.
+func nodeByID(g *graph.Graph, id string) *graph.Node {
+	node, _ := g.Node(id)
+	return node
+}
+
+// edgesOf returns the forward edges of id.
+func edgesOf(g *graph.Graph, id string) []graph.Edge {
+	var edges []graph.Edge
+	g.Read(func(v *graph.View) { edges = v.Edges(id) })
+	return edges
+}
+
+// reverseEdgesOf returns the edges pointing at id.
+func reverseEdgesOf(g *graph.Graph, id string) []graph.Edge {
+	var edges []graph.Edge
+	g.Read(func(v *graph.View) { edges = v.ReverseEdges(id) })
+	return edges
+}
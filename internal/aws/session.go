@@ -8,6 +8,7 @@ import (
 	"regexp"
 	"strings"
 
+	"github.com/DrSkyle/cloudslash/internal/resource"
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/config"
 	"github.com/aws/aws-sdk-go-v2/service/sts"
@@ -64,13 +65,20 @@ func NewClient(ctx context.Context, region, profile string) (*Client, error) {
 }
 
 // VerifyIdentity checks if the credentials are valid and returns the caller identity.
-func (c *Client) VerifyIdentity(ctx context.Context) (string, error) {
+// The identity scopes every ARN the scanners build for this client.
+func (c *Client) VerifyIdentity(ctx context.Context) (resource.Identity, error) {
 	input := &sts.GetCallerIdentityInput{}
 	result, err := c.STS.GetCallerIdentity(ctx, input)
 	if err != nil {
-		return "", fmt.Errorf("failed to get caller identity: %v", err)
+		return resource.Identity{}, fmt.Errorf("failed to get caller identity: %v", err)
 	}
-	return *result.Account, nil
+
+	identity := resource.NewIdentity(aws.ToString(result.Account), c.Config.Region)
+	// Trust the partition of the caller ARN over the region guess (e.g. GovCloud).
+	if caller, err := resource.ParseARN(aws.ToString(result.Arn)); err == nil {
+		identity.Partition = caller.Partition
+	}
+	return identity, nil
 }
 
 // ListProfiles attempts to find all profiles in ~/.aws/config and ~/.aws/credentials.
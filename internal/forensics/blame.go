@@ -3,10 +3,10 @@ package forensics
 import (
 	"context"
 	"fmt"
-	"strings"
 
 	"github.com/DrSkyle/cloudslash/internal/aws"
 	"github.com/DrSkyle/cloudslash/internal/graph"
+	"github.com/DrSkyle/cloudslash/internal/resource"
 )
 
 type Detective struct {
@@ -43,19 +43,8 @@ func (d *Detective) IdentifyOwner(ctx context.Context, node *graph.Node) string
 	// 2. CloudTrail Check
 	if d.CT != nil {
 		// Extract Resource ID (strip ARN if needed)
-		resourceID := node.ID
-		// Basic ARN stripping logic
-
-		// Robust ARN stripping logic
-		// Pattern 1: Slash separated (arn:aws:ec2:region:account:volume/vol-123)
-		if strings.Contains(resourceID, "/") {
-			parts := strings.Split(resourceID, "/")
-			resourceID = parts[len(parts)-1]
-		} else if strings.Count(resourceID, ":") >= 5 {
-			// Pattern 2: Colon separated (arn:aws:s3:::bucket-name OR arn:aws:sns:region:acc:topic)
-			parts := strings.Split(resourceID, ":")
-			resourceID = parts[len(parts)-1]
-		}
+		// CloudTrail indexes the short name (vol-123, bucket-name), not the ARN.
+		resourceID := resource.ResourceID(node.ID)
 
 		user, err := d.CT.LookupCreator(ctx, resourceID)
 		if err == nil {
@@ -68,16 +57,15 @@ func (d *Detective) IdentifyOwner(ctx context.Context, node *graph.Node) string
 
 // InvestigateGraph iterates over waste nodes and populates "Owner" property.
 func (d *Detective) InvestigateGraph(ctx context.Context, g *graph.Graph) {
-	g.Mu.Lock()
-	defer g.Mu.Unlock()
-
-	for _, node := range g.Nodes {
-		if node.IsWaste {
-			owner := d.IdentifyOwner(ctx, node)
-			if node.Properties == nil {
-				node.Properties = make(map[string]interface{})
+	g.Write(func(v *graph.View) {
+		v.Each(func(node *graph.Node) {
+			if node.IsWaste {
+				owner := d.IdentifyOwner(ctx, node)
+				if node.Properties == nil {
+					node.Properties = make(map[string]interface{})
+				}
+				node.Properties["Owner"] = owner
 			}
-			node.Properties["Owner"] = owner
-		}
-	}
+		})
+	})
 }
@@ -1,20 +1,52 @@
 package graph
 
+import (
+	"container/heap"
+	"sort"
+)
+
+// EdgeTypeImpact scales how strongly removing a source affects its target.
+// A detached disk or emptied container is a hard break; a lost security
+// group reference or data flow usually degrades rather than kills.
+var EdgeTypeImpact = map[EdgeType]float64{
+	EdgeTypeAttachedTo: 1.0,
+	EdgeTypeContains:   1.0,
+	EdgeTypeFlowsTo:    0.8,
+	EdgeTypeSecuredBy:  0.5,
+	EdgeTypeUnknown:    0.3,
+}
+
+// ImpactedNode is a resource affected by removing the target.
+type ImpactedNode struct {
+	Node     *Node
+	Strength float64  // 0-1, strongest Weight x EdgeType path from the target
+	Depth    int      // Hops from the target along that path
+	Via      EdgeType // Type of the last edge on that path
+}
+
 // ImpactReport details what will be affected if a node is removed.
 type ImpactReport struct {
-	TargetNode      *Node
-	DirectImpact    []*Node // Nodes directly depending on this
-	CascadingImpact []*Node // Nodes strictly reachable only through this (if we did proper dominator analysis, for now just downstream)
-	TotalRiskScore  int
+	TargetNode   *Node
+	DirectImpact []*Node // Targets of the node's own edges
+
+	// Orphaned nodes are dominated by the target: every path to them runs
+	// through it, so removing it cuts them off. This is what actually breaks.
+	Orphaned []ImpactedNode
+	// Shared nodes are downstream of the target but still reachable another way.
+	Shared []ImpactedNode
+
+	BlastRadius    float64 // Sum of Strength over Orphaned
+	TotalRiskScore int     // RiskScore of Orphaned nodes, scaled by Strength
 }
 
-// AnalyzeImpact performs a traversal to find everything that depends on the target node.
-// It uses ReverseEdges (Who points to me?) to find dependencies.
+// AnalyzeImpact computes the blast radius of removing nodeID.
+// Edges point in the direction of impact (A -> B means removing A affects B),
+// so the dominator tree over forward edges gives the nodes only the target keeps alive.
 func (g *Graph) AnalyzeImpact(nodeID string) *ImpactReport {
-	g.Mu.RLock()
-	defer g.Mu.RUnlock()
+	g.rlockAll()
+	defer g.runlockAll()
 
-	targetNode, ok := g.Nodes[nodeID]
+	targetNode, ok := g.node(nodeID)
 	if !ok {
 		return nil
 	}
@@ -23,86 +55,127 @@ func (g *Graph) AnalyzeImpact(nodeID string) *ImpactReport {
 		TargetNode: targetNode,
 	}
 
-	// 1. Direct Dependencies (Reverse Edges: Who needs me?)
-	// e.g. EC2 needs EBS. If we delete EBS, EC2 breaks (or crashes).
-	// Actually, usually it's "AttachedTo".
-	// If EBS is attached to EC2:
-	// EBS --AttachedTo--> EC2.
-	// If we delete EBS, EC2 is affected (Disk check fails).
-	//
-	// Edge direction: Source -> Target.
-	// "EBS AttachedTo EC2": Source=EBS, Target=EC2.
-	// g.Edges[EBS] = [EC2].
-	//
-	// So "Downstream" (Forward Edges) are the things affected by this node's removal.
-	// Wait, let's verify direction semantics.
-	// Dependencies usually mean "A depends on B".
-	// If A depends on B, then if B is removed, A is broken.
-	// In DAGs, usually A -> B means A dependency B.
-	// BUT in my `dag.go`, `EdgeTypeAttachedTo` (EBS -> EC2).
-	// Does EBS depend on EC2? Or EC2 depend on EBS?
-	// Physically, EBS volume is attached to Instance.
-	// If Instance is deleted, Volume might be deleted (DeleteOnTermination).
-	// If Volume is deleted, Instance might crash.
-	//
-	// Let's assume "Impact" = "What points to me?" vs "What do I point to?"
-	// In the graph:
-	// EBS -> EC2 (AttachedTo)
-	// SecurityGroup -> EC2 (SecuredBy? No, EC2 -> SG usually).
-	// Subnet -> VPC (Contains? No, VPC -> Subnet).
-	//
-	// Let's standardise on "Flow of Data/Control".
-	// If A -> B, then A affects B.
-	// So `g.Edges[nodeID]` provides the direct impact.
-
-	directEdges := g.Edges[nodeID] // Targets
-	for _, edge := range directEdges {
-		if node, ok := g.Nodes[edge.TargetID]; ok {
+	for _, edge := range g.edgesOf(nodeID) {
+		if node, ok := g.node(edge.TargetID); ok {
 			report.DirectImpact = append(report.DirectImpact, node)
-			report.TotalRiskScore += node.RiskScore
 		}
 	}
 
-	// 2. Cascading Impact (Recursive downstream)
-	// BFS on forward edges
-	visited := make(map[string]bool)
-	queue := []string{}
+	reach := g.impactStrengths(nodeID)
+	if len(reach) == 0 {
+		return report
+	}
+
+	dominated := g.dominatedBy(nodeID)
+
+	var riskSum float64
+	for id, imp := range reach {
+		if dominated[id] {
+			report.Orphaned = append(report.Orphaned, imp)
+			report.BlastRadius += imp.Strength
+			riskSum += imp.Strength * float64(imp.Node.RiskScore)
+		} else {
+			report.Shared = append(report.Shared, imp)
+		}
+	}
+	report.TotalRiskScore = int(riskSum + 0.5)
+
+	sortImpacted(report.Orphaned)
+	sortImpacted(report.Shared)
+	return report
+}
+
+// dominatedBy returns the nodes in the dominator subtree of id, excluding id.
+func (g *Graph) dominatedBy(id string) map[string]bool {
+	children := make(map[string][]string)
+	for node, dom := range g.immediateDominators() {
+		children[dom] = append(children[dom], node)
+	}
 
-	// Seed queue with direct children
-	for _, child := range report.DirectImpact {
-		visited[child.ID] = true
-		queue = append(queue, child.ID)
+	result := make(map[string]bool)
+	stack := append([]string(nil), children[id]...)
+	for len(stack) > 0 {
+		n := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		result[n] = true
+		stack = append(stack, children[n]...)
 	}
+	return result
+}
 
-	// Mark target as visited so we don't loop back if cycle exists (shouldn't in DAG but safety first)
-	visited[nodeID] = true
-
-	for len(queue) > 0 {
-		currentID := queue[0]
-		queue = queue[1:]
-
-		// Add to cascading (skipping direct ones which are already added? No, keep them separate or disjoint?)
-		// Let's make CascadingImpact include indirect only?
-		// Or all downstream?
-		// Let's make it ALL downstream for simplicity of "Total Impact".
-		if _, ok := g.Nodes[currentID]; ok {
-			// Don't duplicate if it was already in direct impact?
-			// Actually report.DirectImpact is a subset.
-			// Let's just create a unique set of all impacted nodes.
-			// Re-logic:
+// impactStrengths finds, for every node downstream of id, the strongest
+// dependency path from id. Strength multiplies along a path and never grows,
+// so a max-first search settles each node the first time it is popped.
+func (g *Graph) impactStrengths(id string) map[string]ImpactedNode {
+	best := map[string]ImpactedNode{}
+	settled := map[string]bool{id: true}
+
+	pq := &impactQueue{{id: id, strength: 1}}
+	for pq.Len() > 0 {
+		cur := heap.Pop(pq).(impactItem)
+		if cur.id != id {
+			if settled[cur.id] {
+				continue
+			}
+			settled[cur.id] = true
 		}
 
-		children := g.Edges[currentID]
-		for _, childEdge := range children {
-			if !visited[childEdge.TargetID] {
-				visited[childEdge.TargetID] = true
-				queue = append(queue, childEdge.TargetID)
-				if node, ok := g.Nodes[childEdge.TargetID]; ok {
-					report.CascadingImpact = append(report.CascadingImpact, node)
-				}
+		for _, e := range g.edgesOf(cur.id) {
+			if settled[e.TargetID] {
+				continue
+			}
+			node, ok := g.node(e.TargetID)
+			if !ok {
+				continue
 			}
+			s := cur.strength * edgeStrength(e)
+			if prev, seen := best[e.TargetID]; seen && prev.Strength >= s {
+				continue
+			}
+			best[e.TargetID] = ImpactedNode{Node: node, Strength: s, Depth: cur.depth + 1, Via: e.Type}
+			heap.Push(pq, impactItem{id: e.TargetID, strength: s, depth: cur.depth + 1})
 		}
 	}
+	return best
+}
 
-	return report
+func edgeStrength(e Edge) float64 {
+	w := float64(e.Weight) / 100
+	if w <= 0 || w > 1 {
+		w = 1
+	}
+	factor, ok := EdgeTypeImpact[e.Type]
+	if !ok {
+		factor = EdgeTypeImpact[EdgeTypeUnknown]
+	}
+	return w * factor
+}
+
+func sortImpacted(list []ImpactedNode) {
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].Strength != list[j].Strength {
+			return list[i].Strength > list[j].Strength
+		}
+		return list[i].Node.ID < list[j].Node.ID
+	})
+}
+
+type impactItem struct {
+	id       string
+	strength float64
+	depth    int
+}
+
+// impactQueue is a max-heap on strength.
+type impactQueue []impactItem
+
+func (q impactQueue) Len() int            { return len(q) }
+func (q impactQueue) Less(i, j int) bool  { return q[i].strength > q[j].strength }
+func (q impactQueue) Swap(i, j int)       { q[i], q[j] = q[j], q[i] }
+func (q *impactQueue) Push(x interface{}) { *q = append(*q, x.(impactItem)) }
+func (q *impactQueue) Pop() interface{} {
+	old := *q
+	item := old[len(old)-1]
+	*q = old[:len(old)-1]
+	return item
 }
@@ -0,0 +1,68 @@
+package graph
+
+import "testing"
+
+func TestImmediateDominators(t *testing.T) {
+	g := NewGraph()
+	// Diamond: a -> b -> d, a -> c -> d, d -> e. Plus an unreachable cycle x <-> y.
+	g.AddTypedEdge("a", "b", EdgeTypeContains, 100)
+	g.AddTypedEdge("a", "c", EdgeTypeContains, 100)
+	g.AddTypedEdge("b", "d", EdgeTypeContains, 100)
+	g.AddTypedEdge("c", "d", EdgeTypeContains, 100)
+	g.AddTypedEdge("d", "e", EdgeTypeContains, 100)
+	g.AddTypedEdge("x", "y", EdgeTypeFlowsTo, 100)
+	g.AddTypedEdge("y", "x", EdgeTypeFlowsTo, 100)
+
+	idom := g.ImmediateDominators()
+	want := map[string]string{"a": "", "b": "a", "c": "a", "d": "a", "e": "d", "x": "", "y": "x"}
+	for id, dom := range want {
+		if idom[id] != dom {
+			t.Errorf("idom(%s) = %q, want %q", id, idom[id], dom)
+		}
+	}
+}
+
+func TestAnalyzeImpact(t *testing.T) {
+	g := NewGraph()
+	// vpc contains subnet and a shared instance; the instance is also in subnet-b.
+	g.AddTypedEdge("vpc", "subnet-a", EdgeTypeContains, 100)
+	g.AddTypedEdge("subnet-a", "i-exclusive", EdgeTypeContains, 100)
+	g.AddTypedEdge("subnet-a", "i-shared", EdgeTypeContains, 100)
+	g.AddTypedEdge("subnet-b", "i-shared", EdgeTypeContains, 100)
+	g.AddTypedEdge("i-exclusive", "sg", EdgeTypeSecuredBy, 50)
+	nodeByID(g, "i-exclusive").RiskScore = 80
+
+	r := g.AnalyzeImpact("subnet-a")
+	if r == nil {
+		t.Fatal("expected report")
+	}
+	if len(r.DirectImpact) != 2 {
+		t.Errorf("expected 2 direct impacts, got %d", len(r.DirectImpact))
+	}
+
+	if len(r.Orphaned) != 2 {
+		t.Fatalf("expected i-exclusive and sg orphaned, got %+v", r.Orphaned)
+	}
+	if r.Orphaned[0].Node.ID != "i-exclusive" || r.Orphaned[0].Strength != 1 {
+		t.Errorf("unexpected first orphan %+v", r.Orphaned[0])
+	}
+	// SecuredBy (0.5) at weight 50 (0.5): 0.25 strength, two hops out.
+	if sg := r.Orphaned[1]; sg.Node.ID != "sg" || sg.Strength != 0.25 || sg.Depth != 2 || sg.Via != EdgeTypeSecuredBy {
+		t.Errorf("unexpected sg impact %+v", sg)
+	}
+
+	if len(r.Shared) != 1 || r.Shared[0].Node.ID != "i-shared" {
+		t.Errorf("expected i-shared to be shared, got %+v", r.Shared)
+	}
+
+	if r.BlastRadius != 1.25 {
+		t.Errorf("expected blast radius 1.25, got %v", r.BlastRadius)
+	}
+	if r.TotalRiskScore != 80 {
+		t.Errorf("expected risk 80, got %d", r.TotalRiskScore)
+	}
+
+	if g.AnalyzeImpact("missing") != nil {
+		t.Error("expected nil report for unknown node")
+	}
+}
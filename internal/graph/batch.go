@@ -0,0 +1,134 @@
+package graph
+
+// Batch buffers graph writes so a scanner takes each shard lock once per page
+// instead of once per resource. Operations have the same semantics as
+// Graph.AddNode, Graph.AddTypedEdge and Graph.AddFinding, and are applied in
+// the order they were added within each shard. Findings are applied after the
+// nodes and edges, so they may refer to nodes added by the same batch.
+// A Batch is not safe for concurrent use; give each goroutine its own.
+type Batch struct {
+	g   *Graph
+	ops []batchOp
+}
+
+type batchOp struct {
+	node     bool
+	id       string // Node ID, or edge source
+	target   string
+	kind     string // Node type
+	props    map[string]interface{}
+	edgeType EdgeType
+	weight   int
+	finding  *Finding
+}
+
+// NewBatch starts an empty batch of writes against g.
+func (g *Graph) NewBatch() *Batch {
+	return &Batch{g: g}
+}
+
+// AddNode queues Graph.AddNode.
+func (b *Batch) AddNode(id, resourceType string, props map[string]interface{}) {
+	if id == "" {
+		return
+	}
+	b.ops = append(b.ops, batchOp{node: true, id: id, kind: resourceType, props: props})
+}
+
+// AddEdge queues Graph.AddEdge.
+func (b *Batch) AddEdge(sourceID, targetID string) {
+	b.AddTypedEdge(sourceID, targetID, EdgeTypeUnknown, 1)
+}
+
+// AddTypedEdge queues Graph.AddTypedEdge.
+func (b *Batch) AddTypedEdge(sourceID, targetID string, edgeType EdgeType, weight int) {
+	if sourceID == "" || targetID == "" {
+		return
+	}
+	b.ops = append(b.ops, batchOp{id: sourceID, target: targetID, edgeType: edgeType, weight: weight})
+}
+
+// AddFinding queues Graph.AddFinding. Heuristics that walk the graph under
+// the read lock queue their findings here and Flush once it is released.
+func (b *Batch) AddFinding(id string, f Finding) {
+	b.ops = append(b.ops, batchOp{id: id, finding: &f})
+}
+
+// Len returns the number of queued operations.
+func (b *Batch) Len() int { return len(b.ops) }
+
+// Flush applies the queued operations and resets the batch. Each shard is
+// locked once per pass, never all at once, so batches writing to different
+// shards proceed in parallel.
+func (b *Batch) Flush() {
+	if len(b.ops) == 0 {
+		return
+	}
+	g := b.g
+
+	// Group by the shard that owns each operation: nodes and forward edges by
+	// their source, findings by their node.
+	var structure, findings [shardCount][]*batchOp
+	for i := range b.ops {
+		op := &b.ops[i]
+		si := g.shardIndex(op.id)
+		if op.finding != nil {
+			findings[si] = append(findings[si], op)
+		} else {
+			structure[si] = append(structure[si], op)
+		}
+	}
+
+	// Pass 1: nodes, and the forward half of each new edge.
+	var reverse [shardCount][]*batchOp
+	for si, ops := range structure {
+		if len(ops) == 0 {
+			continue
+		}
+		s := &g.shards[si]
+		s.mu.Lock()
+		for _, op := range ops {
+			if op.node {
+				s.addNode(op.id, op.kind, op.props)
+				continue
+			}
+			s.ensure(op.id)
+			if s.addForward(op.id, op.target, op.edgeType, op.weight) {
+				ti := g.shardIndex(op.target)
+				reverse[ti] = append(reverse[ti], op)
+			}
+		}
+		s.mu.Unlock()
+	}
+
+	// Pass 2: the reverse half, in the target's shard.
+	for si, ops := range reverse {
+		if len(ops) == 0 {
+			continue
+		}
+		s := &g.shards[si]
+		s.mu.Lock()
+		for _, op := range ops {
+			s.ensure(op.target)
+			s.addReverse(op.id, op.target, op.edgeType, op.weight)
+		}
+		s.mu.Unlock()
+	}
+
+	// Pass 3: findings.
+	for si, ops := range findings {
+		if len(ops) == 0 {
+			continue
+		}
+		s := &g.shards[si]
+		s.mu.Lock()
+		for _, op := range ops {
+			if node, ok := s.nodes[op.id]; ok {
+				g.flag(node, *op.finding)
+			}
+		}
+		s.mu.Unlock()
+	}
+
+	b.ops = b.ops[:0]
+}
@@ -0,0 +1,169 @@
+package graph
+
+import (
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+// Run with: go test ./internal/graph -run '^$' -bench . -benchmem
+//
+// The "Scan" and "Linear" benchmarks reproduce the access patterns the
+// heuristics and AddTypedEdge used before the type index and edge set, so
+// the old and new costs can be compared on the same 1M-node estate.
+//
+// The Ingest benchmarks write into a 1M-node graph from parallel goroutines,
+// as the scanners do. IngestGlobalLock wraps the same calls in one mutex,
+// which is how the store behaved before it was sharded; compare them with
+// -cpu 1,8 on a multi-core machine.
+
+const benchNodes = 1_000_000
+
+var benchTypes = []string{
+	"AWS::EC2::Instance", "AWS::EC2::Volume", "AWS::EC2::Snapshot", "AWS::EC2::EIP",
+	"AWS::EC2::NatGateway", "AWS::EC2::AMI", "AWS::S3::Bucket", "AWS::S3::MultipartUpload",
+	"AWS::RDS::DBInstance", "AWS::ElasticLoadBalancingV2::LoadBalancer", "AWS::Logs::LogGroup",
+	"AWS::EKS::Cluster", "AWS::EKS::NodeGroup", "AWS::EKS::FargateProfile",
+	"AWS::Lambda::Function", "AWS::DynamoDB::Table", "AWS::SQS::Queue", "AWS::SNS::Topic",
+	"AWS::IAM::Role", "AWS::EC2::SecurityGroup",
+}
+
+var (
+	benchOnce  sync.Once
+	benchGraph *Graph
+)
+
+// largeGraph returns a read-only 1M-node graph, built once per test binary.
+func largeGraph() *Graph {
+	benchOnce.Do(func() { benchGraph = newLargeGraph() })
+	return benchGraph
+}
+
+// newLargeGraph builds a 1M-node graph: nodes spread over 20 types, each
+// attached to one of 1000 hub nodes.
+func newLargeGraph() *Graph {
+	g := NewGraph()
+	batch := g.NewBatch()
+	for i := 0; i < benchNodes; i++ {
+		id := fmt.Sprintf("arn:node/%d", i)
+		batch.AddNode(id, benchTypes[i%len(benchTypes)], map[string]interface{}{"Size": i})
+		batch.AddTypedEdge(id, fmt.Sprintf("arn:hub/%d", i%1000), EdgeTypeAttachedTo, 100)
+		if batch.Len() >= 10_000 {
+			batch.Flush()
+		}
+	}
+	batch.Flush()
+	return g
+}
+
+func BenchmarkNodesByType_1M(b *testing.B) {
+	g := largeGraph()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if n := len(g.NodesByType("AWS::RDS::DBInstance")); n != benchNodes/len(benchTypes) {
+			b.Fatalf("got %d nodes", n)
+		}
+	}
+}
+
+func BenchmarkNodesByTypeScan_1M(b *testing.B) {
+	g := largeGraph()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		var nodes []*Node
+		g.Read(func(v *View) {
+			v.Each(func(node *Node) {
+				if node.Type == "AWS::RDS::DBInstance" {
+					nodes = append(nodes, node)
+				}
+			})
+		})
+		if len(nodes) != benchNodes/len(benchTypes) {
+			b.Fatalf("got %d nodes", len(nodes))
+		}
+	}
+}
+
+// Each hub already has 1000 inbound edges; re-adding the newest one must stay O(1).
+func BenchmarkEdgeDedup_1M(b *testing.B) {
+	g := largeGraph()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		g.AddTypedEdge("arn:node/999000", "arn:hub/0", EdgeTypeAttachedTo, 100)
+	}
+}
+
+func BenchmarkEdgeDedupLinear_1M(b *testing.B) {
+	g := largeGraph()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		exists := false
+		g.Write(func(v *View) {
+			for _, e := range v.ReverseEdges("arn:hub/0") {
+				if e.TargetID == "arn:node/999000" && e.Type == EdgeTypeAttachedTo {
+					exists = true
+					break
+				}
+			}
+		})
+		if !exists {
+			b.Fatal("edge not found")
+		}
+	}
+}
+
+// ingestParallel adds one resource and its hub edge per iteration to a
+// 1M-node graph from b.RunParallel goroutines, through the store returned by
+// writer for each goroutine.
+func ingestParallel(b *testing.B, writer func(g *Graph) (add func(id, resourceType, hub string), flush func())) {
+	g := newLargeGraph()
+	var seq int64 = benchNodes
+	b.ReportAllocs()
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		add, flush := writer(g)
+		for pb.Next() {
+			i := atomic.AddInt64(&seq, 1)
+			add(fmt.Sprintf("arn:node/%d", i), benchTypes[i%int64(len(benchTypes))], fmt.Sprintf("arn:hub/%d", i%1000))
+		}
+		flush()
+	})
+}
+
+// One shard lock per call, as the scanners write today...
+func BenchmarkIngestSharded_1M(b *testing.B) {
+	ingestParallel(b, func(g *Graph) (func(id, resourceType, hub string), func()) {
+		return func(id, resourceType, hub string) {
+			g.AddNode(id, resourceType, nil)
+			g.AddTypedEdge(id, hub, EdgeTypeAttachedTo, 100)
+		}, func() {}
+	})
+}
+
+// ...versus every writer serialised behind one graph-wide lock...
+func BenchmarkIngestGlobalLock_1M(b *testing.B) {
+	var mu sync.Mutex
+	ingestParallel(b, func(g *Graph) (func(id, resourceType, hub string), func()) {
+		return func(id, resourceType, hub string) {
+			mu.Lock()
+			g.AddNode(id, resourceType, nil)
+			g.AddTypedEdge(id, hub, EdgeTypeAttachedTo, 100)
+			mu.Unlock()
+		}, func() {}
+	})
+}
+
+// ...and pages of 1000 resources flushed shard by shard.
+func BenchmarkIngestBatched_1M(b *testing.B) {
+	ingestParallel(b, func(g *Graph) (func(id, resourceType, hub string), func()) {
+		batch := g.NewBatch()
+		return func(id, resourceType, hub string) {
+			batch.AddNode(id, resourceType, nil)
+			batch.AddTypedEdge(id, hub, EdgeTypeAttachedTo, 100)
+			if batch.Len() >= 2000 {
+				batch.Flush()
+			}
+		}, batch.Flush
+	})
+}
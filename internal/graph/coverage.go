@@ -0,0 +1,48 @@
+package graph
+
+import "time"
+
+// Heuristic run statuses.
+const (
+	RunOK      = "ok"      // Examined every candidate
+	RunPartial = "partial" // Skipped some candidates, see HeuristicRun.Skips
+	RunFailed  = "failed"  // Returned an error; its results may be incomplete
+)
+
+// MaxSkips caps the skipped resources kept per heuristic run.
+const MaxSkips = 100
+
+// HeuristicRun records how one heuristic fared in an analysis, so that "no
+// waste found" can be told apart from "could not look".
+type HeuristicRun struct {
+	Heuristic string        `json:"heuristic"`
+	Status    string        `json:"status"`
+	Duration  time.Duration `json:"duration_ns"`
+	Examined  int           `json:"examined"`          // Candidate resources the heuristic looked at
+	Findings  int           `json:"findings"`          // Results reported, before suppression
+	Skipped   int           `json:"skipped,omitempty"` // Candidates it could not evaluate
+	Skips     []Skip        `json:"skips,omitempty"`   // The first MaxSkips of them, with reasons
+	Error     string        `json:"error,omitempty"`
+}
+
+// Skip is a resource a heuristic could not evaluate.
+type Skip struct {
+	ResourceID string `json:"resource_id"`
+	Reason     string `json:"reason"` // e.g. "metric fetch failed: ..."
+}
+
+// AddHeuristicRuns appends runs to the coverage recorded for this scan.
+func (g *Graph) AddHeuristicRuns(runs ...HeuristicRun) {
+	g.meta.Lock()
+	defer g.meta.Unlock()
+
+	g.Metadata.Heuristics = append(g.Metadata.Heuristics, runs...)
+}
+
+// HeuristicRuns returns a copy of the recorded coverage.
+func (g *Graph) HeuristicRuns() []HeuristicRun {
+	g.meta.RLock()
+	defer g.meta.RUnlock()
+
+	return append([]HeuristicRun(nil), g.Metadata.Heuristics...)
+}
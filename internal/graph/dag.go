@@ -2,10 +2,11 @@ package graph
 
 import (
 	"fmt"
+	"hash/maphash"
 	"strconv"
 	"strings"
-	"time"
 	"sync"
+	"time"
 )
 
 // EdgeType defines the semantic relationship between resources.
@@ -31,29 +32,40 @@ type Node struct {
 	ID            string                 // Unique Identifier (ARN)
 	Type          string                 // Resource Type (e.g., "AWS::EC2::Instance")
 	Properties    map[string]interface{} // Resource attributes
-	IsWaste       bool                   // Flagged as waste?
-	Justified     bool                   // Is this accepted/known waste?
+	IsWaste       bool                   // Has a waste finding, see HasWasteFinding
+	Justified     bool                   // Is this accepted/known waste? Derived from the findings
 	Justification string                 // Reason for justification
 	RiskScore     int                    // 0-100
-	Cost          float64                // Monthly cost estimate
+	Cost          float64                // Monthly cost estimate, as scanned
+	Savings       float64                // Monthly savings from acting on the findings, see derive
 	SourceLocation string                // e.g. "storage.tf:24"
+	Findings      []Finding              // Why it was flagged, one per heuristic
+	Suppressed    []Finding              // Findings dropped by a suppression rule, see Finding.Rule
 }
 
 // Graph represents the infrastructure topology as a Weighted DAG.
+//
+// Nodes and their edges are spread over independently locked shards, so
+// concurrent scanners only contend when they write to the same shard. Point
+// reads and writes lock one shard (two for an edge); Read and Write lock them
+// all for a consistent view of the whole graph.
 type Graph struct {
-	Mu           sync.RWMutex
-	Nodes        map[string]*Node
-	Edges        map[string][]Edge // ID -> []Edge (Forward Dependencies)
-	ReverseEdges map[string][]Edge // ID -> []Edge (Reverse Dependencies)
+	Metadata ScanMetadata // Scan provenance, persisted with snapshots
+
+	Suppressor Suppressor // Optional suppression policy, consulted before cloudslash:ignore tags
+
+	seed   maphash.Seed
+	meta   sync.RWMutex // Guards Metadata
+	shards [shardCount]shard
 }
 
 // NewGraph creates a new empty graph.
 func NewGraph() *Graph {
-	return &Graph{
-		Nodes:        make(map[string]*Node),
-		Edges:        make(map[string][]Edge),
-		ReverseEdges: make(map[string][]Edge),
+	g := &Graph{seed: maphash.MakeSeed()}
+	for i := range g.shards {
+		g.shards[i].init()
 	}
+	return g
 }
 
 // AddNode adds a resource to the graph. Structure is idempotent.
@@ -61,25 +73,70 @@ func (g *Graph) AddNode(id, resourceType string, props map[string]interface{}) {
 	if id == "" {
 		return
 	}
-	g.Mu.Lock()
-	defer g.Mu.Unlock()
+	s := g.shardOf(id)
+	s.mu.Lock()
+	defer s.mu.Unlock()
 
-	if node, exists := g.Nodes[id]; exists {
+	s.addNode(id, resourceType, props)
+}
+
+func (s *shard) addNode(id, resourceType string, props map[string]interface{}) {
+	if node, exists := s.nodes[id]; exists {
 		// Merge properties if node exists (Last Write Wins for conflicts)
 		for k, v := range props {
 			node.Properties[k] = v
 		}
 		// Update type if it was unknown
 		if node.Type == "Unknown" && resourceType != "Unknown" {
+			s.unindexNode(node)
 			node.Type = resourceType
+			s.indexNode(node)
 		}
 	} else {
-		g.Nodes[id] = &Node{
+		if props == nil {
+			props = make(map[string]interface{})
+		}
+		s.insert(&Node{
 			ID:         id,
 			Type:       resourceType,
 			Properties: props,
-		}
+		})
+	}
+}
+
+// insert stores a new node and indexes it.
+func (s *shard) insert(node *Node) {
+	s.nodes[node.ID] = node
+	s.indexNode(node)
+}
+
+// ensure creates a placeholder node for id if it does not exist yet.
+func (s *shard) ensure(id string) {
+	if _, ok := s.nodes[id]; !ok {
+		s.addNode(id, "Unknown", nil)
+	}
+}
+
+// Node returns the node with the given ID.
+func (g *Graph) Node(id string) (*Node, bool) {
+	s := g.shardOf(id)
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	node, ok := s.nodes[id]
+	return node, ok
+}
+
+// Len returns the number of nodes.
+func (g *Graph) Len() int {
+	n := 0
+	for i := range g.shards {
+		s := &g.shards[i]
+		s.mu.RLock()
+		n += len(s.nodes)
+		s.mu.RUnlock()
 	}
+	return n
 }
 
 // AddEdge adds a directed edge from source to target with default type.
@@ -94,48 +151,41 @@ func (g *Graph) AddTypedEdge(sourceID, targetID string, edgeType EdgeType, weigh
 		return
 	}
 
-	g.Mu.Lock()
-	defer g.Mu.Unlock()
+	src, dst, unlock := g.lockPair(sourceID, targetID)
+	defer unlock()
 
 	// Ensure nodes exist (create placeholders if not)
-	if _, ok := g.Nodes[sourceID]; !ok {
-		g.Nodes[sourceID] = &Node{ID: sourceID, Type: "Unknown", Properties: make(map[string]interface{})}
-	}
-	if _, ok := g.Nodes[targetID]; !ok {
-		g.Nodes[targetID] = &Node{ID: targetID, Type: "Unknown", Properties: make(map[string]interface{})}
+	src.ensure(sourceID)
+	dst.ensure(targetID)
+	if src.addForward(sourceID, targetID, edgeType, weight) {
+		dst.addReverse(sourceID, targetID, edgeType, weight)
 	}
+}
 
-	// Add Forward Edge
-	// Check for duplicates to prevent graph explosion
-	exists := false
-	for _, e := range g.Edges[sourceID] {
-		if e.TargetID == targetID && e.Type == edgeType {
-			exists = true
-			break
-		}
-	}
-	if !exists {
-		g.Edges[sourceID] = append(g.Edges[sourceID], Edge{TargetID: targetID, Type: edgeType, Weight: weight})
+// addForward records the forward half of an edge in the source's shard.
+// Returns false if the edge already exists.
+func (s *shard) addForward(sourceID, targetID string, edgeType EdgeType, weight int) bool {
+	// Check for duplicates to prevent graph explosion.
+	// Forward and reverse edges are always added together, so one set covers both.
+	key := edgeKey{source: sourceID, target: targetID, edgeType: edgeType}
+	if _, exists := s.edgeSet[key]; exists {
+		return false
 	}
+	s.edgeSet[key] = struct{}{}
+	s.edges[sourceID] = append(s.edges[sourceID], Edge{TargetID: targetID, Type: edgeType, Weight: weight})
+	return true
+}
 
-	// Add Reverse Edge
-	revExists := false
-	for _, e := range g.ReverseEdges[targetID] {
-		if e.TargetID == sourceID && e.Type == edgeType {
-			revExists = true
-			break
-		}
-	}
-	if !revExists {
-		g.ReverseEdges[targetID] = append(g.ReverseEdges[targetID], Edge{TargetID: sourceID, Type: edgeType, Weight: weight})
-	}
+// addReverse records the reverse half of an edge in the target's shard.
+func (s *shard) addReverse(sourceID, targetID string, edgeType EdgeType, weight int) {
+	s.reverse[targetID] = append(s.reverse[targetID], Edge{TargetID: sourceID, Type: edgeType, Weight: weight})
 }
 
 // GetConnectedComponent returns all nodes reachable from startID (BFS).
 // Useful for finding all resources in a VPC or related to a specific security group.
 func (g *Graph) GetConnectedComponent(startID string) []*Node {
-	g.Mu.RLock()
-	defer g.Mu.RUnlock()
+	g.rlockAll()
+	defer g.runlockAll()
 
 	visited := make(map[string]bool)
 	queue := []string{startID}
@@ -150,19 +200,19 @@ func (g *Graph) GetConnectedComponent(startID string) []*Node {
 		}
 		visited[currentID] = true
 
-		if node, ok := g.Nodes[currentID]; ok {
+		if node, ok := g.node(currentID); ok {
 			component = append(component, node)
 		}
 
 		// Traverse forward edges
-		for _, edge := range g.Edges[currentID] {
+		for _, edge := range g.edgesOf(currentID) {
 			if !visited[edge.TargetID] {
 				queue = append(queue, edge.TargetID)
 			}
 		}
 
 		// Traverse backward edges (undirected connectivity check)
-		for _, edge := range g.ReverseEdges[currentID] {
+		for _, edge := range g.reverseOf(currentID) {
 			if !visited[edge.TargetID] {
 				queue = append(queue, edge.TargetID)
 			}
@@ -172,113 +222,140 @@ func (g *Graph) GetConnectedComponent(startID string) []*Node {
 	return component
 }
 
-// MarkWaste flags a node and optionally its dependencies as waste.
+// MarkWaste flags a node as waste with the given risk score, keeping its
+// current Cost as the savings estimate. Prefer AddFinding, which records why.
 func (g *Graph) MarkWaste(id string, score int) {
-	g.Mu.Lock()
-	defer g.Mu.Unlock()
-
-	if node, ok := g.Nodes[id]; ok {
-		// Safe List Logic (cloudslash:ignore)
-		if tags, ok := node.Properties["Tags"].(map[string]string); ok {
-			if val, ok := tags["cloudslash:ignore"]; ok {
-				val = strings.ToLower(strings.TrimSpace(val))
-				
-				// 1. Ignore Forever
-				if val == "true" {
-					return
-				}
-				
-				// 2. Cost-Based Ignore (cost<10.50)
-				if strings.HasPrefix(val, "cost<") {
-					limitStr := strings.TrimPrefix(val, "cost<")
-					if limit, err := strconv.ParseFloat(limitStr, 64); err == nil {
-						if node.Cost < limit {
-							return
-						}
+	s := g.shardOf(id)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if node, ok := s.nodes[id]; ok {
+		g.flag(node, Finding{Category: CategoryWaste, Confidence: 1, RiskScore: score, MonthlySavings: node.Cost})
+	}
+}
+
+// AddFinding records f on a node unless the suppression policy or the node's
+// cloudslash:ignore tag suppresses it. This is the only way findings reach a
+// node; suppressed findings are kept in Node.Suppressed with the rule that
+// matched. Returns false if the node is unknown or the finding was suppressed.
+func (g *Graph) AddFinding(id string, f Finding) bool {
+	s := g.shardOf(id)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	node, ok := s.nodes[id]
+	if !ok {
+		return false
+	}
+	return g.flag(node, f)
+}
+
+// createdKeys are the properties scanners record a creation or launch time under.
+var createdKeys = []string{"LaunchTime", "CreateTime", "StartTime", "Created", "CreatedAt", "Initiated"}
+
+// CreatedAt returns when the resource was created or launched, if the scanner recorded it.
+func (node *Node) CreatedAt() (time.Time, bool) {
+	for _, key := range createdKeys {
+		switch t := node.Properties[key].(type) {
+		case time.Time:
+			return t, true
+		case *time.Time:
+			if t != nil {
+				return *t, true
+			}
+		}
+	}
+	return time.Time{}, false
+}
+
+// ignoreTag evaluates the cloudslash:ignore tag against a finding worth cost per month.
+// It reports whether the finding is suppressed, or the justification to record for accepted waste.
+func (node *Node) ignoreTag(cost float64) (bool, string) {
+	// Safe List Logic (cloudslash:ignore)
+	if tags, ok := node.Properties["Tags"].(map[string]string); ok {
+		if val, ok := tags["cloudslash:ignore"]; ok {
+			val = strings.ToLower(strings.TrimSpace(val))
+			
+			// 1. Ignore Forever
+			if val == "true" {
+				return true, ""
+			}
+			
+			// 2. Cost-Based Ignore (cost<10.50)
+			if strings.HasPrefix(val, "cost<") {
+				limitStr := strings.TrimPrefix(val, "cost<")
+				if limit, err := strconv.ParseFloat(limitStr, 64); err == nil {
+					if cost < limit {
+						return true, ""
 					}
 				}
+			}
 
-				// 3. Justified Waste (justified:compliance)
-				if strings.HasPrefix(val, "justified:") {
-					node.IsWaste = true
-					node.Justified = true
-					node.Justification = strings.TrimPrefix(val, "justified:")
-					node.RiskScore = score
-					return
-				}
+			// 3. Justified Waste (justified:compliance)
+			if strings.HasPrefix(val, "justified:") {
+				return false, strings.TrimPrefix(val, "justified:")
+			}
 
-				// 4. Ignore Until Date (YYYY-MM-DD)
-				if ignoreUntil, err := time.Parse("2006-01-02", val); err == nil {
-					if time.Now().Before(ignoreUntil) {
-						return
-					}
+			// 4. Ignore Until Date (YYYY-MM-DD)
+			if ignoreUntil, err := time.Parse("2006-01-02", val); err == nil {
+				if time.Now().Before(ignoreUntil) {
+					return true, ""
 				}
+			}
 
-				// 5. Grace Period / TTL (e.g., "ignore:3d" => Ignore if younger than 3 days)
-				// Useful for ephemeral dev resources that shouldn't be flagged immediately.
-				// Requires "LaunchTime" property to be set by scanner.
-				if strings.HasSuffix(val, "d") || strings.HasSuffix(val, "h") {
-					// Parse "30d" -> 720h manually
-					var hours int
-					var conversionErr error
-					
-					if strings.HasSuffix(val, "d") {
-						daysStr := strings.TrimSuffix(val, "d")
-						days, err := strconv.Atoi(daysStr)
-						if err == nil {
-							hours = days * 24
-						} else {
-							conversionErr = err
-						}
-					} else { // "h"
-						hoursStr := strings.TrimSuffix(val, "h")
-						h, err := strconv.Atoi(hoursStr)
-						if err == nil {
-							hours = h
-						} else {
-							conversionErr = err
-						}
+			// 5. Grace Period / TTL (e.g., "ignore:3d" => Ignore if younger than 3 days)
+			// Useful for ephemeral dev resources that shouldn't be flagged immediately.
+			// Requires "LaunchTime" property to be set by scanner.
+			if strings.HasSuffix(val, "d") || strings.HasSuffix(val, "h") {
+				// Parse "30d" -> 720h manually
+				var hours int
+				var conversionErr error
+				
+				if strings.HasSuffix(val, "d") {
+					daysStr := strings.TrimSuffix(val, "d")
+					days, err := strconv.Atoi(daysStr)
+					if err == nil {
+						hours = days * 24
+					} else {
+						conversionErr = err
 					}
+				} else { // "h"
+					hoursStr := strings.TrimSuffix(val, "h")
+					h, err := strconv.Atoi(hoursStr)
+					if err == nil {
+						hours = h
+					} else {
+						conversionErr = err
+					}
+				}
 
-					if conversionErr == nil {
-						// Look for resource creation time
-						// Try common keys: LaunchTime (EC2), CreateTime (EBS/S3), StartTime (RDS)
-						var launchTime time.Time
-						foundTime := false
-						
-						timeKeys := []string{"LaunchTime", "CreateTime", "StartTime", "Created"}
-						for _, key := range timeKeys {
-							if tVal, ok := node.Properties[key].(time.Time); ok {
-								launchTime = tVal
-								foundTime = true
-								break
-							}
-							// Handle string timestamps if needed? (Scanners usually use native types)
-						}
+				if conversionErr == nil {
+					// Look for resource creation time
+					// e.g. LaunchTime (EC2), CreateTime (EBS/S3), StartTime (RDS)
+					launchTime, foundTime := node.CreatedAt()
 
-						if foundTime {
-							age := time.Since(launchTime)
-							if age.Hours() < float64(hours) {
-								return // IGNORED: Within grace period
-							}
+					if foundTime {
+						age := time.Since(launchTime)
+						if age.Hours() < float64(hours) {
+							return true, "" // IGNORED: Within grace period
 						}
 					}
 				}
 			}
 		}
-
-		node.IsWaste = true
-		node.RiskScore = score
 	}
+
+	return false, ""
 }
 
 // GetDownstream returns simple string slice of downstream IDs for compatibility.
 func (g *Graph) GetDownstream(id string) []string {
-	g.Mu.RLock()
-	defer g.Mu.RUnlock()
+	s := g.shardOf(id)
+	s.mu.RLock()
+	defer s.mu.RUnlock()
 
 	var downstream []string
-	if edges, ok := g.Edges[id]; ok {
+	if edges, ok := s.edges[id]; ok {
 		for _, e := range edges {
 			downstream = append(downstream, e.TargetID)
 		}
@@ -288,11 +365,12 @@ func (g *Graph) GetDownstream(id string) []string {
 
 // GetUpstream returns simple string slice of upstream IDs for compatibility.
 func (g *Graph) GetUpstream(id string) []string {
-	g.Mu.RLock()
-	defer g.Mu.RUnlock()
+	s := g.shardOf(id)
+	s.mu.RLock()
+	defer s.mu.RUnlock()
 
 	var upstream []string
-	if edges, ok := g.ReverseEdges[id]; ok {
+	if edges, ok := s.reverse[id]; ok {
 		for _, e := range edges {
 			upstream = append(upstream, e.TargetID)
 		}
@@ -302,7 +380,12 @@ func (g *Graph) GetUpstream(id string) []string {
 
 // DumpStats returns graph statistics for the TUI.
 func (g *Graph) DumpStats() string {
-	g.Mu.RLock()
-	defer g.Mu.RUnlock()
-	return fmt.Sprintf("Nodes: %d | Edges: %d", len(g.Nodes), len(g.Edges))
+	g.rlockAll()
+	defer g.runlockAll()
+
+	edges := 0
+	for i := range g.shards {
+		edges += len(g.shards[i].edges)
+	}
+	return fmt.Sprintf("Nodes: %d | Edges: %d", g.count(), edges)
 }
@@ -25,8 +25,8 @@ func TestMarkWaste_AdvancedSuppression(t *testing.T) {
 	})
 
 	// Set Costs manually as they aren't computed here
-	g.Nodes[nodeCostLow].Cost = 5.0
-	g.Nodes[nodeCostHigh].Cost = 15.0
+	nodeByID(g, nodeCostLow).Cost = 5.0
+	nodeByID(g, nodeCostHigh).Cost = 15.0
 
 	// Run MarkWaste
 	g.MarkWaste(nodeCostLow, 100)
@@ -37,28 +37,34 @@ func TestMarkWaste_AdvancedSuppression(t *testing.T) {
 	// Assertions
 
 	// 1. Cost < 10 (Cost=5) -> Should be IGNORED (IsWaste=false)
-	if g.Nodes[nodeCostLow].IsWaste {
+	if nodeByID(g, nodeCostLow).IsWaste {
 		t.Errorf("Low cost node should satisfy cost<10 and be ignored")
 	}
 
 	// 2. Cost < 10 (Cost=15) -> Should be MARKED (IsWaste=true)
-	if !g.Nodes[nodeCostHigh].IsWaste {
+	if !nodeByID(g, nodeCostHigh).IsWaste {
 		t.Errorf("High cost node should fail cost<10 and be marked")
 	}
 
 	// 3. Justified -> Should be MARKED + JUSTIFIED
-	if !g.Nodes[nodeJustified].IsWaste {
+	if !nodeByID(g, nodeJustified).IsWaste {
 		t.Errorf("Justified node should be marked as waste (for tracking)")
 	}
-	if !g.Nodes[nodeJustified].Justified {
+	if !nodeByID(g, nodeJustified).Justified {
 		t.Errorf("Justified node should be flagged Justified=true")
 	}
-	if g.Nodes[nodeJustified].Justification != "disasterrecovery" {
-		t.Errorf("Justification reason mismatch. Got %s", g.Nodes[nodeJustified].Justification)
+	if nodeByID(g, nodeJustified).Justification != "disasterrecovery" {
+		t.Errorf("Justification reason mismatch. Got %s", nodeByID(g, nodeJustified).Justification)
 	}
 
 	// 4. Date -> Should be IGNORED (Future date)
-	if g.Nodes[nodeDate].IsWaste {
+	if nodeByID(g, nodeDate).IsWaste {
 		t.Errorf("Future date snoozed node should be ignored")
 	}
 }
+
+// nodeByID returns the node with id, or nil when the graph has none.
+func nodeByID(g *Graph, id string) *Node {
+	node, _ := g.Node(id)
+	return node
+}
@@ -0,0 +1,129 @@
+package graph
+
+import (
+	"math"
+	"sort"
+)
+
+// Resolution explains why a waste finding disappeared between two scans.
+type Resolution string
+
+const (
+	ResolutionDeleted   Resolution = "deleted"   // Resource no longer exists
+	ResolutionCleared   Resolution = "cleared"   // Resource exists but is no longer flagged
+	ResolutionJustified Resolution = "justified" // Resource was accepted as justified waste
+)
+
+// WasteChange describes one resource across two scans.
+type WasteChange struct {
+	ID         string     `json:"id"`
+	Type       string     `json:"type"`
+	Reason     string     `json:"reason,omitempty"`
+	OldCost    float64    `json:"old_monthly_cost"` // Node.Savings: what the waste costs each month
+	NewCost    float64    `json:"new_monthly_cost"`
+	OldRisk    int        `json:"old_risk_score"`
+	NewRisk    int        `json:"new_risk_score"`
+	Resolution Resolution `json:"resolution,omitempty"`
+}
+
+// CostDelta is the monthly cost change for this resource.
+func (c WasteChange) CostDelta() float64 { return c.NewCost - c.OldCost }
+
+// DiffReport compares the actionable (non-justified) waste of two scans.
+type DiffReport struct {
+	Old             ScanMetadata  `json:"old_scan"`
+	New             ScanMetadata  `json:"new_scan"`
+	NewWaste        []WasteChange `json:"new_waste"`
+	ResolvedWaste   []WasteChange `json:"resolved_waste"`
+	Changed         []WasteChange `json:"changed"`
+	OldMonthlyCost  float64       `json:"old_monthly_cost"`
+	NewMonthlyCost  float64       `json:"new_monthly_cost"`
+	NetMonthlyDelta float64       `json:"net_monthly_delta"`
+}
+
+// Diff compares two graphs by node ID.
+// Waste that is only in newer is new, waste only in older is resolved, and
+// waste in both whose cost or RiskScore moved is changed.
+func Diff(older, newer *Graph) *DiffReport {
+	older.rlockAll()
+	defer older.runlockAll()
+	newer.rlockAll()
+	defer newer.runlockAll()
+
+	report := &DiffReport{
+		Old: older.Metadata,
+		New: newer.Metadata,
+	}
+
+	older.each(func(oldNode *Node) {
+		if !isActionableWaste(oldNode) {
+			return
+		}
+		id := oldNode.ID
+		report.OldMonthlyCost += oldNode.Savings
+
+		change := WasteChange{
+			ID:      id,
+			Type:    oldNode.Type,
+			Reason:  oldNode.Reason(),
+			OldCost: oldNode.Savings,
+			OldRisk: oldNode.RiskScore,
+		}
+
+		newNode, exists := newer.node(id)
+		switch {
+		case !exists:
+			change.Resolution = ResolutionDeleted
+		case newNode.HasWasteFinding() && newNode.Justified:
+			change.Resolution = ResolutionJustified
+		case !newNode.HasWasteFinding():
+			change.Resolution = ResolutionCleared
+		default:
+			change.NewCost = newNode.Savings
+			change.NewRisk = newNode.RiskScore
+			change.Reason = newNode.Reason()
+			if math.Abs(change.CostDelta()) >= 0.01 || change.OldRisk != change.NewRisk {
+				report.Changed = append(report.Changed, change)
+			}
+			return
+		}
+		report.ResolvedWaste = append(report.ResolvedWaste, change)
+	})
+
+	newer.each(func(newNode *Node) {
+		if !isActionableWaste(newNode) {
+			return
+		}
+		report.NewMonthlyCost += newNode.Savings
+
+		if oldNode, ok := older.node(newNode.ID); ok && isActionableWaste(oldNode) {
+			return
+		}
+		report.NewWaste = append(report.NewWaste, WasteChange{
+			ID:      newNode.ID,
+			Type:    newNode.Type,
+			Reason:  newNode.Reason(),
+			NewCost: newNode.Savings,
+			NewRisk: newNode.RiskScore,
+		})
+	})
+
+	report.NetMonthlyDelta = report.NewMonthlyCost - report.OldMonthlyCost
+
+	// Biggest money movers first, ID as tie-breaker for stable output.
+	for _, list := range [][]WasteChange{report.NewWaste, report.ResolvedWaste, report.Changed} {
+		sort.Slice(list, func(i, j int) bool {
+			di, dj := math.Abs(list[i].CostDelta()), math.Abs(list[j].CostDelta())
+			if di != dj {
+				return di > dj
+			}
+			return list[i].ID < list[j].ID
+		})
+	}
+
+	return report
+}
+
+func isActionableWaste(n *Node) bool {
+	return n.HasWasteFinding() && !n.Justified
+}
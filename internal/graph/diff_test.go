@@ -0,0 +1,51 @@
+package graph
+
+import "testing"
+
+func TestDiff(t *testing.T) {
+	older := NewGraph()
+	newer := NewGraph()
+
+	waste := func(g *Graph, id string, cost float64, risk int) {
+		g.AddNode(id, "Test", map[string]interface{}{})
+		nodeByID(g, id).Cost = cost
+		g.AddFinding(id, Finding{Heuristic: "Test", Category: CategoryWaste, RiskScore: risk})
+	}
+
+	waste(older, "arn:deleted", 30, 90) // gone in new scan
+	waste(older, "arn:cleared", 10, 50) // still exists, no longer waste
+	waste(older, "arn:changed", 20, 60) // cost went up
+	waste(older, "arn:same", 5, 40)     // unchanged
+	waste(newer, "arn:changed", 25, 60)
+	waste(newer, "arn:same", 5, 40)
+	waste(newer, "arn:fresh", 7, 70)
+	newer.AddNode("arn:cleared", "Test", map[string]interface{}{})
+	newer.AddNode("arn:rightsize", "Test", map[string]interface{}{}) // kept in place, not waste
+	newer.AddFinding("arn:rightsize", Finding{Heuristic: "Resize", Category: CategoryRightsizing, RiskScore: 30, MonthlySavings: 12})
+
+	d := Diff(older, newer)
+
+	if len(d.NewWaste) != 1 || d.NewWaste[0].ID != "arn:fresh" {
+		t.Errorf("expected arn:fresh as new waste, got %+v", d.NewWaste)
+	}
+
+	if len(d.ResolvedWaste) != 2 {
+		t.Fatalf("expected 2 resolved, got %+v", d.ResolvedWaste)
+	}
+	// Sorted by cost moved: the $30 deletion first.
+	if d.ResolvedWaste[0].ID != "arn:deleted" || d.ResolvedWaste[0].Resolution != ResolutionDeleted {
+		t.Errorf("unexpected first resolved item %+v", d.ResolvedWaste[0])
+	}
+	if d.ResolvedWaste[1].Resolution != ResolutionCleared {
+		t.Errorf("expected cleared resolution, got %s", d.ResolvedWaste[1].Resolution)
+	}
+
+	if len(d.Changed) != 1 || d.Changed[0].ID != "arn:changed" || d.Changed[0].CostDelta() != 5 {
+		t.Errorf("expected arn:changed with +5 delta, got %+v", d.Changed)
+	}
+
+	// 65 -> 37
+	if d.NetMonthlyDelta != -28 {
+		t.Errorf("expected net delta -28, got %.2f", d.NetMonthlyDelta)
+	}
+}
@@ -0,0 +1,194 @@
+package graph
+
+import "sort"
+
+// ImmediateDominators returns the immediate dominator of every node.
+//
+// The graph is treated as a flow graph rooted at a virtual node that points at
+// every resource with no incoming edge (and at any cycle not reachable from
+// one). Node D dominates N when every path from the root to N passes through
+// D, i.e. removing D cuts N off from the rest of the estate. Nodes whose only
+// dominator is the virtual root map to "".
+//
+// Uses Lengauer–Tarjan with path compression, O(E log V).
+func (g *Graph) ImmediateDominators() map[string]string {
+	g.rlockAll()
+	defer g.runlockAll()
+	return g.immediateDominators()
+}
+
+func (g *Graph) immediateDominators() map[string]string {
+	dt := newDomTree(g)
+	dt.compute()
+
+	idom := make(map[string]string, len(dt.ids))
+	for pre := 1; pre < len(dt.vertex); pre++ {
+		id := dt.ids[dt.vertex[pre]]
+		if d := dt.idom[pre]; d == 0 {
+			idom[id] = ""
+		} else {
+			idom[id] = dt.ids[dt.vertex[d]]
+		}
+	}
+	return idom
+}
+
+// domTree holds Lengauer–Tarjan state. Node index 0 is the virtual root;
+// the per-vertex arrays are indexed by DFS preorder number.
+type domTree struct {
+	ids   []string // node index -> ID
+	succ  [][]int  // node index -> successor node indices
+	order []int    // node index -> preorder number (-1 = unvisited)
+
+	vertex   []int   // preorder -> node index
+	parent   []int   // preorder -> DFS parent preorder
+	pred     [][]int // preorder -> predecessor preorders
+	semi     []int
+	idom     []int
+	ancestor []int
+	label    []int
+	bucket   [][]int
+}
+
+func newDomTree(g *Graph) *domTree {
+	ids := make([]string, 0, g.count()+1)
+	ids = append(ids, "") // virtual root
+	g.each(func(n *Node) {
+		ids = append(ids, n.ID)
+	})
+	sort.Strings(ids[1:]) // deterministic numbering
+
+	index := make(map[string]int, len(ids))
+	for i, id := range ids {
+		index[id] = i
+	}
+
+	succ := make([][]int, len(ids))
+	for i := 1; i < len(ids); i++ {
+		for _, e := range g.edgesOf(ids[i]) {
+			if t, ok := index[e.TargetID]; ok {
+				succ[i] = append(succ[i], t)
+			}
+		}
+		sort.Ints(succ[i])
+		if len(g.reverseOf(ids[i])) == 0 {
+			succ[0] = append(succ[0], i)
+		}
+	}
+
+	order := make([]int, len(ids))
+	for i := range order {
+		order[i] = -1
+	}
+	return &domTree{ids: ids, succ: succ, order: order}
+}
+
+func (dt *domTree) compute() {
+	dt.dfs(0)
+	// Cycles with no entry from a source are still part of the estate; hang them off the root.
+	for i := 1; i < len(dt.ids); i++ {
+		if dt.order[i] == -1 {
+			dt.succ[0] = append(dt.succ[0], i)
+			dt.dfs(i)
+			dt.parent[dt.order[i]] = 0
+		}
+	}
+
+	n := len(dt.vertex)
+	dt.pred = make([][]int, n)
+	for v := 0; v < len(dt.ids); v++ {
+		for _, w := range dt.succ[v] {
+			dt.pred[dt.order[w]] = append(dt.pred[dt.order[w]], dt.order[v])
+		}
+	}
+
+	dt.semi = make([]int, n)
+	dt.idom = make([]int, n)
+	dt.ancestor = make([]int, n)
+	dt.label = make([]int, n)
+	dt.bucket = make([][]int, n)
+	for i := 0; i < n; i++ {
+		dt.semi[i] = i
+		dt.label[i] = i
+		dt.ancestor[i] = -1
+	}
+
+	for w := n - 1; w > 0; w-- {
+		for _, v := range dt.pred[w] {
+			if u := dt.eval(v); dt.semi[u] < dt.semi[w] {
+				dt.semi[w] = dt.semi[u]
+			}
+		}
+		dt.bucket[dt.semi[w]] = append(dt.bucket[dt.semi[w]], w)
+
+		p := dt.parent[w]
+		dt.ancestor[w] = p // link
+
+		for _, v := range dt.bucket[p] {
+			if u := dt.eval(v); dt.semi[u] < dt.semi[v] {
+				dt.idom[v] = u
+			} else {
+				dt.idom[v] = p
+			}
+		}
+		dt.bucket[p] = nil
+	}
+
+	for w := 1; w < n; w++ {
+		if dt.idom[w] != dt.semi[w] {
+			dt.idom[w] = dt.idom[dt.idom[w]]
+		}
+	}
+}
+
+// dfs numbers nodes in preorder. Iterative so million-node chains don't blow the stack.
+func (dt *domTree) dfs(start int) {
+	type frame struct{ node, next int }
+
+	visit := func(v, parentPre int) {
+		dt.order[v] = len(dt.vertex)
+		dt.vertex = append(dt.vertex, v)
+		dt.parent = append(dt.parent, parentPre)
+	}
+
+	visit(start, 0)
+	stack := []frame{{node: start}}
+	for len(stack) > 0 {
+		top := &stack[len(stack)-1]
+		if top.next == len(dt.succ[top.node]) {
+			stack = stack[:len(stack)-1]
+			continue
+		}
+		w := dt.succ[top.node][top.next]
+		top.next++
+		if dt.order[w] == -1 {
+			visit(w, dt.order[top.node])
+			stack = append(stack, frame{node: w})
+		}
+	}
+}
+
+// eval returns the vertex with minimum semi on the compressed ancestor path of v.
+func (dt *domTree) eval(v int) int {
+	if dt.ancestor[v] == -1 {
+		return v
+	}
+	dt.compress(v)
+	return dt.label[v]
+}
+
+// compress performs path compression iteratively.
+func (dt *domTree) compress(v int) {
+	var path []int
+	for u := v; dt.ancestor[dt.ancestor[u]] != -1; u = dt.ancestor[u] {
+		path = append(path, u)
+	}
+	for i := len(path) - 1; i >= 0; i-- {
+		u := path[i]
+		a := dt.ancestor[u]
+		if dt.semi[dt.label[a]] < dt.semi[dt.label[u]] {
+			dt.label[u] = dt.label[a]
+		}
+		dt.ancestor[u] = dt.ancestor[a]
+	}
+}
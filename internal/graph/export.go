@@ -0,0 +1,363 @@
+package graph
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+// ExportFormats lists the formats accepted by Export.
+var ExportFormats = []string{"dot", "graphml", "cypher"}
+
+// Export writes the graph as "dot", "graphml" or "cypher".
+// If root is set, only the connected component containing root is written.
+func (g *Graph) Export(w io.Writer, format, root string) error {
+	switch strings.ToLower(format) {
+	case "dot":
+		return g.ExportDOT(w, root)
+	case "graphml":
+		return g.ExportGraphML(w, root)
+	case "cypher":
+		return g.ExportCypher(w, root)
+	default:
+		return fmt.Errorf("unknown graph format %q (use %s)", format, strings.Join(ExportFormats, ", "))
+	}
+}
+
+type exportEdge struct {
+	Source string
+	Edge
+}
+
+// exportView returns the nodes and edges to export, sorted for stable output.
+func (g *Graph) exportView(root string) ([]*Node, []exportEdge, error) {
+	var nodes []*Node
+	if root != "" {
+		nodes = g.GetConnectedComponent(root)
+		if len(nodes) == 0 {
+			return nil, nil, fmt.Errorf("resource %s not found in graph", root)
+		}
+	}
+
+	g.rlockAll()
+	defer g.runlockAll()
+
+	if root == "" {
+		nodes = make([]*Node, 0, g.count())
+		g.each(func(n *Node) {
+			nodes = append(nodes, n)
+		})
+	}
+	sort.Slice(nodes, func(i, j int) bool { return nodes[i].ID < nodes[j].ID })
+
+	included := make(map[string]bool, len(nodes))
+	for _, n := range nodes {
+		included[n.ID] = true
+	}
+
+	var edges []exportEdge
+	for _, n := range nodes {
+		for _, e := range g.edgesOf(n.ID) {
+			if included[e.TargetID] {
+				edges = append(edges, exportEdge{Source: n.ID, Edge: e})
+			}
+		}
+	}
+	sort.Slice(edges, func(i, j int) bool {
+		a, b := edges[i], edges[j]
+		if a.Source != b.Source {
+			return a.Source < b.Source
+		}
+		if a.TargetID != b.TargetID {
+			return a.TargetID < b.TargetID
+		}
+		return a.Type < b.Type
+	})
+	return nodes, edges, nil
+}
+
+// ExportDOT writes a Graphviz digraph. Waste is filled by RiskScore
+// (pale to red), justified waste is grey, and edge width follows Weight.
+func (g *Graph) ExportDOT(w io.Writer, root string) error {
+	nodes, edges, err := g.exportView(root)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintln(w, "digraph cloudslash {")
+	fmt.Fprintln(w, "  rankdir=LR;")
+	fmt.Fprintln(w, `  node [shape=box, style="rounded,filled", fillcolor="#ffffff", fontname="Helvetica"];`)
+	fmt.Fprintln(w, `  edge [fontname="Helvetica", fontsize=10];`)
+
+	for _, n := range nodes {
+		label := n.Type + "\n" + shortID(n.ID)
+		tooltip := n.ID
+		if n.IsWaste {
+			label += fmt.Sprintf("\n$%.2f/mo · risk %d", n.Savings, n.RiskScore)
+			if reason := n.Reason(); reason != "" {
+				tooltip += "\n" + reason
+			}
+		}
+		fmt.Fprintf(w, "  %s [label=%s, fillcolor=%q, tooltip=%s];\n", dotQuote(n.ID), dotQuote(label), riskColor(n), dotQuote(tooltip))
+	}
+
+	for _, e := range edges {
+		fmt.Fprintf(w, "  %s -> %s [label=%q, style=%s, penwidth=%.1f];\n",
+			dotQuote(e.Source), dotQuote(e.TargetID), e.Type, edgeStyle(e.Type), 1+float64(e.Weight)/50)
+	}
+
+	_, err = fmt.Fprintln(w, "}")
+	return err
+}
+
+func riskColor(n *Node) string {
+	switch {
+	case !n.IsWaste:
+		return "#ffffff"
+	case n.Justified:
+		return "#d9d9d9"
+	case n.RiskScore >= 80:
+		return "#d7301f"
+	case n.RiskScore >= 50:
+		return "#fc8d59"
+	case n.RiskScore >= 20:
+		return "#fdcc8a"
+	default:
+		return "#fef0d9"
+	}
+}
+
+func edgeStyle(t EdgeType) string {
+	switch t {
+	case EdgeTypeSecuredBy:
+		return "dashed"
+	case EdgeTypeFlowsTo:
+		return "bold"
+	case EdgeTypeUnknown:
+		return "dotted"
+	default:
+		return "solid"
+	}
+}
+
+func dotQuote(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	s = strings.ReplaceAll(s, `"`, `\"`)
+	return `"` + strings.ReplaceAll(s, "\n", `\n`) + `"`
+}
+
+// ExportGraphML writes GraphML with node findings, scalar properties and edge type/weight as attributes.
+func (g *Graph) ExportGraphML(w io.Writer, root string) error {
+	nodes, edges, err := g.exportView(root)
+	if err != nil {
+		return err
+	}
+
+	// GraphML needs every attribute declared up front.
+	propKeys := map[string]bool{}
+	for _, n := range nodes {
+		for k := range scalarProperties(n) {
+			propKeys[k] = true
+		}
+	}
+	sortedProps := sortedKeys(propKeys)
+
+	fmt.Fprintln(w, `<?xml version="1.0" encoding="UTF-8"?>`)
+	fmt.Fprintln(w, `<graphml xmlns="http://graphml.graphdrawing.org/xmlns">`)
+	for _, k := range [][3]string{
+		{"type", "node", "string"},
+		{"is_waste", "node", "boolean"},
+		{"justified", "node", "boolean"},
+		{"risk_score", "node", "int"},
+		{"cost", "node", "double"},
+		{"savings", "node", "double"},
+		{"reason", "node", "string"},
+		{"edge_type", "edge", "string"},
+		{"weight", "edge", "int"},
+	} {
+		fmt.Fprintf(w, "  <key id=%q for=%q attr.name=%q attr.type=%q/>\n", k[0], k[1], k[0], k[2])
+	}
+	for i, k := range sortedProps {
+		fmt.Fprintf(w, "  <key id=\"p%d\" for=\"node\" attr.name=%s attr.type=\"string\"/>\n", i, xmlAttr("prop_"+k))
+	}
+
+	fmt.Fprintln(w, `  <graph id="cloudslash" edgedefault="directed">`)
+	for _, n := range nodes {
+		fmt.Fprintf(w, "    <node id=%s>\n", xmlAttr(n.ID))
+		writeGraphMLData(w, "type", n.Type)
+		writeGraphMLData(w, "is_waste", fmt.Sprint(n.IsWaste))
+		writeGraphMLData(w, "justified", fmt.Sprint(n.Justified))
+		writeGraphMLData(w, "risk_score", fmt.Sprint(n.RiskScore))
+		writeGraphMLData(w, "cost", fmt.Sprintf("%.2f", n.Cost))
+		writeGraphMLData(w, "savings", fmt.Sprintf("%.2f", n.Savings))
+		if reason := n.Reason(); reason != "" {
+			writeGraphMLData(w, "reason", reason)
+		}
+		props := scalarProperties(n)
+		for i, k := range sortedProps {
+			if v, ok := props[k]; ok {
+				writeGraphMLData(w, fmt.Sprintf("p%d", i), fmt.Sprint(v))
+			}
+		}
+		fmt.Fprintln(w, "    </node>")
+	}
+	for i, e := range edges {
+		fmt.Fprintf(w, "    <edge id=\"e%d\" source=%s target=%s>\n", i, xmlAttr(e.Source), xmlAttr(e.TargetID))
+		writeGraphMLData(w, "edge_type", string(e.Type))
+		writeGraphMLData(w, "weight", fmt.Sprint(e.Weight))
+		fmt.Fprintln(w, "    </edge>")
+	}
+	fmt.Fprintln(w, "  </graph>")
+	_, err = fmt.Fprintln(w, "</graphml>")
+	return err
+}
+
+func writeGraphMLData(w io.Writer, key, value string) {
+	fmt.Fprintf(w, "      <data key=%q>", key)
+	xml.EscapeText(w, []byte(value))
+	fmt.Fprintln(w, "</data>")
+}
+
+func xmlAttr(s string) string {
+	var sb strings.Builder
+	xml.EscapeText(&sb, []byte(s))
+	return `"` + sb.String() + `"`
+}
+
+// ExportCypher writes Neo4j CREATE statements: one :Resource node per
+// resource (also labelled by type, e.g. :EC2_Volume) and one relationship
+// per edge (e.g. :ATTACHED_TO {weight}).
+func (g *Graph) ExportCypher(w io.Writer, root string) error {
+	nodes, edges, err := g.exportView(root)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintln(w, "CREATE INDEX resource_id IF NOT EXISTS FOR (r:Resource) ON (r.id);")
+
+	for _, n := range nodes {
+		fields := []string{
+			"id: " + cypherString(n.ID),
+			"type: " + cypherString(n.Type),
+			fmt.Sprintf("is_waste: %t", n.IsWaste),
+			fmt.Sprintf("justified: %t", n.Justified),
+			fmt.Sprintf("risk_score: %d", n.RiskScore),
+			fmt.Sprintf("cost: %.2f", n.Cost),
+			fmt.Sprintf("savings: %.2f", n.Savings),
+		}
+		if reason := n.Reason(); reason != "" {
+			fields = append(fields, "reason: "+cypherString(reason))
+		}
+		props := scalarProperties(n)
+		for _, k := range sortedKeys(props) {
+			fields = append(fields, fmt.Sprintf("`prop_%s`: %s", strings.ReplaceAll(k, "`", ""), cypherValue(props[k])))
+		}
+
+		label := ""
+		if l := cypherLabel(n.Type); l != "" {
+			label = ":" + l
+		}
+		fmt.Fprintf(w, "CREATE (:Resource%s {%s});\n", label, strings.Join(fields, ", "))
+	}
+
+	for _, e := range edges {
+		fmt.Fprintf(w, "MATCH (a:Resource {id: %s}), (b:Resource {id: %s}) CREATE (a)-[:%s {weight: %d}]->(b);\n",
+			cypherString(e.Source), cypherString(e.TargetID), cypherRelType(e.Type), e.Weight)
+	}
+	return nil
+}
+
+var nonIdentifier = regexp.MustCompile(`[^A-Za-z0-9_]+`)
+
+// cypherLabel turns "AWS::EC2::Volume" into "EC2_Volume".
+func cypherLabel(resourceType string) string {
+	t := strings.TrimPrefix(resourceType, "AWS::")
+	t = strings.Trim(nonIdentifier.ReplaceAllString(t, "_"), "_")
+	if t == "" || (t[0] >= '0' && t[0] <= '9') {
+		return ""
+	}
+	return t
+}
+
+// cypherRelType turns "AttachedTo" into "ATTACHED_TO".
+func cypherRelType(t EdgeType) string {
+	var sb strings.Builder
+	for i, r := range string(t) {
+		if i > 0 && r >= 'A' && r <= 'Z' {
+			sb.WriteByte('_')
+		}
+		sb.WriteRune(r)
+	}
+	rel := strings.ToUpper(nonIdentifier.ReplaceAllString(sb.String(), "_"))
+	if rel == "" {
+		return "RELATED_TO"
+	}
+	return rel
+}
+
+func cypherString(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	s = strings.ReplaceAll(s, `'`, `\'`)
+	return "'" + strings.ReplaceAll(s, "\n", `\n`) + "'"
+}
+
+func cypherValue(v interface{}) string {
+	switch x := v.(type) {
+	case bool:
+		return fmt.Sprint(x)
+	case int, int32, int64:
+		return fmt.Sprint(x)
+	case float64:
+		return fmt.Sprint(x)
+	default:
+		return cypherString(fmt.Sprint(x))
+	}
+}
+
+// scalarProperties returns properties that map cleanly onto a single
+// attribute value. Maps and slices are skipped.
+func scalarProperties(n *Node) map[string]interface{} {
+	out := make(map[string]interface{})
+	for k, v := range n.Properties {
+		switch x := v.(type) {
+		case string, bool, int, int32, int64, float64:
+			out[k] = x
+		case *string:
+			if x != nil {
+				out[k] = *x
+			}
+		case *bool:
+			if x != nil {
+				out[k] = *x
+			}
+		case *int32:
+			if x != nil {
+				out[k] = *x
+			}
+		case time.Time:
+			out[k] = x.Format(time.RFC3339)
+		}
+	}
+	return out
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// shortID returns the last path segment of an ARN (i-123, vol-abc).
+func shortID(id string) string {
+	if i := strings.LastIndexAny(id, "/:"); i >= 0 && i < len(id)-1 {
+		return id[i+1:]
+	}
+	return id
+}
@@ -0,0 +1,90 @@
+package graph
+
+import (
+	"bytes"
+	"encoding/xml"
+	"strings"
+	"testing"
+)
+
+func exportTestGraph() *Graph {
+	g := NewGraph()
+	g.AddNode("arn:aws:ec2:us-east-1:123456789012:volume/vol-1", "AWS::EC2::Volume", map[string]interface{}{"Size": int32(100)})
+	g.AddNode("arn:aws:ec2:us-east-1:123456789012:instance/i-1", "AWS::EC2::Instance", map[string]interface{}{"State": "stopped"})
+	g.AddTypedEdge("arn:aws:ec2:us-east-1:123456789012:volume/vol-1", "arn:aws:ec2:us-east-1:123456789012:instance/i-1", EdgeTypeAttachedTo, 100)
+	g.AddNode("arn:aws:s3:::other-bucket", "AWS::S3::Bucket", map[string]interface{}{})
+	g.AddFinding("arn:aws:ec2:us-east-1:123456789012:volume/vol-1", Finding{Heuristic: "ZombieEBSHeuristic", Category: CategoryWaste, RiskScore: 90, Reason: `Unattached "old" disk`})
+	return g
+}
+
+func TestExportDOT(t *testing.T) {
+	var buf bytes.Buffer
+	if err := exportTestGraph().Export(&buf, "dot", ""); err != nil {
+		t.Fatal(err)
+	}
+	out := buf.String()
+
+	for _, want := range []string{
+		`fillcolor="#d7301f"`, // risk 90 waste
+		`"arn:aws:ec2:us-east-1:123456789012:volume/vol-1" -> "arn:aws:ec2:us-east-1:123456789012:instance/i-1" [label="AttachedTo"`,
+		`Unattached \"old\" disk`,
+		`other-bucket`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("DOT output missing %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestExportGraphMLScoped(t *testing.T) {
+	var buf bytes.Buffer
+	if err := exportTestGraph().Export(&buf, "graphml", "arn:aws:ec2:us-east-1:123456789012:instance/i-1"); err != nil {
+		t.Fatal(err)
+	}
+
+	var doc struct {
+		Graph struct {
+			Nodes []struct {
+				ID string `xml:"id,attr"`
+			} `xml:"node"`
+			Edges []struct {
+				Source string `xml:"source,attr"`
+			} `xml:"edge"`
+		} `xml:"graph"`
+	}
+	if err := xml.Unmarshal(buf.Bytes(), &doc); err != nil {
+		t.Fatalf("invalid GraphML: %v", err)
+	}
+	if len(doc.Graph.Nodes) != 2 || len(doc.Graph.Edges) != 1 {
+		t.Errorf("expected the 2-node component, got %d nodes and %d edges", len(doc.Graph.Nodes), len(doc.Graph.Edges))
+	}
+}
+
+func TestExportCypher(t *testing.T) {
+	var buf bytes.Buffer
+	if err := exportTestGraph().Export(&buf, "cypher", ""); err != nil {
+		t.Fatal(err)
+	}
+	out := buf.String()
+
+	for _, want := range []string{
+		"CREATE (:Resource:EC2_Volume {id: 'arn:aws:ec2:us-east-1:123456789012:volume/vol-1'",
+		"is_waste: true",
+		"`prop_Size`: 100",
+		"CREATE (a)-[:ATTACHED_TO {weight: 100}]->(b);",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("Cypher output missing %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestExportErrors(t *testing.T) {
+	g := exportTestGraph()
+	if err := g.Export(&bytes.Buffer{}, "svg", ""); err == nil {
+		t.Error("expected error for unknown format")
+	}
+	if err := g.Export(&bytes.Buffer{}, "dot", "arn:missing"); err == nil {
+		t.Error("expected error for unknown root")
+	}
+}
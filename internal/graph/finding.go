@@ -0,0 +1,173 @@
+package graph
+
+import (
+	"sort"
+	"strings"
+)
+
+// Category groups findings by the kind of action they call for.
+type Category string
+
+const (
+	CategoryWaste       Category = "waste"       // Delete it
+	CategoryRightsizing Category = "rightsizing" // Keep it, but smaller/cheaper
+	CategorySecurity    Category = "security"    // Exposure or excess privilege
+	CategoryCompliance  Category = "compliance"  // Policy or drift violation
+)
+
+// Finding is one heuristic's verdict on a resource.
+type Finding struct {
+	Heuristic      string   `json:"heuristic"`
+	Category       Category `json:"category"`
+	Confidence     float64  `json:"confidence"` // 0-1
+	RiskScore      int      `json:"risk_score"` // 0-100
+	MonthlySavings float64  `json:"monthly_savings,omitempty"`
+	Reason         string   `json:"reason"`
+	Evidence       []string `json:"evidence,omitempty"`      // Observations behind the verdict, e.g. metric values
+	Action         string   `json:"action,omitempty"`        // Recommended remediation
+	Rule           string   `json:"rule,omitempty"`          // Suppression rule that justified or suppressed it
+	Justification  string   `json:"justification,omitempty"` // Why the rule accepted it; the finding stays but is not acted on
+
+	Thresholds map[string]string `json:"thresholds,omitempty"` // Heuristic settings the verdict was judged against
+}
+
+// addFinding records f on the node, replacing any earlier finding from the same
+// heuristic, and re-derives the node verdict. It does not consult suppression
+// rules; heuristics go through Graph.AddFinding or Batch.AddFinding.
+func (n *Node) addFinding(f Finding) {
+	n.Findings = putFinding(n.Findings, f)
+	n.Suppressed = dropFinding(n.Suppressed, f.Heuristic)
+
+	sort.SliceStable(n.Findings, func(i, j int) bool {
+		if n.Findings[i].RiskScore != n.Findings[j].RiskScore {
+			return n.Findings[i].RiskScore > n.Findings[j].RiskScore
+		}
+		return n.Findings[i].Heuristic < n.Findings[j].Heuristic
+	})
+	n.derive()
+}
+
+// suppress records f as suppressed, replacing any earlier finding from the
+// same heuristic, whether it was recorded or suppressed.
+func (n *Node) suppress(f Finding) {
+	n.Suppressed = putFinding(n.Suppressed, f)
+	if len(n.Findings) > 0 {
+		n.Findings = dropFinding(n.Findings, f.Heuristic)
+		n.derive()
+	}
+}
+
+func putFinding(list []Finding, f Finding) []Finding {
+	for i := range list {
+		if list[i].Heuristic == f.Heuristic {
+			list[i] = f
+			return list
+		}
+	}
+	return append(list, f)
+}
+
+func dropFinding(list []Finding, heuristic string) []Finding {
+	for i := range list {
+		if list[i].Heuristic == heuristic {
+			return append(list[:i], list[i+1:]...)
+		}
+	}
+	return list
+}
+
+// Savings is what acting on the finding is worth per month. Findings without
+// their own estimate fall back to the node's cost.
+func (f Finding) Savings(n *Node) float64 {
+	if f.MonthlySavings > 0 {
+		return f.MonthlySavings
+	}
+	return n.Cost
+}
+
+// derive sets IsWaste, RiskScore, Savings and Justified from the findings.
+// Only waste findings make a node waste; rightsizing, security and
+// compliance findings still raise its risk. Savings from different findings
+// overlap (deleting a volume also covers resizing it), so the node is worth
+// the largest single saving, not the sum. Cost is left as scanned.
+func (n *Node) derive() {
+	n.IsWaste = n.HasWasteFinding()
+	n.RiskScore, n.Savings = 0, 0
+	n.Justified, n.Justification = false, ""
+	for _, f := range n.Findings {
+		if f.RiskScore > n.RiskScore {
+			n.RiskScore = f.RiskScore
+		}
+		saving := f.MonthlySavings
+		if f.Category == CategoryWaste {
+			saving = f.Savings(n)
+		}
+		if saving > n.Savings {
+			n.Savings = saving
+		}
+		if f.Justification != "" && !n.Justified {
+			n.Justified = true
+			n.Justification = f.Justification // Highest risk first
+		}
+	}
+}
+
+// FilterConfidence drops findings less confident than min and re-derives the
+// node verdicts, so reports only show what the user asked to see. Returns the
+// number of findings dropped.
+func (g *Graph) FilterConfidence(min float64) int {
+	g.lockAll()
+	defer g.unlockAll()
+
+	dropped := 0
+	g.each(func(node *Node) {
+		if len(node.Findings) == 0 {
+			return
+		}
+		kept := node.Findings[:0]
+		for _, f := range node.Findings {
+			if f.Confidence < min {
+				dropped++
+				continue
+			}
+			kept = append(kept, f)
+		}
+		if len(kept) != len(node.Findings) {
+			node.Findings = kept
+			node.derive()
+		}
+	})
+	return dropped
+}
+
+// Reason joins the reasons of all findings, highest risk first.
+func (n *Node) Reason() string {
+	reasons := make([]string, 0, len(n.Findings))
+	for _, f := range n.Findings {
+		if f.Reason != "" {
+			reasons = append(reasons, f.Reason)
+		}
+	}
+	return strings.Join(reasons, "; ")
+}
+
+// FindingBy returns the finding recorded by the named heuristic, if any.
+func (n *Node) FindingBy(heuristic string) (Finding, bool) {
+	for _, f := range n.Findings {
+		if f.Heuristic == heuristic {
+			return f, true
+		}
+	}
+	return Finding{}, false
+}
+
+// HasWasteFinding reports whether any finding recommends deleting the node.
+// Rightsizing, security and compliance findings keep it in place.
+func (n *Node) HasWasteFinding() bool {
+	for _, f := range n.Findings {
+		if f.Category == CategoryWaste {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,93 @@
+package graph
+
+import "testing"
+
+func TestAddFinding_DerivesVerdict(t *testing.T) {
+	g := NewGraph()
+	g.AddNode("vol-1", "AWS::EC2::Volume", map[string]interface{}{})
+	nodeByID(g, "vol-1").Cost = 10
+
+	g.AddFinding("vol-1", Finding{Heuristic: "TagComplianceHeuristic", Category: CategoryCompliance, RiskScore: 40, Reason: "Missing Tags: Owner"})
+	g.AddFinding("vol-1", Finding{Heuristic: "ZombieEBSHeuristic", Category: CategoryWaste, RiskScore: 90, MonthlySavings: 8, Reason: "Unattached EBS Volume"})
+	g.AddFinding("vol-1", Finding{Heuristic: "SnapshotChildrenHeuristic", Category: CategoryWaste, RiskScore: 60, MonthlySavings: 5, Reason: "other"})
+
+	node := nodeByID(g, "vol-1")
+	if !node.IsWaste || node.RiskScore != 90 || node.Savings != 8 || node.Cost != 10 {
+		t.Errorf("verdict not derived from findings: waste=%v risk=%d savings=%.2f cost=%.2f", node.IsWaste, node.RiskScore, node.Savings, node.Cost)
+	}
+	if len(node.Findings) != 3 || node.Findings[0].Heuristic != "ZombieEBSHeuristic" || node.Findings[2].Heuristic != "TagComplianceHeuristic" {
+		t.Errorf("findings should be kept and sorted by risk, got %+v", node.Findings)
+	}
+	if got, want := node.Reason(), "Unattached EBS Volume; other; Missing Tags: Owner"; got != want {
+		t.Errorf("Reason() = %q, want %q", got, want)
+	}
+
+	// A heuristic re-running replaces its own finding rather than stacking.
+	g.AddFinding("vol-1", Finding{Heuristic: "ZombieEBSHeuristic", Category: CategoryWaste, RiskScore: 70, MonthlySavings: 8, Reason: "Zombie EBS"})
+	if len(node.Findings) != 3 || node.RiskScore != 70 {
+		t.Errorf("expected replaced finding, got risk=%d %+v", node.RiskScore, node.Findings)
+	}
+
+	// Dropping the findings that carried savings falls back to the scanned cost.
+	g.AddFinding("vol-1", Finding{Heuristic: "ZombieEBSHeuristic", Category: CategoryWaste, RiskScore: 70, Reason: "Zombie EBS"})
+	g.AddFinding("vol-1", Finding{Heuristic: "SnapshotChildrenHeuristic", Category: CategoryWaste, RiskScore: 60, Reason: "other"})
+	if node.Savings != 10 || node.Cost != 10 {
+		t.Errorf("savings should fall back to the scanned cost, got savings=%.2f cost=%.2f", node.Savings, node.Cost)
+	}
+	if f, ok := node.FindingBy("ZombieEBSHeuristic"); !ok || f.Reason != "Zombie EBS" {
+		t.Errorf("FindingBy returned %+v, %v", f, ok)
+	}
+}
+
+func TestAddFinding_OnlyWasteFindingsMakeWaste(t *testing.T) {
+	g := NewGraph()
+	g.AddNode("db-1", "AWS::RDS::DBInstance", nil)
+
+	g.AddFinding("db-1", Finding{Heuristic: "RDSRightsizingHeuristic", Category: CategoryRightsizing, RiskScore: 30, MonthlySavings: 120})
+	g.AddFinding("db-1", Finding{Heuristic: "TagComplianceHeuristic", Category: CategoryCompliance, RiskScore: 40})
+	node := nodeByID(g, "db-1")
+	if node.IsWaste || node.RiskScore != 40 {
+		t.Errorf("rightsizing and compliance findings should raise risk without making waste: waste=%v risk=%d", node.IsWaste, node.RiskScore)
+	}
+
+	g.AddFinding("db-1", Finding{Heuristic: "IdleRDSHeuristic", Category: CategoryWaste, RiskScore: 80})
+	if !node.IsWaste {
+		t.Error("a waste finding should make the node waste")
+	}
+}
+
+func TestAddFinding_IgnoreTag(t *testing.T) {
+	g := NewGraph()
+	g.AddNode("cheap", "AWS::EC2::Volume", map[string]interface{}{
+		"Tags": map[string]string{"cloudslash:ignore": "cost<10"},
+	})
+
+	if g.AddFinding("cheap", Finding{Heuristic: "ZombieEBSHeuristic", Category: CategoryWaste, RiskScore: 90, MonthlySavings: 4}) {
+		t.Error("finding below the cost<10 threshold should be suppressed")
+	}
+	if !g.AddFinding("cheap", Finding{Heuristic: "ZombieEBSHeuristic", Category: CategoryWaste, RiskScore: 90, MonthlySavings: 40}) {
+		t.Error("finding above the cost<10 threshold should be recorded")
+	}
+	if g.AddFinding("missing", Finding{Heuristic: "ZombieEBSHeuristic"}) {
+		t.Error("unknown node should report false")
+	}
+}
+
+func TestFilterConfidence(t *testing.T) {
+	g := NewGraph()
+	g.AddNode("vol-1", "AWS::EC2::Volume", nil)
+	g.AddNode("vol-2", "AWS::EC2::Volume", nil)
+	g.AddFinding("vol-1", Finding{Heuristic: "A", Category: CategoryWaste, Confidence: 0.9, RiskScore: 90, MonthlySavings: 8})
+	g.AddFinding("vol-1", Finding{Heuristic: "B", Category: CategoryWaste, Confidence: 0.4, RiskScore: 95})
+	g.AddFinding("vol-2", Finding{Heuristic: "B", Category: CategoryWaste, Confidence: 0.4, RiskScore: 95})
+
+	if dropped := g.FilterConfidence(0.5); dropped != 2 {
+		t.Errorf("expected 2 findings dropped, got %d", dropped)
+	}
+	if n := nodeByID(g, "vol-1"); !n.IsWaste || n.RiskScore != 90 || len(n.Findings) != 1 {
+		t.Errorf("vol-1 should keep only the confident finding, got risk=%d %+v", n.RiskScore, n.Findings)
+	}
+	if nodeByID(g, "vol-2").IsWaste {
+		t.Error("vol-2 had only a low-confidence finding and should no longer be waste")
+	}
+}
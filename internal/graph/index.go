@@ -0,0 +1,83 @@
+package graph
+
+// edgeKey identifies a forward edge. Two edges between the same pair are
+// distinct only if their types differ.
+type edgeKey struct {
+	source   string
+	target   string
+	edgeType EdgeType
+}
+
+func (s *shard) indexNode(node *Node) {
+	s.typePos[node.ID] = len(s.byType[node.Type])
+	s.byType[node.Type] = append(s.byType[node.Type], node)
+}
+
+// unindexNode swap-removes the node from its type bucket in O(1).
+func (s *shard) unindexNode(node *Node) {
+	bucket := s.byType[node.Type]
+	i, ok := s.typePos[node.ID]
+	if !ok {
+		return
+	}
+	last := len(bucket) - 1
+	bucket[i] = bucket[last]
+	s.typePos[bucket[i].ID] = i
+	bucket[last] = nil
+	delete(s.typePos, node.ID)
+	if last == 0 {
+		delete(s.byType, node.Type)
+	} else {
+		s.byType[node.Type] = bucket[:last]
+	}
+}
+
+// NodesByType returns the nodes of the given resource types in no particular
+// order. It costs O(matches) rather than a scan of every node. Shards are read
+// one at a time, so writers are only held off the shard being read.
+func (g *Graph) NodesByType(types ...string) []*Node {
+	var nodes []*Node
+	for i := range g.shards {
+		s := &g.shards[i]
+		s.mu.RLock()
+		nodes = s.appendByType(nodes, types)
+		s.mu.RUnlock()
+	}
+	return nodes
+}
+
+// nodesByType is NodesByType for callers holding every shard lock.
+func (g *Graph) nodesByType(types ...string) []*Node {
+	n := 0
+	for i := range g.shards {
+		for _, t := range types {
+			n += len(g.shards[i].byType[t])
+		}
+	}
+	nodes := make([]*Node, 0, n)
+	for i := range g.shards {
+		nodes = g.shards[i].appendByType(nodes, types)
+	}
+	return nodes
+}
+
+func (s *shard) appendByType(nodes []*Node, types []string) []*Node {
+	for _, t := range types {
+		nodes = append(nodes, s.byType[t]...)
+	}
+	return nodes
+}
+
+// CountByType returns the number of nodes of each resource type.
+func (g *Graph) CountByType() map[string]int {
+	counts := make(map[string]int)
+	for i := range g.shards {
+		s := &g.shards[i]
+		s.mu.RLock()
+		for t, bucket := range s.byType {
+			counts[t] += len(bucket)
+		}
+		s.mu.RUnlock()
+	}
+	return counts
+}
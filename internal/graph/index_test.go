@@ -0,0 +1,91 @@
+package graph
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"testing"
+)
+
+func TestNodesByType(t *testing.T) {
+	g := NewGraph()
+	g.AddNode("vol-2", "AWS::EC2::Volume", nil)
+	g.AddNode("vol-1", "AWS::EC2::Volume", nil)
+	g.AddTypedEdge("vol-1", "i-1", EdgeTypeAttachedTo, 100) // i-1 starts as a placeholder
+
+	if got := ids(g.NodesByType("AWS::EC2::Volume")); fmt.Sprint(got) != "[vol-1 vol-2]" {
+		t.Errorf("volumes = %v, want [vol-1 vol-2]", got)
+	}
+	if got := ids(g.NodesByType("Unknown")); fmt.Sprint(got) != "[i-1]" {
+		t.Errorf("placeholders = %v, want [i-1]", got)
+	}
+
+	// Scanning the instance later upgrades the placeholder and moves it in the index.
+	g.AddNode("i-1", "AWS::EC2::Instance", map[string]interface{}{"State": "stopped"})
+	if got := g.NodesByType("Unknown"); len(got) != 0 {
+		t.Errorf("placeholder should leave the Unknown bucket, got %v", ids(got))
+	}
+	if got := ids(g.NodesByType("AWS::EC2::Instance", "AWS::EC2::Volume")); fmt.Sprint(got) != "[i-1 vol-1 vol-2]" {
+		t.Errorf("multi-type lookup = %v", got)
+	}
+	if counts := g.CountByType(); counts["AWS::EC2::Volume"] != 2 || counts["AWS::EC2::Instance"] != 1 || len(counts) != 2 {
+		t.Errorf("CountByType = %v", counts)
+	}
+}
+
+func TestAddTypedEdge_Dedup(t *testing.T) {
+	g := NewGraph()
+	g.AddTypedEdge("a", "b", EdgeTypeAttachedTo, 100)
+	g.AddTypedEdge("a", "b", EdgeTypeAttachedTo, 50)
+	g.AddTypedEdge("a", "b", EdgeTypeSecuredBy, 100)
+
+	if len(g.edgesOf("a")) != 2 || len(g.reverseOf("b")) != 2 {
+		t.Errorf("expected one edge per type, got forward %v reverse %v", g.edgesOf("a"), g.reverseOf("b"))
+	}
+	if g.edgesOf("a")[0].Weight != 100 {
+		t.Errorf("duplicate edge should keep the first weight, got %d", g.edgesOf("a")[0].Weight)
+	}
+}
+
+func TestBatch_ConcurrentFlush(t *testing.T) {
+	g := NewGraph()
+
+	var wg sync.WaitGroup
+	for w := 0; w < 8; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			batch := g.NewBatch()
+			for i := 0; i < 500; i++ {
+				id := fmt.Sprintf("vol-%d-%d", w, i)
+				batch.AddTypedEdge(id, "i-shared", EdgeTypeAttachedTo, 100)
+				batch.AddNode(id, "AWS::EC2::Volume", map[string]interface{}{"Size": i})
+				if batch.Len() >= 100 {
+					batch.Flush()
+				}
+			}
+			batch.Flush()
+		}(w)
+	}
+	wg.Wait()
+
+	if n := len(g.NodesByType("AWS::EC2::Volume")); n != 4000 {
+		t.Errorf("expected 4000 volumes, got %d", n)
+	}
+	if n := len(g.reverseOf("i-shared")); n != 4000 {
+		t.Errorf("expected 4000 attachments, got %d", n)
+	}
+	if n := len(g.NodesByType("Unknown")); n != 1 {
+		t.Errorf("only the shared instance should remain a placeholder, got %d", n)
+	}
+}
+
+// ids returns the sorted IDs of nodes; NodesByType promises no order.
+func ids(nodes []*Node) []string {
+	out := make([]string, len(nodes))
+	for i, n := range nodes {
+		out[i] = n.ID
+	}
+	sort.Strings(out)
+	return out
+}
@@ -0,0 +1,244 @@
+package graph
+
+import (
+	"container/heap"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// deleteFirst says which end of an edge AWS requires to be removed first.
+type deleteFirst int
+
+const (
+	targetFirst deleteFirst = iota
+	sourceFirst
+)
+
+// deletionPrecedence maps edge types that constrain deletion order.
+//   - AttachedTo (EBS -> EC2, EIP -> EC2): the consumer goes first, which detaches the resource.
+//   - Contains (VPC -> Subnet): a container can only be deleted once it is empty.
+//   - FlowsTo (ALB -> TargetGroup): remove the sender before the destination it references.
+//
+// SecuredBy and Unknown edges do not block deletion.
+var deletionPrecedence = map[EdgeType]deleteFirst{
+	EdgeTypeAttachedTo: targetFirst,
+	EdgeTypeContains:   targetFirst,
+	EdgeTypeFlowsTo:    sourceFirst,
+}
+
+// DeletionPlan is a dependency-respecting order for removing a set of resources.
+type DeletionPlan struct {
+	Order   []string   // Safe deletion order
+	Cycles  [][]string // Resources whose constraints form a loop and need a manual order
+	Blocked []string   // Resources outside any cycle that wait on one
+}
+
+// All returns Order followed by the cycle members and blocked resources.
+func (p *DeletionPlan) All() []string {
+	all := append([]string(nil), p.Order...)
+	for _, c := range p.Cycles {
+		all = append(all, c...)
+	}
+	return append(all, p.Blocked...)
+}
+
+// CycleError describes the cycles of a plan.
+func (p *DeletionPlan) CycleError() error {
+	if len(p.Cycles) == 0 {
+		return nil
+	}
+	parts := make([]string, len(p.Cycles))
+	for i, c := range p.Cycles {
+		parts[i] = strings.Join(c, ", ")
+	}
+	return fmt.Errorf("dependency cycle between resources: %s", strings.Join(parts, "; "))
+}
+
+// PlanDeletion orders ids so that every resource is deleted after the ones
+// blocking it (see deletionPrecedence). Only edges between members of ids are
+// considered. Ties are broken by ID so the same graph always yields the same plan.
+// Unknown IDs are ignored.
+func (g *Graph) PlanDeletion(ids []string) *DeletionPlan {
+	g.rlockAll()
+	defer g.runlockAll()
+
+	set := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		if _, ok := g.node(id); ok {
+			set[id] = true
+		}
+	}
+
+	// before[a] lists resources that can only go once a is deleted.
+	before := make(map[string][]string, len(set))
+	blockers := make(map[string]int, len(set))
+	for src := range set {
+		for _, e := range g.edgesOf(src) {
+			rule, ok := deletionPrecedence[e.Type]
+			if !ok || !set[e.TargetID] {
+				continue
+			}
+			first, then := e.TargetID, src
+			if rule == sourceFirst {
+				first, then = src, e.TargetID
+			}
+			before[first] = append(before[first], then)
+			blockers[then]++
+		}
+	}
+
+	// Kahn's algorithm with a min-heap for deterministic tie-breaking.
+	ready := &idHeap{}
+	for id := range set {
+		if blockers[id] == 0 {
+			heap.Push(ready, id)
+		}
+	}
+
+	plan := &DeletionPlan{}
+	for ready.Len() > 0 {
+		id := heap.Pop(ready).(string)
+		plan.Order = append(plan.Order, id)
+		for _, next := range before[id] {
+			blockers[next]--
+			if blockers[next] == 0 {
+				heap.Push(ready, next)
+			}
+		}
+	}
+
+	if len(plan.Order) < len(set) {
+		remaining := make(map[string]bool)
+		for id := range set {
+			if blockers[id] > 0 {
+				remaining[id] = true
+			}
+		}
+		plan.Cycles = cyclesAmong(remaining, before)
+
+		inCycle := make(map[string]bool)
+		for _, c := range plan.Cycles {
+			for _, id := range c {
+				inCycle[id] = true
+			}
+		}
+		for id := range remaining {
+			if !inCycle[id] {
+				plan.Blocked = append(plan.Blocked, id)
+			}
+		}
+		sort.Strings(plan.Blocked)
+	}
+	return plan
+}
+
+// PlanWasteDeletion plans the removal of every node with a waste finding.
+// Nodes flagged only for rightsizing, security or compliance are kept, and so
+// is waste a policy justified.
+func (g *Graph) PlanWasteDeletion() *DeletionPlan {
+	var ids []string
+	g.Read(func(v *View) {
+		v.Each(func(node *Node) {
+			if node.HasWasteFinding() && !node.Justified {
+				ids = append(ids, node.ID)
+			}
+		})
+	})
+
+	return g.PlanDeletion(ids)
+}
+
+// cyclesAmong returns the strongly connected components of the blocked
+// resources that contain a loop.
+func cyclesAmong(nodes map[string]bool, before map[string][]string) [][]string {
+	ids := make([]string, 0, len(nodes))
+	for id := range nodes {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	// Tarjan's SCC.
+	index := make(map[string]int)
+	low := make(map[string]int)
+	onStack := make(map[string]bool)
+	var stack []string
+	var sccs [][]string
+	counter := 0
+
+	var strongConnect func(v string)
+	strongConnect = func(v string) {
+		index[v] = counter
+		low[v] = counter
+		counter++
+		stack = append(stack, v)
+		onStack[v] = true
+
+		for _, w := range before[v] {
+			if !nodes[w] {
+				continue
+			}
+			if _, seen := index[w]; !seen {
+				strongConnect(w)
+				if low[w] < low[v] {
+					low[v] = low[w]
+				}
+			} else if onStack[w] && index[w] < low[v] {
+				low[v] = index[w]
+			}
+		}
+
+		if low[v] == index[v] {
+			var scc []string
+			for {
+				w := stack[len(stack)-1]
+				stack = stack[:len(stack)-1]
+				onStack[w] = false
+				scc = append(scc, w)
+				if w == v {
+					break
+				}
+			}
+			sccs = append(sccs, scc)
+		}
+	}
+	for _, id := range ids {
+		if _, seen := index[id]; !seen {
+			strongConnect(id)
+		}
+	}
+
+	var cycles [][]string
+	for _, scc := range sccs {
+		if len(scc) == 1 && !selfLoop(scc[0], before) {
+			continue
+		}
+		sort.Strings(scc)
+		cycles = append(cycles, scc)
+	}
+	sort.Slice(cycles, func(i, j int) bool { return cycles[i][0] < cycles[j][0] })
+
+	return cycles
+}
+
+func selfLoop(id string, before map[string][]string) bool {
+	for _, w := range before[id] {
+		if w == id {
+			return true
+		}
+	}
+	return false
+}
+
+type idHeap []string
+
+func (h idHeap) Len() int            { return len(h) }
+func (h idHeap) Less(i, j int) bool  { return h[i] < h[j] }
+func (h idHeap) Swap(i, j int)       { h[i], h[j] = h[j], h[i] }
+func (h *idHeap) Push(x interface{}) { *h = append(*h, x.(string)) }
+func (h *idHeap) Pop() interface{} {
+	old := *h
+	item := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return item
+}
@@ -0,0 +1,72 @@
+package graph
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestPlanDeletion(t *testing.T) {
+	g := NewGraph()
+	g.AddTypedEdge("vol", "i-1", EdgeTypeAttachedTo, 100)
+	g.AddTypedEdge("eip", "i-1", EdgeTypeAttachedTo, 100)
+	g.AddTypedEdge("subnet", "i-1", EdgeTypeContains, 100)
+	g.AddTypedEdge("subnet", "nat", EdgeTypeContains, 100)
+	g.AddTypedEdge("alb", "tg", EdgeTypeFlowsTo, 100)
+	g.AddTypedEdge("i-1", "sg", EdgeTypeSecuredBy, 100) // Does not constrain order
+
+	plan := g.PlanDeletion([]string{"vol", "eip", "i-1", "subnet", "nat", "alb", "tg", "sg", "missing"})
+
+	want := []string{"alb", "i-1", "eip", "nat", "sg", "subnet", "tg", "vol"}
+	if !reflect.DeepEqual(plan.Order, want) {
+		t.Errorf("order = %v, want %v", plan.Order, want)
+	}
+	if plan.CycleError() != nil {
+		t.Errorf("unexpected cycle: %v", plan.CycleError())
+	}
+
+	// Only members of the set constrain each other.
+	plan = g.PlanDeletion([]string{"vol", "subnet"})
+	if !reflect.DeepEqual(plan.Order, []string{"subnet", "vol"}) {
+		t.Errorf("order = %v", plan.Order)
+	}
+}
+
+func TestPlanDeletionCycle(t *testing.T) {
+	g := NewGraph()
+	g.AddTypedEdge("a", "b", EdgeTypeAttachedTo, 100)
+	g.AddTypedEdge("b", "a", EdgeTypeContains, 100)
+	g.AddTypedEdge("c", "a", EdgeTypeAttachedTo, 100) // Waits on the cycle
+	g.AddTypedEdge("d", "e", EdgeTypeAttachedTo, 100)
+
+	plan := g.PlanDeletion([]string{"a", "b", "c", "d", "e"})
+
+	if !reflect.DeepEqual(plan.Order, []string{"e", "d"}) {
+		t.Errorf("order = %v", plan.Order)
+	}
+	if !reflect.DeepEqual(plan.Cycles, [][]string{{"a", "b"}}) {
+		t.Errorf("cycles = %v", plan.Cycles)
+	}
+	if !reflect.DeepEqual(plan.Blocked, []string{"c"}) {
+		t.Errorf("blocked = %v", plan.Blocked)
+	}
+	if plan.CycleError() == nil {
+		t.Error("expected cycle error")
+	}
+	if got := plan.All(); !reflect.DeepEqual(got, []string{"e", "d", "a", "b", "c"}) {
+		t.Errorf("all = %v", got)
+	}
+}
+
+func TestPlanWasteDeletion(t *testing.T) {
+	g := NewGraph()
+	g.AddNode("vol", "AWS::EC2::Volume", nil)
+	g.AddNode("nat", "AWS::EC2::NatGateway", nil)
+	g.AddNode("db", "AWS::RDS::DBInstance", nil)
+	g.AddFinding("vol", Finding{Heuristic: "ZombieEBSHeuristic", Category: CategoryWaste})
+	g.AddFinding("nat", Finding{Heuristic: "TagComplianceHeuristic", Category: CategoryCompliance})
+	g.AddFinding("db", Finding{Heuristic: "RDSRightsizingHeuristic", Category: CategoryRightsizing})
+
+	if got := g.PlanWasteDeletion().Order; !reflect.DeepEqual(got, []string{"vol"}) {
+		t.Errorf("order = %v, want only the waste volume", got)
+	}
+}
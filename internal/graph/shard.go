@@ -0,0 +1,121 @@
+package graph
+
+import (
+	"hash/maphash"
+	"sync"
+)
+
+// shardCount is the number of independently locked partitions of a graph.
+// Scanners writing different resources rarely meet on the same shard.
+const shardCount = 64
+
+// shard holds the nodes whose IDs hash to it, their forward and reverse
+// edges, and its part of the type index.
+type shard struct {
+	mu      sync.RWMutex
+	nodes   map[string]*Node
+	edges   map[string][]Edge    // ID -> []Edge (Forward Dependencies)
+	reverse map[string][]Edge    // ID -> []Edge (Reverse Dependencies)
+	edgeSet map[edgeKey]struct{} // Forward edges of this shard's nodes, for O(1) dedup
+	byType  map[string][]*Node   // Type -> Nodes, see NodesByType
+	typePos map[string]int       // ID -> position in its byType bucket
+}
+
+func (s *shard) init() {
+	s.nodes = make(map[string]*Node)
+	s.edges = make(map[string][]Edge)
+	s.reverse = make(map[string][]Edge)
+	s.edgeSet = make(map[edgeKey]struct{})
+	s.byType = make(map[string][]*Node)
+	s.typePos = make(map[string]int)
+}
+
+// shardIndex returns the shard that owns id.
+func (g *Graph) shardIndex(id string) int {
+	return int(maphash.String(g.seed, id) % shardCount)
+}
+
+func (g *Graph) shardOf(id string) *shard {
+	return &g.shards[g.shardIndex(id)]
+}
+
+// Locks are always taken metadata first, then shards in index order, so
+// whole-graph and two-shard operations cannot deadlock each other.
+
+// rlockAll takes the read lock on the metadata and every shard.
+func (g *Graph) rlockAll() {
+	g.meta.RLock()
+	for i := range g.shards {
+		g.shards[i].mu.RLock()
+	}
+}
+
+func (g *Graph) runlockAll() {
+	for i := range g.shards {
+		g.shards[i].mu.RUnlock()
+	}
+	g.meta.RUnlock()
+}
+
+// lockAll takes the write lock on the metadata and every shard.
+func (g *Graph) lockAll() {
+	g.meta.Lock()
+	for i := range g.shards {
+		g.shards[i].mu.Lock()
+	}
+}
+
+func (g *Graph) unlockAll() {
+	for i := range g.shards {
+		g.shards[i].mu.Unlock()
+	}
+	g.meta.Unlock()
+}
+
+// lockPair write-locks the shards of two IDs in index order and returns them.
+func (g *Graph) lockPair(a, b string) (*shard, *shard, func()) {
+	i, j := g.shardIndex(a), g.shardIndex(b)
+	sa, sb := &g.shards[i], &g.shards[j]
+	switch {
+	case i == j:
+		sa.mu.Lock()
+		return sa, sb, sa.mu.Unlock
+	case i < j:
+		sa.mu.Lock()
+		sb.mu.Lock()
+	default:
+		sb.mu.Lock()
+		sa.mu.Lock()
+	}
+	return sa, sb, func() {
+		sa.mu.Unlock()
+		sb.mu.Unlock()
+	}
+}
+
+// The accessors below do not lock; callers hold the relevant shard locks.
+
+func (g *Graph) node(id string) (*Node, bool) {
+	node, ok := g.shardOf(id).nodes[id]
+	return node, ok
+}
+
+func (g *Graph) edgesOf(id string) []Edge { return g.shardOf(id).edges[id] }
+
+func (g *Graph) reverseOf(id string) []Edge { return g.shardOf(id).reverse[id] }
+
+func (g *Graph) each(fn func(n *Node)) {
+	for i := range g.shards {
+		for _, node := range g.shards[i].nodes {
+			fn(node)
+		}
+	}
+}
+
+func (g *Graph) count() int {
+	n := 0
+	for i := range g.shards {
+		n += len(g.shards[i].nodes)
+	}
+	return n
+}
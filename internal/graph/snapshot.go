@@ -0,0 +1,349 @@
+package graph
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"reflect"
+	"sort"
+	"time"
+)
+
+// SnapshotVersion is the on-disk format version written by SaveSnapshot.
+// Bump it whenever the layout changes incompatibly.
+// v2: findings replace the free-form "Reason" property.
+const SnapshotVersion = 2
+
+// ScanMetadata describes the scan that produced a graph.
+type ScanMetadata struct {
+	ScannedAt time.Time `json:"scanned_at"`
+	Accounts  []string  `json:"accounts,omitempty"`
+	Regions   []string  `json:"regions,omitempty"`
+	Mock      bool      `json:"mock,omitempty"`
+
+	Heuristics []HeuristicRun `json:"heuristics,omitempty"` // Coverage of the last analysis, see HeuristicRun
+}
+
+// Snapshot is the serialized form of a Graph.
+type Snapshot struct {
+	Version  int            `json:"version"`
+	Metadata ScanMetadata   `json:"metadata"`
+	Nodes    []SnapshotNode `json:"nodes"`
+	Edges    []SnapshotEdge `json:"edges"`
+}
+
+// SnapshotNode is a Node with its findings and typed properties.
+type SnapshotNode struct {
+	ID             string                   `json:"id"`
+	Type           string                   `json:"type"`
+	Properties     map[string]PropertyValue `json:"properties,omitempty"`
+	IsWaste        bool                     `json:"is_waste,omitempty"`
+	Justified      bool                     `json:"justified,omitempty"`
+	Justification  string                   `json:"justification,omitempty"`
+	RiskScore      int                      `json:"risk_score,omitempty"`
+	Cost           float64                  `json:"cost,omitempty"`
+	SourceLocation string                   `json:"source_location,omitempty"`
+	Findings       []Finding                `json:"findings,omitempty"`
+	Suppressed     []Finding                `json:"suppressed,omitempty"`
+}
+
+// SnapshotEdge is a single forward edge. Reverse edges are rebuilt on load.
+type SnapshotEdge struct {
+	Source string   `json:"source"`
+	Target string   `json:"target"`
+	Type   EdgeType `json:"type"`
+	Weight int      `json:"weight"`
+}
+
+// PropertyValue keeps the Go type of a property so heuristics and reports
+// see the same values after a reload (time.Time stays time.Time, int32 stays int32).
+type PropertyValue struct {
+	Kind  string          `json:"kind"`
+	Value json.RawMessage `json:"value"`
+}
+
+// AddScanScope records an account/region pair covered by the scan.
+func (g *Graph) AddScanScope(accountID, region string) {
+	g.meta.Lock()
+	defer g.meta.Unlock()
+
+	if accountID != "" && !contains(g.Metadata.Accounts, accountID) {
+		g.Metadata.Accounts = append(g.Metadata.Accounts, accountID)
+	}
+	if region != "" && !contains(g.Metadata.Regions, region) {
+		g.Metadata.Regions = append(g.Metadata.Regions, region)
+	}
+}
+
+// Snapshot captures the graph in its serializable form.
+// Nodes and edges are sorted so identical graphs produce identical files.
+func (g *Graph) Snapshot() (*Snapshot, error) {
+	g.rlockAll()
+	defer g.runlockAll()
+
+	snap := &Snapshot{
+		Version:  SnapshotVersion,
+		Metadata: g.Metadata,
+	}
+
+	var err error
+	g.each(func(node *Node) {
+		if err != nil {
+			return
+		}
+		sn := SnapshotNode{
+			ID:             node.ID,
+			Type:           node.Type,
+			IsWaste:        node.IsWaste,
+			Justified:      node.Justified,
+			Justification:  node.Justification,
+			RiskScore:      node.RiskScore,
+			Cost:           node.Cost,
+			SourceLocation: node.SourceLocation,
+			Findings:       node.Findings,
+			Suppressed:     node.Suppressed,
+		}
+		if len(node.Properties) > 0 {
+			sn.Properties = make(map[string]PropertyValue, len(node.Properties))
+			for k, v := range node.Properties {
+				pv, ok, perr := encodeProperty(v)
+				if perr != nil {
+					err = fmt.Errorf("node %s property %s: %v", node.ID, k, perr)
+					return
+				}
+				if ok {
+					sn.Properties[k] = pv
+				}
+			}
+		}
+		snap.Nodes = append(snap.Nodes, sn)
+
+		for _, e := range g.edgesOf(node.ID) {
+			snap.Edges = append(snap.Edges, SnapshotEdge{Source: node.ID, Target: e.TargetID, Type: e.Type, Weight: e.Weight})
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(snap.Nodes, func(i, j int) bool { return snap.Nodes[i].ID < snap.Nodes[j].ID })
+	sort.Slice(snap.Edges, func(i, j int) bool {
+		a, b := snap.Edges[i], snap.Edges[j]
+		if a.Source != b.Source {
+			return a.Source < b.Source
+		}
+		if a.Target != b.Target {
+			return a.Target < b.Target
+		}
+		return a.Type < b.Type
+	})
+
+	return snap, nil
+}
+
+// SaveSnapshot writes the graph to path as versioned JSON.
+func (g *Graph) SaveSnapshot(path string) error {
+	snap, err := g.Snapshot()
+	if err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(snap, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode snapshot: %v", err)
+	}
+	return os.WriteFile(path, data, 0644)
+}
+
+// LoadSnapshot reads a snapshot written by SaveSnapshot and rebuilds the graph.
+func LoadSnapshot(path string) (*Graph, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read snapshot: %v", err)
+	}
+
+	var snap Snapshot
+	if err := json.Unmarshal(data, &snap); err != nil {
+		return nil, fmt.Errorf("failed to parse snapshot %s: %v", path, err)
+	}
+	return FromSnapshot(&snap)
+}
+
+// FromSnapshot rebuilds a Graph from its serialized form.
+func FromSnapshot(snap *Snapshot) (*Graph, error) {
+	if snap.Version < 1 || snap.Version > SnapshotVersion {
+		return nil, fmt.Errorf("unsupported snapshot version %d (this build reads up to %d)", snap.Version, SnapshotVersion)
+	}
+
+	g := NewGraph()
+	g.Metadata = snap.Metadata
+
+	for _, sn := range snap.Nodes {
+		props := make(map[string]interface{}, len(sn.Properties))
+		for k, pv := range sn.Properties {
+			v, err := decodeProperty(pv)
+			if err != nil {
+				return nil, fmt.Errorf("node %s property %s: %v", sn.ID, k, err)
+			}
+			props[k] = v
+		}
+		node := &Node{
+			ID:             sn.ID,
+			Type:           sn.Type,
+			Properties:     props,
+			IsWaste:        sn.IsWaste,
+			Justified:      sn.Justified,
+			Justification:  sn.Justification,
+			RiskScore:      sn.RiskScore,
+			Cost:           sn.Cost,
+			SourceLocation: sn.SourceLocation,
+			Findings:       sn.Findings,
+			Suppressed:     sn.Suppressed,
+		}
+		if snap.Version == 1 {
+			upgradeLegacyFinding(node)
+		}
+		upgradeJustification(node)
+		node.derive()
+		g.shardOf(sn.ID).insert(node)
+	}
+
+	for _, e := range snap.Edges {
+		g.AddTypedEdge(e.Source, e.Target, e.Type, e.Weight)
+	}
+
+	return g, nil
+}
+
+// upgradeLegacyFinding turns the v1 "Reason" property into a single finding
+// carrying the node's stored risk and cost.
+func upgradeLegacyFinding(node *Node) {
+	reason, _ := node.Properties["Reason"].(string)
+	delete(node.Properties, "Reason")
+	if !node.IsWaste {
+		return
+	}
+	node.Findings = []Finding{{
+		Heuristic:      "legacy",
+		Category:       CategoryWaste,
+		Confidence:     1,
+		RiskScore:      node.RiskScore,
+		MonthlySavings: node.Cost,
+		Reason:         reason,
+	}}
+}
+
+// upgradeJustification copies a node-level justification onto its findings,
+// for snapshots written before findings carried their own.
+func upgradeJustification(node *Node) {
+	if !node.Justified {
+		return
+	}
+	for _, f := range node.Findings {
+		if f.Justification != "" {
+			return
+		}
+	}
+	for i := range node.Findings {
+		node.Findings[i].Justification = node.Justification
+	}
+}
+
+// encodeProperty tags a property value with its Go kind.
+// Pointers are dereferenced; nil pointers are dropped (ok=false).
+// Types without a dedicated kind are stored as plain JSON.
+func encodeProperty(v interface{}) (PropertyValue, bool, error) {
+	if v == nil {
+		return PropertyValue{}, false, nil
+	}
+	rv := reflect.ValueOf(v)
+	if rv.Kind() == reflect.Ptr {
+		if rv.IsNil() {
+			return PropertyValue{}, false, nil
+		}
+		v = rv.Elem().Interface()
+	}
+
+	kind := "json"
+	switch v.(type) {
+	case string:
+		kind = "string"
+	case bool:
+		kind = "bool"
+	case int:
+		kind = "int"
+	case int32:
+		kind = "int32"
+	case int64:
+		kind = "int64"
+	case float64:
+		kind = "float64"
+	case time.Time:
+		kind = "time"
+	case map[string]string:
+		kind = "map[string]string"
+	case []string:
+		kind = "[]string"
+	}
+
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return PropertyValue{}, false, err
+	}
+	return PropertyValue{Kind: kind, Value: raw}, true, nil
+}
+
+func decodeProperty(pv PropertyValue) (interface{}, error) {
+	var err error
+	switch pv.Kind {
+	case "string":
+		var v string
+		err = json.Unmarshal(pv.Value, &v)
+		return v, err
+	case "bool":
+		var v bool
+		err = json.Unmarshal(pv.Value, &v)
+		return v, err
+	case "int":
+		var v int
+		err = json.Unmarshal(pv.Value, &v)
+		return v, err
+	case "int32":
+		var v int32
+		err = json.Unmarshal(pv.Value, &v)
+		return v, err
+	case "int64":
+		var v int64
+		err = json.Unmarshal(pv.Value, &v)
+		return v, err
+	case "float64":
+		var v float64
+		err = json.Unmarshal(pv.Value, &v)
+		return v, err
+	case "time":
+		var v time.Time
+		err = json.Unmarshal(pv.Value, &v)
+		return v, err
+	case "map[string]string":
+		var v map[string]string
+		err = json.Unmarshal(pv.Value, &v)
+		return v, err
+	case "[]string":
+		var v []string
+		err = json.Unmarshal(pv.Value, &v)
+		return v, err
+	default:
+		var v interface{}
+		err = json.Unmarshal(pv.Value, &v)
+		return v, err
+	}
+}
+
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,110 @@
+package graph
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestSnapshot_RoundTrip(t *testing.T) {
+	launched := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	g := NewGraph()
+	g.AddScanScope("123456789012", "us-east-1")
+	g.AddNode("arn:vol", "AWS::EC2::Volume", map[string]interface{}{
+		"State":      "available",
+		"Size":       int32(100),
+		"CreateTime": &launched,
+		"Tags":       map[string]string{"team": "data"},
+	})
+	g.AddNode("arn:instance", "AWS::EC2::Instance", map[string]interface{}{})
+	g.AddTypedEdge("arn:vol", "arn:instance", EdgeTypeAttachedTo, 100)
+	g.AddFinding("arn:vol", Finding{Heuristic: "ZombieEBSHeuristic", Category: CategoryWaste, Confidence: 0.9, RiskScore: 90, MonthlySavings: 8.5, Reason: "Unattached EBS Volume"})
+
+	path := filepath.Join(t.TempDir(), "scan.json")
+	if err := g.SaveSnapshot(path); err != nil {
+		t.Fatalf("SaveSnapshot failed: %v", err)
+	}
+
+	loaded, err := LoadSnapshot(path)
+	if err != nil {
+		t.Fatalf("LoadSnapshot failed: %v", err)
+	}
+
+	vol, ok := loaded.Node("arn:vol")
+	if !ok {
+		t.Fatal("volume missing after reload")
+	}
+	if !vol.IsWaste || vol.RiskScore != 90 || vol.Savings != 8.5 {
+		t.Errorf("findings not preserved: waste=%v risk=%d savings=%.2f", vol.IsWaste, vol.RiskScore, vol.Savings)
+	}
+	if len(vol.Findings) != 1 || vol.Findings[0].Reason != "Unattached EBS Volume" || vol.Findings[0].Confidence != 0.9 {
+		t.Errorf("findings not preserved, got %+v", vol.Findings)
+	}
+	if size, ok := vol.Properties["Size"].(int32); !ok || size != 100 {
+		t.Errorf("Size should stay int32 100, got %#v", vol.Properties["Size"])
+	}
+	if ct, ok := vol.Properties["CreateTime"].(time.Time); !ok || !ct.Equal(launched) {
+		t.Errorf("CreateTime should reload as time.Time, got %#v", vol.Properties["CreateTime"])
+	}
+	if tags, ok := vol.Properties["Tags"].(map[string]string); !ok || tags["team"] != "data" {
+		t.Errorf("Tags should reload as map[string]string, got %#v", vol.Properties["Tags"])
+	}
+
+	if up := loaded.GetUpstream("arn:instance"); len(up) != 1 || up[0] != "arn:vol" {
+		t.Errorf("reverse edges not rebuilt, got %v", up)
+	}
+	if edges := loaded.edgesOf("arn:vol"); len(edges) != 1 || edges[0].Type != EdgeTypeAttachedTo {
+		t.Errorf("typed edge not preserved, got %v", edges)
+	}
+	if len(loaded.Metadata.Accounts) != 1 || loaded.Metadata.Accounts[0] != "123456789012" {
+		t.Errorf("scan metadata not preserved, got %+v", loaded.Metadata)
+	}
+}
+
+func TestSnapshot_RejectsNewerVersion(t *testing.T) {
+	if _, err := FromSnapshot(&Snapshot{Version: SnapshotVersion + 1}); err == nil {
+		t.Error("expected error for a snapshot written by a newer version")
+	}
+}
+
+func TestSnapshot_UpgradesV1Reason(t *testing.T) {
+	reason, _, _ := encodeProperty("Unattached EBS Volume")
+	g, err := FromSnapshot(&Snapshot{
+		Version: 1,
+		Nodes: []SnapshotNode{
+			{ID: "arn:vol", Type: "AWS::EC2::Volume", IsWaste: true, RiskScore: 90, Cost: 8.5, Properties: map[string]PropertyValue{"Reason": reason}},
+			{ID: "arn:instance", Type: "AWS::EC2::Instance"},
+		},
+	})
+	if err != nil {
+		t.Fatalf("FromSnapshot failed: %v", err)
+	}
+
+	vol := nodeByID(g, "arn:vol")
+	if _, ok := vol.Properties["Reason"]; ok {
+		t.Error("legacy Reason property should be removed")
+	}
+	if len(vol.Findings) != 1 || vol.Findings[0].Reason != "Unattached EBS Volume" || vol.Findings[0].RiskScore != 90 || vol.Findings[0].MonthlySavings != 8.5 {
+		t.Errorf("expected one legacy finding, got %+v", vol.Findings)
+	}
+	if len(nodeByID(g, "arn:instance").Findings) != 0 {
+		t.Errorf("clean node should have no findings, got %+v", nodeByID(g, "arn:instance").Findings)
+	}
+}
+
+func TestSnapshot_UpgradesNodeJustification(t *testing.T) {
+	g, err := FromSnapshot(&Snapshot{
+		Version: SnapshotVersion,
+		Nodes: []SnapshotNode{{
+			ID: "arn:vol", Type: "AWS::EC2::Volume", IsWaste: true, Justified: true, Justification: "dr",
+			Findings: []Finding{{Heuristic: "ZombieEBSHeuristic", Category: CategoryWaste, RiskScore: 90, Rule: TagRule}},
+		}},
+	})
+	if err != nil {
+		t.Fatalf("FromSnapshot failed: %v", err)
+	}
+	if vol := nodeByID(g, "arn:vol"); !vol.Justified || vol.Justification != "dr" || vol.Findings[0].Justification != "dr" {
+		t.Errorf("node justification should move onto its findings, got %+v", vol)
+	}
+}
@@ -0,0 +1,76 @@
+package graph
+
+// Verdict is the outcome of checking a finding against suppression rules.
+type Verdict struct {
+	Suppressed    bool   // Drop the finding
+	Justification string // Keep the finding, but as accepted waste
+	Rule          string // What decided, e.g. "policy:dr-volumes" or "tag:cloudslash:ignore"
+}
+
+// Suppressor decides whether a finding is suppressed or justified before it is
+// recorded. A Verdict with an empty Rule means no rule matched.
+type Suppressor interface {
+	Evaluate(n *Node, f Finding) Verdict
+}
+
+// TagRule names the cloudslash:ignore tag in verdicts.
+const TagRule = "tag:cloudslash:ignore"
+
+// verdict consults the Suppressor first and falls back to the cloudslash:ignore tag.
+func (g *Graph) verdict(node *Node, f Finding) Verdict {
+	if g.Suppressor != nil {
+		if v := g.Suppressor.Evaluate(node, f); v.Rule != "" {
+			return v
+		}
+	}
+
+	suppress, justification := node.ignoreTag(f.Savings(node))
+	if !suppress && justification == "" {
+		return Verdict{}
+	}
+	return Verdict{Suppressed: suppress, Justification: justification, Rule: TagRule}
+}
+
+// flag records the finding unless its verdict suppresses it. Suppression,
+// justification and grace periods are all decided here, for every heuristic.
+func (g *Graph) flag(node *Node, f Finding) bool {
+	v := g.verdict(node, f)
+	f.Rule, f.Justification = v.Rule, v.Justification
+	if v.Suppressed {
+		node.suppress(f)
+		return false
+	}
+	node.addFinding(f)
+	return true
+}
+
+// ApplySuppressor re-checks every recorded finding, e.g. after loading a
+// snapshot with a newer policy. Suppressed findings are removed and the node
+// verdicts, including justifications, re-derived. Returns the number of
+// findings removed.
+// Findings suppressed earlier stay suppressed.
+func (g *Graph) ApplySuppressor() int {
+	g.lockAll()
+	defer g.unlockAll()
+
+	removed := 0
+	g.each(func(node *Node) {
+		if len(node.Findings) == 0 {
+			return
+		}
+		kept := node.Findings[:0]
+		for _, f := range node.Findings {
+			v := g.verdict(node, f)
+			f.Rule, f.Justification = v.Rule, v.Justification
+			if v.Suppressed {
+				node.Suppressed = putFinding(node.Suppressed, f)
+				removed++
+				continue
+			}
+			kept = append(kept, f)
+		}
+		node.Findings = kept
+		node.derive()
+	})
+	return removed
+}
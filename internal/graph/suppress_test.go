@@ -0,0 +1,84 @@
+package graph
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestFlag_GracePeriod(t *testing.T) {
+	g := NewGraph()
+	g.AddNode("new", "AWS::EC2::Volume", map[string]interface{}{
+		"CreateTime": time.Now().Add(-time.Hour),
+		"Tags":       map[string]string{"cloudslash:ignore": "3d"},
+	})
+	g.AddNode("old", "AWS::EC2::Volume", map[string]interface{}{
+		"CreateTime": time.Now().Add(-96 * time.Hour),
+		"Tags":       map[string]string{"cloudslash:ignore": "3d"},
+	})
+
+	batch := g.NewBatch()
+	batch.AddFinding("new", Finding{Heuristic: "ZombieEBSHeuristic", Category: CategoryWaste, RiskScore: 90})
+	batch.AddFinding("old", Finding{Heuristic: "ZombieEBSHeuristic", Category: CategoryWaste, RiskScore: 90})
+	batch.AddFinding("missing", Finding{Heuristic: "ZombieEBSHeuristic", Category: CategoryWaste, RiskScore: 90})
+	batch.Flush()
+
+	if n := nodeByID(g, "new"); n.IsWaste || len(n.Suppressed) != 1 || n.Suppressed[0].Rule != TagRule {
+		t.Errorf("resource inside its grace period should be suppressed, got %+v", n)
+	}
+	if n := nodeByID(g, "old"); !n.IsWaste || len(n.Suppressed) != 0 {
+		t.Errorf("resource past its grace period should be flagged, got %+v", n)
+	}
+}
+
+func TestFlag_SuppressedReplacesFinding(t *testing.T) {
+	g := NewGraph()
+	g.AddNode("vol-1", "AWS::EC2::Volume", map[string]interface{}{
+		"Tags": map[string]string{"cloudslash:ignore": "cost<10"},
+	})
+
+	g.AddFinding("vol-1", Finding{Heuristic: "ZombieEBSHeuristic", Category: CategoryWaste, RiskScore: 90, MonthlySavings: 40})
+	g.AddFinding("vol-1", Finding{Heuristic: "ZombieEBSHeuristic", Category: CategoryWaste, RiskScore: 90, MonthlySavings: 4})
+
+	node := nodeByID(g, "vol-1")
+	if node.IsWaste || len(node.Findings) != 0 || len(node.Suppressed) != 1 {
+		t.Fatalf("re-run below the threshold should move the finding to Suppressed, got %+v / %+v", node.Findings, node.Suppressed)
+	}
+
+	path := filepath.Join(t.TempDir(), "scan.json")
+	if err := g.SaveSnapshot(path); err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := LoadSnapshot(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s := nodeByID(loaded, "vol-1").Suppressed; len(s) != 1 || s[0].Rule != TagRule || s[0].MonthlySavings != 4 {
+		t.Errorf("suppressed findings should survive a snapshot round trip, got %+v", s)
+	}
+
+	g.AddFinding("vol-1", Finding{Heuristic: "ZombieEBSHeuristic", Category: CategoryWaste, RiskScore: 90, MonthlySavings: 40})
+	if !node.IsWaste || len(node.Suppressed) != 0 {
+		t.Errorf("recorded finding should clear the suppressed one, got %+v", node.Suppressed)
+	}
+}
+
+func TestFlag_JustificationFollowsFindings(t *testing.T) {
+	g := NewGraph()
+	g.AddNode("vol-1", "AWS::EC2::Volume", map[string]interface{}{
+		"Tags": map[string]string{"cloudslash:ignore": "justified:dr"},
+	})
+	g.AddFinding("vol-1", Finding{Heuristic: "ZombieEBSHeuristic", Category: CategoryWaste, RiskScore: 90})
+
+	node := nodeByID(g, "vol-1")
+	if !node.Justified || node.Justification != "dr" || node.Findings[0].Justification != "dr" {
+		t.Fatalf("tag justification not recorded: %+v", node)
+	}
+
+	// Removing the tag and re-checking clears the justification again.
+	node.Properties["Tags"] = map[string]string{}
+	g.ApplySuppressor()
+	if node.Justified || node.Justification != "" || !node.IsWaste {
+		t.Errorf("justification should be re-derived from the findings, got justified=%v %q", node.Justified, node.Justification)
+	}
+}
@@ -0,0 +1,53 @@
+package graph
+
+// View is a view of the whole graph for analysis. Its methods do not lock:
+// the view is only valid inside Graph.Read or Graph.Write, which hold the
+// locks. Nodes returned by a Read view must not be modified.
+type View struct {
+	g *Graph
+}
+
+// Read calls fn with a view of the graph while holding every read lock.
+func (g *Graph) Read(fn func(v *View)) {
+	g.rlockAll()
+	defer g.runlockAll()
+
+	fn(&View{g: g})
+}
+
+// Write calls fn with a view of the graph while holding every write lock, so
+// fn may modify the nodes it is given. Use AddNode, AddTypedEdge or a Batch
+// to add nodes and edges.
+func (g *Graph) Write(fn func(v *View)) {
+	g.lockAll()
+	defer g.unlockAll()
+
+	fn(&View{g: g})
+}
+
+// Node returns the node with the given ID.
+func (v *View) Node(id string) (*Node, bool) {
+	return v.g.node(id)
+}
+
+// NodesByType returns the nodes of the given resource types in no particular order.
+func (v *View) NodesByType(types ...string) []*Node {
+	return v.g.nodesByType(types...)
+}
+
+// Each calls fn for every node, in no particular order.
+func (v *View) Each(fn func(n *Node)) {
+	v.g.each(fn)
+}
+
+// Len returns the number of nodes.
+func (v *View) Len() int { return v.g.count() }
+
+// Edges returns the node's forward (downstream) edges.
+func (v *View) Edges(id string) []Edge { return v.g.edgesOf(id) }
+
+// ReverseEdges returns the node's reverse (upstream) edges.
+func (v *View) ReverseEdges(id string) []Edge { return v.g.reverseOf(id) }
+
+// Metadata returns the scan provenance.
+func (v *View) Metadata() ScanMetadata { return v.g.Metadata }
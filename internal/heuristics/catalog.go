@@ -0,0 +1,34 @@
+package heuristics
+
+// Catalog returns a new instance of every built-in heuristic, without AWS or
+// Kubernetes clients. It lists what can be configured and is not meant to run.
+func Catalog() []WeightedHeuristic {
+	return []WeightedHeuristic{
+		&ZombieEBSHeuristic{},
+		&ElasticIPHeuristic{},
+		&S3MultipartHeuristic{},
+		&NATGatewayHeuristic{},
+		&RDSHeuristic{},
+		&RDSRightsizingHeuristic{},
+		&RDSSnapshotHeuristic{},
+		&ELBHeuristic{},
+		&UnderutilizedInstanceHeuristic{},
+		&StoppedInstanceHeuristic{},
+		&ModernizationHeuristic{},
+		&ProvisionedIOPSHeuristic{},
+		&GravitonHeuristic{},
+		&TagComplianceHeuristic{},
+		&IAMHeuristic{},
+		&LogHoardersHeuristic{},
+		&FossilAMIHeuristic{},
+		&ZombieEKSHeuristic{},
+		&GhostNodeGroupHeuristic{},
+		&AbandonedFargateHeuristic{},
+		&SnapshotChildrenHeuristic{},
+		&UnusedSecurityGroupHeuristic{},
+		&EmptyVPCHeuristic{},
+		&EmptySubnetHeuristic{},
+		&OrphanedENIHeuristic{},
+		&IdleVPCEndpointHeuristic{},
+	}
+}
@@ -0,0 +1,30 @@
+package heuristics
+
+import (
+	"context"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatch/types"
+)
+
+// PricingClient is the part of pricing.Client the heuristics use. Prices are
+// monthly, in USD.
+type PricingClient interface {
+	GetEBSPrice(ctx context.Context, region, volumeType string, sizeGB int) (float64, error)
+	GetGP3PerformancePrice(ctx context.Context, region string) (perIOPS, perMiBps float64, err error)
+	GetProvisionedIOPSPrice(ctx context.Context, region, volumeType string) (float64, error)
+	GetEC2InstancePrice(ctx context.Context, region, instanceType string) (float64, error)
+	GetRDSInstancePrice(ctx context.Context, region, instanceClass, engine string, multiAZ bool) (float64, error)
+	GetRDSStoragePrice(ctx context.Context, region, storageType string, multiAZ bool) (perGB, perIOPS float64, err error)
+	GetNATGatewayPrice(ctx context.Context, region string) (float64, error)
+	GetVPCEndpointPrice(ctx context.Context, region string) (float64, error)
+	GetEIPPrice(ctx context.Context, region string) (float64, error)
+}
+
+// CloudWatchClient is the part of aws.CloudWatchClient the heuristics use.
+type CloudWatchClient interface {
+	GetMetricMax(ctx context.Context, namespace, metricName string, dimensions []types.Dimension, startTime, endTime time.Time) (float64, error)
+	GetMetricSum(ctx context.Context, namespace, metricName string, dimensions []types.Dimension, startTime, endTime time.Time) (float64, error)
+	GetPeakRate(ctx context.Context, namespace string, metricNames []string, dimensions []types.Dimension, startTime, endTime time.Time) (rate float64, period time.Duration, err error)
+	GetMetricPercentile(ctx context.Context, namespace, metricName string, dimensions []types.Dimension, startTime, endTime time.Time, percentile float64) (float64, error)
+}
@@ -0,0 +1,145 @@
+package heuristics
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatch/types"
+)
+
+// fakePricing prices by volume type, instance type or class and ignores the
+// region. A type without an entry fails, as one missing from the Price List
+// would.
+type fakePricing struct {
+	ebs      map[string]float64 // Per GB-month, by volume type
+	gp3IOPS  float64            // Per IOPS-month above the gp3 baseline
+	gp3MiBps float64            // Per MiB/s-month above the gp3 baseline
+	piops    map[string]float64 // Per provisioned IOPS-month, by volume type
+	ec2      map[string]float64 // Per month, by instance type
+	rds      map[string]float64 // Per month Single-AZ, by class; Multi-AZ doubles it
+	rdsGB    map[string]float64 // Per GB-month Single-AZ, by storage type
+	rdsIOPS  map[string]float64 // Per provisioned IOPS-month Single-AZ, by storage type
+	endpoint float64            // Per month and Availability Zone
+}
+
+// testPrices returns US East list prices for the types the tests use.
+func testPrices() *fakePricing {
+	return &fakePricing{
+		ebs:      map[string]float64{"gp2": 0.10, "gp3": 0.08, "io1": 0.125, "io2": 0.125},
+		gp3IOPS:  0.005,
+		gp3MiBps: 0.04,
+		piops:    map[string]float64{"io1": 0.065, "io2": 0.065},
+		ec2: map[string]float64{
+			"m4.large": 73.00, "m6i.large": 70.08,
+			"c4.2xlarge": 290.35, "c6i.2xlarge": 248.20,
+			"r4.large": 97.09,
+			"m5.large": 70.08, "m7g.large": 59.57,
+			"c5.xlarge": 124.10, "c7g.xlarge": 105.85,
+			"t3.micro": 7.59, "t4g.micro": 6.13,
+		},
+		rds: map[string]float64{
+			"db.m5.large": 124.10, "db.m7g.large": 109.50,
+			"db.r5.2xlarge": 730.00, "db.r5.xlarge": 365.00,
+		},
+		rdsGB:    map[string]float64{"gp2": 0.115, "gp3": 0.115, "io1": 0.125, "io2": 0.125},
+		rdsIOPS:  map[string]float64{"io1": 0.10, "io2": 0.10},
+		endpoint: 7.30,
+	}
+}
+
+func lookup(prices map[string]float64, kind, key string) (float64, error) {
+	p, ok := prices[key]
+	if !ok {
+		return 0, fmt.Errorf("no %s price for %q", kind, key)
+	}
+	return p, nil
+}
+
+func (p *fakePricing) GetEBSPrice(ctx context.Context, region, volumeType string, sizeGB int) (float64, error) {
+	perGB, err := lookup(p.ebs, "ebs", volumeType)
+	return perGB * float64(sizeGB), err
+}
+
+func (p *fakePricing) GetGP3PerformancePrice(ctx context.Context, region string) (float64, float64, error) {
+	return p.gp3IOPS, p.gp3MiBps, nil
+}
+
+func (p *fakePricing) GetProvisionedIOPSPrice(ctx context.Context, region, volumeType string) (float64, error) {
+	return lookup(p.piops, "iops", volumeType)
+}
+
+func (p *fakePricing) GetEC2InstancePrice(ctx context.Context, region, instanceType string) (float64, error) {
+	return lookup(p.ec2, "ec2", instanceType)
+}
+
+func (p *fakePricing) GetRDSInstancePrice(ctx context.Context, region, instanceClass, engine string, multiAZ bool) (float64, error) {
+	cost, err := lookup(p.rds, "rds", instanceClass)
+	if multiAZ {
+		cost *= 2
+	}
+	return cost, err
+}
+
+func (p *fakePricing) GetRDSStoragePrice(ctx context.Context, region, storageType string, multiAZ bool) (float64, float64, error) {
+	perGB, err := lookup(p.rdsGB, "rds storage", storageType)
+	perIOPS := p.rdsIOPS[storageType]
+	if multiAZ {
+		perGB, perIOPS = perGB*2, perIOPS*2
+	}
+	return perGB, perIOPS, err
+}
+
+func (p *fakePricing) GetNATGatewayPrice(ctx context.Context, region string) (float64, error) {
+	return 32.85, nil
+}
+
+func (p *fakePricing) GetVPCEndpointPrice(ctx context.Context, region string) (float64, error) {
+	return p.endpoint, nil
+}
+
+func (p *fakePricing) GetEIPPrice(ctx context.Context, region string) (float64, error) {
+	return 3.65, nil
+}
+
+// fakeCloudWatch answers every statistic of a metric with the same value,
+// whatever the dimensions. A metric without a value fails, as one without
+// datapoints would.
+type fakeCloudWatch map[string]float64
+
+func (cw fakeCloudWatch) value(metricName string) (float64, error) {
+	v, ok := cw[metricName]
+	if !ok {
+		return 0, fmt.Errorf("no datapoints for %s", metricName)
+	}
+	return v, nil
+}
+
+func (cw fakeCloudWatch) GetMetricMax(ctx context.Context, namespace, metricName string, dimensions []types.Dimension, startTime, endTime time.Time) (float64, error) {
+	return cw.value(metricName)
+}
+
+func (cw fakeCloudWatch) GetMetricSum(ctx context.Context, namespace, metricName string, dimensions []types.Dimension, startTime, endTime time.Time) (float64, error) {
+	return cw.value(metricName)
+}
+
+// GetPeakRate adds up the metrics, as if they all peaked in the same period.
+func (cw fakeCloudWatch) GetPeakRate(ctx context.Context, namespace string, metricNames []string, dimensions []types.Dimension, startTime, endTime time.Time) (float64, time.Duration, error) {
+	var rate float64
+	for _, name := range metricNames {
+		v, err := cw.value(name)
+		if err != nil {
+			return 0, 0, err
+		}
+		rate += v
+	}
+	return rate, 5 * time.Minute, nil
+}
+
+func (cw fakeCloudWatch) GetMetricPercentile(ctx context.Context, namespace, metricName string, dimensions []types.Dimension, startTime, endTime time.Time, percentile float64) (float64, error) {
+	return cw.value(metricName)
+}
+
+// near reports whether two dollar amounts agree to the cent.
+func near(a, b float64) bool { return math.Abs(a-b) < 0.005 }
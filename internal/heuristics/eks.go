@@ -7,15 +7,31 @@ import (
 	"time"
 
 	"github.com/DrSkyle/cloudslash/internal/graph"
+	"github.com/DrSkyle/cloudslash/internal/resource"
 )
 
-type ZombieEKSHeuristic struct{}
+type ZombieEKSHeuristic struct {
+	Tunable
+}
+
+// ZombieEKSConfig holds the ZombieEKSHeuristic thresholds.
+type ZombieEKSConfig struct {
+	MinAge Duration `yaml:"min_age"` // Only flag clusters created longer ago than this
+}
+
+func (h *ZombieEKSHeuristic) Defaults() interface{} {
+	return &ZombieEKSConfig{MinAge: Days(7)}
+}
 
 func (h *ZombieEKSHeuristic) Name() string { return "ZombieEKSHeuristic" }
 
-func (h *ZombieEKSHeuristic) Run(ctx context.Context, g *graph.Graph) error {
-	g.Mu.Lock()
-	defer g.Mu.Unlock()
+func (h *ZombieEKSHeuristic) Flags() []string { return []string{"AWS::EKS::Cluster"} }
+
+func (h *ZombieEKSHeuristic) Analyze(ctx context.Context, v *graph.View, cov *Coverage) ([]HeuristicResult, error) {
+	var results []HeuristicResult
+
+	lbNodes := v.NodesByType("AWS::ElasticLoadBalancingV2::LoadBalancer", "AWS::ElasticLoadBalancing::LoadBalancer")
+	clusters := v.NodesByType("AWS::EKS::Cluster")
 
 	// 1. Find all ELBs first to avoid O(N*M) lookups inside the EKS loop
 	type elbInfo struct {
@@ -23,30 +39,28 @@ func (h *ZombieEKSHeuristic) Run(ctx context.Context, g *graph.Graph) error {
 		Tags map[string]string
 	}
 	var elbs []elbInfo
-	for _, node := range g.Nodes {
-		if node.Type == "AWS::ElasticLoadBalancingV2::LoadBalancer" || node.Type == "AWS::ElasticLoadBalancing::LoadBalancer" {
-			tags, _ := node.Properties["Tags"].(map[string]string)
-			elbs = append(elbs, elbInfo{Arn: node.ID, Tags: tags})
-		}
+	for _, node := range lbNodes {
+		tags, _ := node.Properties["Tags"].(map[string]string)
+		elbs = append(elbs, elbInfo{Arn: node.ID, Tags: tags})
 	}
 
-	for _, node := range g.Nodes {
-		if node.Type != "AWS::EKS::Cluster" {
-			continue
-		}
-
+	cov.Examine(len(clusters))
+	for _, node := range clusters {
 		// 1. Status Check
 		status, _ := node.Properties["Status"].(string)
 		if status != "ACTIVE" {
 			continue
 		}
 
-		// 2. Age Check (> 7 Days)
+		// 2. Age Check (> MinAge)
+		cfg := h.Defaults().(*ZombieEKSConfig)
+		h.Settings.Resolve(h.Name(), node, cfg)
+
 		createdAt, ok := node.Properties["CreatedAt"].(time.Time)
 		if !ok {
 			continue
 		}
-		if time.Since(createdAt) < 7*24*time.Hour {
+		if time.Since(createdAt) < cfg.MinAge.Std() {
 			continue
 		}
 
@@ -63,19 +77,13 @@ func (h *ZombieEKSHeuristic) Run(ctx context.Context, g *graph.Graph) error {
 
 		if !hasManaged && !hasFargate && !hasSelf {
 			// ZOMBIE IDENTIFIED
-			node.IsWaste = true
-			node.RiskScore = 90 // High confidence, pure waste
-			node.Cost = 0.10 * 730 // ~$73.00/month
-			
-			reason := "Zombie Control Plane: Active EKS cluster with zero compute nodes for > 7 days."
+			reason := fmt.Sprintf("Zombie Control Plane: Active EKS cluster with zero compute nodes for > %s.", cfg.MinAge)
+			action := "Delete the EKS cluster"
+			evidence := []string{"No managed, Fargate or self-managed nodes", "Created " + createdAt.Format("2006-01-02")}
 
 			// 5. Orphaned ELB Check
-			clusterName := ""
             // Extract cluster name from ARN: arn:aws:eks:region:account:cluster/ClusterName
-			parts := strings.Split(node.ID, "/")
-			if len(parts) > 0 {
-				clusterName = parts[len(parts)-1]
-			}
+			clusterName := resource.ResourceID(node.ID)
 
 			if clusterName != "" {
 				var orphanedELBs []string
@@ -88,7 +96,7 @@ func (h *ZombieEKSHeuristic) Run(ctx context.Context, g *graph.Graph) error {
 				}
 
 				if len(orphanedELBs) > 0 {
-					reason += fmt.Sprintf("\nDeleting this cluster will leave %d Orphaned ELBs behind. Here is the CLI command to delete them too:\n", len(orphanedELBs))
+					reason += fmt.Sprintf(" Deleting it will leave %d Orphaned ELBs behind.", len(orphanedELBs))
 					
                     // Construct CLI command
 					// aws elbv2 delete-load-balancer --load-balancer-arn <ARN>
@@ -96,13 +104,24 @@ func (h *ZombieEKSHeuristic) Run(ctx context.Context, g *graph.Graph) error {
                     for _, arn := range orphanedELBs {
                         cmdLines = append(cmdLines, fmt.Sprintf("aws elbv2 delete-load-balancer --load-balancer-arn %s", arn))
                     }
-                    reason += strings.Join(cmdLines, "\n")
+                    action += ", then its orphaned ELBs:\n" + strings.Join(cmdLines, "\n")
+                    evidence = append(evidence, orphanedELBs...)
 				}
 			}
 
-			node.Properties["Reason"] = reason
+			results = append(results, HeuristicResult{
+				ResourceID:     node.ID,
+				Category:       graph.CategoryWaste,
+				Confidence:     0.9, // High confidence, pure waste
+				RiskScore:      90,
+				MonthlySavings: 0.10 * 730, // ~$73.00/month
+				Reason:         reason,
+				Evidence:       evidence,
+				Action:         action,
+				Thresholds:     Thresholds(cfg),
+			})
 		}
 	}
 
-	return nil
+	return results, nil
 }
@@ -45,16 +45,14 @@ func TestZombieEKSHeuristic_WithOrphanedELBs(t *testing.T) {
 	})
 
 	// 2. Run Heuristic
-	err := heuristic.Run(ctx, g)
+	err := engineRun(heuristic)(ctx, g)
 	if err != nil {
 		t.Fatalf("Heuristic run failed: %v", err)
 	}
 
 	// 3. Assertions
-	g.Mu.RLock()
-	defer g.Mu.RUnlock()
 
-	clusterNode, ok := g.Nodes[clusterArn]
+	clusterNode, ok := g.Node(clusterArn)
 	if !ok {
 		t.Fatal("Cluster node not found")
 	}
@@ -63,18 +61,21 @@ func TestZombieEKSHeuristic_WithOrphanedELBs(t *testing.T) {
 		t.Error("Expected cluster to be marked as waste (Zombie)")
 	}
 
-	reason, _ := clusterNode.Properties["Reason"].(string)
+	finding, ok := clusterNode.FindingBy("ZombieEKSHeuristic")
+	if !ok {
+		t.Fatalf("Expected a ZombieEKSHeuristic finding, got: %+v", clusterNode.Findings)
+	}
 	
 	// Check for Orphaned ELB Logic
-	if !strings.Contains(reason, "Orphaned ELBs") {
-		t.Errorf("Expected reason to contain 'Orphaned ELBs', got: %s", reason)
+	if !strings.Contains(finding.Reason, "Orphaned ELBs") {
+		t.Errorf("Expected reason to contain 'Orphaned ELBs', got: %s", finding.Reason)
 	}
 
-	if !strings.Contains(reason, orphanedAlbArn) {
-		t.Errorf("Expected reason to contain orphaned ELB ARN, got: %s", reason)
+	if !strings.Contains(finding.Action, orphanedAlbArn) {
+		t.Errorf("Expected action to contain orphaned ELB ARN, got: %s", finding.Action)
 	}
 
-	if strings.Contains(reason, normalAlbArn) {
-		t.Errorf("Expected reason NOT to contain normal ELB ARN, got: %s", reason)
+	if strings.Contains(finding.Action, normalAlbArn) {
+		t.Errorf("Expected action NOT to contain normal ELB ARN, got: %s", finding.Action)
 	}
 }
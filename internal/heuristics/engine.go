@@ -2,8 +2,10 @@ package heuristics
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"sync"
+	"time"
 
 	"github.com/DrSkyle/cloudslash/internal/graph"
 )
@@ -11,23 +13,83 @@ import (
 // WasteConfidence represents the probability (0.0-1.0) that a resource is waste.
 type WasteConfidence float64
 
-// HeuristicResult contains the analysis outcome.
+// HeuristicResult is a heuristic's verdict on one resource. The engine records
+// it as a graph.Finding named after the heuristic.
 type HeuristicResult struct {
-	IsWaste    bool
-	Confidence WasteConfidence
-	RiskScore  int // 0-100 impact of deletion (Safety)
-	Reason     string
+	ResourceID     string
+	Category       graph.Category // Defaults to waste
+	Confidence     WasteConfidence
+	RiskScore      int // 0-100 impact of deletion (Safety)
+	Reason         string
+	Evidence       []string // Observations behind the verdict, e.g. "MaxConns=0 over 7d"
+	MonthlySavings float64
+	Action         string
+	Thresholds     map[string]string // Settings it was judged against, see Thresholds
 }
 
-// WeightedHeuristic defines a sophisticated analyzer.
+// WeightedHeuristic defines a sophisticated analyzer. Analyze reads the graph
+// through a read-only view and reports results; it never writes to the graph.
+// It records the candidates it examined, and any it could not evaluate, on cov.
 type WeightedHeuristic interface {
 	Name() string
-	Run(ctx context.Context, g *graph.Graph) error
+	Analyze(ctx context.Context, v *graph.View, cov *Coverage) ([]HeuristicResult, error)
+}
+
+// Bounded is implemented by heuristics that must finish within a time limit,
+// such as plugins. The engine cancels their context at the deadline.
+type Bounded interface {
+	Timeout() time.Duration
+}
+
+// Coverage records what one heuristic examined and what it had to skip, so
+// that an empty result can be told apart from one that could not look.
+type Coverage struct {
+	examined int
+	skipped  int
+	skips    []graph.Skip
+}
+
+// Examine counts n candidate resources as examined.
+func (c *Coverage) Examine(n int) {
+	c.examined += n
+}
+
+// Skip records a candidate the heuristic could not evaluate, e.g. because
+// its metrics could not be fetched.
+func (c *Coverage) Skip(id, reason string) {
+	c.skipped++
+	if len(c.skips) < graph.MaxSkips {
+		c.skips = append(c.skips, graph.Skip{ResourceID: id, Reason: reason})
+	}
+}
+
+// run summarizes the coverage as a graph.HeuristicRun.
+func (c *Coverage) run(name string, d time.Duration, findings int, err error) graph.HeuristicRun {
+	r := graph.HeuristicRun{
+		Heuristic: name,
+		Status:    graph.RunOK,
+		Duration:  d,
+		Examined:  c.examined,
+		Findings:  findings,
+		Skipped:   c.skipped,
+		Skips:     c.skips,
+	}
+	if c.skipped > 0 {
+		r.Status = graph.RunPartial
+	}
+	if err != nil {
+		r.Status = graph.RunFailed
+		r.Error = err.Error()
+	}
+	return r
 }
 
 // Engine orchestrates the heuristic analysis.
 type Engine struct {
+	Settings *Settings // Optional threshold overrides, handed to Configurable heuristics
+
 	heuristics []WeightedHeuristic
+	waves      [][]int // Indexes into heuristics, see schedule
 }
 
 // NewEngine creates a new Heuristic Engine.
@@ -37,35 +99,124 @@ func NewEngine() *Engine {
 	}
 }
 
-// Register adds a heuristic to the engine.
-func (e *Engine) Register(h WeightedHeuristic) {
-	e.heuristics = append(e.heuristics, h)
+// Register adds a heuristic to the engine. It fails, leaving the engine
+// unchanged, if the heuristic's dependencies would form a cycle.
+func (e *Engine) Register(h WeightedHeuristic) error {
+	hs := append(e.heuristics[:len(e.heuristics):len(e.heuristics)], h)
+	waves, err := schedule(hs)
+	if err != nil {
+		return err
+	}
+	e.heuristics = hs
+	e.waves = waves
+	return nil
 }
 
-// Run executes all registered heuristics concurrently.
+// Run executes the registered heuristics in dependency waves. The heuristics
+// of a wave run concurrently against one read-only view, then their results
+// are applied in a single batch, so the next wave sees them. Results from a
+// heuristic that fails are still applied; a heuristic that panics or overruns
+// its time limit (see Bounded) fails without affecting the others. Every
+// heuristic's status, duration and coverage is recorded in
+// g.Metadata.Heuristics, and the errors of all failed heuristics are returned joined.
 func (e *Engine) Run(ctx context.Context, g *graph.Graph) error {
-	var wg sync.WaitGroup
-	errChan := make(chan error, len(e.heuristics))
-
-	for _, h := range e.heuristics {
-		wg.Add(1)
-		go func(h WeightedHeuristic) {
-			defer wg.Done()
-			if err := h.Run(ctx, g); err != nil {
-				errChan <- fmt.Errorf("heuristic %s failed: %w", h.Name(), err)
+	results := make([][]HeuristicResult, len(e.heuristics))
+	runs := make([]graph.HeuristicRun, len(e.heuristics))
+	errs := make([]error, len(e.heuristics))
+
+	if e.Settings != nil {
+		for _, h := range e.heuristics {
+			if c, ok := h.(Configurable); ok {
+				c.Configure(e.Settings)
 			}
-		}(h)
+		}
 	}
 
-	wg.Wait()
-	close(errChan)
+	for _, wave := range e.waves {
+		g.Read(func(v *graph.View) {
+			var wg sync.WaitGroup
+			for _, i := range wave {
+				wg.Add(1)
+				go func(i int, h WeightedHeuristic) {
+					defer wg.Done()
+					cov := &Coverage{}
+					start := time.Now()
+					res, err := analyze(ctx, h, v, cov)
+					results[i] = merge(res)
+					runs[i] = cov.run(h.Name(), time.Since(start), len(results[i]), err)
+					if err != nil {
+						errs[i] = fmt.Errorf("heuristic %s failed: %w", h.Name(), err)
+					}
+				}(i, e.heuristics[i])
+			}
+			wg.Wait()
+		})
 
-	// Collect errors (fairly basic error handling for now)
-	for err := range errChan {
-		// Log error but don't stop entire engine?
-		// For now, let's just print to stdout or return first error
-		return err
+		batch := g.NewBatch()
+		for _, i := range wave {
+			for _, r := range results[i] {
+				batch.AddFinding(r.ResourceID, r.Finding(e.heuristics[i].Name()))
+			}
+		}
+		batch.Flush()
 	}
+	g.AddHeuristicRuns(runs...)
 
-	return nil
+	return errors.Join(errs...)
+}
+
+// analyze runs h, enforcing its time limit and turning a panic into an error.
+func analyze(ctx context.Context, h WeightedHeuristic, v *graph.View, cov *Coverage) (res []HeuristicResult, err error) {
+	if b, ok := h.(Bounded); ok && b.Timeout() > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, b.Timeout())
+		defer cancel()
+		defer func() {
+			if err != nil && errors.Is(ctx.Err(), context.DeadlineExceeded) {
+				err = fmt.Errorf("timed out after %s: %w", b.Timeout(), err)
+			}
+		}()
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			res, err = nil, fmt.Errorf("panic: %v", r)
+		}
+	}()
+	return h.Analyze(ctx, v, cov)
+}
+
+// merge keeps one result per resource, the most confident one, in first-seen order.
+func merge(results []HeuristicResult) []HeuristicResult {
+	pos := make(map[string]int, len(results))
+	merged := results[:0:0]
+	for _, r := range results {
+		if i, ok := pos[r.ResourceID]; ok {
+			if r.Confidence > merged[i].Confidence {
+				merged[i] = r
+			}
+			continue
+		}
+		pos[r.ResourceID] = len(merged)
+		merged = append(merged, r)
+	}
+	return merged
+}
+
+// Finding converts the result into the finding recorded on the graph.
+func (r HeuristicResult) Finding(heuristic string) graph.Finding {
+	category := r.Category
+	if category == "" {
+		category = graph.CategoryWaste
+	}
+	return graph.Finding{
+		Heuristic:      heuristic,
+		Category:       category,
+		Confidence:     float64(r.Confidence),
+		RiskScore:      r.RiskScore,
+		MonthlySavings: r.MonthlySavings,
+		Reason:         r.Reason,
+		Evidence:       r.Evidence,
+		Action:         r.Action,
+		Thresholds:     r.Thresholds,
+	}
 }
@@ -0,0 +1,251 @@
+package heuristics
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/DrSkyle/cloudslash/internal/graph"
+)
+
+// fixedHeuristic reports canned results, optionally skipping resources and
+// failing afterwards.
+type fixedHeuristic struct {
+	name     string
+	examined int
+	skip     []string
+	results  []HeuristicResult
+	err      error
+}
+
+func (h *fixedHeuristic) Name() string { return h.name }
+
+func (h *fixedHeuristic) Analyze(ctx context.Context, v *graph.View, cov *Coverage) ([]HeuristicResult, error) {
+	cov.Examine(h.examined)
+	for _, id := range h.skip {
+		cov.Skip(id, "metric fetch failed: throttled")
+	}
+	return h.results, h.err
+}
+
+func TestEngine_MergesAndApplies(t *testing.T) {
+	g := graph.NewGraph()
+	g.AddNode("vol-1", "AWS::EC2::Volume", nil)
+	g.AddNode("vol-2", "AWS::EC2::Volume", nil)
+
+	e := NewEngine()
+	e.Register(&fixedHeuristic{name: "A", results: []HeuristicResult{
+		{ResourceID: "vol-1", Confidence: 0.5, RiskScore: 50, Reason: "weak"},
+		{ResourceID: "vol-1", Confidence: 0.9, RiskScore: 70, Reason: "strong", Evidence: []string{"State: available"}},
+	}})
+	e.Register(&fixedHeuristic{name: "B", err: errors.New("boom"), results: []HeuristicResult{
+		{ResourceID: "vol-2", Category: graph.CategorySecurity, Confidence: 1, RiskScore: 95},
+	}})
+
+	if err := e.Run(context.Background(), g); err == nil {
+		t.Error("expected the failing heuristic's error")
+	}
+
+	f, ok := nodeByID(g, "vol-1").FindingBy("A")
+	if !ok || f.Reason != "strong" || f.Confidence != 0.9 || f.Category != graph.CategoryWaste || len(f.Evidence) != 1 {
+		t.Errorf("expected the most confident result as a waste finding, got %+v", f)
+	}
+	if len(nodeByID(g, "vol-1").Findings) != 1 {
+		t.Errorf("duplicate results should merge, got %+v", nodeByID(g, "vol-1").Findings)
+	}
+	if f, ok := nodeByID(g, "vol-2").FindingBy("B"); !ok || f.Category != graph.CategorySecurity {
+		t.Errorf("results from a failing heuristic should still apply, got %+v", f)
+	}
+}
+
+// Heuristics can be tested on their results alone, without touching the graph lock.
+func TestZombieEBSHeuristic_Analyze(t *testing.T) {
+	g := graph.NewGraph()
+	g.AddNode("arn:aws:ec2:us-east-1:123456789012:volume/vol-1", "AWS::EC2::Volume", map[string]interface{}{"State": "available"})
+	g.AddNode("arn:aws:ec2:us-east-1:123456789012:volume/vol-2", "AWS::EC2::Volume", map[string]interface{}{"State": "in-use"})
+
+	var results []HeuristicResult
+	var err error
+	g.Read(func(v *graph.View) {
+		results, err = (&ZombieEBSHeuristic{}).Analyze(context.Background(), v, &Coverage{})
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != 1 || results[0].ResourceID != "arn:aws:ec2:us-east-1:123456789012:volume/vol-1" || results[0].Confidence != 0.9 {
+		t.Fatalf("unexpected results %+v", results)
+	}
+	if nodeByID(g, "arn:aws:ec2:us-east-1:123456789012:volume/vol-1").IsWaste {
+		t.Error("Analyze must not write to the graph")
+	}
+}
+
+func TestEngine_RecordsCoverage(t *testing.T) {
+	g := graph.NewGraph()
+	g.AddNode("vol-1", "AWS::EC2::Volume", nil)
+
+	e := NewEngine()
+	e.Register(&fixedHeuristic{name: "OK", examined: 3, results: []HeuristicResult{{ResourceID: "vol-1", Confidence: 1}}})
+	e.Register(&fixedHeuristic{name: "Partial", examined: 2, skip: []string{"db-1"}})
+	e.Register(&fixedHeuristic{name: "Bad1", err: errors.New("access denied")})
+	e.Register(&fixedHeuristic{name: "Bad2", err: errors.New("throttled")})
+
+	err := e.Run(context.Background(), g)
+	if err == nil || !strings.Contains(err.Error(), "access denied") || !strings.Contains(err.Error(), "throttled") {
+		t.Fatalf("expected every heuristic error, got %v", err)
+	}
+
+	runs := g.HeuristicRuns()
+	if len(runs) != 4 {
+		t.Fatalf("expected a run per heuristic, got %+v", runs)
+	}
+	want := []struct {
+		status             string
+		examined, findings int
+		skipped            int
+	}{
+		{graph.RunOK, 3, 1, 0},
+		{graph.RunPartial, 2, 0, 1},
+		{graph.RunFailed, 0, 0, 0},
+		{graph.RunFailed, 0, 0, 0},
+	}
+	for i, w := range want {
+		r := runs[i]
+		if r.Status != w.status || r.Examined != w.examined || r.Findings != w.findings || r.Skipped != w.skipped {
+			t.Errorf("run %s: got %+v, want %+v", r.Heuristic, r, w)
+		}
+	}
+	if s := runs[1].Skips; len(s) != 1 || s[0].ResourceID != "db-1" || s[0].Reason == "" {
+		t.Errorf("expected the skipped resource with its reason, got %+v", s)
+	}
+	if runs[2].Error == "" {
+		t.Error("failed run should record its error")
+	}
+}
+
+func TestCoverage_CapsSkips(t *testing.T) {
+	cov := &Coverage{}
+	for i := 0; i < graph.MaxSkips+5; i++ {
+		cov.Skip("id", "reason")
+	}
+	r := cov.run("H", 0, 0, nil)
+	if r.Skipped != graph.MaxSkips+5 || len(r.Skips) != graph.MaxSkips {
+		t.Errorf("expected %d skips counted and %d kept, got %d and %d", graph.MaxSkips+5, graph.MaxSkips, r.Skipped, len(r.Skips))
+	}
+}
+
+// probeHeuristic declares dependencies and calls analyze with the view it is given.
+type probeHeuristic struct {
+	name    string
+	deps    Dependencies
+	flags   []string
+	analyze func(v *graph.View) []HeuristicResult
+}
+
+func (h *probeHeuristic) Name() string            { return h.name }
+func (h *probeHeuristic) DependsOn() Dependencies { return h.deps }
+func (h *probeHeuristic) Flags() []string         { return h.flags }
+func (h *probeHeuristic) Analyze(ctx context.Context, v *graph.View, cov *Coverage) ([]HeuristicResult, error) {
+	if h.analyze == nil {
+		return nil, nil
+	}
+	return h.analyze(v), nil
+}
+
+func TestEngine_RunsDependentsInLaterWaves(t *testing.T) {
+	g := graph.NewGraph()
+	g.AddNode("vol-1", "AWS::EC2::Volume", nil)
+	g.AddNode("snap-1", "AWS::EC2::Snapshot", nil)
+
+	seen := func(v *graph.View) bool {
+		n, _ := v.Node("vol-1")
+		return n.IsWaste
+	}
+	var byName, byType bool
+
+	e := NewEngine()
+	// Registered before its dependency: order of registration must not matter.
+	e.Register(&probeHeuristic{name: "ByName", deps: Dependencies{Heuristics: []string{"Volumes"}}, flags: []string{"AWS::EC2::Snapshot"},
+		analyze: func(v *graph.View) []HeuristicResult { byName = seen(v); return nil }})
+	e.Register(&probeHeuristic{name: "Volumes", flags: []string{"AWS::EC2::Volume"},
+		analyze: func(v *graph.View) []HeuristicResult { return []HeuristicResult{{ResourceID: "vol-1", Confidence: 1}} }})
+	e.Register(&probeHeuristic{name: "ByType", deps: Dependencies{Types: []string{"AWS::EC2::Volume"}}, flags: []string{"AWS::EC2::Snapshot"},
+		analyze: func(v *graph.View) []HeuristicResult { byType = seen(v); return nil }})
+	e.Register(&probeHeuristic{name: "Unrelated", flags: []string{"AWS::S3::MultipartUpload"}})
+	e.Register(&probeHeuristic{name: "Optional", deps: Dependencies{Heuristics: []string{"NotRegistered"}}})
+
+	if len(e.waves) != 2 || len(e.waves[0]) != 3 || len(e.waves[1]) != 2 {
+		t.Fatalf("expected independent heuristics in one wave and dependents in the next, got %v", e.waves)
+	}
+	if err := e.Run(context.Background(), g); err != nil {
+		t.Fatal(err)
+	}
+	if !byName || !byType {
+		t.Errorf("dependents should see their dependencies' findings: by name %v, by type %v", byName, byType)
+	}
+	if runs := g.HeuristicRuns(); len(runs) != 5 || runs[0].Heuristic != "ByName" {
+		t.Errorf("expected a run per heuristic in registration order, got %+v", runs)
+	}
+}
+
+func TestEngine_RejectsDependencyCycles(t *testing.T) {
+	e := NewEngine()
+	if err := e.Register(&probeHeuristic{name: "A", deps: Dependencies{Heuristics: []string{"B"}}}); err != nil {
+		t.Fatal(err)
+	}
+	if err := e.Register(&probeHeuristic{name: "B", deps: Dependencies{Heuristics: []string{"C"}}}); err != nil {
+		t.Fatal(err)
+	}
+	err := e.Register(&probeHeuristic{name: "C", deps: Dependencies{Heuristics: []string{"A"}}})
+	// The cycle is printed in run order: each heuristic would have to run before the next.
+	if err == nil || !strings.Contains(err.Error(), "A -> C -> B -> A") {
+		t.Fatalf("expected the cycle to be reported, got %v", err)
+	}
+	if len(e.heuristics) != 2 {
+		t.Errorf("a rejected heuristic must not be registered, got %d heuristics", len(e.heuristics))
+	}
+
+	// A heuristic that may flag any type and depends on a type waits on every
+	// other heuristic, so two of them form a cycle.
+	e = NewEngine()
+	e.Register(&fixedHeuristic{name: "Any"})
+	if err := e.Register(&anyDependent{name: "D1"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := e.Register(&anyDependent{name: "D2"}); err == nil {
+		t.Error("expected a cycle between two type dependents that may flag any type")
+	}
+}
+
+// anyDependent depends on volume findings and does not declare what it flags.
+type anyDependent struct{ name string }
+
+func (h *anyDependent) Name() string { return h.name }
+func (h *anyDependent) DependsOn() Dependencies {
+	return Dependencies{Types: []string{"AWS::EC2::Volume"}}
+}
+func (h *anyDependent) Analyze(ctx context.Context, v *graph.View, cov *Coverage) ([]HeuristicResult, error) {
+	return nil, nil
+}
+
+type panicHeuristic struct{}
+
+func (panicHeuristic) Name() string { return "panics" }
+func (panicHeuristic) Analyze(ctx context.Context, v *graph.View, cov *Coverage) ([]HeuristicResult, error) {
+	panic("nil map")
+}
+
+func TestEngine_RecoversPanics(t *testing.T) {
+	g := pluginGraph()
+	e := NewEngine()
+	e.Register(panicHeuristic{})
+	e.Register(&fixedHeuristic{name: "ok", results: []HeuristicResult{{ResourceID: "vol-1", Confidence: 1}}})
+
+	if err := e.Run(context.Background(), g); err == nil || !strings.Contains(err.Error(), "panic: nil map") {
+		t.Errorf("expected the panic as an error, got %v", err)
+	}
+	if _, ok := nodeByID(g, "vol-1").FindingBy("ok"); !ok {
+		t.Error("a panicking heuristic must not affect the others")
+	}
+}
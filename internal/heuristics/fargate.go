@@ -5,10 +5,10 @@ import (
 	"fmt"
 	"strings"
 
-
 	"github.com/DrSkyle/cloudslash/internal/graph"
 	"github.com/DrSkyle/cloudslash/internal/k8s"
 	"github.com/aws/aws-sdk-go-v2/service/eks/types"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -18,21 +18,24 @@ type AbandonedFargateHeuristic struct {
 
 func (h *AbandonedFargateHeuristic) Name() string { return "AbandonedFargateHeuristic" }
 
-func (h *AbandonedFargateHeuristic) Run(ctx context.Context, g *graph.Graph) error {
+func (h *AbandonedFargateHeuristic) Flags() []string { return []string{"AWS::EKS::FargateProfile"} }
+
+func (h *AbandonedFargateHeuristic) Analyze(ctx context.Context, v *graph.View, cov *Coverage) ([]HeuristicResult, error) {
+	var results []HeuristicResult
+
+	profiles := v.NodesByType("AWS::EKS::FargateProfile")
+	cov.Examine(len(profiles))
+
 	// If no K8s connection, we cannot perform deep forensics.
-	// Returning nil is safe (skip heuristic).
+	// Record every profile as skipped so the gap shows in the coverage report.
 	if h.K8sClient == nil {
-		return nil
-	}
-	
-	g.Mu.Lock()
-	defer g.Mu.Unlock()
-
-	for _, node := range g.Nodes {
-		if node.Type != "AWS::EKS::FargateProfile" {
-			continue
+		for _, node := range profiles {
+			cov.Skip(node.ID, "no Kubernetes connection")
 		}
+		return nil, nil
+	}
 
+	for _, node := range profiles {
 		profileName, _ := node.Properties["ProfileName"].(string)
 		
 		// 0. The CoreDNS / System Whitelist
@@ -44,15 +47,20 @@ func (h *AbandonedFargateHeuristic) Run(ctx context.Context, g *graph.Graph) err
 		selectors, ok := node.Properties["Selectors"].([]types.FargateProfileSelector)
 		if !ok || len(selectors) == 0 {
 			// No selectors? It matches nothing. Abandoned.
-			node.IsWaste = true
-			node.RiskScore = 100
-			node.Cost = 0 // "Risk Removal"
-			node.Properties["Reason"] = "Empty Fargate Profile: Matches no namespaces."
+			results = append(results, HeuristicResult{
+				ResourceID: node.ID,
+				Category:   graph.CategoryWaste,
+				Confidence: 1,
+				RiskScore:  100, // "Risk Removal", no direct savings
+				Reason:     "Empty Fargate Profile: Matches no namespaces.",
+				Action:     "Delete the Fargate profile",
+			})
 			continue
 		}
 
 		// A Profile is ACTIVE if AT LEAST ONE selector is active (OR Logic).
 		isProfileActive := false
+		skipReason := ""
 		var failureReasons []string
 
 		for i, sel := range selectors {
@@ -68,11 +76,16 @@ func (h *AbandonedFargateHeuristic) Run(ctx context.Context, g *graph.Graph) err
 			// Ideally we cache this list to avoid N calls.
 			// For minimal code change, let's just call Get.
 			_, err := h.K8sClient.Clientset.CoreV1().Namespaces().Get(ctx, nsName, metav1.GetOptions{})
-			if err != nil {
+			if apierrors.IsNotFound(err) {
 				// If 404, this specific selector is dead.
 				failureReasons = append(failureReasons, fmt.Sprintf("Selector #%d: Namespace '%s' not found.", i+1, nsName))
 				continue
 			}
+			if err != nil {
+				// Any other error means we could not look, not that the namespace is gone.
+				skipReason = fmt.Sprintf("namespace lookup failed: %v", err)
+				break
+			}
 			
 			// LAYER 2: The Pulse Check (Active Pods)
 			// List pods in namespace matching labels.
@@ -84,7 +97,11 @@ func (h *AbandonedFargateHeuristic) Run(ctx context.Context, g *graph.Graph) err
 				Limit: 1, // We only need to know if > 0 exist
 			})
 			
-			if err == nil && len(pods.Items) > 0 {
+			if err != nil {
+				skipReason = fmt.Sprintf("pod list failed: %v", err)
+				break
+			}
+			if len(pods.Items) > 0 {
 				isProfileActive = true
 				break // Found life! The specific Trap Door works.
 			}
@@ -96,18 +113,20 @@ func (h *AbandonedFargateHeuristic) Run(ctx context.Context, g *graph.Graph) err
 				LabelSelector: labelSelector,
 			})
 			
+			if err != nil {
+				skipReason = fmt.Sprintf("deployment list failed: %v", err)
+				break
+			}
 			hasActiveController := false
-			if err == nil {
-				for _, d := range deployments.Items {
-					if d.Spec.Replicas != nil && *d.Spec.Replicas > 0 {
-						hasActiveController = true
-						break
-					} else {
-						// Scaled to 0. Check age (TODO: managedFields check is heavy, assuming scale-to-0 is indication enough for now)
-						// User requested "LastScaleTime > 30 Days".
-						// For v1.2.5, let's treat "Scaled to 0" as "Inactive" unless proven otherwise.
-						// The profile ITSELF isn't doing anything if replicas=0.
-					}
+			for _, d := range deployments.Items {
+				if d.Spec.Replicas != nil && *d.Spec.Replicas > 0 {
+					hasActiveController = true
+					break
+				} else {
+					// Scaled to 0. Check age (TODO: managedFields check is heavy, assuming scale-to-0 is indication enough for now)
+					// User requested "LastScaleTime > 30 Days".
+					// For v1.2.5, let's treat "Scaled to 0" as "Inactive" unless proven otherwise.
+					// The profile ITSELF isn't doing anything if replicas=0.
 				}
 			}
 			
@@ -120,12 +139,14 @@ func (h *AbandonedFargateHeuristic) Run(ctx context.Context, g *graph.Graph) err
 			sts, err := h.K8sClient.Clientset.AppsV1().StatefulSets(nsName).List(ctx, metav1.ListOptions{
 				LabelSelector: labelSelector,
 			})
-			if err == nil {
-				for _, s := range sts.Items {
-					if s.Spec.Replicas != nil && *s.Spec.Replicas > 0 {
-						hasActiveController = true
-						break
-					}
+			if err != nil {
+				skipReason = fmt.Sprintf("statefulset list failed: %v", err)
+				break
+			}
+			for _, s := range sts.Items {
+				if s.Spec.Replicas != nil && *s.Spec.Replicas > 0 {
+					hasActiveController = true
+					break
 				}
 			}
 			
@@ -141,22 +162,28 @@ func (h *AbandonedFargateHeuristic) Run(ctx context.Context, g *graph.Graph) err
 			failureReasons = append(failureReasons, fmt.Sprintf("Selector #%d ('%s'): Ghost Town. No active Pods or Controllers.", i+1, nsName))
 		}
 
+		if skipReason != "" {
+			cov.Skip(node.ID, skipReason)
+			continue
+		}
+
 		if !isProfileActive {
 			// ABANDONED
-			node.IsWaste = true
-			node.RiskScore = 60 // Medium Risk (Configuration Debt is mostly risk of confusion/accidental billing)
-			node.Cost = 0       // It's free to have empty profiles.
-			
-			reason := "Abandoned Fargate Profile:\n"
-			for _, r := range failureReasons {
-				reason += fmt.Sprintf(" - %s\n", r)
-			}
-			reason += "Recommendation: Remove to prevent accidental serverless billing if pods are scheduled here."
-			node.Properties["Reason"] = reason
+			// Medium Risk (Configuration Debt is mostly risk of confusion/accidental billing).
+			// It's free to have empty profiles, so there are no savings.
+			results = append(results, HeuristicResult{
+				ResourceID: node.ID,
+				Category:   graph.CategoryWaste,
+				Confidence: 0.6,
+				RiskScore:  60,
+				Reason:     "Abandoned Fargate Profile: " + strings.Join(failureReasons, " "),
+				Evidence:   failureReasons,
+				Action:     "Remove to prevent accidental serverless billing if pods are scheduled here.",
+			})
 		}
 	}
 	
-	return nil
+	return results, nil
 }
 
 // formatLabelSelector converts map[string]string to "key=value,key2=value2"
@@ -13,39 +13,39 @@ func (h *FossilAMIHeuristic) Name() string {
 	return "FossilAMIs"
 }
 
-func (h *FossilAMIHeuristic) Run(ctx context.Context, g *graph.Graph) error {
-	g.Mu.Lock()
-	defer g.Mu.Unlock()
+func (h *FossilAMIHeuristic) Flags() []string { return []string{"AWS::EC2::Snapshot"} }
+
+func (h *FossilAMIHeuristic) Analyze(ctx context.Context, v *graph.View, cov *Coverage) ([]HeuristicResult, error) {
+	var results []HeuristicResult
+
+	amis := v.NodesByType("AWS::EC2::AMI")
+	snapshots := v.NodesByType("AWS::EC2::Snapshot")
 
 	// 1. Collect all Active AMIs
 	activeAMIs := make(map[string]bool)
-	for id, node := range g.Nodes {
-		if node.Type == "AWS::EC2::AMI" {
-			activeAMIs[id] = true
-		}
+	for _, node := range amis {
+		activeAMIs[node.ID] = true
 	}
 
 	// 2. Scan Snapshots
-	for id, node := range g.Nodes {
-		if node.Type != "AWS::EC2::Snapshot" {
-			continue
-		}
-
+	cov.Examine(len(snapshots))
+	for _, node := range snapshots {
+		id := node.ID
 		desc, _ := node.Properties["Description"].(string)
-		
+
 		// "Created by CreateImage(...) for ami-12345678"
 		// If description says it was created for an AMI, but that AMI is not in our graph...
 		// It means the AMI is deregistered (or we failed to scan it, but assuming full scan).
 
 		if strings.Contains(desc, "Created by CreateImage") {
 			// Extract AMI ID if possible, or just rely on the fact it's an AMI-snapshot
-			// If it's not linked to any existing AMI node in the graph via EdgeTypeContains, it's a candidate.
-			// CloudSlash graph edge logic: AMI -> Snapshot (Contains).
-			// So we check ReverseEdges of the Snapshot. If no upstream AMI, it's orphaned.
+			// If it's not linked to any existing AMI node in the graph, it's a candidate.
+			// CloudSlash graph edge logic: Snapshot -> AMI (AttachedTo).
+			// So we check the forward Edges of the Snapshot. If no AMI, it's orphaned.
 
-			upstream := g.ReverseEdges[id]
+			downstream := v.Edges(id)
 			hasAMI := false
-			for _, edge := range upstream {
+			for _, edge := range downstream {
 				if strings.Contains(edge.TargetID, ":image/") || strings.Contains(edge.TargetID, ":ami/") {
 					hasAMI = true
 					break
@@ -53,17 +53,24 @@ func (h *FossilAMIHeuristic) Run(ctx context.Context, g *graph.Graph) error {
 			}
 
 			if !hasAMI {
-				node.IsWaste = true
-				node.RiskScore = 60
-				node.Properties["Reason"] = "Fossil Snapshot: Created by an AMI which no longer exists."
-				
 				// Estimate Cost ($0.05/GB standard-ish)
+				var savings float64
 				if size, ok := node.Properties["VolumeSize"].(int32); ok {
-					node.Cost = float64(size) * 0.05
+					savings = float64(size) * 0.05
 				}
+
+				results = append(results, HeuristicResult{
+					ResourceID:     node.ID,
+					Category:       graph.CategoryWaste,
+					Confidence:     0.7,
+					RiskScore:      60,
+					MonthlySavings: savings,
+					Reason:         "Fossil Snapshot: Created by an AMI which no longer exists.",
+					Action:         "Delete the snapshot",
+				})
 			}
 		}
 	}
 
-	return nil
+	return results, nil
 }
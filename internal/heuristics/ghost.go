@@ -3,7 +3,7 @@ package heuristics
 import (
 	"context"
 	"fmt"
-	
+
 	"github.com/DrSkyle/cloudslash/internal/graph"
 )
 
@@ -12,21 +12,22 @@ type GhostNodeGroupHeuristic struct{}
 
 func (h *GhostNodeGroupHeuristic) Name() string { return "GhostNodeGroupHeuristic" }
 
-func (h *GhostNodeGroupHeuristic) Run(ctx context.Context, g *graph.Graph) error {
-	g.Mu.Lock()
-	defer g.Mu.Unlock()
+func (h *GhostNodeGroupHeuristic) Flags() []string { return []string{"AWS::EKS::NodeGroup"} }
 
-	for _, node := range g.Nodes {
-		if node.Type != "AWS::EKS::NodeGroup" {
-			continue
-		}
+func (h *GhostNodeGroupHeuristic) Analyze(ctx context.Context, v *graph.View, cov *Coverage) ([]HeuristicResult, error) {
+	var results []HeuristicResult
 
+	nodeGroups := v.NodesByType("AWS::EKS::NodeGroup")
+	cov.Examine(len(nodeGroups))
+
+	for _, node := range nodeGroups {
 		realWorkloadCount, ok := node.Properties["RealWorkloadCount"].(int)
 		if !ok {
 			// If property missing, scanner didn't run or failed. Skip.
+			cov.Skip(node.ID, "workload count unavailable (node scan failed or was not run)")
 			continue
 		}
-		
+
 		nodeCount, _ := node.Properties["NodeCount"].(int)
 
 		// THE VERDICT
@@ -34,17 +35,21 @@ func (h *GhostNodeGroupHeuristic) Run(ctx context.Context, g *graph.Graph) error
 		// AND there are actual nodes billing (NodeCount > 0)
 		if realWorkloadCount == 0 && nodeCount > 0 {
 			// GHOST DETECTED
-			node.IsWaste = true
-			node.RiskScore = 95 // Extremely High Confidence
-			
 			// Cost Estimation: Assume generic m5.large (~$70/mo) * nodeCount as a baseline estimate
 			// Optimally we'd look up instance type from ASG, but we have "NodeCount".
 			estCostPerNode := 70.0
-			node.Cost = estCostPerNode * float64(nodeCount)
-			
-			node.Properties["Reason"] = fmt.Sprintf("👻 GHOST DETECTED: Node Group has %d active nodes but serves EXACTLY ZERO user applications.", nodeCount)
+			results = append(results, HeuristicResult{
+				ResourceID:     node.ID,
+				Category:       graph.CategoryWaste,
+				Confidence:     0.95, // Extremely High Confidence
+				RiskScore:      95,
+				MonthlySavings: estCostPerNode * float64(nodeCount),
+				Reason:         fmt.Sprintf("👻 GHOST DETECTED: Node Group has %d active nodes but serves EXACTLY ZERO user applications.", nodeCount),
+				Evidence:       []string{fmt.Sprintf("NodeCount %d", nodeCount), "RealWorkloadCount 0"},
+				Action:         "Scale the node group to zero or delete it",
+			})
 		}
 	}
 
-	return nil
+	return results, nil
 }
@@ -0,0 +1,339 @@
+package heuristics
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/DrSkyle/cloudslash/internal/graph"
+	"github.com/DrSkyle/cloudslash/internal/resource"
+)
+
+// gravitonFamilies maps x86 instance families to the Graviton family with the
+// same vCPU-to-memory ratio. RDS classes use the same map behind "db.".
+var gravitonFamilies = map[string]string{
+	"t2": "t4g", "t3": "t4g", "t3a": "t4g",
+	"m4": "m7g", "m5": "m7g", "m5a": "m7g", "m6i": "m7g", "m6a": "m7g", "m7i": "m7g",
+	"c4": "c7g", "c5": "c7g", "c5a": "c7g", "c6i": "c7g", "c6a": "c7g", "c7i": "c7g",
+	"r4": "r7g", "r5": "r7g", "r5a": "r7g", "r6i": "r7g", "r6a": "r7g", "r7i": "r7g",
+}
+
+// gravitonSizes lists the sizes each Graviton family offers; x86 sizes outside
+// the list (e.g. 24xlarge, metal) have no drop-in equivalent.
+var gravitonSizes = map[string][]string{
+	"t4g":    {"nano", "micro", "small", "medium", "large", "xlarge", "2xlarge"},
+	"m7g":    {"medium", "large", "xlarge", "2xlarge", "4xlarge", "8xlarge", "12xlarge", "16xlarge"},
+	"c7g":    {"medium", "large", "xlarge", "2xlarge", "4xlarge", "8xlarge", "12xlarge", "16xlarge"},
+	"r7g":    {"medium", "large", "xlarge", "2xlarge", "4xlarge", "8xlarge", "12xlarge", "16xlarge"},
+	"db.t4g": {"micro", "small", "medium", "large", "xlarge", "2xlarge"},
+	"db.m7g": {"large", "xlarge", "2xlarge", "4xlarge", "8xlarge", "12xlarge", "16xlarge"},
+	"db.r7g": {"large", "xlarge", "2xlarge", "4xlarge", "8xlarge", "12xlarge", "16xlarge"},
+}
+
+// gravitonEngines holds the oldest major version of each RDS engine that runs
+// on Graviton. Older versions need an engine upgrade first.
+var gravitonEngines = map[string][2]int{
+	"mysql":             {8, 0},
+	"postgres":          {12, 0},
+	"mariadb":           {10, 4},
+	"aurora-mysql":      {8, 0}, // Aurora MySQL 3
+	"aurora-postgresql": {12, 0},
+}
+
+// GravitonType returns the Graviton equivalent of an x86 instance type or RDS
+// instance class, e.g. m5.large -> m7g.large, db.r5.xlarge -> db.r7g.xlarge.
+func GravitonType(instanceType string) (string, bool) {
+	prefix, rest := "", instanceType
+	if strings.HasPrefix(rest, "db.") {
+		prefix, rest = "db.", strings.TrimPrefix(rest, "db.")
+	}
+	family, size, ok := strings.Cut(rest, ".")
+	if !ok {
+		return "", false
+	}
+	target, ok := gravitonFamilies[family]
+	if !ok {
+		return "", false
+	}
+	target = prefix + target
+	for _, s := range gravitonSizes[target] {
+		if s == size {
+			return target + "." + size, true
+		}
+	}
+	return "", false
+}
+
+// MigrationEffort scores how much work a move to Graviton takes.
+type MigrationEffort int
+
+const (
+	EffortLow    MigrationEffort = iota // Change the instance class
+	EffortMedium                        // Rebuild images or upgrade the engine first
+	EffortHigh                          // Rebuild and re-test the workload on arm64
+)
+
+func (e MigrationEffort) String() string {
+	switch e {
+	case EffortLow:
+		return "low"
+	case EffortMedium:
+		return "medium"
+	default:
+		return "high"
+	}
+}
+
+// GravitonPlan describes the move of one resource to Graviton.
+type GravitonPlan struct {
+	From   string
+	To     string
+	Effort MigrationEffort
+	Notes  []string // Compatibility signals behind the effort
+}
+
+// PlanGraviton works out the Graviton target and migration effort of an EC2
+// instance, EKS managed node group or RDS instance. It returns false when the
+// resource already runs on arm64, has no equivalent, or cannot move at all.
+func PlanGraviton(n *graph.Node) (GravitonPlan, bool) {
+	switch n.Type {
+	case "AWS::EC2::Instance":
+		return planInstance(n)
+	case "AWS::EKS::NodeGroup":
+		return planNodeGroup(n)
+	case "AWS::RDS::DBInstance":
+		return planDatabase(n)
+	}
+	return GravitonPlan{}, false
+}
+
+func planInstance(n *graph.Node) (GravitonPlan, bool) {
+	from, _ := n.Properties["InstanceType"].(string)
+	to, ok := GravitonType(from)
+	if !ok || n.Properties["Architecture"] == "arm64" {
+		return GravitonPlan{}, false
+	}
+	plan := GravitonPlan{From: from, To: to, Effort: EffortHigh}
+
+	platform, _ := n.Properties["PlatformDetails"].(string)
+	switch {
+	case strings.Contains(platform, "Windows"), strings.Contains(platform, "SQL Server"):
+		return GravitonPlan{}, false // No Windows on Graviton
+	case platform == "Linux/UNIX":
+		plan.Effort = EffortMedium
+		plan.Notes = append(plan.Notes, "Linux/UNIX platform: needs an arm64 AMI and arm64 builds of installed software")
+	case platform == "":
+		plan.Notes = append(plan.Notes, "Platform unknown")
+	default:
+		plan.Notes = append(plan.Notes, platform+" platform: check that the licensed arm64 AMI is available")
+	}
+	if ami, ok := n.Properties["ImageId"].(string); ok {
+		plan.Notes = append(plan.Notes, "Current AMI "+ami+" is x86_64")
+	}
+	return plan, true
+}
+
+func planNodeGroup(n *graph.Node) (GravitonPlan, bool) {
+	types, _ := n.Properties["InstanceTypes"].([]string)
+	if len(types) == 0 {
+		return GravitonPlan{}, false
+	}
+	to, ok := GravitonType(types[0])
+	if !ok {
+		return GravitonPlan{}, false
+	}
+	plan := GravitonPlan{From: types[0], To: to, Effort: EffortMedium}
+
+	ami, _ := n.Properties["AmiType"].(string)
+	switch {
+	case strings.Contains(ami, "ARM"):
+		return GravitonPlan{}, false
+	case strings.Contains(ami, "GPU"), strings.Contains(ami, "NVIDIA"), strings.Contains(ami, "NEURON"), strings.HasPrefix(ami, "WINDOWS"):
+		return GravitonPlan{}, false
+	case ami == "CUSTOM":
+		plan.Effort = EffortHigh
+		plan.Notes = append(plan.Notes, "Custom AMI: build an arm64 launch template image")
+	default:
+		plan.Notes = append(plan.Notes, fmt.Sprintf("%s node group: switch to the ARM_64 AMI type; every pod image must be multi-arch", ami))
+	}
+	if len(types) > 1 {
+		plan.Notes = append(plan.Notes, fmt.Sprintf("Priced as %s; the group also allows %s", types[0], strings.Join(types[1:], ", ")))
+	}
+	return plan, true
+}
+
+func planDatabase(n *graph.Node) (GravitonPlan, bool) {
+	from, _ := n.Properties["InstanceClass"].(string)
+	to, ok := GravitonType(from)
+	if !ok {
+		return GravitonPlan{}, false
+	}
+	engine, _ := n.Properties["Engine"].(string)
+	minVersion, ok := gravitonEngines[engine]
+	if !ok {
+		return GravitonPlan{}, false // Oracle and SQL Server do not run on Graviton
+	}
+	plan := GravitonPlan{From: from, To: to, Effort: EffortLow}
+
+	version, _ := n.Properties["EngineVersion"].(string)
+	if versionAtLeast(version, minVersion[0], minVersion[1]) {
+		plan.Notes = append(plan.Notes, fmt.Sprintf("%s %s runs on Graviton: modify the instance class", engine, version))
+	} else {
+		plan.Effort = EffortMedium
+		plan.Notes = append(plan.Notes, fmt.Sprintf("%s %s needs an upgrade to %d.%d or later first", engine, orUnknown(version), minVersion[0], minVersion[1]))
+	}
+	return plan, true
+}
+
+// versionAtLeast reports whether a dotted version such as "8.0.35" or
+// "8.0.mysql_aurora.3.04.0" is at least major.minor.
+func versionAtLeast(version string, major, minor int) bool {
+	parts := strings.SplitN(version, ".", 3)
+	maj, err := strconv.Atoi(parts[0])
+	if err != nil {
+		return false
+	}
+	if maj != major {
+		return maj > major
+	}
+	if len(parts) < 2 {
+		return minor == 0
+	}
+	mn, err := strconv.Atoi(parts[1])
+	return err == nil && mn >= minor
+}
+
+func orUnknown(s string) string {
+	if s == "" {
+		return "(unknown version)"
+	}
+	return s
+}
+
+// GravitonHeuristic lists x86 instances, EKS node groups and RDS instances that
+// would cost less on Graviton, with the effort of the move.
+type GravitonHeuristic struct {
+	Tunable
+	Pricing PricingClient
+}
+
+// GravitonConfig holds the GravitonHeuristic thresholds.
+type GravitonConfig struct {
+	MinSavings float64 `yaml:"min_savings"` // Ignore moves that save less per month
+}
+
+func (h *GravitonHeuristic) Defaults() interface{} {
+	return &GravitonConfig{MinSavings: 5}
+}
+
+func (h *GravitonHeuristic) Name() string { return "GravitonHeuristic" }
+
+func (h *GravitonHeuristic) Flags() []string {
+	return []string{"AWS::EC2::Instance", "AWS::EKS::NodeGroup", "AWS::RDS::DBInstance"}
+}
+
+// DependsOn makes the heuristic skip resources already flagged for deletion.
+func (h *GravitonHeuristic) DependsOn() Dependencies {
+	return Dependencies{Heuristics: []string{"StoppedInstanceHeuristic", "GhostNodeGroupHeuristic", "RDSHeuristic"}}
+}
+
+func (h *GravitonHeuristic) Analyze(ctx context.Context, v *graph.View, cov *Coverage) ([]HeuristicResult, error) {
+	var results []HeuristicResult
+
+	var candidates []*graph.Node
+	for _, t := range h.Flags() {
+		for _, n := range v.NodesByType(t) {
+			if !flaggedWaste(n) && gravitonActive(n) {
+				candidates = append(candidates, n)
+			}
+		}
+	}
+	cov.Examine(len(candidates))
+
+	for _, node := range candidates {
+		plan, ok := PlanGraviton(node)
+		if !ok {
+			continue
+		}
+		if h.Pricing == nil {
+			cov.Skip(node.ID, "pricing unavailable")
+			continue
+		}
+		cfg := h.Defaults().(*GravitonConfig)
+		h.Settings.Resolve(h.Name(), node, cfg)
+
+		current, graviton, count, err := h.price(ctx, node, plan)
+		if err != nil {
+			cov.Skip(node.ID, fmt.Sprintf("pricing failed: %v", err))
+			continue
+		}
+		savings := (current - graviton) * float64(count)
+		if savings < cfg.MinSavings {
+			continue
+		}
+
+		evidence := []string{
+			fmt.Sprintf("%s: $%.2f/mo", plan.From, current),
+			fmt.Sprintf("%s: $%.2f/mo", plan.To, graviton),
+		}
+		if count > 1 {
+			evidence = append(evidence, fmt.Sprintf("%d nodes", count))
+		}
+		evidence = append(evidence, "Migration effort: "+plan.Effort.String())
+		evidence = append(evidence, plan.Notes...)
+
+		results = append(results, HeuristicResult{
+			ResourceID:     node.ID,
+			Category:       graph.CategoryRightsizing,
+			Confidence:     WasteConfidence(0.9 - 0.2*float64(plan.Effort)),
+			RiskScore:      20 + 20*int(plan.Effort),
+			MonthlySavings: savings,
+			Reason:         fmt.Sprintf("%s on Graviton (%s) would save $%.2f/mo; %s effort", plan.From, plan.To, savings, plan.Effort),
+			Evidence:       evidence,
+			Action:         fmt.Sprintf("Move to %s", plan.To),
+			Thresholds:     Thresholds(cfg),
+		})
+	}
+	return results, nil
+}
+
+// gravitonActive reports whether n is running, so a move would change the bill.
+func gravitonActive(n *graph.Node) bool {
+	switch n.Type {
+	case "AWS::EC2::Instance":
+		return n.Properties["State"] == "running"
+	case "AWS::EKS::NodeGroup":
+		desired, _ := n.Properties["DesiredSize"].(int32)
+		return desired > 0
+	case "AWS::RDS::DBInstance":
+		return n.Properties["Status"] == "available"
+	}
+	return false
+}
+
+// price returns the monthly cost of one unit on each architecture and the
+// number of units (nodes in a node group, otherwise one).
+func (h *GravitonHeuristic) price(ctx context.Context, node *graph.Node, plan GravitonPlan) (current, graviton float64, count int, err error) {
+	region := resource.Region(node.ID, "us-east-1")
+
+	if node.Type == "AWS::RDS::DBInstance" {
+		engine, _ := node.Properties["Engine"].(string)
+		multiAZ, _ := node.Properties["MultiAZ"].(bool)
+		if current, err = h.Pricing.GetRDSInstancePrice(ctx, region, plan.From, engine, multiAZ); err != nil {
+			return 0, 0, 0, err
+		}
+		graviton, err = h.Pricing.GetRDSInstancePrice(ctx, region, plan.To, engine, multiAZ)
+		return current, graviton, 1, err
+	}
+
+	count = 1
+	if desired, ok := node.Properties["DesiredSize"].(int32); ok {
+		count = int(desired)
+	}
+	if current, err = h.Pricing.GetEC2InstancePrice(ctx, region, plan.From); err != nil {
+		return 0, 0, 0, err
+	}
+	graviton, err = h.Pricing.GetEC2InstancePrice(ctx, region, plan.To)
+	return current, graviton, count, err
+}
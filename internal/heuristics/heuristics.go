@@ -8,37 +8,52 @@ import (
 
 	internalaws "github.com/DrSkyle/cloudslash/internal/aws"
 	"github.com/DrSkyle/cloudslash/internal/graph"
-	"github.com/DrSkyle/cloudslash/internal/pricing"
+	"github.com/DrSkyle/cloudslash/internal/resource"
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/cloudwatch/types"
 )
 
 // NATGatewayHeuristic checks for unused NAT Gateways.
 type NATGatewayHeuristic struct {
-	CW      *internalaws.CloudWatchClient
-	Pricing *pricing.Client
+	Tunable
+	CW      CloudWatchClient
+	Pricing PricingClient
+}
+
+// NATGatewayConfig holds the NATGatewayHeuristic thresholds. A gateway is
+// unused when it stays below both limits over the lookback window.
+type NATGatewayConfig struct {
+	MaxConnections float64  `yaml:"max_connections"` // Peak ActiveConnectionCount
+	MaxBytesOut    float64  `yaml:"max_bytes_out"`   // Total BytesOutToDestination
+	Lookback       Duration `yaml:"lookback"`
+}
+
+func (h *NATGatewayHeuristic) Defaults() interface{} {
+	return &NATGatewayConfig{MaxConnections: 5, MaxBytesOut: 1e9, Lookback: Days(7)}
 }
 
 func (h *NATGatewayHeuristic) Name() string { return "NATGatewayHeuristic" }
 
-func (h *NATGatewayHeuristic) Run(ctx context.Context, g *graph.Graph) error {
-	g.Mu.RLock()
-	var natGateways []*graph.Node
-	for _, node := range g.Nodes {
-		if node.Type == "AWS::EC2::NatGateway" {
-			natGateways = append(natGateways, node)
-		}
-	}
-	g.Mu.RUnlock()
+func (h *NATGatewayHeuristic) Flags() []string { return []string{"AWS::EC2::NatGateway"} }
+
+func (h *NATGatewayHeuristic) Analyze(ctx context.Context, v *graph.View, cov *Coverage) ([]HeuristicResult, error) {
+	var results []HeuristicResult
+
+	natGateways := v.NodesByType("AWS::EC2::NatGateway")
+	cov.Examine(len(natGateways))
 
 	for _, node := range natGateways {
+		cfg := h.Defaults().(*NATGatewayConfig)
+		h.Settings.Resolve(h.Name(), node, cfg)
+
 		endTime := time.Now()
-		startTime := endTime.Add(-7 * 24 * time.Hour)
-		var id string
-		fmt.Sscanf(node.ID, "arn:aws:ec2:region:account:natgateway/%s", &id)
-		if id == "" {
+		startTime := endTime.Add(-cfg.Lookback.Std())
+		arn, err := resource.ParseARN(node.ID)
+		if err != nil || arn.ResourceType != "natgateway" {
+			cov.Skip(node.ID, "unrecognized NAT Gateway ARN")
 			continue
 		}
+		id := arn.ID()
 
 		dims := []types.Dimension{
 			{Name: aws.String("NatGatewayId"), Value: aws.String(id)},
@@ -48,43 +63,70 @@ func (h *NATGatewayHeuristic) Run(ctx context.Context, g *graph.Graph) error {
 		// If these specific dimensions fail (which naturally shouldn't happen for valid NATs),
 		// we return a unique, searchable error string.
 		if id == "nat-0deadbeef" {
-			return fmt.Errorf("CloudSlash: VNAT_0x99 - Plasma Leak Detected in Subnet %s", "unknown")
+			return results, fmt.Errorf("CloudSlash: VNAT_0x99 - Plasma Leak Detected in Subnet %s", "unknown")
 		}
 
 		maxConns, err := h.CW.GetMetricMax(ctx, "AWS/NATGateway", "ActiveConnectionCount", dims, startTime, endTime)
 		if err != nil {
+			cov.Skip(node.ID, fmt.Sprintf("metric fetch failed: %v", err))
 			continue
 		}
 		sumBytes, err := h.CW.GetMetricSum(ctx, "AWS/NATGateway", "BytesOutToDestination", dims, startTime, endTime)
 		if err != nil {
+			cov.Skip(node.ID, fmt.Sprintf("metric fetch failed: %v", err))
 			continue
 		}
 
-		if maxConns < 5 && sumBytes < 1e9 {
-			g.MarkWaste(node.ID, 80)
-			node.Properties["Reason"] = fmt.Sprintf("Unused NAT Gateway: MaxConns=%.0f, BytesOut=%.0f", maxConns, sumBytes)
-			
+		if maxConns < cfg.MaxConnections && sumBytes < cfg.MaxBytesOut {
+			var savings float64
 			if h.Pricing != nil {
-				// NAT is usually region-based, but we can assume us-east-1 for simple estimate or parse region from ARN
-				cost, err := h.Pricing.GetNATGatewayPrice(ctx, "us-east-1")
+				cost, err := h.Pricing.GetNATGatewayPrice(ctx, resource.Region(node.ID, "us-east-1"))
 				if err == nil {
-					node.Cost = cost
+					savings = cost
 				}
 			}
+
+			results = append(results, HeuristicResult{
+				ResourceID:     node.ID,
+				Category:       graph.CategoryWaste,
+				Confidence:     0.9,
+				RiskScore:      80,
+				MonthlySavings: savings,
+				Reason:         fmt.Sprintf("Unused NAT Gateway: MaxConns=%.0f, BytesOut=%.0f", maxConns, sumBytes),
+				Evidence:       []string{fmt.Sprintf("ActiveConnectionCount max %.0f over %s", maxConns, cfg.Lookback), fmt.Sprintf("BytesOutToDestination %.0f over %s", sumBytes, cfg.Lookback)},
+				Action:         "Delete the NAT Gateway and release its Elastic IP",
+				Thresholds:     Thresholds(cfg),
+			})
 		}
 	}
-	return nil
+	return results, nil
 }
 
 // ZombieEBSHeuristic checks for unattached or zombie volumes.
 type ZombieEBSHeuristic struct {
-	Pricing *pricing.Client
+	Tunable
+	Pricing PricingClient
+}
+
+// ZombieEBSConfig holds the ZombieEBSHeuristic thresholds.
+type ZombieEBSConfig struct {
+	StoppedInstanceAge Duration `yaml:"stopped_instance_age"` // Flag volumes on instances stopped longer than this
+}
+
+func (h *ZombieEBSHeuristic) Defaults() interface{} {
+	return &ZombieEBSConfig{StoppedInstanceAge: Days(30)}
 }
 
 func (h *ZombieEBSHeuristic) Name() string { return "ZombieEBSHeuristic" }
 
-func (h *ZombieEBSHeuristic) Run(ctx context.Context, g *graph.Graph) error {
-	g.Mu.RLock()
+func (h *ZombieEBSHeuristic) Flags() []string { return []string{"AWS::EC2::Volume"} }
+
+func (h *ZombieEBSHeuristic) Analyze(ctx context.Context, v *graph.View, cov *Coverage) ([]HeuristicResult, error) {
+	var results []HeuristicResult
+
+	volumeNodes := v.NodesByType("AWS::EC2::Volume")
+	cov.Examine(len(volumeNodes))
+
 	type volumeData struct {
 		Node             *graph.Node
 		State            string
@@ -95,177 +137,264 @@ func (h *ZombieEBSHeuristic) Run(ctx context.Context, g *graph.Graph) error {
 	}
 	var volumes []volumeData
 
-	for _, node := range g.Nodes {
-		if node.Type == "AWS::EC2::Volume" {
-			sizeVal := 0
-			if s, ok := node.Properties["Size"].(int32); ok {
-				sizeVal = int(s)
-			} else if s, ok := node.Properties["Size"].(int); ok {
-				sizeVal = s
-			}
-
-			state, _ := node.Properties["State"].(string)
-			volType, _ := node.Properties["VolumeType"].(string)
-			attachedInstance, _ := node.Properties["AttachedInstanceId"].(string)
-
-			volumes = append(volumes, volumeData{
-				Node:             node,
-				State:            state,
-				Size:             sizeVal,
-				Type:             volType,
-				AttachedInstance: attachedInstance,
-				DeleteOnTerm:     func() bool { v, _ := node.Properties["DeleteOnTermination"].(bool); return v }(),
-			})
+	for _, node := range volumeNodes {
+		sizeVal := 0
+		if s, ok := node.Properties["Size"].(int32); ok {
+			sizeVal = int(s)
+		} else if s, ok := node.Properties["Size"].(int); ok {
+			sizeVal = s
 		}
+
+		state, _ := node.Properties["State"].(string)
+		volType, _ := node.Properties["VolumeType"].(string)
+		attachedInstance, _ := node.Properties["AttachedInstanceId"].(string)
+
+		volumes = append(volumes, volumeData{
+			Node:             node,
+			State:            state,
+			Size:             sizeVal,
+			Type:             volType,
+			AttachedInstance: attachedInstance,
+			DeleteOnTerm:     func() bool { d, _ := node.Properties["DeleteOnTermination"].(bool); return d }(),
+		})
 	}
-	g.Mu.RUnlock()
 
 	for _, vol := range volumes {
+		cfg := h.Defaults().(*ZombieEBSConfig)
+		h.Settings.Resolve(h.Name(), vol.Node, cfg)
+
 		isWaste := false
 		reason := ""
+		action := ""
 		score := 0
+		var evidence []string
 
 		if vol.State == "available" {
 			isWaste = true
 			score = 90
 			reason = "Unattached EBS Volume"
+			action = "Snapshot the volume if needed, then delete it"
+			evidence = []string{"State: available"}
 		} else if vol.State == "in-use" && vol.AttachedInstance != "" {
-			instanceARN := fmt.Sprintf("arn:aws:ec2:region:account:instance/%s", vol.AttachedInstance)
+			volARN, err := resource.ParseARN(vol.Node.ID)
+			if err != nil {
+				cov.Skip(vol.Node.ID, "unrecognized volume ARN")
+				continue
+			}
+			// The instance lives in the same account and region as its volume.
+			instanceARN := volARN.Identity().EC2("instance", vol.AttachedInstance)
 
-			g.Mu.RLock()
-			instanceNode, ok := g.Nodes[instanceARN]
+			instanceNode, ok := v.Node(instanceARN)
 			var instanceState string
-			var launchTime time.Time
+			var since time.Time
+			sinceWhat := "stopped since"
 			if ok {
 				instanceState, _ = instanceNode.Properties["State"].(string)
-				launchTime, _ = instanceNode.Properties["LaunchTime"].(time.Time)
+				// Snapshots from older scans have no stop time; the launch time bounds it.
+				var known bool
+				if since, known = instanceNode.Properties["StoppedAt"].(time.Time); !known {
+					since, _ = instanceNode.CreatedAt()
+					sinceWhat = "stopped, launched"
+				}
 			}
-			g.Mu.RUnlock()
 
 			if ok {
-				if instanceState == "stopped" && time.Since(launchTime) > 30*24*time.Hour && !vol.DeleteOnTerm {
+				if instanceState == "stopped" && time.Since(since) > cfg.StoppedInstanceAge.Std() && !vol.DeleteOnTerm {
 					isWaste = true
 					score = 70
-					reason = "Zombie EBS: Attached to stopped instance > 30 days"
+					reason = fmt.Sprintf("Zombie EBS: Attached to instance stopped > %s", cfg.StoppedInstanceAge)
+					action = "Detach and delete the volume, or terminate the instance"
+					evidence = []string{"Instance " + vol.AttachedInstance + " " + sinceWhat + " " + since.Format("2006-01-02")}
 				}
 			}
 		}
 
 		if isWaste {
-			g.MarkWaste(vol.Node.ID, score)
-			vol.Node.Properties["Reason"] = reason
-
+			var savings float64
 			if h.Pricing != nil && vol.Size > 0 {
-				cost, err := h.Pricing.GetEBSPrice(ctx, "us-east-1", vol.Type, vol.Size)
+				cost, err := h.Pricing.GetEBSPrice(ctx, resource.Region(vol.Node.ID, "us-east-1"), vol.Type, vol.Size)
 				if err == nil {
-					vol.Node.Cost = cost
+					savings = cost
 				}
 			}
+
+			results = append(results, HeuristicResult{
+				ResourceID:     vol.Node.ID,
+				Category:       graph.CategoryWaste,
+				Confidence:     WasteConfidence(score) / 100,
+				RiskScore:      score,
+				MonthlySavings: savings,
+				Reason:         reason,
+				Evidence:       evidence,
+				Action:         action,
+				Thresholds:     Thresholds(cfg),
+			})
 		}
 	}
-	return nil
+	return results, nil
 }
 
 // ElasticIPHeuristic checks for EIPs attached to stopped instances or unattached.
-type ElasticIPHeuristic struct{
-	Pricing *pricing.Client
+type ElasticIPHeuristic struct {
+	Pricing PricingClient
 }
 
 func (h *ElasticIPHeuristic) Name() string { return "ElasticIPHeuristic" }
 
-func (h *ElasticIPHeuristic) Run(ctx context.Context, g *graph.Graph) error {
-	g.Mu.Lock()
-	defer g.Mu.Unlock()
+func (h *ElasticIPHeuristic) Flags() []string { return []string{"AWS::EC2::EIP"} }
 
-	for _, node := range g.Nodes {
-		if node.Type != "AWS::EC2::EIP" {
-			continue
-		}
+func (h *ElasticIPHeuristic) Analyze(ctx context.Context, v *graph.View, cov *Coverage) ([]HeuristicResult, error) {
+	var results []HeuristicResult
 
+	eips := v.NodesByType("AWS::EC2::EIP")
+	cov.Examine(len(eips))
+
+	for _, node := range eips {
 		instanceID, hasInstance := node.Properties["InstanceId"].(string)
 		if !hasInstance {
-			node.IsWaste = true
-			node.RiskScore = 50
-			node.Properties["Reason"] = "Unattached Elastic IP"
-
+			var savings float64
 			if h.Pricing != nil {
-				cost, err := h.Pricing.GetEIPPrice(ctx, "us-east-1")
+				cost, err := h.Pricing.GetEIPPrice(ctx, resource.Region(node.ID, "us-east-1"))
 				if err == nil {
-					node.Cost = cost
+					savings = cost
 				}
 			}
+
+			results = append(results, HeuristicResult{
+				ResourceID:     node.ID,
+				Category:       graph.CategoryWaste,
+				Confidence:     1,
+				RiskScore:      50,
+				MonthlySavings: savings,
+				Reason:         "Unattached Elastic IP",
+				Action:         "Release the Elastic IP",
+			})
 			continue
 		}
 
-		instanceARN := fmt.Sprintf("arn:aws:ec2:region:account:instance/%s", instanceID)
-		instanceNode, ok := g.Nodes[instanceARN]
+		eipARN, err := resource.ParseARN(node.ID)
+		if err != nil {
+			cov.Skip(node.ID, "unrecognized Elastic IP ARN")
+			continue
+		}
+		instanceARN := eipARN.Identity().EC2("instance", instanceID)
+		instanceNode, ok := v.Node(instanceARN)
 		if ok {
 			state, _ := instanceNode.Properties["State"].(string)
 			if state == "stopped" {
-				node.IsWaste = true
-				node.RiskScore = 60
-				node.Properties["Reason"] = "Elastic IP attached to stopped instance"
+				results = append(results, HeuristicResult{
+					ResourceID: node.ID,
+					Category:   graph.CategoryWaste,
+					Confidence: 0.8,
+					RiskScore:  60,
+					Reason:     "Elastic IP attached to stopped instance",
+					Evidence:   []string{"Instance " + instanceID + " is stopped"},
+					Action:     "Release the Elastic IP or start the instance",
+				})
 			}
 		}
 	}
-	return nil
+	return results, nil
 }
 
 // S3MultipartHeuristic checks for incomplete multipart uploads.
-type S3MultipartHeuristic struct{}
+type S3MultipartHeuristic struct {
+	Tunable
+}
+
+// S3MultipartConfig holds the S3MultipartHeuristic thresholds.
+type S3MultipartConfig struct {
+	MinAge Duration `yaml:"min_age"` // Flag uploads initiated longer ago than this
+}
+
+func (h *S3MultipartHeuristic) Defaults() interface{} {
+	return &S3MultipartConfig{MinAge: Days(7)}
+}
 
 func (h *S3MultipartHeuristic) Name() string { return "S3MultipartHeuristic" }
 
-func (h *S3MultipartHeuristic) Run(ctx context.Context, g *graph.Graph) error {
-	g.Mu.Lock()
-	defer g.Mu.Unlock()
-
-	for _, node := range g.Nodes {
-		if node.Type == "AWS::S3::MultipartUpload" {
-			initiated, ok := node.Properties["Initiated"].(time.Time)
-			if ok && time.Since(initiated) > 7*24*time.Hour {
-				node.IsWaste = true
-				node.RiskScore = 40
-				node.Properties["Reason"] = "Stale S3 Multipart Upload (> 7 days)"
-			}
+func (h *S3MultipartHeuristic) Flags() []string { return []string{"AWS::S3::MultipartUpload"} }
+
+func (h *S3MultipartHeuristic) Analyze(ctx context.Context, v *graph.View, cov *Coverage) ([]HeuristicResult, error) {
+	var results []HeuristicResult
+
+	uploads := v.NodesByType("AWS::S3::MultipartUpload")
+	cov.Examine(len(uploads))
+
+	for _, node := range uploads {
+		cfg := h.Defaults().(*S3MultipartConfig)
+		h.Settings.Resolve(h.Name(), node, cfg)
+
+		initiated, ok := node.Properties["Initiated"].(time.Time)
+		if ok && time.Since(initiated) > cfg.MinAge.Std() {
+			results = append(results, HeuristicResult{
+				ResourceID: node.ID,
+				Category:   graph.CategoryWaste,
+				Confidence: 0.9,
+				RiskScore:  40,
+				Reason:     fmt.Sprintf("Stale S3 Multipart Upload (> %s)", cfg.MinAge),
+				Evidence:   []string{"Initiated " + initiated.Format("2006-01-02")},
+				Action:     "Abort the upload, or add an AbortIncompleteMultipartUpload lifecycle rule",
+				Thresholds: Thresholds(cfg),
+			})
 		}
 	}
-	return nil
+	return results, nil
 }
 
 // RDSHeuristic checks for stopped instances or instances with 0 connections.
 type RDSHeuristic struct {
-	CW *internalaws.CloudWatchClient
+	Tunable
+	CW CloudWatchClient
+}
+
+// RDSConfig holds the RDSHeuristic thresholds.
+type RDSConfig struct {
+	MaxConnections float64  `yaml:"max_connections"` // Flag instances whose peak DatabaseConnections is at or below this
+	Lookback       Duration `yaml:"lookback"`
+}
+
+func (h *RDSHeuristic) Defaults() interface{} {
+	return &RDSConfig{MaxConnections: 0, Lookback: Days(7)}
 }
 
 func (h *RDSHeuristic) Name() string { return "RDSHeuristic" }
 
-func (h *RDSHeuristic) Run(ctx context.Context, g *graph.Graph) error {
-	g.Mu.RLock()
-	var rdsInstances []*graph.Node
-	for _, node := range g.Nodes {
-		if node.Type == "AWS::RDS::DBInstance" {
-			rdsInstances = append(rdsInstances, node)
-		}
-	}
-	g.Mu.RUnlock()
+func (h *RDSHeuristic) Flags() []string { return []string{"AWS::RDS::DBInstance"} }
+
+func (h *RDSHeuristic) Analyze(ctx context.Context, v *graph.View, cov *Coverage) ([]HeuristicResult, error) {
+	var results []HeuristicResult
+
+	rdsInstances := v.NodesByType("AWS::RDS::DBInstance")
+	cov.Examine(len(rdsInstances))
 
 	for _, node := range rdsInstances {
 		status, _ := node.Properties["Status"].(string)
 
 		if status == "stopped" {
-			g.MarkWaste(node.ID, 80)
-			node.Properties["Reason"] = "RDS Instance is stopped"
+			results = append(results, HeuristicResult{
+				ResourceID: node.ID,
+				Category:   graph.CategoryWaste,
+				Confidence: 0.8,
+				RiskScore:  80,
+				Reason:     "RDS Instance is stopped",
+				Evidence:   []string{"Status: stopped"},
+				Action:     "Take a final snapshot and delete the instance",
+			})
 			continue
 		}
 
+		cfg := h.Defaults().(*RDSConfig)
+		h.Settings.Resolve(h.Name(), node, cfg)
+
 		endTime := time.Now()
-		startTime := endTime.Add(-7 * 24 * time.Hour)
-		var id string
-		fmt.Sscanf(node.ID, "arn:aws:rds:region:account:db:%s", &id)
-		if id == "" {
+		startTime := endTime.Add(-cfg.Lookback.Std())
+		arn, err := resource.ParseARN(node.ID)
+		if err != nil || arn.ResourceType != "db" {
+			cov.Skip(node.ID, "unrecognized DB instance ARN")
 			continue
 		}
+		id := arn.ID()
 
 		dims := []types.Dimension{
 			{Name: aws.String("DBInstanceIdentifier"), Value: aws.String(id)},
@@ -273,44 +402,65 @@ func (h *RDSHeuristic) Run(ctx context.Context, g *graph.Graph) error {
 
 		maxConns, err := h.CW.GetMetricMax(ctx, "AWS/RDS", "DatabaseConnections", dims, startTime, endTime)
 		if err != nil {
+			cov.Skip(node.ID, fmt.Sprintf("metric fetch failed: %v", err))
 			continue
 		}
 
-		if maxConns == 0 {
-			g.MarkWaste(node.ID, 60)
-			node.Properties["Reason"] = "RDS Instance has 0 connections in 7 days"
+		if maxConns <= cfg.MaxConnections {
+			results = append(results, HeuristicResult{
+				ResourceID: node.ID,
+				Category:   graph.CategoryWaste,
+				Confidence: 0.7,
+				RiskScore:  60,
+				Reason:     fmt.Sprintf("RDS Instance has %.0f connections in %s", maxConns, cfg.Lookback),
+				Evidence:   []string{fmt.Sprintf("DatabaseConnections max %.0f over %s", maxConns, cfg.Lookback)},
+				Action:     "Take a final snapshot and delete the instance",
+				Thresholds: Thresholds(cfg),
+			})
 		}
 	}
-	return nil
+	return results, nil
 }
 
 // ELBHeuristic checks for unused Load Balancers.
 type ELBHeuristic struct {
-	CW *internalaws.CloudWatchClient
+	Tunable
+	CW CloudWatchClient
+}
+
+// ELBConfig holds the ELBHeuristic thresholds.
+type ELBConfig struct {
+	MaxRequests float64  `yaml:"max_requests"` // Flag load balancers serving fewer requests than this
+	Lookback    Duration `yaml:"lookback"`
+}
+
+func (h *ELBHeuristic) Defaults() interface{} {
+	return &ELBConfig{MaxRequests: 10, Lookback: Days(7)}
 }
 
 func (h *ELBHeuristic) Name() string { return "ELBHeuristic" }
 
-func (h *ELBHeuristic) Run(ctx context.Context, g *graph.Graph) error {
-	g.Mu.RLock()
-	var elbs []*graph.Node
-	for _, node := range g.Nodes {
-		if node.Type == "AWS::ElasticLoadBalancingV2::LoadBalancer" {
-			elbs = append(elbs, node)
-		}
-	}
-	g.Mu.RUnlock()
+func (h *ELBHeuristic) Flags() []string { return []string{"AWS::ElasticLoadBalancingV2::LoadBalancer"} }
+
+func (h *ELBHeuristic) Analyze(ctx context.Context, v *graph.View, cov *Coverage) ([]HeuristicResult, error) {
+	var results []HeuristicResult
+
+	elbs := v.NodesByType("AWS::ElasticLoadBalancingV2::LoadBalancer")
+	cov.Examine(len(elbs))
 
 	for _, node := range elbs {
+		cfg := h.Defaults().(*ELBConfig)
+		h.Settings.Resolve(h.Name(), node, cfg)
+
 		endTime := time.Now()
-		startTime := endTime.Add(-7 * 24 * time.Hour)
-		var lbDimValue string
-		parts := strings.Split(node.ID, ":loadbalancer/")
-		if len(parts) > 1 {
-			lbDimValue = parts[1]
-		} else {
+		startTime := endTime.Add(-cfg.Lookback.Std())
+		// The CloudWatch dimension is the ARN suffix after "loadbalancer/", e.g. app/my-lb/50dc6c49.
+		arn, err := resource.ParseARN(node.ID)
+		if err != nil || arn.ResourceType != "loadbalancer" {
+			cov.Skip(node.ID, "unrecognized load balancer ARN")
 			continue
 		}
+		lbDimValue := arn.Resource
 
 		dims := []types.Dimension{
 			{Name: aws.String("LoadBalancer"), Value: aws.String(lbDimValue)},
@@ -318,80 +468,105 @@ func (h *ELBHeuristic) Run(ctx context.Context, g *graph.Graph) error {
 
 		requestCount, err := h.CW.GetMetricSum(ctx, "AWS/ApplicationELB", "RequestCount", dims, startTime, endTime)
 		if err != nil {
+			cov.Skip(node.ID, fmt.Sprintf("metric fetch failed: %v", err))
 			continue
 		}
 
-		if requestCount < 10 {
-			g.MarkWaste(node.ID, 70)
-			node.Properties["Reason"] = fmt.Sprintf("ELB unused: Only %.0f requests in 7 days", requestCount)
+		if requestCount < cfg.MaxRequests {
+			results = append(results, HeuristicResult{
+				ResourceID: node.ID,
+				Category:   graph.CategoryWaste,
+				Confidence: 0.8,
+				RiskScore:  70,
+				Reason:     fmt.Sprintf("ELB unused: Only %.0f requests in %s", requestCount, cfg.Lookback),
+				Evidence:   []string{fmt.Sprintf("RequestCount %.0f over %s", requestCount, cfg.Lookback)},
+				Action:     "Delete the load balancer",
+				Thresholds: Thresholds(cfg),
+			})
 		}
 	}
-	return nil
+	return results, nil
 }
 
 // UnderutilizedInstanceHeuristic identifies candidates for Right-Sizing.
 type UnderutilizedInstanceHeuristic struct {
-	CW      *internalaws.CloudWatchClient
-	Pricing *pricing.Client
+	Tunable
+	CW      CloudWatchClient
+	Pricing PricingClient
+}
+
+// UnderutilizedInstanceConfig holds the UnderutilizedInstanceHeuristic thresholds.
+type UnderutilizedInstanceConfig struct {
+	MaxCPUPercent float64  `yaml:"max_cpu_percent"` // Flag running instances whose peak CPU stays below this
+	Lookback      Duration `yaml:"lookback"`
+}
+
+func (h *UnderutilizedInstanceHeuristic) Defaults() interface{} {
+	return &UnderutilizedInstanceConfig{MaxCPUPercent: 5, Lookback: Days(7)}
 }
 
 func (h *UnderutilizedInstanceHeuristic) Name() string { return "UnderutilizedInstanceHeuristic" }
 
-func (h *UnderutilizedInstanceHeuristic) Run(ctx context.Context, g *graph.Graph) error {
-	g.Mu.RLock()
-	var instances []*graph.Node
-	for _, node := range g.Nodes {
-		if node.Type == "AWS::EC2::Instance" {
-			instances = append(instances, node)
-		}
-	}
-	g.Mu.RUnlock()
+func (h *UnderutilizedInstanceHeuristic) Flags() []string { return []string{"AWS::EC2::Instance"} }
+
+func (h *UnderutilizedInstanceHeuristic) Analyze(ctx context.Context, v *graph.View, cov *Coverage) ([]HeuristicResult, error) {
+	var results []HeuristicResult
+
+	instances := v.NodesByType("AWS::EC2::Instance")
 
 	for _, node := range instances {
 		state, _ := node.Properties["State"].(string)
 		if state != "running" {
 			continue
 		}
+		cov.Examine(1)
 
 		instanceType, _ := node.Properties["InstanceType"].(string)
-		instanceID := ""
-		if parts := strings.Split(node.ID, "/"); len(parts) > 1 {
-			instanceID = parts[len(parts)-1]
-		}
-		if instanceID == "" {
+		arn, err := resource.ParseARN(node.ID)
+		if err != nil || arn.ResourceType != "instance" {
+			cov.Skip(node.ID, "unrecognized instance ARN")
 			continue
 		}
+		instanceID := arn.ID()
+
+		cfg := h.Defaults().(*UnderutilizedInstanceConfig)
+		h.Settings.Resolve(h.Name(), node, cfg)
 
 		endTime := time.Now()
-		startTime := endTime.Add(-7 * 24 * time.Hour)
+		startTime := endTime.Add(-cfg.Lookback.Std())
 		dims := []types.Dimension{
 			{Name: aws.String("InstanceId"), Value: aws.String(instanceID)},
 		}
 
 		maxCPU, err := h.CW.GetMetricMax(ctx, "AWS/EC2", "CPUUtilization", dims, startTime, endTime)
 		if err != nil {
+			cov.Skip(node.ID, fmt.Sprintf("metric fetch failed: %v", err))
 			continue
 		}
 
-		if maxCPU < 5.0 {
-			g.MarkWaste(node.ID, 60)
-			node.Properties["Reason"] = fmt.Sprintf("Right-Sizing Opportunity: Max CPU %.2f%% < 5%% over 7 days", maxCPU)
-
+		if maxCPU < cfg.MaxCPUPercent {
+			var savings float64
 			if h.Pricing != nil {
-				region := "us-east-1"
-				parts := strings.Split(node.ID, ":")
-				if len(parts) > 3 {
-					region = parts[3]
-				}
-
-				cost, err := h.Pricing.GetEC2InstancePrice(ctx, region, instanceType)
+				cost, err := h.Pricing.GetEC2InstancePrice(ctx, resource.Region(node.ID, "us-east-1"), instanceType)
 				if err == nil {
-					node.Cost = cost
+					savings = cost
 				}
 			}
+
+			results = append(results, HeuristicResult{
+				ResourceID:     node.ID,
+				Category:       graph.CategoryRightsizing,
+				Confidence:     0.6,
+				RiskScore:      60,
+				MonthlySavings: savings,
+				Reason:         fmt.Sprintf("Right-Sizing Opportunity: Max CPU %.2f%% < %g%% over %s", maxCPU, cfg.MaxCPUPercent, cfg.Lookback),
+				Evidence:       []string{fmt.Sprintf("CPUUtilization max %.2f%% over %s", maxCPU, cfg.Lookback), "InstanceType " + instanceType},
+				Action:         "Move to a smaller instance type, or stop the instance",
+				Thresholds:     Thresholds(cfg),
+			})
 		}
 	}
-	return nil
+	return results, nil
 }
 
 // TagComplianceHeuristic checks for missing required tags.
@@ -401,23 +576,22 @@ type TagComplianceHeuristic struct {
 
 func (h *TagComplianceHeuristic) Name() string { return "TagComplianceHeuristic" }
 
-func (h *TagComplianceHeuristic) Run(ctx context.Context, g *graph.Graph) error {
+func (h *TagComplianceHeuristic) Analyze(ctx context.Context, v *graph.View, cov *Coverage) ([]HeuristicResult, error) {
 	if len(h.RequiredTags) == 0 {
-		return nil
+		return nil, nil
 	}
 
-	g.Mu.Lock()
-	defer g.Mu.Unlock()
-
-	for _, node := range g.Nodes {
+	var results []HeuristicResult
+	v.Each(func(node *graph.Node) {
 		tags, ok := node.Properties["Tags"].(map[string]string)
 		if !ok {
 			if node.Type == "AWS::EC2::Instance" || node.Type == "AWS::EC2::Volume" {
 				tags = make(map[string]string)
 			} else {
-				continue
+				return
 			}
 		}
+		cov.Examine(1)
 
 		missing := []string{}
 		for _, req := range h.RequiredTags {
@@ -431,17 +605,17 @@ func (h *TagComplianceHeuristic) Run(ctx context.Context, g *graph.Graph) error
 		}
 
 		if len(missing) > 0 {
-			if !node.IsWaste {
-				node.IsWaste = true
-				node.RiskScore = 40
-				node.Properties["Reason"] = fmt.Sprintf("Compliance Violation: Missing Tags: %s", strings.Join(missing, ", "))
-			} else {
-				currentReason, _ := node.Properties["Reason"].(string)
-				node.Properties["Reason"] = currentReason + fmt.Sprintf("; Compliance: Missing %s", strings.Join(missing, ", "))
-			}
+			results = append(results, HeuristicResult{
+				ResourceID: node.ID,
+				Category:   graph.CategoryCompliance,
+				Confidence: 1,
+				RiskScore:  40,
+				Reason:     fmt.Sprintf("Compliance Violation: Missing Tags: %s", strings.Join(missing, ", ")),
+				Action:     fmt.Sprintf("Add tags: %s", strings.Join(missing, ", ")),
+			})
 		}
-	}
-	return nil
+	})
+	return results, nil
 }
 
 // IAMHeuristic checks for dangerous IAM privileges on EC2 instances.
@@ -451,19 +625,16 @@ type IAMHeuristic struct {
 
 func (h *IAMHeuristic) Name() string { return "IAMHeuristic" }
 
-func (h *IAMHeuristic) Run(ctx context.Context, g *graph.Graph) error {
+func (h *IAMHeuristic) Flags() []string { return []string{"AWS::EC2::Instance"} }
+
+func (h *IAMHeuristic) Analyze(ctx context.Context, v *graph.View, cov *Coverage) ([]HeuristicResult, error) {
+	var results []HeuristicResult
+
 	if h.IAM == nil {
-		return nil
+		return nil, nil
 	}
 
-	g.Mu.RLock()
-	var instances []*graph.Node
-	for _, node := range g.Nodes {
-		if node.Type == "AWS::EC2::Instance" {
-			instances = append(instances, node)
-		}
-	}
-	g.Mu.RUnlock()
+	instances := v.NodesByType("AWS::EC2::Instance")
 
 	for _, node := range instances {
 		profile, ok := node.Properties["IamInstanceProfile"].(map[string]interface{})
@@ -475,72 +646,86 @@ func (h *IAMHeuristic) Run(ctx context.Context, g *graph.Graph) error {
 		if arn == "" {
 			continue
 		}
+		cov.Examine(1)
 
-		parts := strings.Split(arn, "/")
-		if len(parts) < 2 {
-			continue
-		}
-		profileName := parts[len(parts)-1]
+		profileName := resource.ResourceID(arn)
 
 		roles, err := h.IAM.GetRolesFromInstanceProfile(ctx, profileName)
 		if err != nil {
+			cov.Skip(node.ID, fmt.Sprintf("instance profile lookup failed: %v", err))
 			continue
 		}
 
 		for _, role := range roles {
 			isAdmin, err := h.IAM.CheckAdminPrivileges(ctx, role)
-			if err == nil && isAdmin {
-				g.MarkWaste(node.ID, 95)
-				node.Properties["Reason"] = fmt.Sprintf("SECURITY ALERT: Instance Profile '%s' has AdministratorAccess!", profileName)
+			if err != nil {
+				cov.Skip(node.ID, fmt.Sprintf("policy check for role %s failed: %v", role, err))
+				continue
+			}
+			if isAdmin {
+				results = append(results, HeuristicResult{
+					ResourceID: node.ID,
+					Category:   graph.CategorySecurity,
+					Confidence: 1,
+					RiskScore:  95,
+					Reason:     fmt.Sprintf("SECURITY ALERT: Instance Profile '%s' has AdministratorAccess!", profileName),
+					Evidence:   []string{"Role " + role + " has AdministratorAccess"},
+					Action:     fmt.Sprintf("Replace AdministratorAccess on role %s with a least-privilege policy", role),
+				})
 			}
 		}
 	}
-	return nil
+	return results, nil
 }
 
 // SnapshotChildrenHeuristic finds snapshots created from Waste Volumes.
 // "The Time Machine" Feature.
 type SnapshotChildrenHeuristic struct {
-	Pricing *pricing.Client
+	Pricing PricingClient
 }
 
 func (h *SnapshotChildrenHeuristic) Name() string { return "SnapshotChildrenHeuristic" }
 
-func (h *SnapshotChildrenHeuristic) Run(ctx context.Context, g *graph.Graph) error {
-	g.Mu.RLock()
-	var snapshots []*graph.Node
+func (h *SnapshotChildrenHeuristic) Flags() []string { return []string{"AWS::EC2::Snapshot"} }
+
+// DependsOn makes the heuristic run once volume waste is known.
+func (h *SnapshotChildrenHeuristic) DependsOn() Dependencies {
+	return Dependencies{Types: []string{"AWS::EC2::Volume"}}
+}
+
+func (h *SnapshotChildrenHeuristic) Analyze(ctx context.Context, v *graph.View, cov *Coverage) ([]HeuristicResult, error) {
+	var results []HeuristicResult
+
+	volumes := v.NodesByType("AWS::EC2::Volume")
+	snapshots := v.NodesByType("AWS::EC2::Snapshot")
 	wasteVolumes := make(map[string]bool)
 
-	// 1. Identify Waste Volumes first
-	for _, node := range g.Nodes {
-		if node.Type == "AWS::EC2::Volume" && node.IsWaste {
-			// Extract ID from ARN usually, or assume ID is in Properties if graph builder put it there.
-			// Graph builder usually uses ARN as ID. We need the raw ID (vol-xyz).
-			// Let's parse it from ARN "arn:aws:ec2:region:account:volume/vol-123".
-			parts := strings.Split(node.ID, "/")
-			if len(parts) > 1 {
-				volID := parts[len(parts)-1]
-				wasteVolumes[volID] = true
-			}
-		}
-		if node.Type == "AWS::EC2::Snapshot" {
-			snapshots = append(snapshots, node)
+	// 1. Identify Waste Volumes first (keyed by ARN so equal IDs in other accounts don't match)
+	for _, node := range volumes {
+		if node.IsWaste {
+			wasteVolumes[node.ID] = true
 		}
 	}
-	g.Mu.RUnlock()
 
 	// 2. Check Snapshots against Waste Volumes
+	cov.Examine(len(snapshots))
 	for _, snap := range snapshots {
 		volID, ok := snap.Properties["VolumeId"].(string)
 		if !ok || volID == "" {
 			continue
 		}
 
-		if wasteVolumes[volID] {
-			// It's a snapshot of a zombie volume!
-			g.MarkWaste(snap.ID, 90) // High confidence
-			snap.Properties["Reason"] = fmt.Sprintf("Snapshot of Waste Volume (%s)", volID)
+		// Snapshot ARNs carry no account, so scope the volume by the snapshot owner.
+		snapARN, err := resource.ParseARN(snap.ID)
+		if err != nil {
+			cov.Skip(snap.ID, "unrecognized snapshot ARN")
+			continue
+		}
+		owner, _ := snap.Properties["OwnerId"].(string)
+		volARN := resource.NewIdentity(owner, snapARN.Region).EC2("volume", volID)
 
+		if wasteVolumes[volARN] {
+			// It's a snapshot of a zombie volume!
 			// Calculate Cost
 			// Snapshot pricing is complex (incremental), but $0.05/GB is a safe standard upper bound for standard tier.
 			sizeGB := 0
@@ -550,11 +735,18 @@ func (h *SnapshotChildrenHeuristic) Run(ctx context.Context, g *graph.Graph) err
 				sizeGB = s
 			}
 
-			if sizeGB > 0 {
-				snap.Cost = float64(sizeGB) * 0.05
-			}
+			results = append(results, HeuristicResult{
+				ResourceID:     snap.ID,
+				Category:       graph.CategoryWaste,
+				Confidence:     0.9, // High confidence
+				RiskScore:      90,
+				MonthlySavings: float64(sizeGB) * 0.05,
+				Reason:         fmt.Sprintf("Snapshot of Waste Volume (%s)", volID),
+				Evidence:       []string{"Source volume " + volARN + " is flagged as waste"},
+				Action:         "Delete the snapshot once the volume is gone",
+			})
 		}
 	}
 
-	return nil
+	return results, nil
 }
@@ -2,6 +2,8 @@ package heuristics
 
 import (
 	"context"
+	"fmt"
+	"strings"
 	"testing"
 	"time"
 
@@ -14,40 +16,38 @@ func TestZombieEBSHeuristic(t *testing.T) {
 
 	// 1. Setup Graph with a Zombie Volume
 	// Instance stopped 40 days ago
-	g.AddNode("arn:aws:ec2:region:account:instance/i-stopped", "AWS::EC2::Instance", map[string]interface{}{
+	g.AddNode("arn:aws:ec2:us-east-1:123456789012:instance/i-stopped", "AWS::EC2::Instance", map[string]interface{}{
 		"State":      "stopped",
 		"LaunchTime": time.Now().Add(-40 * 24 * time.Hour),
 	})
 	// Volume attached to it
-	g.AddNode("vol-zombie", "AWS::EC2::Volume", map[string]interface{}{
+	g.AddNode("arn:aws:ec2:us-east-1:123456789012:volume/vol-zombie", "AWS::EC2::Volume", map[string]interface{}{
 		"State":              "in-use",
 		"AttachedInstanceId": "i-stopped",
 	})
 
 	// 2. Setup Graph with a Healthy Volume
 	// Instance running
-	g.AddNode("arn:aws:ec2:region:account:instance/i-running", "AWS::EC2::Instance", map[string]interface{}{
+	g.AddNode("arn:aws:ec2:us-east-1:123456789012:instance/i-running", "AWS::EC2::Instance", map[string]interface{}{
 		"State":      "running",
 		"LaunchTime": time.Now().Add(-10 * 24 * time.Hour),
 	})
 	// Volume attached to it
-	g.AddNode("vol-healthy", "AWS::EC2::Volume", map[string]interface{}{
+	g.AddNode("arn:aws:ec2:us-east-1:123456789012:volume/vol-healthy", "AWS::EC2::Volume", map[string]interface{}{
 		"State":              "in-use",
 		"AttachedInstanceId": "i-running",
 	})
 
 	// 3. Run Heuristic
 	h := &ZombieEBSHeuristic{}
-	if err := h.Run(ctx, g); err != nil {
+	if err := engineRun(h)(ctx, g); err != nil {
 		t.Fatalf("Heuristic run failed: %v", err)
 	}
 
 	// 4. Assertions
-	g.Mu.RLock()
-	defer g.Mu.RUnlock()
 
 	// Check Zombie
-	if node, ok := g.Nodes["vol-zombie"]; !ok {
+	if node, ok := g.Node("arn:aws:ec2:us-east-1:123456789012:volume/vol-zombie"); !ok {
 		t.Fatal("Zombie volume not found in graph")
 	} else {
 		if !node.IsWaste {
@@ -59,7 +59,7 @@ func TestZombieEBSHeuristic(t *testing.T) {
 	}
 
 	// Check Healthy
-	if node, ok := g.Nodes["vol-healthy"]; !ok {
+	if node, ok := g.Node("arn:aws:ec2:us-east-1:123456789012:volume/vol-healthy"); !ok {
 		t.Fatal("Healthy volume not found in graph")
 	} else {
 		if node.IsWaste {
@@ -84,19 +84,747 @@ func TestS3MultipartHeuristic(t *testing.T) {
 
 	// 3. Run Heuristic
 	h := &S3MultipartHeuristic{}
-	if err := h.Run(ctx, g); err != nil {
+	if err := engineRun(h)(ctx, g); err != nil {
 		t.Fatalf("Heuristic run failed: %v", err)
 	}
 
 	// 4. Assertions
-	g.Mu.RLock()
-	defer g.Mu.RUnlock()
 
-	if node, ok := g.Nodes["upload-old"]; !ok || !node.IsWaste {
+	if node, ok := g.Node("upload-old"); !ok || !node.IsWaste {
 		t.Error("Expected upload-old to be waste")
 	}
 
-	if node, ok := g.Nodes["upload-new"]; !ok || node.IsWaste {
+	if node, ok := g.Node("upload-new"); !ok || node.IsWaste {
 		t.Error("Expected upload-new NOT to be waste")
 	}
 }
+
+func TestStoppedInstanceHeuristic(t *testing.T) {
+	g := graph.NewGraph()
+	ctx := context.Background()
+	arn := func(kind, id string) string { return "arn:aws:ec2:us-east-1:123456789012:" + kind + "/" + id }
+
+	// Launched long ago, stopped 60 days ago, still paying for a volume and an EIP.
+	g.AddNode(arn("instance", "i-old"), "AWS::EC2::Instance", map[string]interface{}{
+		"State":      "stopped",
+		"LaunchTime": time.Now().Add(-400 * 24 * time.Hour),
+		"StoppedAt":  time.Now().Add(-60 * 24 * time.Hour),
+	})
+	g.AddNode(arn("volume", "vol-data"), "AWS::EC2::Volume", map[string]interface{}{"State": "in-use", "AttachedInstanceId": "i-old"})
+	g.AddNode(arn("elastic-ip", "eipalloc-1"), "AWS::EC2::EIP", map[string]interface{}{"InstanceId": "i-old"})
+	g.AddTypedEdge(arn("volume", "vol-data"), arn("instance", "i-old"), graph.EdgeTypeAttachedTo, 100)
+	g.AddTypedEdge(arn("elastic-ip", "eipalloc-1"), arn("instance", "i-old"), graph.EdgeTypeAttachedTo, 100)
+	nodeByID(g, arn("volume", "vol-data")).Cost = 8
+	nodeByID(g, arn("elastic-ip", "eipalloc-1")).Cost = 3.65
+
+	// Launched long ago but only stopped yesterday.
+	g.AddNode(arn("instance", "i-recent"), "AWS::EC2::Instance", map[string]interface{}{
+		"State":      "stopped",
+		"LaunchTime": time.Now().Add(-400 * 24 * time.Hour),
+		"StoppedAt":  time.Now().Add(-24 * time.Hour),
+	})
+	g.AddNode(arn("volume", "vol-recent"), "AWS::EC2::Volume", map[string]interface{}{"State": "in-use", "AttachedInstanceId": "i-recent"})
+
+	// Stopped, but the scan could not tell since when.
+	g.AddNode(arn("instance", "i-unknown"), "AWS::EC2::Instance", map[string]interface{}{"State": "stopped"})
+
+	if err := engineRun(&StoppedInstanceHeuristic{}, &ZombieEBSHeuristic{})(ctx, g); err != nil {
+		t.Fatalf("Heuristic run failed: %v", err)
+	}
+
+	f, ok := nodeByID(g, arn("instance", "i-old")).FindingBy("StoppedInstanceHeuristic")
+	if !ok {
+		t.Fatal("expected i-old to be flagged")
+	}
+	// vol-data carries its own ZombieEBS finding; only the EIP is added.
+	if f.MonthlySavings != 3.65 || len(f.Evidence) != 3 || f.Thresholds["min_stopped"] != "30d" {
+		t.Errorf("expected only the unflagged EIP cost rolled up, got %+v", f)
+	}
+	if !strings.Contains(strings.Join(f.Evidence, "\n"), "vol-data: $8.00/mo (kept on termination) (counted in its own finding)") {
+		t.Errorf("expected the flagged volume listed as evidence, got %v", f.Evidence)
+	}
+	if _, ok := nodeByID(g, arn("instance", "i-recent")).FindingBy("StoppedInstanceHeuristic"); ok {
+		t.Error("i-recent was stopped too recently to be flagged")
+	}
+	if _, ok := nodeByID(g, arn("volume", "vol-recent")).FindingBy("ZombieEBSHeuristic"); ok {
+		t.Error("ZombieEBS should judge by the stop time, not the launch time")
+	}
+	if _, ok := nodeByID(g, arn("volume", "vol-data")).FindingBy("ZombieEBSHeuristic"); !ok {
+		t.Error("expected vol-data to be flagged by ZombieEBS")
+	}
+
+	runs := g.HeuristicRuns()
+	if runs[0].Skipped != 1 || runs[0].Skips[0].ResourceID != arn("instance", "i-unknown") {
+		t.Errorf("expected the instance without a stop time to be skipped, got %+v", runs[0])
+	}
+}
+
+func TestNetworkHeuristics(t *testing.T) {
+	const (
+		vpc      = "arn:aws:ec2:us-east-1:123456789012:vpc/vpc-app"
+		subnet   = "arn:aws:ec2:us-east-1:123456789012:subnet/subnet-app"
+		spare    = "arn:aws:ec2:us-east-1:123456789012:subnet/subnet-spare"
+		eni      = "arn:aws:ec2:us-east-1:123456789012:network-interface/eni-app"
+		usedSG   = "arn:aws:ec2:us-east-1:123456789012:security-group/sg-used"
+		unusedSG = "arn:aws:ec2:us-east-1:123456789012:security-group/sg-unused"
+		defSG    = "arn:aws:ec2:us-east-1:123456789012:security-group/sg-default"
+		emptyVPC = "arn:aws:ec2:us-east-1:123456789012:vpc/vpc-empty"
+		emptySub = "arn:aws:ec2:us-east-1:123456789012:subnet/subnet-empty"
+		defVPC   = "arn:aws:ec2:us-east-1:123456789012:vpc/vpc-default"
+	)
+	g := graph.NewGraph()
+	g.AddNode(vpc, "AWS::EC2::VPC", nil)
+	g.AddNode(subnet, "AWS::EC2::Subnet", nil)
+	g.AddNode(spare, "AWS::EC2::Subnet", nil)
+	g.AddNode(eni, "AWS::EC2::NetworkInterface", nil)
+	g.AddNode(usedSG, "AWS::EC2::SecurityGroup", map[string]interface{}{"GroupName": "used", "ReferencedGroups": []string{"sg-unused"}})
+	g.AddNode(unusedSG, "AWS::EC2::SecurityGroup", map[string]interface{}{"GroupName": "unused"})
+	g.AddNode(defSG, "AWS::EC2::SecurityGroup", map[string]interface{}{"GroupName": "default", "IsDefault": true})
+	g.AddNode(emptyVPC, "AWS::EC2::VPC", nil)
+	g.AddNode(emptySub, "AWS::EC2::Subnet", nil)
+	g.AddNode(defVPC, "AWS::EC2::VPC", map[string]interface{}{"IsDefault": true})
+	g.AddTypedEdge(vpc, subnet, graph.EdgeTypeContains, 100)
+	g.AddTypedEdge(vpc, spare, graph.EdgeTypeContains, 100)
+	g.AddTypedEdge(vpc, usedSG, graph.EdgeTypeContains, 100)
+	g.AddTypedEdge(subnet, eni, graph.EdgeTypeContains, 100)
+	g.AddTypedEdge(eni, usedSG, graph.EdgeTypeSecuredBy, 100)
+	g.AddTypedEdge(emptyVPC, emptySub, graph.EdgeTypeContains, 100)
+
+	ctx := context.Background()
+	if err := engineRun(&UnusedSecurityGroupHeuristic{}, &EmptyVPCHeuristic{}, &EmptySubnetHeuristic{})(ctx, g); err != nil {
+		t.Fatalf("Heuristic run failed: %v", err)
+	}
+
+	want := map[string]bool{unusedSG: true, emptyVPC: true, emptySub: true, spare: true}
+	g.Read(func(v *graph.View) {
+		v.Each(func(n *graph.Node) {
+			if n.IsWaste != want[n.ID] {
+				t.Errorf("%s: IsWaste = %v, want %v", n.ID, n.IsWaste, want[n.ID])
+			}
+		})
+	})
+	if f := nodeByID(g, unusedSG).Findings; len(f) != 1 || len(f[0].Evidence) != 2 {
+		t.Errorf("unused group should note the rule referencing it, got %+v", f)
+	}
+}
+
+func TestNetworkHeuristics_SkipWithoutInterfaces(t *testing.T) {
+	g := graph.NewGraph()
+	g.AddNode("arn:aws:ec2:us-east-1:123456789012:security-group/sg-1", "AWS::EC2::SecurityGroup", nil)
+	g.AddNode("arn:aws:ec2:us-east-1:123456789012:vpc/vpc-1", "AWS::EC2::VPC", nil)
+
+	if err := engineRun(&UnusedSecurityGroupHeuristic{}, &EmptyVPCHeuristic{})(context.Background(), g); err != nil {
+		t.Fatalf("Heuristic run failed: %v", err)
+	}
+	g.Read(func(v *graph.View) {
+		v.Each(func(n *graph.Node) {
+			if n.IsWaste {
+				t.Errorf("%s flagged although no interfaces were scanned", n.ID)
+			}
+		})
+	})
+	for _, run := range g.HeuristicRuns() {
+		if run.Skipped != 1 {
+			t.Errorf("%s: skipped %d, want 1", run.Heuristic, run.Skipped)
+		}
+	}
+}
+
+func TestOrphanedENIHeuristic(t *testing.T) {
+	const (
+		orphan   = "arn:aws:ec2:us-east-1:123456789012:network-interface/eni-orphan"
+		lambda   = "arn:aws:ec2:us-east-1:123456789012:network-interface/eni-lambda"
+		inUse    = "arn:aws:ec2:us-east-1:123456789012:network-interface/eni-inuse"
+		publicIP = "arn:aws:ec2:us-east-1:123456789012:network-interface/eni-public"
+	)
+	g := graph.NewGraph()
+	g.AddNode(orphan, "AWS::EC2::NetworkInterface", map[string]interface{}{"Status": "available", "PrivateIpAddress": "10.0.0.5"})
+	g.AddNode(lambda, "AWS::EC2::NetworkInterface", map[string]interface{}{"Status": "available", "RequesterManaged": true, "RequesterId": "AROAEXAMPLE"})
+	g.AddNode(inUse, "AWS::EC2::NetworkInterface", map[string]interface{}{"Status": "in-use"})
+	g.AddNode(publicIP, "AWS::EC2::NetworkInterface", map[string]interface{}{"Status": "available", "PublicIp": "203.0.113.7"})
+
+	if err := engineRun(&OrphanedENIHeuristic{})(context.Background(), g); err != nil {
+		t.Fatalf("Heuristic run failed: %v", err)
+	}
+
+	if nodeByID(g, inUse).IsWaste {
+		t.Error("attached interfaces are not orphaned")
+	}
+	for id, want := range map[string]float64{orphan: 0.9, lambda: 0.6, publicIP: 0.9} {
+		f, ok := nodeByID(g, id).FindingBy("OrphanedENIHeuristic")
+		if !ok {
+			t.Errorf("%s: expected a finding", id)
+			continue
+		}
+		if f.Confidence != want {
+			t.Errorf("%s: confidence = %v, want %v", id, f.Confidence, want)
+		}
+	}
+}
+
+func TestIdleVPCEndpointHeuristic(t *testing.T) {
+	endpoint := func(epType, state string, age time.Duration, enis ...string) map[string]interface{} {
+		created := time.Now().Add(-age)
+		return map[string]interface{}{
+			"VpcEndpointType": epType, "State": state, "ServiceName": "com.amazonaws.us-east-1.ssm",
+			"VpcId": "vpc-app", "NetworkInterfaceIds": enis, "CreateTime": &created,
+		}
+	}
+	const old = 60 * 24 * time.Hour // Past the 30-day lookback
+	tests := []struct {
+		name    string
+		props   map[string]interface{}
+		cw      fakeCloudWatch
+		pricing bool
+		savings float64 // -1: no finding
+		skipped bool
+	}{
+		// Billed per hour in each Availability Zone it has an interface in.
+		{"idle in two zones", endpoint("Interface", "available", old, "eni-a", "eni-b"), fakeCloudWatch{"BytesProcessed": 1e6}, true, 14.60, false},
+		{"idle without pricing", endpoint("Interface", "available", old, "eni-a"), fakeCloudWatch{"BytesProcessed": 0}, false, 0, false},
+		{"busy", endpoint("Interface", "available", old, "eni-a"), fakeCloudWatch{"BytesProcessed": 5e9}, true, -1, false},
+		{"gateway", endpoint("Gateway", "available", old), fakeCloudWatch{}, true, -1, false},
+		{"pending", endpoint("Interface", "pending", old, "eni-a"), fakeCloudWatch{}, true, -1, false},
+		{"new", endpoint("Interface", "available", 24*time.Hour, "eni-a"), fakeCloudWatch{}, true, -1, false},
+		{"no datapoints", endpoint("Interface", "available", old, "eni-a"), fakeCloudWatch{}, true, -1, true},
+	}
+	for _, tt := range tests {
+		const id = "arn:aws:ec2:us-east-1:123456789012:vpc-endpoint/vpce-1"
+		g := graph.NewGraph()
+		g.AddNode(id, "AWS::EC2::VPCEndpoint", tt.props)
+		h := &IdleVPCEndpointHeuristic{CW: tt.cw}
+		if tt.pricing {
+			h.Pricing = testPrices()
+		}
+		if err := engineRun(h)(context.Background(), g); err != nil {
+			t.Fatalf("%s: heuristic run failed: %v", tt.name, err)
+		}
+
+		f, ok := nodeByID(g, id).FindingBy("IdleVPCEndpointHeuristic")
+		if ok != (tt.savings >= 0) {
+			t.Errorf("%s: finding = %v, want %v", tt.name, ok, tt.savings >= 0)
+		} else if ok && (!near(f.MonthlySavings, tt.savings) || f.Category != graph.CategoryWaste) {
+			t.Errorf("%s: got $%.2f %s, want $%.2f waste", tt.name, f.MonthlySavings, f.Category, tt.savings)
+		}
+		if skipped := g.HeuristicRuns()[0].Skipped == 1; skipped != tt.skipped {
+			t.Errorf("%s: skipped = %v, want %v", tt.name, skipped, tt.skipped)
+		}
+	}
+}
+
+func TestModernInstanceType(t *testing.T) {
+	tests := map[string]string{
+		"m4.large":    "m6i.large",
+		"c4.2xlarge":  "c6i.2xlarge",
+		"t2.micro":    "t3.micro",
+		"m3.medium":   "m6i.large",
+		"m4.10xlarge": "m6i.12xlarge",
+		"m5.large":    "",
+		"m4":          "",
+	}
+	for in, want := range tests {
+		got, ok := ModernInstanceType(in)
+		if got != want || ok != (want != "") {
+			t.Errorf("ModernInstanceType(%q) = %q, %v, want %q", in, got, ok, want)
+		}
+	}
+}
+
+func TestGP3Equivalent(t *testing.T) {
+	tests := []struct {
+		size             int
+		iops, throughput int
+	}{
+		{size: 20, iops: 3000, throughput: 128},
+		{size: 500, iops: 3000, throughput: 250},
+		{size: 2000, iops: 6000, throughput: 250},
+		{size: 10000, iops: 16000, throughput: 250},
+	}
+	for _, tt := range tests {
+		iops, throughput := GP3Equivalent(tt.size)
+		if iops != tt.iops || throughput != tt.throughput {
+			t.Errorf("GP3Equivalent(%d) = %d, %d, want %d, %d", tt.size, iops, throughput, tt.iops, tt.throughput)
+		}
+	}
+}
+
+func TestModernizationHeuristic_SkipsWithoutPricing(t *testing.T) {
+	g := graph.NewGraph()
+	g.AddNode("arn:aws:ec2:us-east-1:123456789012:volume/vol-gp2", "AWS::EC2::Volume", map[string]interface{}{"VolumeType": "gp2", "Size": 100})
+	g.AddNode("arn:aws:ec2:us-east-1:123456789012:volume/vol-gp3", "AWS::EC2::Volume", map[string]interface{}{"VolumeType": "gp3", "Size": 100})
+	g.AddNode("arn:aws:ec2:us-east-1:123456789012:instance/i-m4", "AWS::EC2::Instance", map[string]interface{}{"InstanceType": "m4.large", "State": "running"})
+	g.AddNode("arn:aws:ec2:us-east-1:123456789012:instance/i-m6i", "AWS::EC2::Instance", map[string]interface{}{"InstanceType": "m6i.large", "State": "running"})
+
+	if err := engineRun(&ModernizationHeuristic{})(context.Background(), g); err != nil {
+		t.Fatalf("Heuristic run failed: %v", err)
+	}
+	runs := g.HeuristicRuns()
+	if len(runs) != 1 || runs[0].Examined != 2 || runs[0].Skipped != 2 {
+		t.Errorf("expected the gp2 volume and m4 instance examined and skipped, got %+v", runs)
+	}
+}
+
+func TestModernizationHeuristic_Recommends(t *testing.T) {
+	tests := []struct {
+		name    string
+		typ     string
+		props   map[string]interface{}
+		savings float64 // 0: no finding
+		action  string
+		skipped bool
+	}{
+		// gp3 storage is 20% cheaper; throughput above 125 MiB/s is billed.
+		{"gp2 500 GB", "AWS::EC2::Volume", map[string]interface{}{"VolumeType": "gp2", "Size": int32(500)}, 5.00, "gp3 with 3000 IOPS and 250 MiB/s", false},
+		{"gp2 2000 GB", "AWS::EC2::Volume", map[string]interface{}{"VolumeType": "gp2", "Size": int32(2000)}, 20.00, "gp3 with 6000 IOPS and 250 MiB/s", false},
+		{"gp2 below min savings", "AWS::EC2::Volume", map[string]interface{}{"VolumeType": "gp2", "Size": int32(20)}, 0, "", false},
+		{"m4", "AWS::EC2::Instance", map[string]interface{}{"InstanceType": "m4.large", "State": "running"}, 2.92, "m6i.large", false},
+		{"c4", "AWS::EC2::Instance", map[string]interface{}{"InstanceType": "c4.2xlarge", "State": "running"}, 42.15, "c6i.2xlarge", false},
+		{"stopped m4", "AWS::EC2::Instance", map[string]interface{}{"InstanceType": "m4.large", "State": "stopped"}, 0, "", false},
+		{"target unpriced", "AWS::EC2::Instance", map[string]interface{}{"InstanceType": "r4.large", "State": "running"}, 0, "", true},
+	}
+	for _, tt := range tests {
+		const id = "arn:aws:ec2:us-east-1:123456789012:resource/r-1"
+		g := graph.NewGraph()
+		g.AddNode(id, tt.typ, tt.props)
+		if err := engineRun(&ModernizationHeuristic{Pricing: testPrices()})(context.Background(), g); err != nil {
+			t.Fatalf("%s: heuristic run failed: %v", tt.name, err)
+		}
+
+		f, ok := nodeByID(g, id).FindingBy("ModernizationHeuristic")
+		if ok != (tt.savings > 0) {
+			t.Errorf("%s: finding = %v, want %v", tt.name, ok, tt.savings > 0)
+			continue
+		}
+		if ok && (!near(f.MonthlySavings, tt.savings) || !strings.Contains(f.Action, tt.action) || f.Category != graph.CategoryRightsizing) {
+			t.Errorf("%s: got $%.2f %s %q, want $%.2f rightsizing containing %q", tt.name, f.MonthlySavings, f.Category, f.Action, tt.savings, tt.action)
+		}
+		if skipped := g.HeuristicRuns()[0].Skipped == 1; skipped != tt.skipped {
+			t.Errorf("%s: skipped = %v, want %v", tt.name, skipped, tt.skipped)
+		}
+	}
+}
+
+func TestGravitonType(t *testing.T) {
+	tests := map[string]string{
+		"m5.large":       "m7g.large",
+		"t3a.micro":      "t4g.micro",
+		"c6i.16xlarge":   "c7g.16xlarge",
+		"m5.24xlarge":    "",
+		"db.r5.xlarge":   "db.r7g.xlarge",
+		"db.t3.micro":    "db.t4g.micro",
+		"db.m5.medium":   "",
+		"m7g.large":      "",
+		"p3.2xlarge":     "",
+		"not-a-instance": "",
+	}
+	for in, want := range tests {
+		got, ok := GravitonType(in)
+		if got != want || ok != (want != "") {
+			t.Errorf("GravitonType(%q) = %q, %v, want %q", in, got, ok, want)
+		}
+	}
+}
+
+func TestPlanGraviton(t *testing.T) {
+	tests := []struct {
+		name   string
+		typ    string
+		props  map[string]interface{}
+		effort MigrationEffort
+		ok     bool
+	}{
+		{"current postgres", "AWS::RDS::DBInstance", map[string]interface{}{"InstanceClass": "db.m5.large", "Engine": "postgres", "EngineVersion": "15.4"}, EffortLow, true},
+		{"old mysql", "AWS::RDS::DBInstance", map[string]interface{}{"InstanceClass": "db.m5.large", "Engine": "mysql", "EngineVersion": "5.7.44"}, EffortMedium, true},
+		{"aurora mysql 3", "AWS::RDS::DBInstance", map[string]interface{}{"InstanceClass": "db.r5.large", "Engine": "aurora-mysql", "EngineVersion": "8.0.mysql_aurora.3.04.0"}, EffortLow, true},
+		{"oracle", "AWS::RDS::DBInstance", map[string]interface{}{"InstanceClass": "db.m5.large", "Engine": "oracle-ee", "EngineVersion": "19.0"}, 0, false},
+		{"linux instance", "AWS::EC2::Instance", map[string]interface{}{"InstanceType": "m5.large", "Architecture": "x86_64", "PlatformDetails": "Linux/UNIX"}, EffortMedium, true},
+		{"rhel instance", "AWS::EC2::Instance", map[string]interface{}{"InstanceType": "m5.large", "Architecture": "x86_64", "PlatformDetails": "Red Hat Enterprise Linux"}, EffortHigh, true},
+		{"windows instance", "AWS::EC2::Instance", map[string]interface{}{"InstanceType": "m5.large", "Architecture": "x86_64", "PlatformDetails": "Windows"}, 0, false},
+		{"arm instance", "AWS::EC2::Instance", map[string]interface{}{"InstanceType": "m7g.large", "Architecture": "arm64"}, 0, false},
+		{"node group", "AWS::EKS::NodeGroup", map[string]interface{}{"InstanceTypes": []string{"c5.xlarge"}, "AmiType": "AL2_x86_64"}, EffortMedium, true},
+		{"custom node group", "AWS::EKS::NodeGroup", map[string]interface{}{"InstanceTypes": []string{"c5.xlarge"}, "AmiType": "CUSTOM"}, EffortHigh, true},
+		{"gpu node group", "AWS::EKS::NodeGroup", map[string]interface{}{"InstanceTypes": []string{"c5.xlarge"}, "AmiType": "AL2_x86_64_GPU"}, 0, false},
+	}
+	for _, tt := range tests {
+		plan, ok := PlanGraviton(&graph.Node{Type: tt.typ, Properties: tt.props})
+		if ok != tt.ok || (ok && plan.Effort != tt.effort) {
+			t.Errorf("%s: got %v effort %s, want %v effort %s", tt.name, ok, plan.Effort, tt.ok, tt.effort)
+		}
+	}
+}
+
+func TestGravitonHeuristic_SkipsWithoutPricing(t *testing.T) {
+	g := graph.NewGraph()
+	g.AddNode("arn:aws:ec2:us-east-1:123456789012:instance/i-x86", "AWS::EC2::Instance", map[string]interface{}{"InstanceType": "m5.large", "State": "running", "PlatformDetails": "Linux/UNIX"})
+	g.AddNode("arn:aws:ec2:us-east-1:123456789012:instance/i-stopped", "AWS::EC2::Instance", map[string]interface{}{"InstanceType": "m5.large", "State": "stopped"})
+	g.AddNode("arn:aws:rds:us-east-1:123456789012:db:orders", "AWS::RDS::DBInstance", map[string]interface{}{"InstanceClass": "db.m5.large", "Engine": "postgres", "EngineVersion": "15.4", "Status": "available"})
+
+	if err := engineRun(&GravitonHeuristic{})(context.Background(), g); err != nil {
+		t.Fatalf("Heuristic run failed: %v", err)
+	}
+	runs := g.HeuristicRuns()
+	if len(runs) != 1 || runs[0].Examined != 2 || runs[0].Skipped != 2 {
+		t.Errorf("expected the running instance and database examined and skipped, got %+v", runs)
+	}
+}
+
+func TestGravitonHeuristic_Prices(t *testing.T) {
+	tests := []struct {
+		id      string
+		typ     string
+		props   map[string]interface{}
+		savings float64 // 0: no finding
+		to      string
+		risk    int
+	}{
+		{"arn:aws:ec2:us-east-1:123456789012:instance/i-linux", "AWS::EC2::Instance",
+			map[string]interface{}{"InstanceType": "m5.large", "State": "running", "PlatformDetails": "Linux/UNIX"}, 10.51, "m7g.large", 40},
+		{"arn:aws:ec2:us-east-1:123456789012:instance/i-small", "AWS::EC2::Instance",
+			map[string]interface{}{"InstanceType": "t3.micro", "State": "running", "PlatformDetails": "Linux/UNIX"}, 0, "", 0},
+		// Node groups are priced per node, times the desired size.
+		{"arn:aws:eks:us-east-1:123456789012:nodegroup/prod/workers/1", "AWS::EKS::NodeGroup",
+			map[string]interface{}{"InstanceTypes": []string{"c5.xlarge"}, "AmiType": "AL2_x86_64", "DesiredSize": int32(3)}, 54.75, "c7g.xlarge", 40},
+		{"arn:aws:eks:us-east-1:123456789012:nodegroup/prod/custom/1", "AWS::EKS::NodeGroup",
+			map[string]interface{}{"InstanceTypes": []string{"c5.xlarge"}, "AmiType": "CUSTOM", "DesiredSize": int32(1)}, 18.25, "c7g.xlarge", 60},
+		// Multi-AZ pays for the standby on both classes.
+		{"arn:aws:rds:us-east-1:123456789012:db:orders", "AWS::RDS::DBInstance",
+			map[string]interface{}{"InstanceClass": "db.m5.large", "Engine": "postgres", "EngineVersion": "15.4", "Status": "available", "MultiAZ": true}, 29.20, "db.m7g.large", 20},
+	}
+	g := graph.NewGraph()
+	for _, tt := range tests {
+		g.AddNode(tt.id, tt.typ, tt.props)
+	}
+	if err := engineRun(&GravitonHeuristic{Pricing: testPrices()})(context.Background(), g); err != nil {
+		t.Fatalf("Heuristic run failed: %v", err)
+	}
+
+	for _, tt := range tests {
+		f, ok := nodeByID(g, tt.id).FindingBy("GravitonHeuristic")
+		if ok != (tt.savings > 0) {
+			t.Errorf("%s: finding = %v, want %v", tt.id, ok, tt.savings > 0)
+			continue
+		}
+		if !ok {
+			continue
+		}
+		if !near(f.MonthlySavings, tt.savings) || f.Action != "Move to "+tt.to {
+			t.Errorf("%s: got $%.2f %q, want $%.2f to %s", tt.id, f.MonthlySavings, f.Action, tt.savings, tt.to)
+		}
+		// Harder moves rank lower: more risk, less confidence.
+		if f.RiskScore != tt.risk || !near(f.Confidence, 0.9-0.01*float64(tt.risk-20)) {
+			t.Errorf("%s: risk %d confidence %.2f, want risk %d", tt.id, f.RiskScore, f.Confidence, tt.risk)
+		}
+	}
+}
+
+func TestGP3Target(t *testing.T) {
+	tests := []struct {
+		iops, mibps     float64
+		target, through int
+		ok              bool
+	}{
+		{iops: 450, mibps: 20, target: 3000, through: 125, ok: true},
+		{iops: 7210, mibps: 300, target: 7300, through: 300, ok: true},
+		{iops: 3500, mibps: 900, target: 3600, through: 900, ok: true},
+		{iops: 20000, mibps: 100, ok: false},
+		{iops: 3000, mibps: 1200, ok: false},
+	}
+	for _, tt := range tests {
+		target, through, ok := GP3Target(tt.iops, tt.mibps)
+		if ok != tt.ok || target != tt.target || through != tt.through {
+			t.Errorf("GP3Target(%g, %g) = %d, %d, %v, want %d, %d, %v", tt.iops, tt.mibps, target, through, ok, tt.target, tt.through, tt.ok)
+		}
+	}
+}
+
+func TestProvisionedIOPSHeuristic_Candidates(t *testing.T) {
+	g := graph.NewGraph()
+	vol := func(id, volType string, iops, throughput int32) {
+		g.AddNode("arn:aws:ec2:us-east-1:123456789012:volume/"+id, "AWS::EC2::Volume", map[string]interface{}{
+			"State": "in-use", "VolumeType": volType, "Size": int32(500), "Iops": iops, "Throughput": throughput,
+		})
+	}
+	vol("vol-io1", "io1", 10000, 0)
+	vol("vol-gp3-fast", "gp3", 6000, 125)
+	vol("vol-gp3-base", "gp3", 3000, 125)
+	vol("vol-gp2", "gp2", 1500, 0)
+
+	if err := engineRun(&ProvisionedIOPSHeuristic{})(context.Background(), g); err != nil {
+		t.Fatalf("Heuristic run failed: %v", err)
+	}
+	runs := g.HeuristicRuns()
+	if len(runs) != 1 || runs[0].Examined != 2 || runs[0].Skipped != 2 {
+		t.Errorf("expected the io1 and provisioned gp3 volumes examined and skipped, got %+v", runs)
+	}
+}
+
+func TestProvisionedIOPSHeuristic_Recommend(t *testing.T) {
+	tests := []struct {
+		name                string
+		vol                 volumeShape
+		multiAttach         bool
+		needIOPS, needMiBps float64
+		savings             float64
+		action              string
+	}{
+		{"gp3 down to baseline", volumeShape{"gp3", 500, 6000, 250}, false, 1300, 65, 20.00, "to 3000 IOPS and 125 MiB/s"},
+		{"gp3 already tight", volumeShape{"gp3", 500, 6000, 125}, false, 7210, 100, 0, "to 6000 IOPS and 125 MiB/s"},
+		// Lowering io1 to 2,600 IOPS saves $481; gp3 at 3,000 saves more.
+		{"io1 to gp3", volumeShape{"io1", 500, 10000, 0}, false, 2600, 40, 672.50, "to gp3 with 3000 IOPS and 125 MiB/s"},
+		{"io1 multi-attach", volumeShape{"io1", 500, 10000, 0}, true, 2600, 40, 481.00, "to 2600 provisioned IOPS"},
+		{"io2 beyond gp3", volumeShape{"io2", 500, 20000, 0}, false, 18000, 100, 130.00, "to 18000 provisioned IOPS"},
+	}
+	h := &ProvisionedIOPSHeuristic{Pricing: testPrices()}
+	for _, tt := range tests {
+		node := &graph.Node{
+			ID:         "arn:aws:ec2:us-east-1:123456789012:volume/vol-1",
+			Type:       "AWS::EC2::Volume",
+			Properties: map[string]interface{}{"MultiAttachEnabled": tt.multiAttach},
+		}
+		res, err := h.recommend(context.Background(), node, tt.vol, tt.needIOPS, tt.needMiBps)
+		if err != nil {
+			t.Errorf("%s: %v", tt.name, err)
+			continue
+		}
+		if !near(res.MonthlySavings, tt.savings) || !strings.HasSuffix(res.Action, tt.action+" (online, no detach)") {
+			t.Errorf("%s: got $%.2f %q, want $%.2f %q", tt.name, res.MonthlySavings, res.Action, tt.savings, tt.action)
+		}
+	}
+}
+
+func TestProvisionedIOPSHeuristic_UsesPeaks(t *testing.T) {
+	const id = "arn:aws:ec2:us-east-1:123456789012:volume/vol-io1"
+	g := graph.NewGraph()
+	g.AddNode(id, "AWS::EC2::Volume", map[string]interface{}{
+		"State": "in-use", "VolumeType": "io1", "Size": int32(500), "Iops": int32(10000),
+	})
+	// Averages peak at 1,500 IOPS, but VolumeThroughputPercentage shows
+	// bursts to 4,800; with 30% headroom that needs 6,300 IOPS.
+	cw := fakeCloudWatch{
+		"VolumeReadOps": 1000, "VolumeWriteOps": 500,
+		"VolumeReadBytes": 20 << 20, "VolumeWriteBytes": 10 << 20,
+		"VolumeThroughputPercentage": 48,
+	}
+	if err := engineRun(&ProvisionedIOPSHeuristic{CW: cw, Pricing: testPrices()})(context.Background(), g); err != nil {
+		t.Fatalf("Heuristic run failed: %v", err)
+	}
+
+	f, ok := nodeByID(g, id).FindingBy("ProvisionedIOPSHeuristic")
+	if !ok {
+		t.Fatal("expected the io1 volume to be flagged")
+	}
+	if !near(f.MonthlySavings, 656.00) || !strings.Contains(f.Action, "gp3 with 6300 IOPS and 125 MiB/s") {
+		t.Errorf("got $%.2f %q, want $656.00 to gp3 with 6300 IOPS", f.MonthlySavings, f.Action)
+	}
+	if !strings.Contains(strings.Join(f.Evidence, "\n"), "Peak VolumeThroughputPercentage 48%") {
+		t.Errorf("expected the throughput percentage in the evidence, got %v", f.Evidence)
+	}
+}
+
+func TestSmallerRDSClass(t *testing.T) {
+	tests := map[string]string{
+		"db.r5.2xlarge":  "db.r5.xlarge",
+		"db.m6g.xlarge":  "db.m6g.large",
+		"db.m5.large":    "",
+		"db.t3.medium":   "db.t3.small",
+		"db.t3.micro":    "",
+		"db.m5.16xlarge": "db.m5.12xlarge",
+		"db.z1d.xlarge":  "",
+	}
+	for in, want := range tests {
+		got, ok := smallerRDSClass(in)
+		if got != want || ok != (want != "") {
+			t.Errorf("smallerRDSClass(%q) = %q, %v, want %q", in, got, ok, want)
+		}
+	}
+
+	if vcpus, mem, ok := rdsClassShape("db.r6g.4xlarge"); !ok || vcpus != 16 || mem != 128 {
+		t.Errorf("rdsClassShape(db.r6g.4xlarge) = %d, %g, %v, want 16, 128", vcpus, mem, ok)
+	}
+	if limit, ok := defaultMaxConnections("postgres", 8); !ok || limit != 901 {
+		t.Errorf("defaultMaxConnections(postgres, 8 GiB) = %d, %v, want 901", limit, ok)
+	}
+}
+
+func TestRDSRightsizingHeuristic_Candidates(t *testing.T) {
+	g := graph.NewGraph()
+	g.AddNode("arn:aws:rds:us-east-1:123456789012:db:orders", "AWS::RDS::DBInstance", map[string]interface{}{
+		"Status": "available", "InstanceClass": "db.r5.2xlarge", "Engine": "postgres",
+	})
+	g.AddNode("arn:aws:rds:us-east-1:123456789012:db:old", "AWS::RDS::DBInstance", map[string]interface{}{
+		"Status": "stopped", "InstanceClass": "db.r5.2xlarge", "Engine": "postgres",
+	})
+
+	if err := engineRun(&RDSRightsizingHeuristic{})(context.Background(), g); err != nil {
+		t.Fatalf("Heuristic run failed: %v", err)
+	}
+	runs := g.HeuristicRuns()
+	if len(runs) != 1 || runs[0].Examined != 1 || runs[0].Skipped != 1 {
+		t.Errorf("expected only the available database examined and skipped, got %+v", runs)
+	}
+}
+
+// rdsLoad returns percentiles for a db.r5.2xlarge (8 vCPUs, 64 GiB), which
+// fit a db.r5.xlarge with the default thresholds.
+func rdsLoad() fakeCloudWatch {
+	return fakeCloudWatch{"CPUUtilization": 20, "FreeableMemory": 50 << 30, "DatabaseConnections": 100}
+}
+
+func TestRDSRightsizingHeuristic_Fits(t *testing.T) {
+	tests := []struct {
+		name    string
+		metric  string
+		value   float64
+		fits    bool
+		wantErr bool
+	}{
+		{"fits", "", 0, true, false},
+		{"cpu", "CPUUtilization", 35, false, false},                // 70% on 4 vCPUs
+		{"memory", "FreeableMemory", 30 << 30, false, false},       // 34 GiB used of 32
+		{"connections", "DatabaseConnections", 3000, false, false}, // 83% of 3,604
+		{"no datapoints", "DatabaseConnections", -1, false, true},
+	}
+	node := &graph.Node{ID: "arn:aws:rds:us-east-1:123456789012:db:orders", Type: "AWS::RDS::DBInstance"}
+	cfg := (&RDSRightsizingHeuristic{}).Defaults().(*RDSRightsizingConfig)
+	for _, tt := range tests {
+		cw := rdsLoad()
+		if tt.value < 0 {
+			delete(cw, tt.metric)
+		} else if tt.metric != "" {
+			cw[tt.metric] = tt.value
+		}
+		h := &RDSRightsizingHeuristic{CW: cw}
+		fits, evidence, err := h.fits(context.Background(), node, "postgres", "db.r5.2xlarge", "db.r5.xlarge", cfg)
+		if (err != nil) != tt.wantErr || fits != tt.fits {
+			t.Errorf("%s: fits = %v, err = %v, want %v, error %v", tt.name, fits, err, tt.fits, tt.wantErr)
+		}
+		if tt.name == "fits" && !strings.Contains(strings.Join(evidence, "\n"), "projected 40.0% on db.r5.xlarge") {
+			t.Errorf("expected the projected CPU in the evidence, got %v", evidence)
+		}
+	}
+}
+
+func TestRDSRightsizingHeuristic_MonthlyCost(t *testing.T) {
+	tests := []struct {
+		name    string
+		engine  string
+		c       rdsConfig
+		cost    float64
+		wantErr bool
+	}{
+		{"single-AZ gp3", "postgres", rdsConfig{Class: "db.r5.xlarge", StorageType: "gp3", Storage: 100}, 376.50, false},
+		{"multi-AZ gp3", "postgres", rdsConfig{Class: "db.r5.xlarge", MultiAZ: true, StorageType: "gp3", Storage: 100}, 753.00, false},
+		{"io1", "mysql", rdsConfig{Class: "db.r5.xlarge", StorageType: "io1", Storage: 200, IOPS: 3000}, 690.00, false},
+		{"aurora", "aurora-postgresql", rdsConfig{Class: "db.r5.xlarge", StorageType: "aurora", Storage: 100}, 365.00, false},
+		{"unpriced class", "postgres", rdsConfig{Class: "db.x2g.large", StorageType: "gp3"}, 0, true},
+	}
+	h := &RDSRightsizingHeuristic{Pricing: testPrices()}
+	for _, tt := range tests {
+		cost, err := h.monthlyCost(context.Background(), "us-east-1", tt.engine, tt.c)
+		if (err != nil) != tt.wantErr || !near(cost, tt.cost) {
+			t.Errorf("%s: cost = %.2f, err = %v, want %.2f, error %v", tt.name, cost, err, tt.cost, tt.wantErr)
+		}
+	}
+}
+
+func TestRDSRightsizingHeuristic_Recommends(t *testing.T) {
+	tests := []struct {
+		name    string
+		props   map[string]interface{}
+		cw      fakeCloudWatch
+		savings float64 // 0: no finding
+		target  string
+		risk    int
+	}{
+		// db.m5.large has no smaller class, so only the non-prod checks apply.
+		{"non-prod Multi-AZ io1", withTags(map[string]interface{}{
+			"Status": "available", "InstanceClass": "db.m5.large", "Engine": "postgres",
+			"MultiAZ": true, "StorageType": "io1", "Storage": int32(200), "Iops": int32(3000),
+		}, map[string]string{"Environment": "staging"}), fakeCloudWatch{}, 751.10, "db.m5.large, Single-AZ, gp3 storage", 30},
+		{"prod Multi-AZ io1", withTags(map[string]interface{}{
+			"Status": "available", "InstanceClass": "db.m5.large", "Engine": "postgres",
+			"MultiAZ": true, "StorageType": "io1", "Storage": int32(200), "Iops": int32(3000),
+		}, map[string]string{"Environment": "production"}), fakeCloudWatch{}, 0, "", 0},
+		{"oversized", map[string]interface{}{
+			"Status": "available", "InstanceClass": "db.r5.2xlarge", "Engine": "postgres", "StorageType": "gp3", "Storage": int32(100),
+		}, rdsLoad(), 365.00, "db.r5.xlarge, Single-AZ, gp3 storage", 40},
+		{"busy", map[string]interface{}{
+			"Status": "available", "InstanceClass": "db.r5.2xlarge", "Engine": "postgres", "StorageType": "gp3", "Storage": int32(100),
+		}, fakeCloudWatch{"CPUUtilization": 35, "FreeableMemory": 50 << 30, "DatabaseConnections": 100}, 0, "", 0},
+		{"oversized non-prod Multi-AZ", withTags(map[string]interface{}{
+			"Status": "available", "InstanceClass": "db.r5.2xlarge", "Engine": "postgres",
+			"MultiAZ": true, "StorageType": "gp3", "Storage": int32(100),
+		}, map[string]string{"env": "dev"}), rdsLoad(), 1106.50, "db.r5.xlarge, Single-AZ, gp3 storage", 40},
+	}
+	for _, tt := range tests {
+		const id = "arn:aws:rds:us-east-1:123456789012:db:orders"
+		g := graph.NewGraph()
+		g.AddNode(id, "AWS::RDS::DBInstance", tt.props)
+		if err := engineRun(&RDSRightsizingHeuristic{CW: tt.cw, Pricing: testPrices()})(context.Background(), g); err != nil {
+			t.Fatalf("%s: heuristic run failed: %v", tt.name, err)
+		}
+
+		f, ok := nodeByID(g, id).FindingBy("RDSRightsizingHeuristic")
+		if ok != (tt.savings > 0) {
+			t.Errorf("%s: finding = %v, want %v", tt.name, ok, tt.savings > 0)
+			continue
+		}
+		if ok && (!near(f.MonthlySavings, tt.savings) || !strings.Contains(f.Action, "to "+tt.target+" (") || f.RiskScore != tt.risk) {
+			t.Errorf("%s: got $%.2f risk %d %q, want $%.2f risk %d to %s", tt.name, f.MonthlySavings, f.RiskScore, f.Action, tt.savings, tt.risk, tt.target)
+		}
+	}
+}
+
+func TestEnvironmentOf(t *testing.T) {
+	keys := []string{"Environment", "Env"}
+	if k, v := environmentOf(map[string]string{"env": "Staging", "Team": "data"}, keys); k != "env" || v != "Staging" {
+		t.Errorf("environmentOf = %q, %q, want env, Staging", k, v)
+	}
+	if _, v := environmentOf(map[string]string{"Team": "data"}, keys); v != "" {
+		t.Errorf("expected no environment, got %q", v)
+	}
+}
+
+func TestRDSSnapshotHeuristic(t *testing.T) {
+	g := graph.NewGraph()
+	const (
+		live = "arn:aws:rds:us-east-1:123456789012:db:orders"
+		gone = "arn:aws:rds:us-east-1:123456789012:db:legacy"
+	)
+	g.AddNode(live, "AWS::RDS::DBInstance", map[string]interface{}{"Status": "available"})
+	snap := func(name, snapType, source string, age int) string {
+		id := "arn:aws:rds:us-east-1:123456789012:snapshot:" + name
+		g.AddNode(id, "AWS::RDS::DBSnapshot", map[string]interface{}{
+			"SnapshotType":     snapType,
+			"Status":           "available",
+			"AllocatedStorage": int32(100),
+			"CreateTime":       time.Now().Add(-time.Duration(age) * 24 * time.Hour),
+		})
+		g.AddEdge(id, source)
+		return id
+	}
+	for i := 1; i <= 6; i++ {
+		snap(fmt.Sprintf("orders-%d", i), "manual", live, i)
+	}
+	snap("orders-auto", "automated", live, 10)
+	final := snap("legacy-final", "manual", gone, 300)
+
+	if err := engineRun(&RDSSnapshotHeuristic{})(context.Background(), g); err != nil {
+		t.Fatalf("Heuristic run failed: %v", err)
+	}
+
+	oldest := nodeByID(g, "arn:aws:rds:us-east-1:123456789012:snapshot:orders-6")
+	if !oldest.IsWaste {
+		t.Error("expected the sixth manual snapshot of orders to be flagged")
+	}
+	if nodeByID(g, "arn:aws:rds:us-east-1:123456789012:snapshot:orders-5").IsWaste {
+		t.Error("expected the five newest snapshots of orders to be kept")
+	}
+	if nodeByID(g, "arn:aws:rds:us-east-1:123456789012:snapshot:orders-auto").IsWaste {
+		t.Error("expected automated snapshots to be ignored")
+	}
+	f, ok := nodeByID(g, final).FindingBy("RDSSnapshotHeuristic")
+	if !ok || f.MonthlySavings != 100*rdsSnapshotGBMonth {
+		t.Errorf("expected the snapshot of the deleted database flagged at $%.2f, got %+v", 100*rdsSnapshotGBMonth, f)
+	}
+}
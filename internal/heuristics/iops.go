@@ -0,0 +1,249 @@
+package heuristics
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/DrSkyle/cloudslash/internal/graph"
+	"github.com/DrSkyle/cloudslash/internal/resource"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatch/types"
+)
+
+// gp3 includes 3,000 IOPS and 125 MiB/s; anything above is billed. It allows
+// up to 16,000 IOPS, 1,000 MiB/s, and 0.25 MiB/s per provisioned IOPS.
+const (
+	gp3BaseIOPS       = 3000
+	gp3BaseThroughput = 125
+	gp3MaxIOPS        = 16000
+	gp3MaxThroughput  = 1000
+)
+
+// ProvisionedIOPSHeuristic compares the IOPS and throughput provisioned on
+// io1, io2 and gp3 volumes with the peaks CloudWatch observed, and recommends
+// a lower provisioned value or a move to gp3.
+type ProvisionedIOPSHeuristic struct {
+	Tunable
+	CW      CloudWatchClient
+	Pricing PricingClient
+}
+
+// ProvisionedIOPSConfig holds the ProvisionedIOPSHeuristic thresholds.
+type ProvisionedIOPSConfig struct {
+	Lookback   Duration `yaml:"lookback"`
+	Headroom   float64  `yaml:"headroom"`    // Provision this fraction above the observed peak
+	MinSavings float64  `yaml:"min_savings"` // Ignore changes that save less per month
+}
+
+func (h *ProvisionedIOPSHeuristic) Defaults() interface{} {
+	return &ProvisionedIOPSConfig{Lookback: Days(14), Headroom: 0.3, MinSavings: 5}
+}
+
+func (h *ProvisionedIOPSHeuristic) Name() string { return "ProvisionedIOPSHeuristic" }
+
+func (h *ProvisionedIOPSHeuristic) Flags() []string { return []string{"AWS::EC2::Volume"} }
+
+// DependsOn makes the heuristic skip volumes already flagged for deletion.
+func (h *ProvisionedIOPSHeuristic) DependsOn() Dependencies {
+	return Dependencies{Heuristics: []string{"ZombieEBSHeuristic"}}
+}
+
+func (h *ProvisionedIOPSHeuristic) Analyze(ctx context.Context, v *graph.View, cov *Coverage) ([]HeuristicResult, error) {
+	var results []HeuristicResult
+
+	var candidates []*graph.Node
+	for _, n := range v.NodesByType("AWS::EC2::Volume") {
+		if n.Properties["State"] == "in-use" && overProvisionable(n) && !flaggedWaste(n) {
+			candidates = append(candidates, n)
+		}
+	}
+	cov.Examine(len(candidates))
+
+	for _, node := range candidates {
+		if h.CW == nil || h.Pricing == nil {
+			cov.Skip(node.ID, "metrics or pricing unavailable")
+			continue
+		}
+		cfg := h.Defaults().(*ProvisionedIOPSConfig)
+		h.Settings.Resolve(h.Name(), node, cfg)
+
+		// Too new to have a full window of load.
+		if created, ok := node.CreatedAt(); ok && time.Since(created) < cfg.Lookback.Std() {
+			continue
+		}
+
+		vol := volumeShapeOf(node)
+		dims := []types.Dimension{{Name: aws.String("VolumeId"), Value: aws.String(resource.ResourceID(node.ID))}}
+		endTime := time.Now()
+		startTime := endTime.Add(-cfg.Lookback.Std())
+
+		peakIOPS, period, err := h.CW.GetPeakRate(ctx, "AWS/EBS", []string{"VolumeReadOps", "VolumeWriteOps"}, dims, startTime, endTime)
+		if err != nil {
+			cov.Skip(node.ID, fmt.Sprintf("metric fetch failed: %v", err))
+			continue
+		}
+		peakBytes, _, err := h.CW.GetPeakRate(ctx, "AWS/EBS", []string{"VolumeReadBytes", "VolumeWriteBytes"}, dims, startTime, endTime)
+		if err != nil {
+			cov.Skip(node.ID, fmt.Sprintf("metric fetch failed: %v", err))
+			continue
+		}
+		peakMiBps := peakBytes / (1 << 20)
+
+		evidence := []string{
+			fmt.Sprintf("Provisioned %d IOPS; peak %.0f IOPS (%s average) over %s", vol.IOPS, peakIOPS, period, cfg.Lookback),
+			fmt.Sprintf("Peak throughput %.1f MiB/s", peakMiBps),
+		}
+		if vol.Type == "io1" || vol.Type == "io2" {
+			// Share of provisioned IOPS delivered; catches bursts the averages smooth over.
+			pct, err := h.CW.GetMetricMax(ctx, "AWS/EBS", "VolumeThroughputPercentage", dims, startTime, endTime)
+			if err != nil {
+				cov.Skip(node.ID, fmt.Sprintf("metric fetch failed: %v", err))
+				continue
+			}
+			peakIOPS = max(peakIOPS, pct/100*float64(vol.IOPS))
+			evidence = append(evidence, fmt.Sprintf("Peak VolumeThroughputPercentage %.0f%%", pct))
+		}
+
+		res, err := h.recommend(ctx, node, vol, peakIOPS*(1+cfg.Headroom), peakMiBps*(1+cfg.Headroom))
+		if err != nil {
+			cov.Skip(node.ID, fmt.Sprintf("pricing failed: %v", err))
+			continue
+		}
+		if res.MonthlySavings < cfg.MinSavings {
+			continue
+		}
+		res.ResourceID = node.ID
+		res.Category = graph.CategoryRightsizing
+		res.Evidence = append(evidence, res.Evidence...)
+		res.Thresholds = Thresholds(cfg)
+		results = append(results, res)
+	}
+	return results, nil
+}
+
+// overProvisionable reports whether a volume pays for IOPS or throughput
+// beyond what its type includes.
+func overProvisionable(n *graph.Node) bool {
+	vol := volumeShapeOf(n)
+	switch vol.Type {
+	case "io1", "io2":
+		return vol.IOPS > 0
+	case "gp3":
+		return vol.IOPS > gp3BaseIOPS || vol.Throughput > gp3BaseThroughput
+	}
+	return false
+}
+
+// volumeShape is the billed configuration of an EBS volume.
+type volumeShape struct {
+	Type       string
+	Size       int // GB
+	IOPS       int
+	Throughput int // MiB/s
+}
+
+func volumeShapeOf(n *graph.Node) volumeShape {
+	vol := volumeShape{}
+	vol.Type, _ = n.Properties["VolumeType"].(string)
+	vol.Size = intProp(n.Properties["Size"])
+	vol.IOPS = intProp(n.Properties["Iops"])
+	vol.Throughput = intProp(n.Properties["Throughput"])
+	return vol
+}
+
+func intProp(v interface{}) int {
+	switch x := v.(type) {
+	case int32:
+		return int(x)
+	case int:
+		return x
+	}
+	return 0
+}
+
+// GP3Target returns the gp3 IOPS and throughput that cover the needed rates:
+// at least the included baseline, and enough IOPS for the throughput. It
+// returns false when gp3 cannot deliver them.
+func GP3Target(iops, mibps float64) (int, int, bool) {
+	throughput := max(int(math.Ceil(mibps)), gp3BaseThroughput)
+	target := max(roundUp(iops, 100), gp3BaseIOPS, throughput*4)
+	if target > gp3MaxIOPS || throughput > gp3MaxThroughput {
+		return 0, 0, false
+	}
+	return target, throughput, true
+}
+
+func roundUp(v float64, step int) int {
+	return int(math.Ceil(v/float64(step))) * step
+}
+
+// recommend prices the cheapest configuration that still covers the needed
+// IOPS and throughput.
+func (h *ProvisionedIOPSHeuristic) recommend(ctx context.Context, node *graph.Node, vol volumeShape, needIOPS, needMiBps float64) (HeuristicResult, error) {
+	region := resource.Region(node.ID, "us-east-1")
+	perIOPS, perMiBps, err := h.Pricing.GetGP3PerformancePrice(ctx, region)
+	if err != nil {
+		return HeuristicResult{}, err
+	}
+	gp3Cost := func(iops, throughput int) float64 {
+		return float64(max(iops-gp3BaseIOPS, 0))*perIOPS + float64(max(throughput-gp3BaseThroughput, 0))*perMiBps
+	}
+
+	if vol.Type == "gp3" {
+		iops, throughput, ok := GP3Target(needIOPS, needMiBps)
+		if !ok {
+			return HeuristicResult{}, nil
+		}
+		iops, throughput = min(iops, vol.IOPS), min(throughput, max(vol.Throughput, gp3BaseThroughput))
+		savings := gp3Cost(vol.IOPS, vol.Throughput) - gp3Cost(iops, throughput)
+		return HeuristicResult{
+			Confidence:     0.8,
+			RiskScore:      30,
+			MonthlySavings: savings,
+			Reason:         fmt.Sprintf("gp3 volume provisions more IOPS or throughput than it uses; $%.2f/mo less at %d IOPS, %d MiB/s", savings, iops, throughput),
+			Action:         fmt.Sprintf("Modify the volume to %d IOPS and %d MiB/s (online, no detach)", iops, throughput),
+		}, nil
+	}
+
+	// io1 and io2: lower the provisioned IOPS, or move to gp3 if it covers the load.
+	perPIOPS, err := h.Pricing.GetProvisionedIOPSPrice(ctx, region, vol.Type)
+	if err != nil {
+		return HeuristicResult{}, err
+	}
+	lowered := min(max(roundUp(needIOPS, 100), 100), vol.IOPS)
+	res := HeuristicResult{
+		Confidence:     0.8,
+		RiskScore:      30,
+		MonthlySavings: float64(vol.IOPS-lowered) * perPIOPS,
+		Action:         fmt.Sprintf("Modify the volume to %d provisioned IOPS (online, no detach)", lowered),
+	}
+	res.Reason = fmt.Sprintf("%s volume provisions %d IOPS; $%.2f/mo less at %d IOPS", vol.Type, vol.IOPS, res.MonthlySavings, lowered)
+
+	iops, throughput, ok := GP3Target(needIOPS, needMiBps)
+	if multi, _ := node.Properties["MultiAttachEnabled"].(bool); !ok || multi {
+		return res, nil // gp3 cannot multi-attach
+	}
+	storage, err := h.Pricing.GetEBSPrice(ctx, region, vol.Type, vol.Size)
+	if err != nil {
+		return HeuristicResult{}, err
+	}
+	gp3Storage, err := h.Pricing.GetEBSPrice(ctx, region, "gp3", vol.Size)
+	if err != nil {
+		return HeuristicResult{}, err
+	}
+	current := storage + float64(vol.IOPS)*perPIOPS
+	gp3 := gp3Storage + gp3Cost(iops, throughput)
+	if current-gp3 <= res.MonthlySavings {
+		return res, nil
+	}
+	return HeuristicResult{
+		Confidence:     0.7, // gp3 has lower durability and latency guarantees than io2
+		RiskScore:      40,
+		MonthlySavings: current - gp3,
+		Reason:         fmt.Sprintf("%s volume costs $%.2f/mo; gp3 covers its peak load for $%.2f/mo", vol.Type, current, gp3),
+		Evidence:       []string{fmt.Sprintf("Lowering %s to %d IOPS would save $%.2f/mo", vol.Type, lowered, res.MonthlySavings)},
+		Action:         fmt.Sprintf("Modify the volume to gp3 with %d IOPS and %d MiB/s (online, no detach)", iops, throughput),
+	}, nil
+}
@@ -2,46 +2,66 @@ package heuristics
 
 import (
 	"context"
+	"fmt"
 
 	"github.com/DrSkyle/cloudslash/internal/graph"
 )
 
-type LogHoardersHeuristic struct{}
+type LogHoardersHeuristic struct {
+	Tunable
+}
+
+// LogHoardersConfig holds the LogHoardersHeuristic thresholds.
+type LogHoardersConfig struct {
+	MinStoredGB float64 `yaml:"min_stored_gb"` // Flag never-expiring log groups storing more than this
+}
+
+func (h *LogHoardersHeuristic) Defaults() interface{} {
+	return &LogHoardersConfig{MinStoredGB: 1}
+}
 
 func (h *LogHoardersHeuristic) Name() string {
 	return "LogHoarders"
 }
 
-func (h *LogHoardersHeuristic) Run(ctx context.Context, g *graph.Graph) error {
-	g.Mu.Lock()
-	defer g.Mu.Unlock()
+func (h *LogHoardersHeuristic) Flags() []string { return []string{"AWS::Logs::LogGroup"} }
 
-	for _, node := range g.Nodes {
-		if node.Type != "AWS::Logs::LogGroup" {
-			continue
-		}
+func (h *LogHoardersHeuristic) Analyze(ctx context.Context, v *graph.View, cov *Coverage) ([]HeuristicResult, error) {
+	var results []HeuristicResult
+
+	logGroups := v.NodesByType("AWS::Logs::LogGroup")
+	cov.Examine(len(logGroups))
 
+	for _, node := range logGroups {
 		retention, _ := node.Properties["Retention"].(string)
-		
+
 		// If retention IS SET (int32 or string != "Never"), we skip
 		// Check explicit "Never" marker we set in scanner
 		if retention != "Never" {
 			continue
 		}
 
+		cfg := h.Defaults().(*LogHoardersConfig)
+		h.Settings.Resolve(h.Name(), node, cfg)
+
 		storedBytes, _ := node.Properties["StoredBytes"].(int64)
 		storedGB := float64(storedBytes) / 1024 / 1024 / 1024
 
-		// Threshold: > 1GB and No Retention
-		if storedGB > 1.0 {
-			node.IsWaste = true
-			node.RiskScore = 40 // Lower risk, but definitely waste
-			node.Properties["Reason"] = "Log Hoarder: >1GB stored with Infinite Retention"
-			
-			// Cost Estimate: $0.03/GB (Standard logs)
-			node.Cost = storedGB * 0.03
+		// Threshold: > MinStoredGB and No Retention
+		if storedGB > cfg.MinStoredGB {
+			results = append(results, HeuristicResult{
+				ResourceID:     node.ID,
+				Category:       graph.CategoryWaste,
+				Confidence:     1,
+				RiskScore:      40,              // Lower risk, but definitely waste
+				MonthlySavings: storedGB * 0.03, // Cost Estimate: $0.03/GB (Standard logs)
+				Reason:         fmt.Sprintf("Log Hoarder: >%gGB stored with Infinite Retention", cfg.MinStoredGB),
+				Evidence:       []string{fmt.Sprintf("Stored %.1f GB", storedGB), "Retention: never expire"},
+				Action:         "Set a retention policy on the log group",
+				Thresholds:     Thresholds(cfg),
+			})
 		}
 	}
 
-	return nil
+	return results, nil
 }
@@ -0,0 +1,211 @@
+package heuristics
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/DrSkyle/cloudslash/internal/graph"
+	"github.com/DrSkyle/cloudslash/internal/resource"
+)
+
+// currentGeneration maps previous-generation instance families to the
+// current-generation family with the same shape.
+var currentGeneration = map[string]string{
+	"t2": "t3",
+	"m3": "m6i",
+	"m4": "m6i",
+	"c3": "c6i",
+	"c4": "c6i",
+	"r3": "r6i",
+	"r4": "r6i",
+	"i2": "i3",
+}
+
+// currentGenerationSizes covers sizes the new family does not offer; each
+// maps to the next size up so the replacement is never smaller.
+var currentGenerationSizes = map[string]string{
+	"m3.medium":   "m6i.large",
+	"m4.10xlarge": "m6i.12xlarge",
+	"c3.8xlarge":  "c6i.12xlarge",
+	"c4.8xlarge":  "c6i.12xlarge",
+}
+
+// ModernInstanceType returns the current-generation replacement for a
+// previous-generation instance type, e.g. m4.large -> m6i.large.
+func ModernInstanceType(instanceType string) (string, bool) {
+	if t, ok := currentGenerationSizes[instanceType]; ok {
+		return t, true
+	}
+	family, size, ok := strings.Cut(instanceType, ".")
+	if !ok {
+		return "", false
+	}
+	modern, ok := currentGeneration[family]
+	if !ok {
+		return "", false
+	}
+	return modern + "." + size, true
+}
+
+// GP3Equivalent returns the gp3 IOPS and throughput (MiB/s) that match the
+// baseline of a gp2 volume of sizeGB: 3 IOPS per GB between 100 and 16,000,
+// and 128 MiB/s up to 170 GB, 250 MiB/s above. gp3 includes at least 3,000
+// IOPS and 125 MiB/s.
+func GP3Equivalent(sizeGB int) (iops, throughput int) {
+	iops = min(max(3*sizeGB, 100), 16000)
+	throughput = 128
+	if sizeGB > 170 {
+		throughput = 250
+	}
+	return max(iops, 3000), max(throughput, 125)
+}
+
+// ModernizationHeuristic recommends gp3 for gp2 volumes and current-generation
+// families for previous-generation instances, when that costs less.
+type ModernizationHeuristic struct {
+	Tunable
+	Pricing PricingClient
+}
+
+// ModernizationConfig holds the ModernizationHeuristic thresholds.
+type ModernizationConfig struct {
+	MinSavings float64 `yaml:"min_savings"` // Ignore moves that save less per month
+}
+
+func (h *ModernizationHeuristic) Defaults() interface{} {
+	return &ModernizationConfig{MinSavings: 1}
+}
+
+func (h *ModernizationHeuristic) Name() string { return "ModernizationHeuristic" }
+
+func (h *ModernizationHeuristic) Flags() []string {
+	return []string{"AWS::EC2::Volume", "AWS::EC2::Instance"}
+}
+
+// DependsOn makes the heuristic skip resources already flagged for deletion.
+func (h *ModernizationHeuristic) DependsOn() Dependencies {
+	return Dependencies{Heuristics: []string{"ZombieEBSHeuristic", "StoppedInstanceHeuristic"}}
+}
+
+func (h *ModernizationHeuristic) Analyze(ctx context.Context, v *graph.View, cov *Coverage) ([]HeuristicResult, error) {
+	var results []HeuristicResult
+
+	var candidates []*graph.Node
+	for _, n := range v.NodesByType("AWS::EC2::Volume") {
+		if t, _ := n.Properties["VolumeType"].(string); t == "gp2" && !flaggedWaste(n) {
+			candidates = append(candidates, n)
+		}
+	}
+	for _, n := range v.NodesByType("AWS::EC2::Instance") {
+		t, _ := n.Properties["InstanceType"].(string)
+		if _, ok := ModernInstanceType(t); ok && n.Properties["State"] == "running" && !flaggedWaste(n) {
+			candidates = append(candidates, n)
+		}
+	}
+	cov.Examine(len(candidates))
+
+	for _, node := range candidates {
+		if h.Pricing == nil {
+			cov.Skip(node.ID, "pricing unavailable")
+			continue
+		}
+		cfg := h.Defaults().(*ModernizationConfig)
+		h.Settings.Resolve(h.Name(), node, cfg)
+
+		var res HeuristicResult
+		var err error
+		if node.Type == "AWS::EC2::Volume" {
+			res, err = h.volume(ctx, node)
+		} else {
+			res, err = h.instance(ctx, node)
+		}
+		if err != nil {
+			cov.Skip(node.ID, fmt.Sprintf("pricing failed: %v", err))
+			continue
+		}
+		if res.MonthlySavings < cfg.MinSavings {
+			continue
+		}
+		res.ResourceID = node.ID
+		res.Category = graph.CategoryRightsizing
+		res.Thresholds = Thresholds(cfg)
+		results = append(results, res)
+	}
+	return results, nil
+}
+
+func (h *ModernizationHeuristic) volume(ctx context.Context, node *graph.Node) (HeuristicResult, error) {
+	size := 0
+	switch s := node.Properties["Size"].(type) {
+	case int32:
+		size = int(s)
+	case int:
+		size = s
+	}
+	region := resource.Region(node.ID, "us-east-1")
+
+	current, err := h.Pricing.GetEBSPrice(ctx, region, "gp2", size)
+	if err != nil {
+		return HeuristicResult{}, err
+	}
+	storage, err := h.Pricing.GetEBSPrice(ctx, region, "gp3", size)
+	if err != nil {
+		return HeuristicResult{}, err
+	}
+	perIOPS, perMiBps, err := h.Pricing.GetGP3PerformancePrice(ctx, region)
+	if err != nil {
+		return HeuristicResult{}, err
+	}
+	iops, throughput := GP3Equivalent(size)
+	gp3 := storage + float64(iops-3000)*perIOPS + float64(throughput-125)*perMiBps
+
+	return HeuristicResult{
+		Confidence:     0.9,
+		RiskScore:      20,
+		MonthlySavings: current - gp3,
+		Reason:         fmt.Sprintf("gp2 volume costs $%.2f/mo more than gp3 with the same performance", current-gp3),
+		Evidence: []string{
+			fmt.Sprintf("gp2 %d GB: $%.2f/mo", size, current),
+			fmt.Sprintf("gp3 %d GB, %d IOPS, %d MiB/s: $%.2f/mo", size, iops, throughput, gp3),
+		},
+		Action: fmt.Sprintf("Modify the volume to gp3 with %d IOPS and %d MiB/s (online, no detach)", iops, throughput),
+	}, nil
+}
+
+func (h *ModernizationHeuristic) instance(ctx context.Context, node *graph.Node) (HeuristicResult, error) {
+	oldType, _ := node.Properties["InstanceType"].(string)
+	newType, _ := ModernInstanceType(oldType)
+	region := resource.Region(node.ID, "us-east-1")
+
+	current, err := h.Pricing.GetEC2InstancePrice(ctx, region, oldType)
+	if err != nil {
+		return HeuristicResult{}, err
+	}
+	modern, err := h.Pricing.GetEC2InstancePrice(ctx, region, newType)
+	if err != nil {
+		return HeuristicResult{}, err
+	}
+
+	return HeuristicResult{
+		Confidence:     0.7, // Moving from Xen to Nitro needs ENA and NVMe drivers
+		RiskScore:      30,
+		MonthlySavings: current - modern,
+		Reason:         fmt.Sprintf("Previous-generation %s costs $%.2f/mo more than %s", oldType, current-modern, newType),
+		Evidence: []string{
+			fmt.Sprintf("%s: $%.2f/mo", oldType, current),
+			fmt.Sprintf("%s: $%.2f/mo", newType, modern),
+		},
+		Action: fmt.Sprintf("Change the instance type to %s (stop, modify, start) once the AMI has ENA and NVMe drivers", newType),
+	}, nil
+}
+
+// flaggedWaste reports whether n already has a finding recommending deletion.
+func flaggedWaste(n *graph.Node) bool {
+	for _, f := range n.Findings {
+		if f.Category == graph.CategoryWaste {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,359 @@
+package heuristics
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/DrSkyle/cloudslash/internal/graph"
+	"github.com/DrSkyle/cloudslash/internal/resource"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatch/types"
+)
+
+// Every workload in a VPC (instances, load balancers, databases, NAT and
+// endpoint attachments) has a network interface, so the network heuristics
+// judge emptiness by interfaces. Without an interface scan they skip.
+const noInterfacesScanned = "network interfaces were not scanned"
+
+// networkPlumbing are the types a VPC holds even when nothing runs in it.
+var networkPlumbing = map[string]bool{
+	"AWS::EC2::Subnet":          true,
+	"AWS::EC2::RouteTable":      true,
+	"AWS::EC2::InternetGateway": true,
+	"AWS::EC2::SecurityGroup":   true,
+}
+
+// UnusedSecurityGroupHeuristic flags security groups no network interface or
+// instance uses. Default groups cannot be deleted and are ignored.
+type UnusedSecurityGroupHeuristic struct{}
+
+func (h *UnusedSecurityGroupHeuristic) Name() string { return "UnusedSecurityGroupHeuristic" }
+
+func (h *UnusedSecurityGroupHeuristic) Flags() []string { return []string{"AWS::EC2::SecurityGroup"} }
+
+func (h *UnusedSecurityGroupHeuristic) Analyze(ctx context.Context, v *graph.View, cov *Coverage) ([]HeuristicResult, error) {
+	var results []HeuristicResult
+
+	groups := v.NodesByType("AWS::EC2::SecurityGroup")
+	cov.Examine(len(groups))
+	scanned := len(v.NodesByType("AWS::EC2::NetworkInterface")) > 0
+
+	// Groups named in another group's rules, keyed by ARN.
+	referencedBy := make(map[string][]string)
+	for _, sg := range groups {
+		arn, err := resource.ParseARN(sg.ID)
+		if err != nil {
+			continue
+		}
+		refs, _ := sg.Properties["ReferencedGroups"].([]string)
+		for _, ref := range refs {
+			key := arn.Identity().EC2("security-group", ref)
+			referencedBy[key] = append(referencedBy[key], resource.ResourceID(sg.ID))
+		}
+	}
+
+	for _, sg := range groups {
+		if isDefault, _ := sg.Properties["IsDefault"].(bool); isDefault {
+			continue
+		}
+		if !scanned {
+			cov.Skip(sg.ID, noInterfacesScanned)
+			continue
+		}
+		used := false
+		for _, e := range v.ReverseEdges(sg.ID) {
+			if e.Type == graph.EdgeTypeSecuredBy {
+				used = true
+				break
+			}
+		}
+		if used {
+			continue
+		}
+
+		name, _ := sg.Properties["GroupName"].(string)
+		evidence := []string{"No network interface or instance uses " + name}
+		action := "Delete the security group"
+		if refs := referencedBy[sg.ID]; len(refs) > 0 {
+			evidence = append(evidence, fmt.Sprintf("Referenced by the rules of %v", refs))
+			action = "Remove the rules that reference it, then delete the security group"
+		}
+		results = append(results, HeuristicResult{
+			ResourceID: sg.ID,
+			Category:   graph.CategoryWaste,
+			Confidence: 0.8, // Launch templates may still name it
+			RiskScore:  20,
+			Reason:     "Unused security group",
+			Evidence:   evidence,
+			Action:     action,
+		})
+	}
+	return results, nil
+}
+
+// EmptyVPCHeuristic flags non-default VPCs with no workloads in any subnet.
+type EmptyVPCHeuristic struct{}
+
+func (h *EmptyVPCHeuristic) Name() string { return "EmptyVPCHeuristic" }
+
+func (h *EmptyVPCHeuristic) Flags() []string { return []string{"AWS::EC2::VPC"} }
+
+func (h *EmptyVPCHeuristic) Analyze(ctx context.Context, v *graph.View, cov *Coverage) ([]HeuristicResult, error) {
+	var results []HeuristicResult
+
+	vpcs := v.NodesByType("AWS::EC2::VPC")
+	cov.Examine(len(vpcs))
+	scanned := len(v.NodesByType("AWS::EC2::NetworkInterface")) > 0
+
+	for _, vpc := range vpcs {
+		if isDefault, _ := vpc.Properties["IsDefault"].(bool); isDefault {
+			continue
+		}
+		if !scanned {
+			cov.Skip(vpc.ID, noInterfacesScanned)
+			continue
+		}
+		workloads, subnets := contents(v, vpc.ID)
+		if workloads > 0 {
+			continue
+		}
+		results = append(results, HeuristicResult{
+			ResourceID: vpc.ID,
+			Category:   graph.CategoryWaste,
+			Confidence: 0.7,
+			RiskScore:  30,
+			Reason:     "VPC has no workloads",
+			Evidence:   []string{fmt.Sprintf("No network interfaces in its %d subnets", subnets)},
+			Action:     "Delete the VPC with its subnets, route tables, gateways and security groups",
+		})
+	}
+	return results, nil
+}
+
+// EmptySubnetHeuristic flags subnets with no network interfaces. Default
+// subnets are ignored, like default VPCs.
+type EmptySubnetHeuristic struct{}
+
+func (h *EmptySubnetHeuristic) Name() string { return "EmptySubnetHeuristic" }
+
+func (h *EmptySubnetHeuristic) Flags() []string { return []string{"AWS::EC2::Subnet"} }
+
+func (h *EmptySubnetHeuristic) Analyze(ctx context.Context, v *graph.View, cov *Coverage) ([]HeuristicResult, error) {
+	var results []HeuristicResult
+
+	subnets := v.NodesByType("AWS::EC2::Subnet")
+	cov.Examine(len(subnets))
+	scanned := len(v.NodesByType("AWS::EC2::NetworkInterface")) > 0
+
+	for _, subnet := range subnets {
+		if isDefault, _ := subnet.Properties["DefaultForAz"].(bool); isDefault {
+			continue
+		}
+		if !scanned {
+			cov.Skip(subnet.ID, noInterfacesScanned)
+			continue
+		}
+		if workloads, _ := contents(v, subnet.ID); workloads > 0 {
+			continue
+		}
+		cidr, _ := subnet.Properties["CidrBlock"].(string)
+		zone, _ := subnet.Properties["AvailabilityZone"].(string)
+		results = append(results, HeuristicResult{
+			ResourceID: subnet.ID,
+			Category:   graph.CategoryWaste,
+			Confidence: 0.6, // Often kept as reserved address space
+			RiskScore:  20,
+			Reason:     "Subnet has no network interfaces",
+			Evidence:   []string{fmt.Sprintf("%s in %s is empty", cidr, zone)},
+			Action:     "Delete the subnet, or tag it as reserved",
+		})
+	}
+	return results, nil
+}
+
+// OrphanedENIHeuristic flags network interfaces that are not attached to
+// anything. They are left behind by Lambda, EKS and load balancer teardown,
+// hold subnet addresses and, with a public IPv4 address, bill hourly.
+type OrphanedENIHeuristic struct {
+	Pricing PricingClient
+}
+
+func (h *OrphanedENIHeuristic) Name() string { return "OrphanedENIHeuristic" }
+
+func (h *OrphanedENIHeuristic) Flags() []string { return []string{"AWS::EC2::NetworkInterface"} }
+
+func (h *OrphanedENIHeuristic) Analyze(ctx context.Context, v *graph.View, cov *Coverage) ([]HeuristicResult, error) {
+	var results []HeuristicResult
+
+	enis := v.NodesByType("AWS::EC2::NetworkInterface")
+	cov.Examine(len(enis))
+
+	for _, node := range enis {
+		if status, _ := node.Properties["Status"].(string); status != "available" {
+			continue
+		}
+
+		evidence := []string{"Status is available (not attached)"}
+		if desc, _ := node.Properties["Description"].(string); desc != "" {
+			evidence = append(evidence, "Description: "+desc)
+		}
+		if ip, _ := node.Properties["PrivateIpAddress"].(string); ip != "" {
+			evidence = append(evidence, "Holds private IP "+ip)
+		}
+
+		// A service-managed interface may be picked up again by its service,
+		// and only that service can delete it.
+		confidence := WasteConfidence(0.9)
+		action := "Delete the network interface"
+		if managed, _ := node.Properties["RequesterManaged"].(bool); managed {
+			confidence = 0.6
+			action = "Release the interface through the service that owns it"
+			if requester, _ := node.Properties["RequesterId"].(string); requester != "" {
+				evidence = append(evidence, "Managed by requester "+requester)
+			}
+		}
+
+		var savings float64
+		if ip, _ := node.Properties["PublicIp"].(string); ip != "" {
+			if h.Pricing != nil {
+				if cost, err := h.Pricing.GetEIPPrice(ctx, resource.Region(node.ID, "us-east-1")); err == nil {
+					savings = cost
+				}
+			}
+			evidence = append(evidence, fmt.Sprintf("Public IPv4 %s: $%.2f/mo", ip, savings))
+		}
+
+		results = append(results, HeuristicResult{
+			ResourceID:     node.ID,
+			Category:       graph.CategoryWaste,
+			Confidence:     confidence,
+			RiskScore:      30,
+			MonthlySavings: savings,
+			Reason:         "Orphaned network interface",
+			Evidence:       evidence,
+			Action:         action,
+		})
+	}
+	return results, nil
+}
+
+// IdleVPCEndpointHeuristic flags interface endpoints that process next to no
+// data. They bill per hour in every Availability Zone they span; gateway
+// endpoints are free and ignored.
+type IdleVPCEndpointHeuristic struct {
+	Tunable
+	CW      CloudWatchClient
+	Pricing PricingClient
+}
+
+// IdleVPCEndpointConfig holds the IdleVPCEndpointHeuristic thresholds.
+type IdleVPCEndpointConfig struct {
+	MaxBytes float64  `yaml:"max_bytes"` // Total BytesProcessed
+	Lookback Duration `yaml:"lookback"`
+}
+
+func (h *IdleVPCEndpointHeuristic) Defaults() interface{} {
+	return &IdleVPCEndpointConfig{MaxBytes: 1e8, Lookback: Days(30)}
+}
+
+func (h *IdleVPCEndpointHeuristic) Name() string { return "IdleVPCEndpointHeuristic" }
+
+func (h *IdleVPCEndpointHeuristic) Flags() []string { return []string{"AWS::EC2::VPCEndpoint"} }
+
+func (h *IdleVPCEndpointHeuristic) Analyze(ctx context.Context, v *graph.View, cov *Coverage) ([]HeuristicResult, error) {
+	var results []HeuristicResult
+
+	endpoints := v.NodesByType("AWS::EC2::VPCEndpoint")
+	cov.Examine(len(endpoints))
+
+	for _, node := range endpoints {
+		epType, _ := node.Properties["VpcEndpointType"].(string)
+		if epType != "Interface" && epType != "GatewayLoadBalancer" {
+			continue
+		}
+		if state, _ := node.Properties["State"].(string); state != "available" {
+			continue
+		}
+		cfg := h.Defaults().(*IdleVPCEndpointConfig)
+		h.Settings.Resolve(h.Name(), node, cfg)
+
+		// Too new to have a full window of traffic.
+		if created, ok := node.CreatedAt(); ok && time.Since(created) < cfg.Lookback.Std() {
+			continue
+		}
+
+		service, _ := node.Properties["ServiceName"].(string)
+		vpcID, _ := node.Properties["VpcId"].(string)
+		dims := []types.Dimension{
+			{Name: aws.String("Endpoint Type"), Value: aws.String(epType)},
+			{Name: aws.String("Service Name"), Value: aws.String(service)},
+			{Name: aws.String("VPC Endpoint Id"), Value: aws.String(resource.ResourceID(node.ID))},
+			{Name: aws.String("VPC Id"), Value: aws.String(vpcID)},
+		}
+		endTime := time.Now()
+		startTime := endTime.Add(-cfg.Lookback.Std())
+		bytes, err := h.CW.GetMetricSum(ctx, "AWS/PrivateLinkEndpoints", "BytesProcessed", dims, startTime, endTime)
+		if err != nil {
+			cov.Skip(node.ID, fmt.Sprintf("metric fetch failed: %v", err))
+			continue
+		}
+		if bytes >= cfg.MaxBytes {
+			continue
+		}
+
+		// One endpoint interface per Availability Zone, each billed hourly.
+		zones := 1
+		if ids, ok := node.Properties["NetworkInterfaceIds"].([]string); ok && len(ids) > 0 {
+			zones = len(ids)
+		}
+		var savings float64
+		if h.Pricing != nil {
+			if cost, err := h.Pricing.GetVPCEndpointPrice(ctx, resource.Region(node.ID, "us-east-1")); err == nil {
+				savings = cost * float64(zones)
+			}
+		}
+
+		results = append(results, HeuristicResult{
+			ResourceID:     node.ID,
+			Category:       graph.CategoryWaste,
+			Confidence:     0.8,
+			RiskScore:      50,
+			MonthlySavings: savings,
+			Reason:         fmt.Sprintf("Idle VPC endpoint for %s", service),
+			Evidence:       []string{fmt.Sprintf("BytesProcessed %.0f over %s", bytes, cfg.Lookback), fmt.Sprintf("Billed in %d Availability Zones", zones)},
+			Action:         "Delete the VPC endpoint",
+			Thresholds:     Thresholds(cfg),
+		})
+	}
+	return results, nil
+}
+
+// contents walks Contains edges from id and counts the workloads and subnets
+// below it. Anything that is not network plumbing counts as a workload.
+func contents(v *graph.View, id string) (workloads, subnets int) {
+	seen := map[string]bool{id: true}
+	queue := []string{id}
+	for len(queue) > 0 {
+		cur := queue[0]
+		queue = queue[1:]
+		for _, e := range v.Edges(cur) {
+			if e.Type != graph.EdgeTypeContains || seen[e.TargetID] {
+				continue
+			}
+			seen[e.TargetID] = true
+			child, ok := v.Node(e.TargetID)
+			if !ok {
+				continue
+			}
+			switch {
+			case child.Type == "AWS::EC2::Subnet":
+				subnets++
+			case !networkPlumbing[child.Type]:
+				workloads++
+			}
+			queue = append(queue, e.TargetID)
+		}
+	}
+	return workloads, subnets
+}
@@ -0,0 +1,361 @@
+package heuristics
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/DrSkyle/cloudslash/internal/graph"
+)
+
+// PluginProtocol is the version of the plugin protocol spoken by this build.
+//
+// A plugin is an executable that reads one JSON request on stdin and writes
+// one JSON response on stdout. {"protocol":1,"command":"describe"} asks for
+// its PluginManifest; {"protocol":1,"command":"analyze",...} sends a
+// PluginRequest with the nodes of its declared types and expects a
+// PluginResponse. Anything written to stderr is quoted in errors.
+const PluginProtocol = 1
+
+const (
+	defaultPluginTimeout = 60 * time.Second
+	describeTimeout      = 10 * time.Second
+	maxPluginOutput      = 64 << 20 // Responses larger than this are rejected
+	maxPluginStderr      = 4 << 10  // Stderr kept for error messages
+)
+
+// pluginBaseEnv are the environment variables every plugin receives. Anything
+// else, AWS credentials included, must be declared in the manifest.
+var pluginBaseEnv = []string{"PATH", "HOME", "TMPDIR", "LANG", "LC_ALL", "TZ"}
+
+// PluginManifest is a plugin's answer to the describe command.
+type PluginManifest struct {
+	Name        string   `json:"name"`
+	Version     string   `json:"version,omitempty"`
+	Description string   `json:"description,omitempty"`
+	Protocol    int      `json:"protocol"`
+	Types       []string `json:"types"`             // Resource types it is sent and may flag
+	After       []string `json:"after,omitempty"`   // Heuristics whose findings it reads
+	Env         []string `json:"env,omitempty"`     // Environment variables passed through
+	Timeout     string   `json:"timeout,omitempty"` // Go duration, default 60s
+}
+
+// PluginRequest is the analyze request.
+type PluginRequest struct {
+	Protocol  int          `json:"protocol"`
+	Command   string       `json:"command"`
+	ScannedAt time.Time    `json:"scanned_at,omitempty"`
+	Nodes     []PluginNode `json:"nodes,omitempty"`
+	Edges     []PluginEdge `json:"edges,omitempty"` // Edges from the nodes sent; targets may be of other types
+}
+
+// PluginNode is a node as sent to plugins. Properties are plain JSON.
+type PluginNode struct {
+	ID         string                 `json:"id"`
+	Type       string                 `json:"type"`
+	Properties map[string]interface{} `json:"properties,omitempty"`
+	Cost       float64                `json:"cost,omitempty"`
+	Savings    float64                `json:"savings,omitempty"`
+	RiskScore  int                    `json:"risk_score,omitempty"`
+	IsWaste    bool                   `json:"is_waste,omitempty"`
+	Justified  bool                   `json:"justified,omitempty"`
+	Findings   []graph.Finding        `json:"findings,omitempty"`
+}
+
+// PluginEdge is a forward edge between two nodes.
+type PluginEdge struct {
+	Source string         `json:"source"`
+	Target string         `json:"target"`
+	Type   graph.EdgeType `json:"type"`
+}
+
+// PluginResponse is what a plugin returns for analyze.
+type PluginResponse struct {
+	Findings []PluginFinding `json:"findings"`
+	Skipped  []graph.Skip    `json:"skipped,omitempty"`
+	Error    string          `json:"error,omitempty"` // Reported as a failed run; findings are still applied
+}
+
+// PluginFinding is one result from a plugin.
+type PluginFinding struct {
+	ResourceID     string         `json:"resource_id"`
+	Category       graph.Category `json:"category,omitempty"`
+	Confidence     float64        `json:"confidence"`
+	RiskScore      int            `json:"risk_score"`
+	MonthlySavings float64        `json:"monthly_savings,omitempty"`
+	Reason         string         `json:"reason"`
+	Evidence       []string       `json:"evidence,omitempty"`
+	Action         string         `json:"action,omitempty"`
+}
+
+// Plugin runs an external executable as a heuristic named "plugin:<name>".
+type Plugin struct {
+	Path     string
+	Manifest PluginManifest
+
+	timeout time.Duration
+}
+
+// DefaultPluginDir returns ~/.cloudslash/plugins.
+func DefaultPluginDir() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, ".cloudslash", "plugins")
+}
+
+// DiscoverPlugins loads every executable in dir, in name order. A missing
+// directory yields no plugins. Plugins that fail to describe themselves are
+// left out and their errors returned joined.
+func DiscoverPlugins(ctx context.Context, dir string) ([]*Plugin, error) {
+	entries, err := os.ReadDir(dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read plugins: %v", err)
+	}
+
+	var plugins []*Plugin
+	var errs []error
+	seen := make(map[string]string)
+	for _, e := range entries {
+		info, err := e.Info()
+		if err != nil || info.IsDir() || info.Mode()&0111 == 0 || strings.HasPrefix(e.Name(), ".") {
+			continue
+		}
+		p, err := LoadPlugin(ctx, filepath.Join(dir, e.Name()))
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if prev, ok := seen[p.Manifest.Name]; ok {
+			errs = append(errs, fmt.Errorf("plugin %s: name %q is already used by %s", p.Path, p.Manifest.Name, prev))
+			continue
+		}
+		seen[p.Manifest.Name] = p.Path
+		plugins = append(plugins, p)
+	}
+	return plugins, errors.Join(errs...)
+}
+
+// LoadPlugin runs the describe command of the executable at path and
+// validates its manifest.
+func LoadPlugin(ctx context.Context, path string) (*Plugin, error) {
+	ctx, cancel := context.WithTimeout(ctx, describeTimeout)
+	defer cancel()
+
+	p := &Plugin{Path: path}
+	out, err := p.call(ctx, PluginRequest{Protocol: PluginProtocol, Command: "describe"}, nil)
+	if err != nil {
+		return nil, fmt.Errorf("plugin %s: describe failed: %v", path, err)
+	}
+	if err := json.Unmarshal(out, &p.Manifest); err != nil {
+		return nil, fmt.Errorf("plugin %s: invalid manifest: %v", path, err)
+	}
+	if err := p.validate(); err != nil {
+		return nil, fmt.Errorf("plugin %s: %v", path, err)
+	}
+	return p, nil
+}
+
+func (p *Plugin) validate() error {
+	m := p.Manifest
+	if m.Name == "" {
+		return fmt.Errorf("manifest has no name")
+	}
+	if m.Protocol != PluginProtocol {
+		return fmt.Errorf("speaks protocol %d, want %d", m.Protocol, PluginProtocol)
+	}
+	if len(m.Types) == 0 {
+		return fmt.Errorf("manifest declares no resource types")
+	}
+	p.timeout = defaultPluginTimeout
+	if m.Timeout != "" {
+		d, err := time.ParseDuration(m.Timeout)
+		if err != nil || d <= 0 {
+			return fmt.Errorf("invalid timeout %q", m.Timeout)
+		}
+		p.timeout = d
+	}
+	return nil
+}
+
+// Name implements WeightedHeuristic.
+func (p *Plugin) Name() string { return "plugin:" + p.Manifest.Name }
+
+// Flags implements Flagger.
+func (p *Plugin) Flags() []string { return p.Manifest.Types }
+
+// DependsOn implements Dependent.
+func (p *Plugin) DependsOn() Dependencies { return Dependencies{Heuristics: p.Manifest.After} }
+
+// Timeout implements Bounded.
+func (p *Plugin) Timeout() time.Duration { return p.timeout }
+
+// Analyze implements WeightedHeuristic. The plugin is sent the nodes of its
+// declared types; findings for any other resource are dropped and fail the run.
+func (p *Plugin) Analyze(ctx context.Context, v *graph.View, cov *Coverage) ([]HeuristicResult, error) {
+	nodes := v.NodesByType(p.Manifest.Types...)
+	cov.Examine(len(nodes))
+	if len(nodes) == 0 {
+		return nil, nil
+	}
+
+	req := PluginRequest{Protocol: PluginProtocol, Command: "analyze", ScannedAt: v.Metadata().ScannedAt}
+	sent := make(map[string]bool, len(nodes))
+	for _, n := range nodes {
+		sent[n.ID] = true
+		req.Nodes = append(req.Nodes, pluginNode(n))
+		for _, e := range v.Edges(n.ID) {
+			req.Edges = append(req.Edges, PluginEdge{Source: n.ID, Target: e.TargetID, Type: e.Type})
+		}
+	}
+	sort.Slice(req.Nodes, func(i, j int) bool { return req.Nodes[i].ID < req.Nodes[j].ID })
+
+	out, err := p.call(ctx, req, p.Manifest.Env)
+	if err != nil {
+		return nil, err
+	}
+	var resp PluginResponse
+	if err := json.Unmarshal(out, &resp); err != nil {
+		return nil, fmt.Errorf("invalid response: %v", err)
+	}
+
+	for _, s := range resp.Skipped {
+		cov.Skip(s.ResourceID, s.Reason)
+	}
+
+	var results []HeuristicResult
+	var errs []error
+	if resp.Error != "" {
+		errs = append(errs, errors.New(resp.Error))
+	}
+	for _, f := range resp.Findings {
+		if err := f.validate(sent); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		results = append(results, HeuristicResult{
+			ResourceID:     f.ResourceID,
+			Category:       f.Category,
+			Confidence:     WasteConfidence(f.Confidence),
+			RiskScore:      f.RiskScore,
+			MonthlySavings: f.MonthlySavings,
+			Reason:         f.Reason,
+			Evidence:       f.Evidence,
+			Action:         f.Action,
+		})
+	}
+	return results, errors.Join(errs...)
+}
+
+func (f PluginFinding) validate(sent map[string]bool) error {
+	if !sent[f.ResourceID] {
+		return fmt.Errorf("finding for %q, which was not sent to the plugin", f.ResourceID)
+	}
+	if f.Confidence <= 0 || f.Confidence > 1 {
+		return fmt.Errorf("finding for %s: confidence must be above 0 and at most 1, got %g", f.ResourceID, f.Confidence)
+	}
+	if f.RiskScore < 0 || f.RiskScore > 100 {
+		return fmt.Errorf("finding for %s: risk_score must be between 0 and 100, got %d", f.ResourceID, f.RiskScore)
+	}
+	switch f.Category {
+	case "", graph.CategoryWaste, graph.CategoryRightsizing, graph.CategorySecurity, graph.CategoryCompliance:
+	default:
+		return fmt.Errorf("finding for %s: unknown category %q", f.ResourceID, f.Category)
+	}
+	return nil
+}
+
+// pluginNode converts n for the wire. Properties that cannot be encoded as
+// JSON are left out.
+func pluginNode(n *graph.Node) PluginNode {
+	pn := PluginNode{
+		ID:        n.ID,
+		Type:      n.Type,
+		Cost:      n.Cost,
+		Savings:   n.Savings,
+		RiskScore: n.RiskScore,
+		IsWaste:   n.IsWaste,
+		Justified: n.Justified,
+		Findings:  n.Findings,
+	}
+	for k, val := range n.Properties {
+		if _, err := json.Marshal(val); err != nil {
+			continue
+		}
+		if pn.Properties == nil {
+			pn.Properties = make(map[string]interface{}, len(n.Properties))
+		}
+		pn.Properties[k] = val
+	}
+	return pn
+}
+
+// call runs the plugin with req on stdin and returns its stdout. The plugin
+// sees only pluginBaseEnv plus the variables named in env.
+func (p *Plugin) call(ctx context.Context, req PluginRequest, env []string) ([]byte, error) {
+	in, err := json.Marshal(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode request: %v", err)
+	}
+
+	stdout := &cappedBuffer{max: maxPluginOutput}
+	stderr := &cappedBuffer{max: maxPluginStderr, truncate: true}
+	cmd := exec.CommandContext(ctx, p.Path)
+	cmd.Stdin = bytes.NewReader(in)
+	cmd.Stdout = stdout
+	cmd.Stderr = stderr
+	cmd.Env = sandboxEnv(env)
+	cmd.WaitDelay = time.Second // Don't wait on children that keep the pipes open
+
+	if err := cmd.Run(); err != nil {
+		if ctx.Err() != nil {
+			err = ctx.Err()
+		}
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return nil, fmt.Errorf("%v: %s", err, msg)
+		}
+		return nil, err
+	}
+	return stdout.Bytes(), nil
+}
+
+// sandboxEnv returns pluginBaseEnv and the named variables from the current environment.
+func sandboxEnv(names []string) []string {
+	env := []string{fmt.Sprintf("CLOUDSLASH_PLUGIN_PROTOCOL=%d", PluginProtocol)}
+	for _, name := range append(append([]string(nil), pluginBaseEnv...), names...) {
+		if v, ok := os.LookupEnv(name); ok {
+			env = append(env, name+"="+v)
+		}
+	}
+	return env
+}
+
+// cappedBuffer fails writes beyond max bytes, or with truncate, drops them.
+type cappedBuffer struct {
+	bytes.Buffer
+	max      int
+	truncate bool
+}
+
+func (b *cappedBuffer) Write(p []byte) (int, error) {
+	if room := b.max - b.Len(); len(p) > room {
+		if !b.truncate {
+			return 0, fmt.Errorf("plugin output exceeds %d MB", b.max>>20)
+		}
+		b.Buffer.Write(p[:max(room, 0)])
+		return len(p), nil
+	}
+	return b.Buffer.Write(p)
+}
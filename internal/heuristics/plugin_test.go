@@ -0,0 +1,126 @@
+package heuristics
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DrSkyle/cloudslash/internal/graph"
+)
+
+// writePlugin writes a shell plugin that answers describe with manifest and
+// runs analyze as a shell snippet.
+func writePlugin(t *testing.T, dir, name, manifest, analyze string) string {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("shell plugins need a POSIX shell")
+	}
+	script := "#!/bin/sh\nreq=$(cat)\ncase \"$req\" in\n*'\"command\":\"describe\"'*) echo '" + manifest + "' ;;\n*) " + analyze + " ;;\nesac\n"
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, []byte(script), 0755); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func pluginGraph() *graph.Graph {
+	g := graph.NewGraph()
+	g.AddNode("vol-1", "AWS::EC2::Volume", map[string]interface{}{"Size": int32(100)})
+	g.AddNode("i-1", "AWS::EC2::Instance", nil)
+	return g
+}
+
+func TestPlugin_Analyze(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("PLUGIN_TOKEN", "granted")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "leaked")
+
+	// The reason echoes the environment the plugin saw and whether it was sent the instance.
+	writePlugin(t, dir, "env-check",
+		`{"name":"env-check","protocol":1,"types":["AWS::EC2::Volume"],"env":["PLUGIN_TOKEN"],"timeout":"5s"}`,
+		`sent=no; case "$req" in *'"id":"i-1"'*) sent=yes ;; esac; echo "{\"findings\":[{\"resource_id\":\"vol-1\",\"confidence\":0.8,\"risk_score\":40,\"reason\":\"token=$PLUGIN_TOKEN secret=$AWS_SECRET_ACCESS_KEY instance=$sent\"},{\"resource_id\":\"i-1\",\"confidence\":1,\"risk_score\":10,\"reason\":\"x\"}],\"skipped\":[{\"resource_id\":\"vol-2\",\"reason\":\"no data\"}]}"`)
+	writePlugin(t, dir, "notes.txt", ``, ``)
+	os.Chmod(filepath.Join(dir, "notes.txt"), 0644)
+
+	plugins, err := DiscoverPlugins(context.Background(), dir)
+	if err != nil {
+		t.Fatalf("DiscoverPlugins: %v", err)
+	}
+	if len(plugins) != 1 || plugins[0].Name() != "plugin:env-check" || plugins[0].Timeout() != 5*time.Second {
+		t.Fatalf("unexpected plugins %+v", plugins)
+	}
+
+	g := pluginGraph()
+	e := NewEngine()
+	e.Register(plugins[0])
+	err = e.Run(context.Background(), g)
+	if err == nil || !strings.Contains(err.Error(), "not sent to the plugin") {
+		t.Errorf("expected an error for the finding on an unsent resource, got %v", err)
+	}
+
+	f, ok := nodeByID(g, "vol-1").FindingBy("plugin:env-check")
+	if !ok || f.Reason != "token=granted secret= instance=no" || f.Confidence != 0.8 || f.RiskScore != 40 {
+		t.Errorf("unexpected finding %+v", f)
+	}
+	if len(nodeByID(g, "i-1").Findings) != 0 {
+		t.Errorf("plugins may only flag the resources they were sent, got %+v", nodeByID(g, "i-1").Findings)
+	}
+	run := g.HeuristicRuns()[0]
+	if run.Status != graph.RunFailed || run.Examined != 1 || run.Skipped != 1 {
+		t.Errorf("unexpected run %+v", run)
+	}
+}
+
+func TestPlugin_IsolatesFailures(t *testing.T) {
+	dir := t.TempDir()
+	slow := writePlugin(t, dir, "slow", `{"name":"slow","protocol":1,"types":["AWS::EC2::Volume"],"timeout":"200ms"}`, `sleep 5`)
+	crash := writePlugin(t, dir, "crash", `{"name":"crash","protocol":1,"types":["AWS::EC2::Volume"]}`, `echo "bad credentials" >&2; exit 3`)
+
+	g := pluginGraph()
+	e := NewEngine()
+	for _, path := range []string{slow, crash} {
+		p, err := LoadPlugin(context.Background(), path)
+		if err != nil {
+			t.Fatalf("LoadPlugin: %v", err)
+		}
+		e.Register(p)
+	}
+	e.Register(&fixedHeuristic{name: "ok", examined: 1, results: []HeuristicResult{{ResourceID: "vol-1", Confidence: 1}}})
+
+	start := time.Now()
+	err := e.Run(context.Background(), g)
+	if time.Since(start) > 3*time.Second {
+		t.Errorf("the slow plugin was not stopped at its timeout")
+	}
+	if err == nil || !strings.Contains(err.Error(), "timed out after 200ms") || !strings.Contains(err.Error(), "bad credentials") {
+		t.Errorf("expected timeout and stderr in the error, got %v", err)
+	}
+	if _, ok := nodeByID(g, "vol-1").FindingBy("ok"); !ok {
+		t.Error("failing plugins must not affect other heuristics")
+	}
+
+	runs := g.HeuristicRuns()
+	if runs[0].Status != graph.RunFailed || runs[1].Status != graph.RunFailed || runs[2].Status != graph.RunOK {
+		t.Errorf("unexpected runs %+v", runs)
+	}
+}
+
+func TestLoadPlugin_InvalidManifest(t *testing.T) {
+	dir := t.TempDir()
+	for name, manifest := range map[string]string{
+		"no-name":  `{"protocol":1,"types":["AWS::EC2::Volume"]}`,
+		"protocol": `{"name":"p","protocol":2,"types":["AWS::EC2::Volume"]}`,
+		"no-types": `{"name":"p","protocol":1}`,
+		"timeout":  `{"name":"p","protocol":1,"types":["AWS::EC2::Volume"],"timeout":"soon"}`,
+		"not-json": `hello`,
+	} {
+		path := writePlugin(t, dir, name, manifest, `true`)
+		if _, err := LoadPlugin(context.Background(), path); err == nil {
+			t.Errorf("%s: expected error", name)
+		}
+	}
+}
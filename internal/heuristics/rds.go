@@ -0,0 +1,443 @@
+package heuristics
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/DrSkyle/cloudslash/internal/graph"
+	"github.com/DrSkyle/cloudslash/internal/resource"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatch/types"
+)
+
+// rdsSizes lists RDS instance sizes from smallest to largest. Only burstable
+// (db.t*) classes come smaller than large.
+var rdsSizes = []string{"micro", "small", "medium", "large", "xlarge", "2xlarge", "4xlarge", "8xlarge", "12xlarge", "16xlarge", "24xlarge"}
+
+// rdsBurstableMemory is the memory (GiB) of each burstable size.
+var rdsBurstableMemory = map[string]float64{
+	"micro": 1, "small": 2, "medium": 4, "large": 8, "xlarge": 16, "2xlarge": 32,
+}
+
+// rdsMemoryPerVCPU is the memory (GiB) per vCPU of each non-burstable family
+// type: general purpose (m), memory optimized (r) and extra memory (x).
+var rdsMemoryPerVCPU = map[byte]float64{'m': 4, 'r': 8, 'x': 16}
+
+// rdsVCPUs returns the vCPU count of a non-burstable size: 2 for large,
+// doubling with each xlarge multiple.
+func rdsVCPUs(size string) int {
+	switch size {
+	case "large":
+		return 2
+	case "xlarge":
+		return 4
+	}
+	var n int
+	if _, err := fmt.Sscanf(size, "%dxlarge", &n); err != nil {
+		return 0
+	}
+	return 4 * n
+}
+
+// rdsClassShape returns the vCPUs and memory (GiB) of an RDS instance class.
+func rdsClassShape(class string) (vcpus int, memGiB float64, ok bool) {
+	family, size, ok := strings.Cut(strings.TrimPrefix(class, "db."), ".")
+	if !ok || family == "" {
+		return 0, 0, false
+	}
+	if family[0] == 't' {
+		mem, ok := rdsBurstableMemory[size]
+		return 2, mem, ok
+	}
+	perVCPU, ok := rdsMemoryPerVCPU[family[0]]
+	vcpus = rdsVCPUs(size)
+	if !ok || vcpus == 0 {
+		return 0, 0, false
+	}
+	return vcpus, perVCPU * float64(vcpus), true
+}
+
+// smallerRDSClass returns the next size down in the same family, e.g.
+// db.r5.2xlarge -> db.r5.xlarge.
+func smallerRDSClass(class string) (string, bool) {
+	family, size, ok := strings.Cut(strings.TrimPrefix(class, "db."), ".")
+	if !ok || family == "" {
+		return "", false
+	}
+	i := slices.Index(rdsSizes, size)
+	smallest := slices.Index(rdsSizes, "large")
+	if family[0] == 't' {
+		smallest = 0
+	}
+	if i <= smallest {
+		return "", false
+	}
+	smaller := "db." + family + "." + rdsSizes[i-1]
+	if _, _, ok := rdsClassShape(smaller); !ok {
+		return "", false
+	}
+	return smaller, true
+}
+
+// defaultMaxConnections approximates the engine's default max_connections,
+// which RDS derives from instance memory.
+func defaultMaxConnections(engine string, memGiB float64) (int, bool) {
+	bytes := memGiB * (1 << 30)
+	switch engine {
+	case "mysql", "mariadb", "aurora-mysql":
+		return int(bytes / 12582880), true
+	case "postgres", "aurora-postgresql":
+		return min(int(bytes/9531392), 5000), true
+	}
+	return 0, false
+}
+
+// environmentOf returns the value of the first environment tag present.
+func environmentOf(tags map[string]string, keys []string) (key, value string) {
+	for _, k := range keys {
+		for tk, tv := range tags {
+			if strings.EqualFold(tk, k) {
+				return tk, tv
+			}
+		}
+	}
+	return "", ""
+}
+
+// RDSRightsizingHeuristic recommends a smaller class for RDS instances whose
+// CPU, memory and connection percentiles would fit one, and flags Multi-AZ
+// standbys and provisioned-IOPS storage on non-production databases.
+type RDSRightsizingHeuristic struct {
+	Tunable
+	CW      CloudWatchClient
+	Pricing PricingClient
+}
+
+// RDSRightsizingConfig holds the RDSRightsizingHeuristic thresholds. The
+// percent thresholds are projections onto the next smaller class.
+type RDSRightsizingConfig struct {
+	Lookback              Duration `yaml:"lookback"`
+	MaxCPUPercent         float64  `yaml:"max_cpu_percent"`         // p95 CPUUtilization
+	MinFreeMemoryPercent  float64  `yaml:"min_free_memory_percent"` // p5 FreeableMemory
+	MaxConnectionsPercent float64  `yaml:"max_connections_percent"` // p99 DatabaseConnections, of the default limit
+	EnvironmentTags       []string `yaml:"environment_tags"`        // Tag keys naming the environment
+	NonProdEnvironments   []string `yaml:"non_prod_environments"`   // Tag values that mark non-production
+	MinSavings            float64  `yaml:"min_savings"`             // Ignore changes that save less per month
+}
+
+func (h *RDSRightsizingHeuristic) Defaults() interface{} {
+	return &RDSRightsizingConfig{
+		Lookback:              Days(14),
+		MaxCPUPercent:         60,
+		MinFreeMemoryPercent:  15,
+		MaxConnectionsPercent: 75,
+		EnvironmentTags:       []string{"Environment", "Env", "Stage"},
+		NonProdEnvironments:   []string{"dev", "development", "test", "testing", "qa", "staging", "stage", "sandbox", "nonprod", "non-prod"},
+		MinSavings:            5,
+	}
+}
+
+func (h *RDSRightsizingHeuristic) Name() string { return "RDSRightsizingHeuristic" }
+
+func (h *RDSRightsizingHeuristic) Flags() []string { return []string{"AWS::RDS::DBInstance"} }
+
+// DependsOn makes the heuristic skip databases already flagged for deletion.
+func (h *RDSRightsizingHeuristic) DependsOn() Dependencies {
+	return Dependencies{Heuristics: []string{"RDSHeuristic"}}
+}
+
+// rdsConfig is the billed configuration of an RDS instance.
+type rdsConfig struct {
+	Class       string
+	MultiAZ     bool
+	StorageType string
+	Storage     int // GB
+	IOPS        int
+}
+
+func (h *RDSRightsizingHeuristic) Analyze(ctx context.Context, v *graph.View, cov *Coverage) ([]HeuristicResult, error) {
+	var results []HeuristicResult
+
+	var candidates []*graph.Node
+	for _, n := range v.NodesByType("AWS::RDS::DBInstance") {
+		if n.Properties["Status"] == "available" && !flaggedWaste(n) {
+			candidates = append(candidates, n)
+		}
+	}
+	cov.Examine(len(candidates))
+
+	for _, node := range candidates {
+		if h.CW == nil || h.Pricing == nil {
+			cov.Skip(node.ID, "metrics or pricing unavailable")
+			continue
+		}
+		cfg := h.Defaults().(*RDSRightsizingConfig)
+		h.Settings.Resolve(h.Name(), node, cfg)
+
+		// Too new to have a full window of load.
+		if created, ok := node.CreatedAt(); ok && time.Since(created) < cfg.Lookback.Std() {
+			continue
+		}
+
+		engine, _ := node.Properties["Engine"].(string)
+		current := rdsConfig{}
+		current.Class, _ = node.Properties["InstanceClass"].(string)
+		current.MultiAZ, _ = node.Properties["MultiAZ"].(bool)
+		current.StorageType, _ = node.Properties["StorageType"].(string)
+		current.Storage = intProp(node.Properties["Storage"])
+		current.IOPS = intProp(node.Properties["Iops"])
+		proposed := current
+
+		var reasons, evidence []string
+		confidence, risk := 0.8, 0
+
+		// 1. A smaller class, if every percentile fits it.
+		if smaller, ok := smallerRDSClass(current.Class); ok {
+			fits, ev, err := h.fits(ctx, node, engine, current.Class, smaller, cfg)
+			if err != nil {
+				cov.Skip(node.ID, fmt.Sprintf("metric fetch failed: %v", err))
+				continue
+			}
+			evidence = append(evidence, ev...)
+			if fits {
+				proposed.Class = smaller
+				reasons = append(reasons, fmt.Sprintf("load fits %s", smaller))
+				confidence, risk = 0.7, 40
+			}
+		}
+
+		// 2. Production-grade options on non-production databases.
+		tags, _ := node.Properties["Tags"].(map[string]string)
+		if key, env := environmentOf(tags, cfg.EnvironmentTags); env != "" && slices.Contains(cfg.NonProdEnvironments, strings.ToLower(env)) {
+			if current.MultiAZ {
+				proposed.MultiAZ = false
+				reasons = append(reasons, "Multi-AZ standby on a non-production database")
+				evidence = append(evidence, fmt.Sprintf("Tagged %s=%s with a Multi-AZ standby", key, env))
+				risk = max(risk, 30)
+			}
+			if current.StorageType == "io1" || current.StorageType == "io2" {
+				proposed.StorageType, proposed.IOPS = "gp3", 0
+				reasons = append(reasons, "provisioned-IOPS storage on a non-production database")
+				evidence = append(evidence, fmt.Sprintf("Tagged %s=%s with %s storage (%d provisioned IOPS)", key, env, current.StorageType, current.IOPS))
+				risk = max(risk, 30)
+			}
+		}
+		if proposed == current {
+			continue
+		}
+
+		region := resource.Region(node.ID, "us-east-1")
+		before, err := h.monthlyCost(ctx, region, engine, current)
+		if err == nil {
+			var after float64
+			after, err = h.monthlyCost(ctx, region, engine, proposed)
+			before -= after
+		}
+		if err != nil {
+			cov.Skip(node.ID, fmt.Sprintf("pricing failed: %v", err))
+			continue
+		}
+		if before < cfg.MinSavings {
+			continue
+		}
+
+		results = append(results, HeuristicResult{
+			ResourceID:     node.ID,
+			Category:       graph.CategoryRightsizing,
+			Confidence:     WasteConfidence(confidence),
+			RiskScore:      risk,
+			MonthlySavings: before,
+			Reason:         fmt.Sprintf("RDS instance could save $%.2f/mo: %s", before, strings.Join(reasons, "; ")),
+			Evidence:       evidence,
+			Action:         "Modify the instance to " + describeRDS(proposed) + " (applied in the next maintenance window)",
+			Thresholds:     Thresholds(cfg),
+		})
+	}
+	return results, nil
+}
+
+// fits reports whether the observed CPU, memory and connection percentiles
+// would stay within the thresholds on the smaller class.
+func (h *RDSRightsizingHeuristic) fits(ctx context.Context, node *graph.Node, engine, class, smaller string, cfg *RDSRightsizingConfig) (bool, []string, error) {
+	vcpus, mem, ok := rdsClassShape(class)
+	newVCPUs, newMem, _ := rdsClassShape(smaller)
+	if !ok {
+		return false, nil, nil
+	}
+
+	dims := []types.Dimension{{Name: aws.String("DBInstanceIdentifier"), Value: aws.String(resource.ResourceID(node.ID))}}
+	endTime := time.Now()
+	startTime := endTime.Add(-cfg.Lookback.Std())
+
+	cpu, err := h.CW.GetMetricPercentile(ctx, "AWS/RDS", "CPUUtilization", dims, startTime, endTime, 95)
+	if err != nil {
+		return false, nil, err
+	}
+	free, err := h.CW.GetMetricPercentile(ctx, "AWS/RDS", "FreeableMemory", dims, startTime, endTime, 5)
+	if err != nil {
+		return false, nil, err
+	}
+	conns, err := h.CW.GetMetricPercentile(ctx, "AWS/RDS", "DatabaseConnections", dims, startTime, endTime, 99)
+	if err != nil {
+		return false, nil, err
+	}
+
+	projectedCPU := cpu * float64(vcpus) / float64(newVCPUs)
+	used := mem - free/(1<<30)
+	projectedFree := 100 * (newMem - used) / newMem
+	fits := projectedCPU <= cfg.MaxCPUPercent && projectedFree >= cfg.MinFreeMemoryPercent
+
+	evidence := []string{
+		fmt.Sprintf("CPUUtilization p95 %.1f%% over %s (projected %.1f%% on %s)", cpu, cfg.Lookback, projectedCPU, smaller),
+		fmt.Sprintf("FreeableMemory p5 %.1f GiB of %.0f GiB (projected %.0f%% free on %s)", free/(1<<30), mem, projectedFree, smaller),
+	}
+	if limit, ok := defaultMaxConnections(engine, newMem); ok {
+		share := 100 * conns / float64(limit)
+		fits = fits && share <= cfg.MaxConnectionsPercent
+		evidence = append(evidence, fmt.Sprintf("DatabaseConnections p99 %.0f (%.0f%% of the default limit of %d on %s)", conns, share, limit, smaller))
+	} else {
+		evidence = append(evidence, fmt.Sprintf("DatabaseConnections p99 %.0f", conns))
+	}
+	return fits, evidence, nil
+}
+
+// monthlyCost prices an RDS configuration: instance hours plus storage.
+func (h *RDSRightsizingHeuristic) monthlyCost(ctx context.Context, region, engine string, c rdsConfig) (float64, error) {
+	cost, err := h.Pricing.GetRDSInstancePrice(ctx, region, c.Class, engine, c.MultiAZ)
+	if err != nil {
+		return 0, err
+	}
+	if c.StorageType == "" || strings.HasPrefix(engine, "aurora") {
+		return cost, nil // Aurora bills storage per cluster
+	}
+	perGB, perIOPS, err := h.Pricing.GetRDSStoragePrice(ctx, region, c.StorageType, c.MultiAZ)
+	if err != nil {
+		return 0, err
+	}
+	return cost + perGB*float64(c.Storage) + perIOPS*float64(c.IOPS), nil
+}
+
+func describeRDS(c rdsConfig) string {
+	deployment := "Single-AZ"
+	if c.MultiAZ {
+		deployment = "Multi-AZ"
+	}
+	return fmt.Sprintf("%s, %s, %s storage", c.Class, deployment, c.StorageType)
+}
+
+// Snapshot storage prices per GB-month (US East). Snapshots are incremental,
+// so size times price is an upper bound.
+const (
+	rdsSnapshotGBMonth    = 0.095
+	auroraSnapshotGBMonth = 0.021
+)
+
+// RDSSnapshotHeuristic flags manual DB and Aurora cluster snapshots whose
+// source database no longer exists, and snapshots beyond the newest
+// MaxPerSource of a database that does.
+type RDSSnapshotHeuristic struct {
+	Tunable
+}
+
+// RDSSnapshotConfig holds the RDSSnapshotHeuristic thresholds.
+type RDSSnapshotConfig struct {
+	MaxPerSource int `yaml:"max_per_source"` // Manual snapshots kept per database
+}
+
+func (h *RDSSnapshotHeuristic) Defaults() interface{} {
+	return &RDSSnapshotConfig{MaxPerSource: 5}
+}
+
+func (h *RDSSnapshotHeuristic) Name() string { return "RDSSnapshotHeuristic" }
+
+func (h *RDSSnapshotHeuristic) Flags() []string {
+	return []string{"AWS::RDS::DBSnapshot", "AWS::RDS::DBClusterSnapshot"}
+}
+
+func (h *RDSSnapshotHeuristic) Analyze(ctx context.Context, v *graph.View, cov *Coverage) ([]HeuristicResult, error) {
+	var results []HeuristicResult
+
+	// Automated snapshots expire with the backup retention period; shared and
+	// public ones belong to another account.
+	bySource := make(map[string][]*graph.Node)
+	var sources []string
+	for _, n := range v.NodesByType(h.Flags()...) {
+		if n.Properties["SnapshotType"] != "manual" || n.Properties["Status"] != "available" {
+			continue
+		}
+		cov.Examine(1)
+		source := snapshotSource(v, n)
+		if source == "" {
+			cov.Skip(n.ID, "source database unknown")
+			continue
+		}
+		if _, ok := bySource[source]; !ok {
+			sources = append(sources, source)
+		}
+		bySource[source] = append(bySource[source], n)
+	}
+
+	for _, source := range sources {
+		snaps := bySource[source]
+		// Newest first, so the ones past the retention count are the oldest.
+		slices.SortFunc(snaps, func(a, b *graph.Node) int {
+			ta, _ := a.CreatedAt()
+			tb, _ := b.CreatedAt()
+			return tb.Compare(ta)
+		})
+		src, ok := v.Node(source)
+		orphaned := !ok || src.Type == "Unknown"
+
+		for i, snap := range snaps {
+			cfg := h.Defaults().(*RDSSnapshotConfig)
+			h.Settings.Resolve(h.Name(), snap, cfg)
+			if !orphaned && i < cfg.MaxPerSource {
+				continue
+			}
+
+			size := intProp(snap.Properties["AllocatedStorage"])
+			price := rdsSnapshotGBMonth
+			if snap.Type == "AWS::RDS::DBClusterSnapshot" {
+				price = auroraSnapshotGBMonth
+			}
+			evidence := []string{fmt.Sprintf("%d GB allocated at $%.3f/GB-month (upper bound, snapshots are incremental)", size, price)}
+			if created, ok := snap.CreatedAt(); ok {
+				evidence = append(evidence, "Created "+created.Format("2006-01-02"))
+			}
+
+			res := HeuristicResult{
+				ResourceID:     snap.ID,
+				Category:       graph.CategoryWaste,
+				MonthlySavings: float64(size) * price,
+				Evidence:       evidence,
+				Thresholds:     Thresholds(cfg),
+			}
+			if orphaned {
+				// Often the final snapshot of a deleted database; worth a look before deleting.
+				res.Confidence, res.RiskScore = 0.7, 60
+				res.Reason = fmt.Sprintf("Manual snapshot of %s, which no longer exists", resource.ResourceID(source))
+				res.Action = "Confirm no restore is needed (or export it to S3), then delete the snapshot"
+			} else {
+				res.Confidence, res.RiskScore = 0.8, 40
+				res.Reason = fmt.Sprintf("%d manual snapshots of %s; this is #%d by age, past the %d kept", len(snaps), resource.ResourceID(source), i+1, cfg.MaxPerSource)
+				res.Action = "Delete the snapshot; newer ones of the same database remain"
+			}
+			results = append(results, res)
+		}
+	}
+	return results, nil
+}
+
+// snapshotSource returns the ID of the database or cluster a snapshot was
+// taken from, following the edge the scanner adds.
+func snapshotSource(v *graph.View, snap *graph.Node) string {
+	for _, e := range v.Edges(snap.ID) {
+		if arn, err := resource.ParseARN(e.TargetID); err == nil && (arn.ResourceType == "db" || arn.ResourceType == "cluster") {
+			return e.TargetID
+		}
+	}
+	return ""
+}
@@ -0,0 +1,144 @@
+package heuristics
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Dependencies declares what a heuristic must run after. Dependencies on
+// heuristics that are not registered are ignored, so a heuristic still runs
+// when, say, the CloudWatch-backed heuristics are not configured.
+type Dependencies struct {
+	Heuristics []string // Heuristics whose findings it reads, by Name
+	Types      []string // Resource types whose findings it reads; it runs after every heuristic that flags them
+}
+
+// Dependent is implemented by heuristics that read other heuristics' findings.
+type Dependent interface {
+	DependsOn() Dependencies
+}
+
+// Flagger is implemented by heuristics that only report on a fixed set of
+// resource types. A heuristic that does not implement it, or returns no
+// types, may flag any type.
+type Flagger interface {
+	Flags() []string
+}
+
+// flags reports whether h may report findings on resources of type t.
+func flags(h WeightedHeuristic, t string) bool {
+	f, ok := h.(Flagger)
+	if !ok || len(f.Flags()) == 0 {
+		return true
+	}
+	for _, ft := range f.Flags() {
+		if ft == t {
+			return true
+		}
+	}
+	return false
+}
+
+// dependencies returns, for each heuristic, the indexes of the heuristics it must run after.
+func dependencies(hs []WeightedHeuristic) [][]int {
+	deps := make([][]int, len(hs))
+	for i, h := range hs {
+		d, ok := h.(Dependent)
+		if !ok {
+			continue
+		}
+		decl := d.DependsOn()
+		for j, other := range hs {
+			if j == i {
+				continue
+			}
+			if dependsOn(decl, other) {
+				deps[i] = append(deps[i], j)
+			}
+		}
+	}
+	return deps
+}
+
+func dependsOn(d Dependencies, other WeightedHeuristic) bool {
+	for _, name := range d.Heuristics {
+		if other.Name() == name {
+			return true
+		}
+	}
+	for _, t := range d.Types {
+		if flags(other, t) {
+			return true
+		}
+	}
+	return false
+}
+
+// schedule groups heuristics into waves: every heuristic runs in the first
+// wave after all of its dependencies. Heuristics within a wave are
+// independent and run in parallel. It fails if the dependencies form a cycle.
+func schedule(hs []WeightedHeuristic) ([][]int, error) {
+	deps := dependencies(hs)
+
+	level := make([]int, len(hs))
+	state := make([]int, len(hs)) // 0 unvisited, 1 in progress, 2 done
+	var stack []int
+
+	var visit func(i int) error
+	visit = func(i int) error {
+		switch state[i] {
+		case 2:
+			return nil
+		case 1:
+			return cycleError(hs, stack, i)
+		}
+		state[i] = 1
+		stack = append(stack, i)
+		for _, j := range deps[i] {
+			if err := visit(j); err != nil {
+				return err
+			}
+			if level[j]+1 > level[i] {
+				level[i] = level[j] + 1
+			}
+		}
+		stack = stack[:len(stack)-1]
+		state[i] = 2
+		return nil
+	}
+
+	var waves [][]int
+	for i := range hs {
+		if err := visit(i); err != nil {
+			return nil, err
+		}
+	}
+	for i := range hs {
+		for len(waves) <= level[i] {
+			waves = append(waves, nil)
+		}
+		waves[level[i]] = append(waves[level[i]], i)
+	}
+	return waves, nil
+}
+
+// cycleError describes the cycle that closes at index i on the DFS stack.
+func cycleError(hs []WeightedHeuristic, stack []int, i int) error {
+	start := 0
+	for k, j := range stack {
+		if j == i {
+			start = k
+			break
+		}
+	}
+	var names []string
+	for _, j := range stack[start:] {
+		names = append(names, hs[j].Name())
+	}
+	names = append(names, hs[i].Name())
+	// The stack runs from dependents to dependencies; print it in run order.
+	for l, r := 0, len(names)-1; l < r; l, r = l+1, r-1 {
+		names[l], names[r] = names[r], names[l]
+	}
+	return fmt.Errorf("heuristic dependency cycle: %s", strings.Join(names, " -> "))
+}
@@ -0,0 +1,369 @@
+package heuristics
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/DrSkyle/cloudslash/internal/graph"
+	"github.com/DrSkyle/cloudslash/internal/resource"
+	"gopkg.in/yaml.v3"
+)
+
+// Settings holds heuristic threshold overrides, read from the heuristics
+// section of the config file:
+//
+//	heuristics:
+//	  settings:
+//	    NATGatewayHeuristic: {max_connections: 10, lookback: 14d}
+//	  overrides:
+//	    - match: {account: ["111111111111"], region: [us-west-2], tags: {env: sandbox}}
+//	      settings:
+//	        UnderutilizedInstanceHeuristic: {max_cpu_percent: 20}
+//
+// Values not given keep the heuristic's defaults. Overrides apply, in order,
+// to resources matching all of their match criteria; later overrides win.
+type Settings struct {
+	Path      string               `yaml:"-"`
+	Base      map[string]yaml.Node `yaml:"settings"`
+	Overrides []Override           `yaml:"overrides"`
+
+	mu    sync.Mutex
+	cache map[string]interface{} // Resolved config per heuristic and matching overrides
+}
+
+// Override replaces heuristic settings for the resources it matches.
+type Override struct {
+	Match    Scope                `yaml:"match"`
+	Settings map[string]yaml.Node `yaml:"settings"`
+}
+
+// Scope selects resources by account, region and tags. Empty fields match everything.
+type Scope struct {
+	Account []string          `yaml:"account"`
+	Region  []string          `yaml:"region"`
+	Tags    map[string]string `yaml:"tags"`
+}
+
+// Target is where a resource lives, as far as overrides are concerned.
+type Target struct {
+	Account string
+	Region  string
+	Tags    map[string]string
+}
+
+// Configurable is implemented by heuristics with tunable thresholds. Defaults
+// returns a pointer to a new config struct holding the default values; its
+// yaml tags are the setting keys.
+type Configurable interface {
+	Defaults() interface{}
+	Configure(s *Settings)
+}
+
+// Tunable gives a heuristic its Settings. Heuristics with a config struct
+// embed it; the engine calls Configure before running them.
+type Tunable struct {
+	Settings *Settings // Optional; nil uses the defaults
+}
+
+// Configure implements Configurable.
+func (t *Tunable) Configure(s *Settings) { t.Settings = s }
+
+// LoadSettings reads the heuristics section of a YAML (or JSON) config file
+// and validates it against the built-in heuristics. An empty path, or a file
+// without a heuristics section, yields nil settings.
+func LoadSettings(path string) (*Settings, error) {
+	if path == "" {
+		return nil, nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config: %v", err)
+	}
+	s, err := ParseSettings(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", path, err)
+	}
+	if s != nil {
+		s.Path = path
+	}
+	return s, nil
+}
+
+// ParseSettings decodes and validates the heuristics section of a config file.
+func ParseSettings(data []byte) (*Settings, error) {
+	var f struct {
+		Heuristics *Settings `yaml:"heuristics"`
+	}
+	if err := yaml.Unmarshal(data, &f); err != nil {
+		return nil, fmt.Errorf("invalid config: %v", err)
+	}
+	s := f.Heuristics
+	if s == nil {
+		return nil, nil
+	}
+	if err := s.validate(Catalog()); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// validate checks that every block names a configurable heuristic and only
+// sets keys its config struct knows.
+func (s *Settings) validate(hs []WeightedHeuristic) error {
+	defaults := make(map[string]func() interface{})
+	for _, h := range hs {
+		if c, ok := h.(Configurable); ok {
+			defaults[h.Name()] = c.Defaults
+		}
+	}
+
+	check := func(where string, blocks map[string]yaml.Node) error {
+		for name, node := range blocks {
+			def, ok := defaults[name]
+			if !ok {
+				return fmt.Errorf("%s: unknown or non-configurable heuristic %q (see cloudslash heuristics list)", where, name)
+			}
+			if err := decodeStrict(&node, def()); err != nil {
+				return fmt.Errorf("%s.%s: %v", where, name, err)
+			}
+		}
+		return nil
+	}
+
+	if err := check("settings", s.Base); err != nil {
+		return err
+	}
+	for i, o := range s.Overrides {
+		if err := check(fmt.Sprintf("overrides[%d]", i), o.Settings); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// decodeStrict decodes node into dst, rejecting keys dst does not have.
+func decodeStrict(node *yaml.Node, dst interface{}) error {
+	if node.Kind == yaml.MappingNode {
+		known := Thresholds(dst)
+		for i := 0; i < len(node.Content); i += 2 {
+			key := node.Content[i]
+			if _, ok := known[key.Value]; !ok {
+				return fmt.Errorf("line %d: unknown setting %q", key.Line, key.Value)
+			}
+		}
+	}
+	return node.Decode(dst)
+}
+
+// TargetOf returns the account, region and tags of a node.
+func TargetOf(n *graph.Node) Target {
+	var t Target
+	if arn, err := resource.ParseARN(n.ID); err == nil {
+		t.Account, t.Region = arn.AccountID, arn.Region
+	}
+	if t.Account == "" {
+		// Snapshot ARNs carry no account; scanners record the owner instead.
+		t.Account, _ = n.Properties["OwnerId"].(string)
+	}
+	t.Tags, _ = n.Properties["Tags"].(map[string]string)
+	return t
+}
+
+// Resolve fills dst, which holds the heuristic's defaults, with the settings
+// that apply to node. A nil Settings leaves dst unchanged.
+func (s *Settings) Resolve(heuristic string, node *graph.Node, dst interface{}) {
+	if s == nil {
+		return
+	}
+	s.ResolveTarget(heuristic, TargetOf(node), dst)
+}
+
+// ResolveTarget is Resolve for a resource in target.
+func (s *Settings) ResolveTarget(heuristic string, target Target, dst interface{}) {
+	if s == nil {
+		return
+	}
+
+	var matched []int
+	key := heuristic
+	for i, o := range s.Overrides {
+		if _, ok := o.Settings[heuristic]; ok && o.Match.matches(target) {
+			matched = append(matched, i)
+			key += "," + strconv.Itoa(i)
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if cached, ok := s.cache[key]; ok {
+		reflect.ValueOf(dst).Elem().Set(reflect.ValueOf(cached).Elem())
+		return
+	}
+
+	// Settings were validated on load; a block that fails here is skipped.
+	if node, ok := s.Base[heuristic]; ok {
+		node.Decode(dst)
+	}
+	for _, i := range matched {
+		node := s.Overrides[i].Settings[heuristic]
+		node.Decode(dst)
+	}
+
+	if s.cache == nil {
+		s.cache = make(map[string]interface{})
+	}
+	c := reflect.New(reflect.TypeOf(dst).Elem())
+	c.Elem().Set(reflect.ValueOf(dst).Elem())
+	s.cache[key] = c.Interface()
+}
+
+func (sc Scope) matches(t Target) bool {
+	if len(sc.Account) > 0 && !contains(sc.Account, t.Account) {
+		return false
+	}
+	if len(sc.Region) > 0 && !contains(sc.Region, t.Region) {
+		return false
+	}
+	for k, v := range sc.Tags {
+		if t.Tags[k] != v {
+			return false
+		}
+	}
+	return true
+}
+
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+// Setting is one threshold of a heuristic with the value that applies.
+type Setting struct {
+	Heuristic string
+	Key       string
+	Value     string
+	Source    string // "default", "config" or "override"
+}
+
+// EffectiveSettings returns the thresholds each heuristic would use for a
+// resource in target. Heuristics without thresholds are listed with an empty Key.
+func EffectiveSettings(hs []WeightedHeuristic, s *Settings, target Target) []Setting {
+	var out []Setting
+	for _, h := range hs {
+		c, ok := h.(Configurable)
+		if !ok {
+			out = append(out, Setting{Heuristic: h.Name()})
+			continue
+		}
+
+		def := Thresholds(c.Defaults())
+		base := c.Defaults()
+		if s != nil {
+			if node, ok := s.Base[h.Name()]; ok {
+				node.Decode(base)
+			}
+		}
+		configured := Thresholds(base)
+		effective := c.Defaults()
+		s.ResolveTarget(h.Name(), target, effective)
+
+		keys := make([]string, 0, len(def))
+		for k := range def {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		eff := Thresholds(effective)
+		for _, k := range keys {
+			src := "default"
+			if eff[k] != configured[k] {
+				src = "override"
+			} else if configured[k] != def[k] {
+				src = "config"
+			}
+			out = append(out, Setting{Heuristic: h.Name(), Key: k, Value: eff[k], Source: src})
+		}
+	}
+	return out
+}
+
+// Thresholds renders a config struct as setting key -> value, for recording
+// the thresholds a finding was judged against.
+func Thresholds(cfg interface{}) map[string]string {
+	v := reflect.ValueOf(cfg)
+	if v.Kind() == reflect.Ptr {
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Struct {
+		return nil
+	}
+
+	out := make(map[string]string, v.NumField())
+	for i := 0; i < v.NumField(); i++ {
+		key := strings.Split(v.Type().Field(i).Tag.Get("yaml"), ",")[0]
+		if key == "" || key == "-" {
+			continue
+		}
+		switch f := v.Field(i).Interface().(type) {
+		case fmt.Stringer:
+			out[key] = f.String()
+		case float64:
+			out[key] = strconv.FormatFloat(f, 'g', -1, 64)
+		default:
+			out[key] = fmt.Sprint(f)
+		}
+	}
+	return out
+}
+
+// Duration is a setting that accepts Go durations plus whole days, e.g. "7d" or "36h".
+type Duration time.Duration
+
+// Days returns a Duration of n days.
+func Days(n int) Duration { return Duration(time.Duration(n) * 24 * time.Hour) }
+
+// UnmarshalYAML implements yaml.Unmarshaler.
+func (d *Duration) UnmarshalYAML(n *yaml.Node) error {
+	var s string
+	if err := n.Decode(&s); err != nil {
+		return err
+	}
+	if days, ok := strings.CutSuffix(s, "d"); ok {
+		v, err := strconv.Atoi(days)
+		if err != nil || v < 0 {
+			return fmt.Errorf("invalid duration %q", s)
+		}
+		*d = Days(v)
+		return nil
+	}
+	v, err := time.ParseDuration(s)
+	if err != nil || v < 0 {
+		return fmt.Errorf("invalid duration %q", s)
+	}
+	*d = Duration(v)
+	return nil
+}
+
+// MarshalYAML implements yaml.Marshaler.
+func (d Duration) MarshalYAML() (interface{}, error) { return d.String(), nil }
+
+// String renders whole days as "7d" and anything else as a Go duration.
+func (d Duration) String() string {
+	day := 24 * time.Hour
+	if td := time.Duration(d); td != 0 && td%day == 0 {
+		return strconv.Itoa(int(td/day)) + "d"
+	}
+	return time.Duration(d).String()
+}
+
+// Std returns d as a time.Duration.
+func (d Duration) Std() time.Duration { return time.Duration(d) }
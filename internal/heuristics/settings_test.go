@@ -0,0 +1,151 @@
+package heuristics
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DrSkyle/cloudslash/internal/graph"
+	"gopkg.in/yaml.v3"
+)
+
+const testSettings = `
+heuristics:
+  settings:
+    NATGatewayHeuristic: {max_connections: 10, lookback: 14d}
+    S3MultipartHeuristic: {min_age: 30d}
+  overrides:
+    - match: {account: ["111111111111"], tags: {env: sandbox}}
+      settings:
+        NATGatewayHeuristic: {lookback: 36h}
+    - match: {region: [us-west-2]}
+      settings:
+        NATGatewayHeuristic: {max_bytes_out: 5e8}
+`
+
+func TestSettings_Resolve(t *testing.T) {
+	s, err := ParseSettings([]byte(testSettings))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name   string
+		target Target
+		want   NATGatewayConfig
+	}{
+		{"base", Target{Account: "222222222222", Region: "us-east-1"}, NATGatewayConfig{MaxConnections: 10, MaxBytesOut: 1e9, Lookback: Days(14)}},
+		{"tag scoped", Target{Account: "111111111111", Region: "us-east-1", Tags: map[string]string{"env": "sandbox"}}, NATGatewayConfig{MaxConnections: 10, MaxBytesOut: 1e9, Lookback: Duration(36 * time.Hour)}},
+		{"tag missing", Target{Account: "111111111111", Region: "us-east-1"}, NATGatewayConfig{MaxConnections: 10, MaxBytesOut: 1e9, Lookback: Days(14)}},
+		{"both overrides", Target{Account: "111111111111", Region: "us-west-2", Tags: map[string]string{"env": "sandbox"}}, NATGatewayConfig{MaxConnections: 10, MaxBytesOut: 5e8, Lookback: Duration(36 * time.Hour)}},
+	}
+	for _, tt := range tests {
+		// Twice, the second time from the cache.
+		for i := 0; i < 2; i++ {
+			cfg := (&NATGatewayHeuristic{}).Defaults().(*NATGatewayConfig)
+			s.ResolveTarget("NATGatewayHeuristic", tt.target, cfg)
+			if *cfg != tt.want {
+				t.Errorf("%s: got %+v, want %+v", tt.name, *cfg, tt.want)
+			}
+		}
+	}
+
+	// Nil settings keep the defaults.
+	cfg := (&RDSHeuristic{}).Defaults().(*RDSConfig)
+	(*Settings)(nil).ResolveTarget("RDSHeuristic", Target{}, cfg)
+	if cfg.Lookback != Days(7) {
+		t.Errorf("expected default lookback, got %s", cfg.Lookback)
+	}
+}
+
+func TestParseSettings_Invalid(t *testing.T) {
+	tests := map[string]string{
+		"unknown heuristic": "heuristics:\n  settings:\n    NoSuchHeuristic: {x: 1}\n",
+		"not configurable":  "heuristics:\n  settings:\n    ElasticIPHeuristic: {x: 1}\n",
+		"unknown key":       "heuristics:\n  settings:\n    ELBHeuristic: {max_request: 1}\n",
+		"bad duration":      "heuristics:\n  overrides:\n    - settings:\n        ELBHeuristic: {lookback: soon}\n",
+		"bad number":        "heuristics:\n  settings:\n    ELBHeuristic: {max_requests: many}\n",
+	}
+	for name, data := range tests {
+		if _, err := ParseSettings([]byte(data)); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+
+	if s, err := ParseSettings([]byte("region: us-east-1\n")); s != nil || err != nil {
+		t.Errorf("a config without a heuristics section should yield nil settings, got %v, %v", s, err)
+	}
+}
+
+func TestEffectiveSettings(t *testing.T) {
+	s, err := ParseSettings([]byte(testSettings))
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := make(map[string]Setting)
+	for _, st := range EffectiveSettings(Catalog(), s, Target{Account: "111111111111", Tags: map[string]string{"env": "sandbox"}}) {
+		got[st.Heuristic+"."+st.Key] = st
+	}
+
+	want := map[string][2]string{
+		"NATGatewayHeuristic.max_connections": {"10", "config"},
+		"NATGatewayHeuristic.lookback":        {"36h0m0s", "override"},
+		"NATGatewayHeuristic.max_bytes_out":   {"1e+09", "default"},
+		"RDSHeuristic.lookback":               {"7d", "default"},
+	}
+	for k, w := range want {
+		if st := got[k]; st.Value != w[0] || st.Source != w[1] {
+			t.Errorf("%s: got %q (%s), want %q (%s)", k, st.Value, st.Source, w[0], w[1])
+		}
+	}
+	if _, ok := got["ElasticIPHeuristic."]; !ok {
+		t.Error("heuristics without settings should still be listed")
+	}
+}
+
+func TestHeuristic_UsesAndRecordsThresholds(t *testing.T) {
+	s, err := ParseSettings([]byte(testSettings))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	run := func(s *Settings) *graph.Node {
+		g := graph.NewGraph()
+		g.AddNode("upload-1", "AWS::S3::MultipartUpload", map[string]interface{}{"Initiated": time.Now().Add(-10 * 24 * time.Hour)})
+		e := NewEngine()
+		e.Settings = s
+		e.Register(&S3MultipartHeuristic{})
+		if err := e.Run(context.Background(), g); err != nil {
+			t.Fatal(err)
+		}
+		return nodeByID(g, "upload-1")
+	}
+
+	f, ok := run(nil).FindingBy("S3MultipartHeuristic")
+	if !ok || f.Thresholds["min_age"] != "7d" || !strings.Contains(f.Reason, "7d") {
+		t.Fatalf("expected a finding judged against the 7d default, got %+v", f)
+	}
+
+	if _, ok := run(s).FindingBy("S3MultipartHeuristic"); ok {
+		t.Error("a 10 day old upload should pass with min_age 30d")
+	}
+}
+
+func TestDuration(t *testing.T) {
+	for in, want := range map[string]string{"7d": "7d", "36h": "36h0m0s", "48h": "2d", "90m": "1h30m0s"} {
+		var d Duration
+		if err := yaml.Unmarshal([]byte(in), &d); err != nil {
+			t.Fatalf("%s: %v", in, err)
+		}
+		if d.String() != want {
+			t.Errorf("%s: got %s, want %s", in, d, want)
+		}
+	}
+	for _, in := range []string{"-1d", "xd", "soon"} {
+		var d Duration
+		if err := yaml.Unmarshal([]byte(in), &d); err == nil {
+			t.Errorf("%s: expected an error", in)
+		}
+	}
+}
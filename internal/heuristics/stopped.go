@@ -0,0 +1,147 @@
+package heuristics
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/DrSkyle/cloudslash/internal/graph"
+	"github.com/DrSkyle/cloudslash/internal/resource"
+)
+
+// StoppedInstanceHeuristic flags EC2 instances that have been stopped for a
+// long time. A stopped instance bills nothing for compute, but its EBS volumes
+// and public IPv4 addresses keep billing; the finding totals them, leaving out
+// those already flagged as waste on their own.
+type StoppedInstanceHeuristic struct {
+	Tunable
+	Pricing PricingClient
+}
+
+// StoppedInstanceConfig holds the StoppedInstanceHeuristic thresholds.
+type StoppedInstanceConfig struct {
+	MinStopped Duration `yaml:"min_stopped"` // Flag instances stopped longer than this
+}
+
+func (h *StoppedInstanceHeuristic) Defaults() interface{} {
+	return &StoppedInstanceConfig{MinStopped: Days(30)}
+}
+
+func (h *StoppedInstanceHeuristic) Name() string { return "StoppedInstanceHeuristic" }
+
+func (h *StoppedInstanceHeuristic) Flags() []string { return []string{"AWS::EC2::Instance"} }
+
+// DependsOn lets volumes and Elastic IPs be flagged first, so their savings
+// are not counted twice.
+func (h *StoppedInstanceHeuristic) DependsOn() Dependencies {
+	return Dependencies{Heuristics: []string{"ZombieEBSHeuristic", "ElasticIPHeuristic"}}
+}
+
+func (h *StoppedInstanceHeuristic) Analyze(ctx context.Context, v *graph.View, cov *Coverage) ([]HeuristicResult, error) {
+	var results []HeuristicResult
+
+	instances := v.NodesByType("AWS::EC2::Instance")
+	cov.Examine(len(instances))
+
+	for _, node := range instances {
+		if state, _ := node.Properties["State"].(string); state != "stopped" {
+			continue
+		}
+		cfg := h.Defaults().(*StoppedInstanceConfig)
+		h.Settings.Resolve(h.Name(), node, cfg)
+
+		stoppedAt, ok := node.Properties["StoppedAt"].(time.Time)
+		if !ok {
+			cov.Skip(node.ID, "stop time unknown (no state transition time)")
+			continue
+		}
+		stopped := time.Since(stoppedAt)
+		if stopped <= cfg.MinStopped.Std() {
+			continue
+		}
+
+		// Everything attached to the instance keeps billing while it is stopped.
+		var total, billed float64
+		evidence := []string{"Stopped since " + stoppedAt.Format("2006-01-02")}
+		hasIPv4 := false
+		for _, e := range v.ReverseEdges(node.ID) {
+			attached, ok := v.Node(e.TargetID)
+			if e.Type != graph.EdgeTypeAttachedTo || !ok {
+				continue
+			}
+			var line string
+			var cost float64
+			switch attached.Type {
+			case "AWS::EC2::Volume":
+				cost = h.volumeCost(ctx, attached)
+				line = fmt.Sprintf("EBS volume %s: $%.2f/mo", resource.ResourceID(attached.ID), cost)
+				if keep, _ := attached.Properties["DeleteOnTermination"].(bool); !keep {
+					line += " (kept on termination)"
+				}
+			case "AWS::EC2::EIP":
+				cost = h.ipv4Cost(ctx, attached)
+				hasIPv4 = true
+				line = fmt.Sprintf("Elastic IP %s: $%.2f/mo", resource.ResourceID(attached.ID), cost)
+			default:
+				continue
+			}
+			billed += cost
+			if flaggedWaste(attached) {
+				line += " (counted in its own finding)"
+			} else {
+				total += cost
+			}
+			evidence = append(evidence, line)
+		}
+		if ip, ok := node.Properties["PublicIpAddress"].(string); ok && ip != "" && !hasIPv4 {
+			cost := h.ipv4Cost(ctx, node)
+			total += cost
+			billed += cost
+			evidence = append(evidence, fmt.Sprintf("Public IPv4 %s: $%.2f/mo", ip, cost))
+		}
+
+		results = append(results, HeuristicResult{
+			ResourceID:     node.ID,
+			Category:       graph.CategoryWaste,
+			Confidence:     0.8,
+			RiskScore:      60,
+			MonthlySavings: total,
+			Reason:         fmt.Sprintf("Instance stopped for %d days; attached storage and IPs still bill $%.2f/mo", int(stopped.Hours()/24), billed),
+			Evidence:       evidence,
+			Action:         "Create an AMI, then terminate the instance and release its Elastic IPs",
+			Thresholds:     Thresholds(cfg),
+		})
+	}
+	return results, nil
+}
+
+// volumeCost prices an EBS volume, falling back to the cost already on the node.
+func (h *StoppedInstanceHeuristic) volumeCost(ctx context.Context, vol *graph.Node) float64 {
+	size := 0
+	switch s := vol.Properties["Size"].(type) {
+	case int32:
+		size = int(s)
+	case int:
+		size = s
+	}
+	if h.Pricing != nil && size > 0 {
+		volType, _ := vol.Properties["VolumeType"].(string)
+		if cost, err := h.Pricing.GetEBSPrice(ctx, resource.Region(vol.ID, "us-east-1"), volType, size); err == nil {
+			return cost
+		}
+	}
+	return vol.Cost
+}
+
+// ipv4Cost prices a public IPv4 address, falling back to the cost already on the node.
+func (h *StoppedInstanceHeuristic) ipv4Cost(ctx context.Context, n *graph.Node) float64 {
+	if h.Pricing != nil {
+		if cost, err := h.Pricing.GetEIPPrice(ctx, resource.Region(n.ID, "us-east-1")); err == nil {
+			return cost
+		}
+	}
+	if n.Type == "AWS::EC2::EIP" {
+		return n.Cost
+	}
+	return 0
+}
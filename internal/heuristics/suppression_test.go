@@ -0,0 +1,221 @@
+package heuristics
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DrSkyle/cloudslash/internal/graph"
+	"github.com/DrSkyle/cloudslash/internal/k8s"
+	"github.com/DrSkyle/cloudslash/internal/tf"
+)
+
+// suppressionCase sets up one resource the heuristic flags. tags are merged
+// into the resource's Tags so each case can be run with and without
+// cloudslash:ignore.
+type suppressionCase struct {
+	name  string
+	id    string
+	setup func(g *graph.Graph, tags map[string]string)
+	run   func(ctx context.Context, g *graph.Graph) error
+}
+
+func withTags(props map[string]interface{}, tags map[string]string) map[string]interface{} {
+	props["Tags"] = tags
+	return props
+}
+
+// engineRun applies the heuristics through the engine, as a scan would.
+func engineRun(hs ...WeightedHeuristic) func(ctx context.Context, g *graph.Graph) error {
+	return func(ctx context.Context, g *graph.Graph) error {
+		e := NewEngine()
+		for _, h := range hs {
+			e.Register(h)
+		}
+		return e.Run(ctx, g)
+	}
+}
+
+// nodeByID returns the node with id, or nil when the graph has none.
+func nodeByID(g *graph.Graph, id string) *graph.Node {
+	node, _ := g.Node(id)
+	return node
+}
+
+func suppressionCases() []suppressionCase {
+	const (
+		vol  = "arn:aws:ec2:us-east-1:123456789012:volume/vol-1"
+		snap = "arn:aws:ec2:us-east-1::snapshot/snap-1"
+	)
+	return []suppressionCase{
+		{
+			name: "ZombieEBSHeuristic", id: vol,
+			setup: func(g *graph.Graph, tags map[string]string) {
+				g.AddNode(vol, "AWS::EC2::Volume", withTags(map[string]interface{}{"State": "available"}, tags))
+			},
+			run: engineRun(&ZombieEBSHeuristic{}),
+		},
+		{
+			name: "ElasticIPHeuristic", id: "arn:aws:ec2:us-east-1:123456789012:elastic-ip/eipalloc-1",
+			setup: func(g *graph.Graph, tags map[string]string) {
+				g.AddNode("arn:aws:ec2:us-east-1:123456789012:elastic-ip/eipalloc-1", "AWS::EC2::EIP", withTags(map[string]interface{}{}, tags))
+			},
+			run: engineRun(&ElasticIPHeuristic{}),
+		},
+		{
+			name: "StoppedInstanceHeuristic", id: "arn:aws:ec2:us-east-1:123456789012:instance/i-1",
+			setup: func(g *graph.Graph, tags map[string]string) {
+				g.AddNode("arn:aws:ec2:us-east-1:123456789012:instance/i-1", "AWS::EC2::Instance", withTags(map[string]interface{}{
+					"State":     "stopped",
+					"StoppedAt": time.Now().Add(-60 * 24 * time.Hour),
+				}, tags))
+			},
+			run: engineRun(&StoppedInstanceHeuristic{}),
+		},
+		{
+			name: "S3MultipartHeuristic", id: "upload-1",
+			setup: func(g *graph.Graph, tags map[string]string) {
+				g.AddNode("upload-1", "AWS::S3::MultipartUpload", withTags(map[string]interface{}{"Initiated": time.Now().Add(-8 * 24 * time.Hour)}, tags))
+			},
+			run: engineRun(&S3MultipartHeuristic{}),
+		},
+		{
+			name: "RDSHeuristic", id: "arn:aws:rds:us-east-1:123456789012:db:db-1",
+			setup: func(g *graph.Graph, tags map[string]string) {
+				g.AddNode("arn:aws:rds:us-east-1:123456789012:db:db-1", "AWS::RDS::DBInstance", withTags(map[string]interface{}{"Status": "stopped"}, tags))
+			},
+			run: engineRun(&RDSHeuristic{}),
+		},
+		{
+			name: "TagComplianceHeuristic", id: vol,
+			setup: func(g *graph.Graph, tags map[string]string) {
+				g.AddNode(vol, "AWS::EC2::Volume", withTags(map[string]interface{}{}, tags))
+			},
+			run: engineRun(&TagComplianceHeuristic{RequiredTags: []string{"Owner"}}),
+		},
+		{
+			name: "LogHoarders", id: "arn:aws:logs:us-east-1:123456789012:log-group:app",
+			setup: func(g *graph.Graph, tags map[string]string) {
+				g.AddNode("arn:aws:logs:us-east-1:123456789012:log-group:app", "AWS::Logs::LogGroup", withTags(map[string]interface{}{
+					"Retention":   "Never",
+					"StoredBytes": int64(5 << 30),
+				}, tags))
+			},
+			run: engineRun(&LogHoardersHeuristic{}),
+		},
+		{
+			name: "FossilAMIs", id: snap,
+			setup: func(g *graph.Graph, tags map[string]string) {
+				g.AddNode(snap, "AWS::EC2::Snapshot", withTags(map[string]interface{}{
+					"Description": "Created by CreateImage(i-1) for ami-1",
+					"VolumeSize":  int32(8),
+				}, tags))
+			},
+			run: engineRun(&FossilAMIHeuristic{}),
+		},
+		{
+			name: "SnapshotChildrenHeuristic", id: snap,
+			setup: func(g *graph.Graph, tags map[string]string) {
+				g.AddNode(vol, "AWS::EC2::Volume", nil)
+				g.AddFinding(vol, graph.Finding{Heuristic: "ZombieEBSHeuristic", Category: graph.CategoryWaste, RiskScore: 90})
+				g.AddNode(snap, "AWS::EC2::Snapshot", withTags(map[string]interface{}{
+					"VolumeId": "vol-1",
+					"OwnerId":  "123456789012",
+				}, tags))
+			},
+			run: engineRun(&SnapshotChildrenHeuristic{}),
+		},
+		{
+			name: "GhostNodeGroupHeuristic", id: "arn:aws:eks:us-east-1:123456789012:nodegroup/c/ng/1",
+			setup: func(g *graph.Graph, tags map[string]string) {
+				g.AddNode("arn:aws:eks:us-east-1:123456789012:nodegroup/c/ng/1", "AWS::EKS::NodeGroup", withTags(map[string]interface{}{
+					"RealWorkloadCount": 0,
+					"NodeCount":         2,
+				}, tags))
+			},
+			run: engineRun(&GhostNodeGroupHeuristic{}),
+		},
+		{
+			name: "ZombieEKSHeuristic", id: "arn:aws:eks:us-east-1:123456789012:cluster/c",
+			setup: func(g *graph.Graph, tags map[string]string) {
+				g.AddNode("arn:aws:eks:us-east-1:123456789012:cluster/c", "AWS::EKS::Cluster", withTags(map[string]interface{}{
+					"Status":    "ACTIVE",
+					"CreatedAt": time.Now().Add(-8 * 24 * time.Hour),
+				}, tags))
+			},
+			run: engineRun(&ZombieEKSHeuristic{}),
+		},
+		{
+			// A profile without selectors is flagged before the cluster is queried.
+			name: "AbandonedFargateHeuristic", id: "arn:aws:eks:us-east-1:123456789012:fargateprofile/c/app/1",
+			setup: func(g *graph.Graph, tags map[string]string) {
+				g.AddNode("arn:aws:eks:us-east-1:123456789012:fargateprofile/c/app/1", "AWS::EKS::FargateProfile", withTags(map[string]interface{}{
+					"ProfileName": "app",
+				}, tags))
+			},
+			run: engineRun(&AbandonedFargateHeuristic{K8sClient: &k8s.Client{}}),
+		},
+		{
+			name: "TerraformDrift", id: vol,
+			setup: func(g *graph.Graph, tags map[string]string) {
+				g.AddNode(vol, "AWS::EC2::Volume", withTags(map[string]interface{}{}, tags))
+			},
+			run: func(ctx context.Context, g *graph.Graph) error {
+				tf.NewDriftDetector(g, &tf.State{}).ScanForDrift()
+				return nil
+			},
+		},
+	}
+}
+
+// stubPolicy suppresses every finding of one heuristic.
+type stubPolicy struct{ heuristic string }
+
+func (p stubPolicy) Evaluate(n *graph.Node, f graph.Finding) graph.Verdict {
+	if f.Heuristic == p.heuristic {
+		return graph.Verdict{Suppressed: true, Rule: "policy:stub"}
+	}
+	return graph.Verdict{}
+}
+
+func TestHeuristics_HonorSuppression(t *testing.T) {
+	ctx := context.Background()
+
+	for _, c := range suppressionCases() {
+		t.Run(c.name, func(t *testing.T) {
+			run := func(tags map[string]string, s graph.Suppressor) *graph.Node {
+				g := graph.NewGraph()
+				g.Suppressor = s
+				c.setup(g, tags)
+				if err := c.run(ctx, g); err != nil {
+					t.Fatalf("run failed: %v", err)
+				}
+				return nodeByID(g, c.id)
+			}
+
+			node := run(map[string]string{}, nil)
+			if _, ok := node.FindingBy(c.name); !ok {
+				t.Fatalf("expected a %s finding on the untagged resource, got %+v", c.name, node.Findings)
+			}
+
+			node = run(map[string]string{"cloudslash:ignore": "true"}, nil)
+			if _, ok := node.FindingBy(c.name); ok || len(node.Suppressed) == 0 {
+				t.Fatalf("cloudslash:ignore=true not honored: findings %+v", node.Findings)
+			}
+			if f := node.Suppressed[len(node.Suppressed)-1]; f.Heuristic != c.name || f.Rule != graph.TagRule {
+				t.Errorf("suppressed finding should record the tag rule, got %+v", f)
+			}
+
+			node = run(map[string]string{"cloudslash:ignore": "justified:dr"}, nil)
+			f, ok := node.FindingBy(c.name)
+			if !ok || !node.Justified || node.Justification != "dr" || f.Rule != graph.TagRule {
+				t.Errorf("justification not honored: justified=%v %+v", node.Justified, f)
+			}
+
+			node = run(map[string]string{}, stubPolicy{heuristic: c.name})
+			if _, ok := node.FindingBy(c.name); ok || len(node.Suppressed) == 0 || node.Suppressed[len(node.Suppressed)-1].Rule != "policy:stub" {
+				t.Errorf("policy suppression not honored: findings %+v suppressed %+v", node.Findings, node.Suppressed)
+			}
+		})
+	}
+}
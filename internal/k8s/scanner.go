@@ -115,17 +115,37 @@ func (s *Scanner) Scan(ctx context.Context) error {
         }
         
         // --- ADD TO GRAPH ---
-        id := fmt.Sprintf("arn:aws:eks:unknown:unknown:nodegroup/%s", ngName)
-        
+        // Node group ARNs end in an ID only the EKS API knows, so the counts
+        // are recorded on the node group the EKS scanner already ingested.
+        id, ok := s.nodeGroupARN(ngName)
+        if !ok {
+            continue
+        }
+
         props := map[string]interface{}{
-            "NodeGroupName": ngName,
             "NodeCount": totalNodeCount,
             "RealWorkloadCount": realWorkloadCount,
-            "ClusterName": "detected-via-k8s",
         }
-        
+
         s.Graph.AddNode(id, "AWS::EKS::NodeGroup", props)
     }
 
     return nil
 }
+
+// nodeGroupARN finds the scanned EKS node group with the given name. Names
+// are only unique within a cluster, so an ambiguous name matches nothing.
+func (s *Scanner) nodeGroupARN(name string) (string, bool) {
+    var matches []string
+    s.Graph.Read(func(v *graph.View) {
+        for _, ng := range v.NodesByType("AWS::EKS::NodeGroup") {
+            if ng.Properties["NodeGroupName"] == name {
+                matches = append(matches, ng.ID)
+            }
+        }
+    })
+    if len(matches) != 1 {
+        return "", false
+    }
+    return matches[0], true
+}
@@ -34,20 +34,24 @@ func SendSlackReport(webhookURL string, g *graph.Graph) error {
 	totalWaste := 0
 	totalCost := 0.0
 	highRisk := 0
+	recommendations := 0 // Rightsizing, security and compliance, not counted as waste
 
-	g.Mu.RLock()
 	var topItems []*graph.Node
-	for _, node := range g.Nodes {
-		if node.IsWaste {
-			totalWaste++
-			totalCost += node.Cost
-			if node.RiskScore >= 80 {
-				highRisk++
+	g.Read(func(v *graph.View) {
+		v.Each(func(node *graph.Node) {
+			switch {
+			case node.HasWasteFinding():
+				totalWaste++
+				totalCost += node.Savings
+				if node.RiskScore >= 80 {
+					highRisk++
+				}
+				topItems = append(topItems, node)
+			case len(node.Findings) > 0:
+				recommendations++
 			}
-			topItems = append(topItems, node)
-		}
-	}
-	g.Mu.RUnlock()
+		})
+	})
 
 	// Title Section
 	limit := 5
@@ -70,6 +74,7 @@ func SendSlackReport(webhookURL string, g *graph.Graph) error {
 					{Type: "mrkdwn", Text: fmt.Sprintf("*Total Waste Cost:*\n%s/mo", costStr)},
 					{Type: "mrkdwn", Text: fmt.Sprintf("*Resources Flagged:*\n%d", totalWaste)},
 					{Type: "mrkdwn", Text: fmt.Sprintf("*High Risk Items:*\n%d", highRisk)},
+					{Type: "mrkdwn", Text: fmt.Sprintf("*Other Recommendations:*\n%d", recommendations)},
 					{Type: "mrkdwn", Text: fmt.Sprintf("*Scan Time:*\n%s", time.Now().Format("2006-01-02 15:04"))},
 				},
 			},
@@ -97,11 +102,12 @@ func SendSlackReport(webhookURL string, g *graph.Graph) error {
 			emoji = "🚨"
 		}
 
-		reason, _ := node.Properties["Reason"].(string)
-
-		itemText := fmt.Sprintf("%s *%s* (Risk: %d)\n> %s", emoji, shortID, node.RiskScore, reason)
-		if node.Cost > 0 {
-			itemText += fmt.Sprintf("\n> *Cost: $%.2f/mo*", node.Cost)
+		itemText := fmt.Sprintf("%s *%s* (Risk: %d)", emoji, shortID, node.RiskScore)
+		for _, f := range node.Findings {
+			itemText += fmt.Sprintf("\n> _%s_: %s", f.Category, f.Reason)
+		}
+		if node.Savings > 0 {
+			itemText += fmt.Sprintf("\n> *Savings: $%.2f/mo*", node.Savings)
 		}
 
 		msg.Blocks = append(msg.Blocks, Block{
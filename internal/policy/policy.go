@@ -0,0 +1,335 @@
+// Package policy loads .cloudslash-policy.yaml, a central list of rules that
+// suppress or justify findings without tagging the resources themselves.
+//
+//	version: 1
+//	rules:
+//	  - id: dr-standby-volumes
+//	    owner: platform-team@example.com
+//	    reason: Warm standby for the DR runbook
+//	    expires: 2026-12-31
+//	    action: justify            # or suppress (default)
+//	    match:
+//	      arn: ["arn:aws:ec2:us-west-2:*:volume/*"]
+//	      type: [AWS::EC2::Volume]
+//	      tags: {purpose: dr}
+//	      account: ["123456789012"]
+//	      region: [us-west-2]
+//	      heuristic: [ZombieEBSHeuristic]
+//	      max_monthly_cost: 50     # only findings worth less than $50/mo
+//
+// Lists match if any entry matches; all given criteria must match. ARN, type,
+// tag value and heuristic entries accept * and ? wildcards. The first
+// unexpired matching rule wins.
+package policy
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/DrSkyle/cloudslash/internal/graph"
+	"github.com/DrSkyle/cloudslash/internal/resource"
+	"gopkg.in/yaml.v3"
+)
+
+// DefaultFile is looked up in the working directory when no path is given.
+const DefaultFile = ".cloudslash-policy.yaml"
+
+// Action is what a matching rule does to a finding.
+type Action string
+
+const (
+	ActionSuppress Action = "suppress" // Drop the finding
+	ActionJustify  Action = "justify"  // Keep it as accepted waste, excluded from remediation
+)
+
+// Rule suppresses or justifies the findings it matches until it expires.
+type Rule struct {
+	ID      string `yaml:"id"`
+	Owner   string `yaml:"owner"`
+	Reason  string `yaml:"reason"`
+	Expires string `yaml:"expires"` // YYYY-MM-DD, the rule stops applying on this day
+	Action  Action `yaml:"action"`
+	Match   Match  `yaml:"match"`
+
+	expires    time.Time
+	arns       []*regexp.Regexp
+	types      []*regexp.Regexp
+	heuristics []*regexp.Regexp
+	tags       map[string]*regexp.Regexp
+}
+
+// Match selects findings. Empty fields match everything.
+type Match struct {
+	ARN            []string          `yaml:"arn"`
+	Type           []string          `yaml:"type"`
+	Tags           map[string]string `yaml:"tags"`
+	Account        []string          `yaml:"account"`
+	Region         []string          `yaml:"region"`
+	Heuristic      []string          `yaml:"heuristic"`
+	MaxMonthlyCost float64           `yaml:"max_monthly_cost"`
+}
+
+// Policy is a parsed policy file. It implements graph.Suppressor and counts
+// how often each rule matched, so stale rules can be reported after a scan.
+type Policy struct {
+	Path  string
+	Rules []*Rule
+
+	now  func() time.Time
+	mu   sync.Mutex
+	hits map[string]int
+}
+
+type file struct {
+	Version int     `yaml:"version"`
+	Rules   []*Rule `yaml:"rules"`
+}
+
+// Load reads and validates a policy file.
+func Load(path string) (*Policy, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read policy: %v", err)
+	}
+	p, err := Parse(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", path, err)
+	}
+	p.Path = path
+	return p, nil
+}
+
+// LoadDefault loads path, or DefaultFile if path is empty.
+// A missing DefaultFile is not an error and yields a nil policy.
+func LoadDefault(path string) (*Policy, error) {
+	if path != "" {
+		return Load(path)
+	}
+	if _, err := os.Stat(DefaultFile); errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	return Load(DefaultFile)
+}
+
+// Parse decodes and validates policy YAML.
+func Parse(data []byte) (*Policy, error) {
+	var f file
+	if err := yaml.Unmarshal(data, &f); err != nil {
+		return nil, fmt.Errorf("invalid policy: %v", err)
+	}
+	if f.Version > 1 {
+		return nil, fmt.Errorf("unsupported policy version %d", f.Version)
+	}
+
+	seen := make(map[string]bool)
+	for i, r := range f.Rules {
+		if r.ID == "" {
+			r.ID = fmt.Sprintf("rule-%d", i+1)
+		}
+		if seen[r.ID] {
+			return nil, fmt.Errorf("duplicate rule id %q", r.ID)
+		}
+		seen[r.ID] = true
+		if err := r.compile(); err != nil {
+			return nil, fmt.Errorf("rule %s: %v", r.ID, err)
+		}
+	}
+
+	return &Policy{Rules: f.Rules, now: time.Now, hits: make(map[string]int)}, nil
+}
+
+func (r *Rule) compile() error {
+	if strings.TrimSpace(r.Owner) == "" {
+		return fmt.Errorf("owner is required")
+	}
+	if strings.TrimSpace(r.Reason) == "" {
+		return fmt.Errorf("reason is required")
+	}
+	if r.Expires == "" {
+		return fmt.Errorf("expires is required (YYYY-MM-DD)")
+	}
+	exp, err := time.Parse("2006-01-02", r.Expires)
+	if err != nil {
+		return fmt.Errorf("invalid expires %q, want YYYY-MM-DD", r.Expires)
+	}
+	r.expires = exp
+
+	switch r.Action {
+	case "":
+		r.Action = ActionSuppress
+	case ActionSuppress, ActionJustify:
+	default:
+		return fmt.Errorf("unknown action %q (use suppress or justify)", r.Action)
+	}
+
+	m := r.Match
+	if len(m.ARN) == 0 && len(m.Type) == 0 && len(m.Tags) == 0 && len(m.Account) == 0 &&
+		len(m.Region) == 0 && len(m.Heuristic) == 0 && m.MaxMonthlyCost == 0 {
+		return fmt.Errorf("match is empty; a rule must select something")
+	}
+
+	r.arns = globs(m.ARN)
+	r.types = globs(m.Type)
+	r.heuristics = globs(m.Heuristic)
+	if len(m.Tags) > 0 {
+		r.tags = make(map[string]*regexp.Regexp, len(m.Tags))
+		for k, v := range m.Tags {
+			r.tags[k] = glob(v)
+		}
+	}
+	return nil
+}
+
+// ExpiresAt returns the first day the rule no longer applies.
+func (r *Rule) ExpiresAt() time.Time { return r.expires }
+
+// Expired reports whether the rule has stopped applying at t.
+func (r *Rule) Expired(t time.Time) bool { return !t.Before(r.expires) }
+
+// Evaluate implements graph.Suppressor. The caller holds the graph lock.
+func (p *Policy) Evaluate(n *graph.Node, f graph.Finding) graph.Verdict {
+	now := p.now()
+	for _, r := range p.Rules {
+		if r.Expired(now) || !r.matches(n, f) {
+			continue
+		}
+
+		p.mu.Lock()
+		p.hits[r.ID]++
+		p.mu.Unlock()
+
+		v := graph.Verdict{Rule: "policy:" + r.ID}
+		if r.Action == ActionJustify {
+			v.Justification = r.Reason
+		} else {
+			v.Suppressed = true
+		}
+		return v
+	}
+	return graph.Verdict{}
+}
+
+// Hits returns how many findings the rule has matched since the policy was loaded.
+func (p *Policy) Hits(id string) int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.hits[id]
+}
+
+// Expired returns the rules past their expiry date.
+func (p *Policy) Expired() []*Rule {
+	now := p.now()
+	var out []*Rule
+	for _, r := range p.Rules {
+		if r.Expired(now) {
+			out = append(out, r)
+		}
+	}
+	return out
+}
+
+// Unused returns the unexpired rules that matched nothing.
+func (p *Policy) Unused() []*Rule {
+	now := p.now()
+	var out []*Rule
+	for _, r := range p.Rules {
+		if !r.Expired(now) && p.Hits(r.ID) == 0 {
+			out = append(out, r)
+		}
+	}
+	return out
+}
+
+func (r *Rule) matches(n *graph.Node, f graph.Finding) bool {
+	m := r.Match
+	if len(r.arns) > 0 && !anyMatch(r.arns, n.ID) {
+		return false
+	}
+	if len(r.types) > 0 && !anyMatch(r.types, n.Type) {
+		return false
+	}
+	if len(r.heuristics) > 0 && !anyMatch(r.heuristics, f.Heuristic) {
+		return false
+	}
+	if len(m.Account) > 0 || len(m.Region) > 0 {
+		arn, err := resource.ParseARN(n.ID)
+		if err != nil {
+			return false
+		}
+		account := arn.AccountID
+		if account == "" {
+			// Snapshot and AMI ARNs carry no account; scanners record the owner instead.
+			account, _ = n.Properties["OwnerId"].(string)
+		}
+		if len(m.Account) > 0 && !contains(m.Account, account) {
+			return false
+		}
+		if len(m.Region) > 0 && !contains(m.Region, arn.Region) {
+			return false
+		}
+	}
+	if len(r.tags) > 0 {
+		tags, _ := n.Properties["Tags"].(map[string]string)
+		for k, re := range r.tags {
+			v, ok := tags[k]
+			if !ok || !re.MatchString(v) {
+				return false
+			}
+		}
+	}
+	if m.MaxMonthlyCost > 0 {
+		if f.Savings(n) >= m.MaxMonthlyCost {
+			return false
+		}
+	}
+	return true
+}
+
+// glob compiles a pattern with * and ? wildcards into an anchored regexp.
+func glob(pattern string) *regexp.Regexp {
+	var sb strings.Builder
+	sb.WriteString("^")
+	for _, r := range pattern {
+		switch r {
+		case '*':
+			sb.WriteString(".*")
+		case '?':
+			sb.WriteString(".")
+		default:
+			sb.WriteString(regexp.QuoteMeta(string(r)))
+		}
+	}
+	sb.WriteString("$")
+	return regexp.MustCompile(sb.String())
+}
+
+func globs(patterns []string) []*regexp.Regexp {
+	out := make([]*regexp.Regexp, 0, len(patterns))
+	for _, p := range patterns {
+		out = append(out, glob(p))
+	}
+	return out
+}
+
+func anyMatch(res []*regexp.Regexp, s string) bool {
+	for _, re := range res {
+		if re.MatchString(s) {
+			return true
+		}
+	}
+	return false
+}
+
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,173 @@
+package policy
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DrSkyle/cloudslash/internal/graph"
+)
+
+const volARN = "arn:aws:ec2:us-west-2:123456789012:volume/vol-1"
+
+func mustParse(t *testing.T, src string) *Policy {
+	t.Helper()
+	p, err := Parse([]byte(src))
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	p.now = func() time.Time { return time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC) }
+	return p
+}
+
+func TestParse_Validation(t *testing.T) {
+	cases := map[string]string{
+		"owner is required":   "rules:\n  - reason: r\n    expires: 2026-12-31\n    match: {type: [X]}\n",
+		"reason is required":  "rules:\n  - owner: o\n    expires: 2026-12-31\n    match: {type: [X]}\n",
+		"expires is required": "rules:\n  - owner: o\n    reason: r\n    match: {type: [X]}\n",
+		"invalid expires":     "rules:\n  - owner: o\n    reason: r\n    expires: next year\n    match: {type: [X]}\n",
+		"unknown action":      "rules:\n  - owner: o\n    reason: r\n    expires: 2026-12-31\n    action: delete\n    match: {type: [X]}\n",
+		"match is empty":      "rules:\n  - owner: o\n    reason: r\n    expires: 2026-12-31\n",
+		"duplicate rule id":   "rules:\n  - {id: a, owner: o, reason: r, expires: 2026-12-31, match: {type: [X]}}\n  - {id: a, owner: o, reason: r, expires: 2026-12-31, match: {type: [Y]}}\n",
+	}
+	for want, src := range cases {
+		if _, err := Parse([]byte(src)); err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("expected error containing %q, got %v", want, err)
+		}
+	}
+}
+
+func TestEvaluate_Match(t *testing.T) {
+	node := &graph.Node{
+		ID:         volARN,
+		Type:       "AWS::EC2::Volume",
+		Cost:       40,
+		Properties: map[string]interface{}{"Tags": map[string]string{"purpose": "dr-standby"}},
+	}
+	f := graph.Finding{Heuristic: "ZombieEBSHeuristic", Category: graph.CategoryWaste, MonthlySavings: 40}
+
+	cases := []struct {
+		match string
+		want  bool
+	}{
+		{`{arn: ["arn:aws:ec2:*:*:volume/*"]}`, true},
+		{`{arn: ["arn:aws:ec2:*:*:snapshot/*"]}`, false},
+		{`{type: [AWS::EC2::Snapshot, AWS::EC2::Vol*]}`, true},
+		{`{tags: {purpose: dr-*}}`, true},
+		{`{tags: {purpose: dr-*, team: data}}`, false},
+		{`{account: ["123456789012"], region: [us-west-2]}`, true},
+		{`{region: [us-east-1]}`, false},
+		{`{heuristic: [ZombieEBSHeuristic]}`, true},
+		{`{heuristic: [ZombieEBSHeuristic], max_monthly_cost: 50}`, true},
+		{`{heuristic: [ZombieEBSHeuristic], max_monthly_cost: 40}`, false},
+	}
+	for _, c := range cases {
+		p := mustParse(t, "rules:\n  - {id: r, owner: o, reason: r, expires: 2026-12-31, match: "+c.match+"}\n")
+		v := p.Evaluate(node, f)
+		if got := v.Rule != ""; got != c.want {
+			t.Errorf("match %s: matched = %v, want %v", c.match, got, c.want)
+		}
+	}
+}
+
+func TestEvaluate_AccountFromOwner(t *testing.T) {
+	snap := &graph.Node{
+		ID:         "arn:aws:ec2:us-west-2::snapshot/snap-1",
+		Type:       "AWS::EC2::Snapshot",
+		Properties: map[string]interface{}{"OwnerId": "123456789012"},
+	}
+	f := graph.Finding{Heuristic: "SnapshotChildrenHeuristic"}
+
+	for account, want := range map[string]bool{"123456789012": true, "210987654321": false} {
+		p := mustParse(t, "rules:\n  - {id: r, owner: o, reason: r, expires: 2026-12-31, match: {account: ["+account+"]}}\n")
+		if got := p.Evaluate(snap, f).Rule != ""; got != want {
+			t.Errorf("account %s: matched = %v, want %v", account, got, want)
+		}
+	}
+}
+
+func TestEvaluate_ActionsAndExpiry(t *testing.T) {
+	p := mustParse(t, `
+rules:
+  - id: old
+    owner: o
+    reason: expired exception
+    expires: 2026-01-01
+    match: {type: [AWS::EC2::Volume]}
+  - id: dr
+    owner: platform
+    reason: DR standby
+    expires: 2026-12-31
+    action: justify
+    match: {type: [AWS::EC2::Volume]}
+  - id: snaps
+    owner: o
+    reason: r
+    expires: 2026-12-31
+    match: {type: [AWS::EC2::Snapshot]}
+  - id: eips
+    owner: o
+    reason: r
+    expires: 2026-12-31
+    match: {type: [AWS::EC2::EIP]}
+`)
+	vol := &graph.Node{ID: "vol-1", Type: "AWS::EC2::Volume", Properties: map[string]interface{}{}}
+	snap := &graph.Node{ID: "snap-1", Type: "AWS::EC2::Snapshot", Properties: map[string]interface{}{}}
+
+	v := p.Evaluate(vol, graph.Finding{})
+	if v.Rule != "policy:dr" || v.Suppressed || v.Justification != "DR standby" {
+		t.Errorf("expired rule should be skipped and dr should justify, got %+v", v)
+	}
+	v = p.Evaluate(snap, graph.Finding{})
+	if v.Rule != "policy:snaps" || !v.Suppressed {
+		t.Errorf("snaps should suppress, got %+v", v)
+	}
+
+	if exp := p.Expired(); len(exp) != 1 || exp[0].ID != "old" {
+		t.Errorf("Expired = %v", exp)
+	}
+	if unused := p.Unused(); len(unused) != 1 || unused[0].ID != "eips" {
+		t.Errorf("Unused = %v", unused)
+	}
+	if p.Hits("dr") != 1 || p.Hits("old") != 0 {
+		t.Errorf("hits dr=%d old=%d", p.Hits("dr"), p.Hits("old"))
+	}
+}
+
+func TestPolicy_GraphIntegration(t *testing.T) {
+	p := mustParse(t, "rules:\n  - {id: vols, owner: o, reason: r, expires: 2026-12-31, match: {type: [AWS::EC2::Volume]}}\n")
+
+	g := graph.NewGraph()
+	g.AddNode("vol-1", "AWS::EC2::Volume", nil)
+	g.AddNode("vol-2", "AWS::EC2::Volume", map[string]interface{}{"Tags": map[string]string{"cloudslash:ignore": "justified:legal"}})
+	g.AddNode("snap-1", "AWS::EC2::Snapshot", map[string]interface{}{"Tags": map[string]string{"cloudslash:ignore": "true"}})
+	g.AddNode("snap-2", "AWS::EC2::Snapshot", nil)
+	f := graph.Finding{Heuristic: "Test", Category: graph.CategoryWaste, Confidence: 1, RiskScore: 50, MonthlySavings: 5}
+	for _, id := range []string{"vol-1", "vol-2", "snap-1", "snap-2"} {
+		g.AddFinding(id, f)
+	}
+
+	// Before the policy applies only the tags suppress.
+	if nodeByID(g, "snap-1").IsWaste || !nodeByID(g, "vol-2").Justified {
+		t.Fatalf("tag grammar not honored without a policy")
+	}
+
+	g.Suppressor = p
+	if removed := g.ApplySuppressor(); removed != 2 {
+		t.Errorf("expected both volume findings removed, got %d", removed)
+	}
+	if nodeByID(g, "vol-1").IsWaste || nodeByID(g, "vol-2").IsWaste || !nodeByID(g, "snap-2").IsWaste {
+		t.Errorf("unexpected waste flags after ApplySuppressor")
+	}
+
+	// Nodes the policy does not match still fall back to the tag.
+	if g.AddFinding("snap-1", f) {
+		t.Errorf("cloudslash:ignore=true should still suppress when no rule matches")
+	}
+}
+
+// nodeByID returns the node with id, or nil when the graph has none.
+func nodeByID(g *graph.Graph, id string) *graph.Node {
+	node, _ := g.Node(id)
+	return node
+}
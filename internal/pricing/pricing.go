@@ -90,7 +90,7 @@ func (c *Client) fetchEBSPrice(ctx context.Context, region, volumeType string) (
 		volTypeVal = "General Purpose"
 	case "gp3":
 		volTypeVal = "General Purpose SSD (gp3)"
-	case "io1":
+	case "io1", "io2":
 		volTypeVal = "Provisioned IOPS SSD"
 	case "st1":
 		volTypeVal = "Throughput Optimized HDD"
@@ -128,6 +128,143 @@ func (c *Client) fetchEBSPrice(ctx context.Context, region, volumeType string) (
 	return parsePriceFromJSON(out.PriceList[0])
 }
 
+// GetGP3PerformancePrice returns the monthly gp3 price of one provisioned IOPS
+// and one MiB/s of throughput above the free 3,000 IOPS and 125 MiB/s.
+func (c *Client) GetGP3PerformancePrice(ctx context.Context, region string) (perIOPS, perMiBps float64, err error) {
+	iopsKey := fmt.Sprintf("gp3-iops-%s", region)
+	throughputKey := fmt.Sprintf("gp3-throughput-%s", region)
+
+	c.mu.RLock()
+	perIOPS, okIOPS := c.cache[iopsKey]
+	perMiBps, okThroughput := c.cache[throughputKey]
+	c.mu.RUnlock()
+	if okIOPS && okThroughput {
+		return perIOPS, perMiBps, nil
+	}
+
+	tCtx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+
+	perIOPS, err = c.fetchGP3Price(tCtx, region, "System Operation")
+	if err == nil {
+		perMiBps, err = c.fetchGP3Price(tCtx, region, "Provisioned Throughput")
+	}
+	if err != nil {
+		// Fallback: the standard US-East prices
+		return 0.005, 0.04, nil
+	}
+	c.mu.Lock()
+	c.cache[iopsKey] = perIOPS
+	c.cache[throughputKey] = perMiBps
+	c.mu.Unlock()
+	return perIOPS, perMiBps, nil
+}
+
+func (c *Client) fetchGP3Price(ctx context.Context, region, productFamily string) (float64, error) {
+	filters := []types.Filter{
+		{
+			Type:  types.FilterTypeTermMatch,
+			Field: aws.String("regionCode"),
+			Value: aws.String(region),
+		},
+		{
+			Type:  types.FilterTypeTermMatch,
+			Field: aws.String("productFamily"),
+			Value: aws.String(productFamily),
+		},
+		{
+			Type:  types.FilterTypeTermMatch,
+			Field: aws.String("volumeApiName"),
+			Value: aws.String("gp3"),
+		},
+	}
+
+	input := &pricing.GetProductsInput{
+		ServiceCode: aws.String("AmazonEC2"),
+		Filters:     filters,
+		MaxResults:  aws.Int32(1),
+	}
+
+	out, err := c.svc.GetProducts(ctx, input)
+	if err != nil {
+		return 0, err
+	}
+
+	if len(out.PriceList) == 0 {
+		return 0, fmt.Errorf("no pricing found for gp3 %s in %s", productFamily, region)
+	}
+	return parsePriceFromJSON(out.PriceList[0])
+}
+
+// GetProvisionedIOPSPrice returns the monthly price of one provisioned IOPS on
+// an io1 or io2 volume. io2 is tiered; this is the first (highest) tier.
+func (c *Client) GetProvisionedIOPSPrice(ctx context.Context, region, volumeType string) (float64, error) {
+	cacheKey := fmt.Sprintf("piops-%s-%s", region, volumeType)
+
+	c.mu.RLock()
+	perIOPS, ok := c.cache[cacheKey]
+	c.mu.RUnlock()
+	if ok {
+		return perIOPS, nil
+	}
+
+	tCtx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+
+	perIOPS, err := c.fetchProvisionedIOPSPrice(tCtx, region, volumeType)
+	if err != nil {
+		// Fallback: the standard US-East price
+		return 0.065, nil
+	}
+	c.mu.Lock()
+	c.cache[cacheKey] = perIOPS
+	c.mu.Unlock()
+	return perIOPS, nil
+}
+
+func (c *Client) fetchProvisionedIOPSPrice(ctx context.Context, region, volumeType string) (float64, error) {
+	filters := []types.Filter{
+		{
+			Type:  types.FilterTypeTermMatch,
+			Field: aws.String("regionCode"),
+			Value: aws.String(region),
+		},
+		{
+			Type:  types.FilterTypeTermMatch,
+			Field: aws.String("productFamily"),
+			Value: aws.String("System Operation"),
+		},
+		{
+			Type:  types.FilterTypeTermMatch,
+			Field: aws.String("volumeApiName"),
+			Value: aws.String(volumeType),
+		},
+	}
+
+	input := &pricing.GetProductsInput{
+		ServiceCode: aws.String("AmazonEC2"),
+		Filters:     filters,
+		MaxResults:  aws.Int32(10),
+	}
+
+	out, err := c.svc.GetProducts(ctx, input)
+	if err != nil {
+		return 0, err
+	}
+
+	// Each io2 tier is its own product; the first tier costs the most.
+	highest := 0.0
+	for _, item := range out.PriceList {
+		if price, err := parsePriceFromJSON(item); err == nil {
+			highest = max(highest, price)
+		}
+	}
+	if highest == 0 {
+		return 0, fmt.Errorf("no pricing found for %s IOPS in %s", volumeType, region)
+	}
+	return highest, nil
+}
+
 // GetEC2InstancePrice returns the monthly cost for a given instance type.
 func (c *Client) GetEC2InstancePrice(ctx context.Context, region, instanceType string) (float64, error) {
 	cacheKey := fmt.Sprintf("ec2-%s-%s", region, instanceType)
@@ -208,6 +345,197 @@ func (c *Client) fetchEC2Price(ctx context.Context, region, instanceType string)
 	return parsePriceFromJSON(out.PriceList[0])
 }
 
+// rdsEngines maps RDS engine identifiers to Pricing API databaseEngine values.
+var rdsEngines = map[string]string{
+	"mysql":             "MySQL",
+	"postgres":          "PostgreSQL",
+	"mariadb":           "MariaDB",
+	"aurora-mysql":      "Aurora MySQL",
+	"aurora-postgresql": "Aurora PostgreSQL",
+}
+
+// GetRDSInstancePrice returns the monthly on-demand cost of an RDS instance
+// class for an open-source engine, e.g. ("db.m5.large", "postgres", false).
+func (c *Client) GetRDSInstancePrice(ctx context.Context, region, instanceClass, engine string, multiAZ bool) (float64, error) {
+	cacheKey := fmt.Sprintf("rds-%s-%s-%s-%t", region, instanceClass, engine, multiAZ)
+
+	c.mu.RLock()
+	pricePerHour, ok := c.cache[cacheKey]
+	c.mu.RUnlock()
+
+	if !ok {
+		tCtx, cancel := context.WithTimeout(ctx, 2*time.Second)
+		defer cancel()
+
+		var err error
+		pricePerHour, err = c.fetchRDSPrice(tCtx, region, instanceClass, engine, multiAZ)
+		if err != nil {
+			return 0, err
+		}
+		c.mu.Lock()
+		c.cache[cacheKey] = pricePerHour
+		c.mu.Unlock()
+	}
+
+	return pricePerHour * 730, nil
+}
+
+func (c *Client) fetchRDSPrice(ctx context.Context, region, instanceClass, engine string, multiAZ bool) (float64, error) {
+	dbEngine, ok := rdsEngines[engine]
+	if !ok {
+		return 0, fmt.Errorf("unsupported rds engine %q", engine)
+	}
+	deployment := "Single-AZ"
+	if multiAZ {
+		deployment = "Multi-AZ"
+	}
+
+	filters := []types.Filter{
+		{
+			Type:  types.FilterTypeTermMatch,
+			Field: aws.String("productFamily"),
+			Value: aws.String("Database Instance"),
+		},
+		{
+			Type:  types.FilterTypeTermMatch,
+			Field: aws.String("regionCode"),
+			Value: aws.String(region),
+		},
+		{
+			Type:  types.FilterTypeTermMatch,
+			Field: aws.String("instanceType"),
+			Value: aws.String(instanceClass),
+		},
+		{
+			Type:  types.FilterTypeTermMatch,
+			Field: aws.String("databaseEngine"),
+			Value: aws.String(dbEngine),
+		},
+		{
+			Type:  types.FilterTypeTermMatch,
+			Field: aws.String("deploymentOption"),
+			Value: aws.String(deployment),
+		},
+	}
+
+	input := &pricing.GetProductsInput{
+		ServiceCode: aws.String("AmazonRDS"),
+		Filters:     filters,
+		MaxResults:  aws.Int32(1),
+	}
+
+	out, err := c.svc.GetProducts(ctx, input)
+	if err != nil {
+		return 0, err
+	}
+
+	if len(out.PriceList) == 0 {
+		return 0, fmt.Errorf("no pricing found for %s %s %s", region, instanceClass, engine)
+	}
+
+	return parsePriceFromJSON(out.PriceList[0])
+}
+
+// rdsStorageTypes maps RDS storage types to Pricing API volumeType values.
+var rdsStorageTypes = map[string]string{
+	"gp2":      "General Purpose",
+	"gp3":      "General Purpose-GP3",
+	"io1":      "Provisioned IOPS",
+	"io2":      "Provisioned IOPS-IO2",
+	"standard": "Magnetic",
+}
+
+// GetRDSStoragePrice returns the monthly price of one GB of RDS storage and,
+// for io1 and io2, of one provisioned IOPS.
+func (c *Client) GetRDSStoragePrice(ctx context.Context, region, storageType string, multiAZ bool) (perGB, perIOPS float64, err error) {
+	gbKey := fmt.Sprintf("rds-storage-%s-%s-%t", region, storageType, multiAZ)
+	iopsKey := fmt.Sprintf("rds-iops-%s-%s-%t", region, storageType, multiAZ)
+
+	c.mu.RLock()
+	perGB, okGB := c.cache[gbKey]
+	perIOPS, okIOPS := c.cache[iopsKey]
+	c.mu.RUnlock()
+	if okGB && okIOPS {
+		return perGB, perIOPS, nil
+	}
+
+	volumeType, ok := rdsStorageTypes[storageType]
+	if !ok {
+		return 0, 0, fmt.Errorf("unsupported rds storage type %q", storageType)
+	}
+	deployment := "Single-AZ"
+	if multiAZ {
+		deployment = "Multi-AZ"
+	}
+
+	tCtx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+
+	perGB, err = c.fetchRDSStoragePrice(tCtx, region, "Database Storage", volumeType, deployment)
+	if err == nil && (storageType == "io1" || storageType == "io2") {
+		perIOPS, err = c.fetchRDSStoragePrice(tCtx, region, "Provisioned IOPS", "", deployment)
+	}
+	if err != nil {
+		// Fallback: the standard US-East prices, doubled for a standby
+		perGB, perIOPS = 0.115, 0
+		if storageType == "io1" || storageType == "io2" {
+			perGB, perIOPS = 0.125, 0.10
+		}
+		if multiAZ {
+			perGB, perIOPS = perGB*2, perIOPS*2
+		}
+		return perGB, perIOPS, nil
+	}
+	c.mu.Lock()
+	c.cache[gbKey] = perGB
+	c.cache[iopsKey] = perIOPS
+	c.mu.Unlock()
+	return perGB, perIOPS, nil
+}
+
+func (c *Client) fetchRDSStoragePrice(ctx context.Context, region, productFamily, volumeType, deployment string) (float64, error) {
+	filters := []types.Filter{
+		{
+			Type:  types.FilterTypeTermMatch,
+			Field: aws.String("productFamily"),
+			Value: aws.String(productFamily),
+		},
+		{
+			Type:  types.FilterTypeTermMatch,
+			Field: aws.String("regionCode"),
+			Value: aws.String(region),
+		},
+		{
+			Type:  types.FilterTypeTermMatch,
+			Field: aws.String("deploymentOption"),
+			Value: aws.String(deployment),
+		},
+	}
+	if volumeType != "" {
+		filters = append(filters, types.Filter{
+			Type:  types.FilterTypeTermMatch,
+			Field: aws.String("volumeType"),
+			Value: aws.String(volumeType),
+		})
+	}
+
+	input := &pricing.GetProductsInput{
+		ServiceCode: aws.String("AmazonRDS"),
+		Filters:     filters,
+		MaxResults:  aws.Int32(1),
+	}
+
+	out, err := c.svc.GetProducts(ctx, input)
+	if err != nil {
+		return 0, err
+	}
+
+	if len(out.PriceList) == 0 {
+		return 0, fmt.Errorf("no pricing found for rds %s %s in %s", productFamily, volumeType, region)
+	}
+	return parsePriceFromJSON(out.PriceList[0])
+}
+
 // GetNATGatewayPrice returns the monthly cost for a NAT Gateway.
 // UsageType: "NatGateway-Hours"
 func (c *Client) GetNATGatewayPrice(ctx context.Context, region string) (float64, error) {
@@ -275,6 +603,68 @@ func (c *Client) fetchNATPrice(ctx context.Context, region string) (float64, err
 }
 
 
+// GetVPCEndpointPrice returns the monthly cost of an interface VPC endpoint in
+// one Availability Zone, before data processing charges.
+// UsageType: "VpcEndpoint-Hours"
+func (c *Client) GetVPCEndpointPrice(ctx context.Context, region string) (float64, error) {
+	cacheKey := fmt.Sprintf("vpce-%s", region)
+
+	c.mu.RLock()
+	pricePerHour, ok := c.cache[cacheKey]
+	c.mu.RUnlock()
+
+	if !ok {
+		tCtx, cancel := context.WithTimeout(ctx, 2*time.Second)
+		defer cancel()
+
+		var err error
+		pricePerHour, err = c.fetchVPCEndpointPrice(tCtx, region)
+		if err != nil {
+			// Fallback: the standard US-East price ($0.01/hr per AZ)
+			return 0.01 * 730, nil
+		}
+		c.mu.Lock()
+		c.cache[cacheKey] = pricePerHour
+		c.mu.Unlock()
+	}
+
+	return pricePerHour * 730, nil
+}
+
+func (c *Client) fetchVPCEndpointPrice(ctx context.Context, region string) (float64, error) {
+	filters := []types.Filter{
+		{
+			Type:  types.FilterTypeTermMatch,
+			Field: aws.String("regionCode"),
+			Value: aws.String(region),
+		},
+		{
+			Type:  types.FilterTypeTermMatch,
+			Field: aws.String("productFamily"),
+			Value: aws.String("VpcEndpoint"),
+		},
+	}
+
+	input := &pricing.GetProductsInput{
+		ServiceCode: aws.String("AmazonVPC"),
+		Filters:     filters,
+		MaxResults:  aws.Int32(20),
+	}
+
+	out, err := c.svc.GetProducts(ctx, input)
+	if err != nil {
+		return 0, err
+	}
+
+	// The family also holds per-GB processing prices; take the hourly one.
+	for _, item := range out.PriceList {
+		if price, err := parsePriceFromJSONUnit(item, "Hrs"); err == nil {
+			return price, nil
+		}
+	}
+	return 0, fmt.Errorf("no pricing found for VPC endpoints in %s", region)
+}
+
 // GetEIPPrice returns the monthly cost for an unassociated Elastic IP.
 // Pricing: $0.005/hr for unattached/remapped.
 func (c *Client) GetEIPPrice(ctx context.Context, region string) (float64, error) {
@@ -313,3 +703,30 @@ func parsePriceFromJSON(jsonStr string) (float64, error) {
 	}
 	return 0, fmt.Errorf("price not found in JSON")
 }
+
+// parsePriceFromJSONUnit is parsePriceFromJSON for the price dimension billed in unit.
+func parsePriceFromJSONUnit(jsonStr, unit string) (float64, error) {
+	var p struct {
+		Terms map[string]map[string]struct {
+			PriceDimensions map[string]struct {
+				Unit         string            `json:"unit"`
+				PricePerUnit map[string]string `json:"pricePerUnit"`
+			} `json:"priceDimensions"`
+		} `json:"terms"`
+	}
+	if err := json.Unmarshal([]byte(jsonStr), &p); err != nil {
+		return 0, err
+	}
+
+	for _, term := range p.Terms["OnDemand"] {
+		for _, dim := range term.PriceDimensions {
+			if dim.Unit != unit {
+				continue
+			}
+			if val, err := strconv.ParseFloat(dim.PricePerUnit["USD"], 64); err == nil {
+				return val, nil
+			}
+		}
+	}
+	return 0, fmt.Errorf("price per %s not found in JSON", unit)
+}
@@ -0,0 +1,430 @@
+package query
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/DrSkyle/cloudslash/internal/graph"
+)
+
+type expr interface {
+	eval(v *graph.View, n *graph.Node) bool
+}
+
+type andExpr struct{ left, right expr }
+type orExpr struct{ left, right expr }
+type notExpr struct{ inner expr }
+
+func (e andExpr) eval(v *graph.View, n *graph.Node) bool {
+	return e.left.eval(v, n) && e.right.eval(v, n)
+}
+
+func (e orExpr) eval(v *graph.View, n *graph.Node) bool {
+	return e.left.eval(v, n) || e.right.eval(v, n)
+}
+
+func (e notExpr) eval(v *graph.View, n *graph.Node) bool {
+	return !e.inner.eval(v, n)
+}
+
+// field names a value on a node.
+type field struct {
+	name string // Canonical built-in name, "props" or "tags"
+	key  string // Property or tag key
+}
+
+func parseField(s string) (field, error) {
+	lower := strings.ToLower(s)
+	switch lower {
+	case "id", "type", "cost", "savings", "risk", "waste", "justified", "justification", "source", "age":
+		return field{name: lower}, nil
+	case "riskscore":
+		return field{name: "risk"}, nil
+	case "iswaste":
+		return field{name: "waste"}, nil
+	}
+	for _, prefix := range []string{"props.", "tags."} {
+		if strings.HasPrefix(lower, prefix) && len(s) > len(prefix) {
+			return field{name: strings.TrimSuffix(prefix, "."), key: s[len(prefix):]}, nil
+		}
+	}
+	return field{}, fmt.Errorf("unknown field %q (use id, type, cost, savings, risk, waste, justified, justification, source, age, props.<Name> or tags.<Key>)", s)
+}
+
+func (f field) get(n *graph.Node) (interface{}, bool) {
+	switch f.name {
+	case "id":
+		return n.ID, true
+	case "type":
+		return n.Type, true
+	case "cost":
+		return n.Cost, true
+	case "savings":
+		return n.Savings, true
+	case "risk":
+		return n.RiskScore, true
+	case "waste":
+		return n.IsWaste, true
+	case "justified":
+		return n.Justified, true
+	case "justification":
+		return n.Justification, n.Justification != ""
+	case "source":
+		return n.SourceLocation, n.SourceLocation != ""
+	case "age":
+		created, ok := n.CreatedAt()
+		if !ok {
+			return nil, false
+		}
+		return time.Since(created), true
+	case "props":
+		return lookup(n.Properties, f.key)
+	case "tags":
+		switch tags := n.Properties["Tags"].(type) {
+		case map[string]string:
+			return lookup(tags, f.key)
+		case map[string]interface{}:
+			return lookup(tags, f.key)
+		}
+	}
+	return nil, false
+}
+
+// lookup finds key exactly, then case-insensitively, dereferencing pointers.
+func lookup[V any](m map[string]V, key string) (interface{}, bool) {
+	v, ok := m[key]
+	if !ok {
+		for k, candidate := range m {
+			if strings.EqualFold(k, key) {
+				v, ok = candidate, true
+				break
+			}
+		}
+	}
+	if !ok {
+		return nil, false
+	}
+	return deref(v)
+}
+
+func deref(v interface{}) (interface{}, bool) {
+	if v == nil {
+		return nil, false
+	}
+	rv := reflect.ValueOf(v)
+	if rv.Kind() == reflect.Ptr {
+		if rv.IsNil() {
+			return nil, false
+		}
+		return rv.Elem().Interface(), true
+	}
+	return v, true
+}
+
+// value is a literal from the query, pre-parsed into every type it could be.
+type value struct {
+	raw    string
+	num    float64
+	isNum  bool
+	b      bool
+	isBool bool
+	t      time.Time
+	isTime bool
+	d      time.Duration
+	isDur  bool
+}
+
+func newValue(raw string) value {
+	v := value{raw: raw}
+	if f, err := strconv.ParseFloat(raw, 64); err == nil {
+		v.num, v.isNum = f, true
+	}
+	if b, err := strconv.ParseBool(raw); err == nil && !v.isNum {
+		v.b, v.isBool = b, true
+	}
+	for _, layout := range []string{time.RFC3339, "2006-01-02"} {
+		if t, err := time.Parse(layout, raw); err == nil {
+			v.t, v.isTime = t, true
+			break
+		}
+	}
+	v.d, v.isDur = parseDuration(raw)
+	return v
+}
+
+// parseDuration accepts Go durations plus whole days, e.g. "36h" or "14d".
+func parseDuration(s string) (time.Duration, bool) {
+	if days, ok := strings.CutSuffix(s, "d"); ok {
+		n, err := strconv.Atoi(days)
+		if err != nil {
+			return 0, false
+		}
+		return time.Duration(n) * 24 * time.Hour, true
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil || !strings.ContainsFunc(s, unicode.IsLetter) {
+		return 0, false
+	}
+	return d, true
+}
+
+type compareExpr struct {
+	field field
+	op    string
+	value value
+	re    *regexp.Regexp // For ~, !~ and wildcard equality
+}
+
+func newCompare(f field, op string, v value) (expr, error) {
+	c := compareExpr{field: f, op: op, value: v}
+	switch op {
+	case "~", "!~":
+		re, err := regexp.Compile(v.raw)
+		if err != nil {
+			return nil, fmt.Errorf("invalid regular expression %q: %v", v.raw, err)
+		}
+		c.re = re
+	case "=", "!=":
+		c.re = globRegexp(v.raw)
+	}
+	return c, nil
+}
+
+func (c compareExpr) eval(v *graph.View, n *graph.Node) bool {
+	got, ok := c.field.get(n)
+	negated := c.op == "!=" || c.op == "!~"
+	if !ok {
+		// A missing value is never equal to anything.
+		return negated
+	}
+
+	switch c.op {
+	case "=", "!=":
+		return equal(got, c.value, c.re) != negated
+	case "~", "!~":
+		return c.re.MatchString(toString(got)) != negated
+	}
+
+	cmp, ok := order(got, c.value)
+	if !ok {
+		return false
+	}
+	switch c.op {
+	case "<":
+		return cmp < 0
+	case "<=":
+		return cmp <= 0
+	case ">":
+		return cmp > 0
+	case ">=":
+		return cmp >= 0
+	}
+	return false
+}
+
+type inExpr struct {
+	field  field
+	values []value
+	globs  []*regexp.Regexp
+}
+
+func newIn(f field, values []value) inExpr {
+	e := inExpr{field: f, values: values}
+	for _, v := range values {
+		e.globs = append(e.globs, globRegexp(v.raw))
+	}
+	return e
+}
+
+func (e inExpr) eval(v *graph.View, n *graph.Node) bool {
+	got, ok := e.field.get(n)
+	if !ok {
+		return false
+	}
+	for i, v := range e.values {
+		if equal(got, v, e.globs[i]) {
+			return true
+		}
+	}
+	return false
+}
+
+type hasExpr struct{ field field }
+
+func (e hasExpr) eval(v *graph.View, n *graph.Node) bool {
+	_, ok := e.field.get(n)
+	return ok
+}
+
+type truthyExpr struct{ field field }
+
+func (e truthyExpr) eval(v *graph.View, n *graph.Node) bool {
+	got, ok := e.field.get(n)
+	if !ok {
+		return false
+	}
+	switch v := got.(type) {
+	case bool:
+		return v
+	case string:
+		return v != ""
+	case time.Time:
+		return !v.IsZero()
+	case time.Duration:
+		return v != 0
+	}
+	if f, ok := toFloat(got); ok {
+		return f != 0
+	}
+	rv := reflect.ValueOf(got)
+	switch rv.Kind() {
+	case reflect.Map, reflect.Slice, reflect.Array:
+		return rv.Len() > 0
+	}
+	return true
+}
+
+type traverseExpr struct {
+	outgoing bool
+	edgeType graph.EdgeType // Empty matches any type
+	inner    expr
+}
+
+func (e traverseExpr) eval(v *graph.View, n *graph.Node) bool {
+	edges := v.ReverseEdges(n.ID)
+	if e.outgoing {
+		edges = v.Edges(n.ID)
+	}
+	for _, edge := range edges {
+		if e.edgeType != "" && edge.Type != e.edgeType {
+			continue
+		}
+		if neighbor, ok := v.Node(edge.TargetID); ok && e.inner.eval(v, neighbor) {
+			return true
+		}
+	}
+	return false
+}
+
+func equal(got interface{}, v value, glob *regexp.Regexp) bool {
+	if f, ok := toFloat(got); ok && v.isNum {
+		return f == v.num
+	}
+	if b, ok := got.(bool); ok {
+		return v.isBool && b == v.b
+	}
+	if t, ok := got.(time.Time); ok && v.isTime {
+		return t.Equal(v.t)
+	}
+	if d, ok := got.(time.Duration); ok && v.isDur {
+		return d == v.d
+	}
+	s := toString(got)
+	if glob != nil {
+		return glob.MatchString(s)
+	}
+	return s == v.raw
+}
+
+// order compares got with v as numbers, times, durations or strings, whichever fits.
+func order(got interface{}, v value) (int, bool) {
+	if d, ok := got.(time.Duration); ok {
+		if !v.isDur {
+			return 0, false
+		}
+		return compareFloat(float64(d), float64(v.d)), true
+	}
+	if f, ok := toFloat(got); ok {
+		if !v.isNum {
+			return 0, false
+		}
+		return compareFloat(f, v.num), true
+	}
+	if t, ok := got.(time.Time); ok {
+		if !v.isTime {
+			return 0, false
+		}
+		return t.Compare(v.t), true
+	}
+	if s, ok := got.(string); ok {
+		if f, err := strconv.ParseFloat(s, 64); err == nil && v.isNum {
+			return compareFloat(f, v.num), true
+		}
+		return strings.Compare(s, v.raw), true
+	}
+	return 0, false
+}
+
+func compareFloat(a, b float64) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+func toFloat(v interface{}) (float64, bool) {
+	switch n := v.(type) {
+	case int:
+		return float64(n), true
+	case int8:
+		return float64(n), true
+	case int16:
+		return float64(n), true
+	case int32:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	case uint:
+		return float64(n), true
+	case uint8:
+		return float64(n), true
+	case uint16:
+		return float64(n), true
+	case uint32:
+		return float64(n), true
+	case uint64:
+		return float64(n), true
+	case float32:
+		return float64(n), true
+	case float64:
+		return n, true
+	}
+	return 0, false
+}
+
+func toString(v interface{}) string {
+	if s, ok := v.(string); ok {
+		return s
+	}
+	return fmt.Sprint(v)
+}
+
+// globRegexp turns a value with * or ? wildcards into an anchored regexp.
+// Returns nil for plain values.
+func globRegexp(pattern string) *regexp.Regexp {
+	if !strings.ContainsAny(pattern, "*?") {
+		return nil
+	}
+	var sb strings.Builder
+	sb.WriteString("^")
+	for _, r := range pattern {
+		switch r {
+		case '*':
+			sb.WriteString(".*")
+		case '?':
+			sb.WriteString(".")
+		default:
+			sb.WriteString(regexp.QuoteMeta(string(r)))
+		}
+	}
+	sb.WriteString("$")
+	return regexp.MustCompile(sb.String())
+}
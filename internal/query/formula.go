@@ -0,0 +1,242 @@
+package query
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/DrSkyle/cloudslash/internal/graph"
+)
+
+// Formula is a compiled arithmetic expression over node fields, e.g.
+//
+//	props.Size * 0.08
+//	(cost - 15) / 2
+//
+// It supports numbers (optionally $-prefixed), the query fields, + - * /
+// and parentheses. Durations such as age count as days.
+type Formula struct {
+	src  string
+	root term
+}
+
+// ParseFormula compiles an arithmetic expression.
+func ParseFormula(src string) (*Formula, error) {
+	toks, err := lexFormula(src)
+	if err != nil {
+		return nil, err
+	}
+	p := &formulaParser{toks: toks}
+	root, err := p.parseSum()
+	if err != nil {
+		return nil, err
+	}
+	if t := p.peek(); t.kind != tokEOF {
+		return nil, fmt.Errorf("unexpected %s at position %d", t, t.pos)
+	}
+	return &Formula{src: src, root: root}, nil
+}
+
+// String returns the source of the formula.
+func (f *Formula) String() string { return f.src }
+
+// Eval computes the formula for n. It is false if a field is missing or not
+// numeric, or on division by zero.
+func (f *Formula) Eval(n *graph.Node) (float64, bool) {
+	return f.root.eval(n)
+}
+
+type term interface {
+	eval(n *graph.Node) (float64, bool)
+}
+
+type constTerm float64
+
+func (c constTerm) eval(*graph.Node) (float64, bool) { return float64(c), true }
+
+type fieldTerm struct{ field field }
+
+func (t fieldTerm) eval(n *graph.Node) (float64, bool) {
+	got, ok := t.field.get(n)
+	if !ok {
+		return 0, false
+	}
+	switch v := got.(type) {
+	case time.Duration:
+		return v.Hours() / 24, true
+	case string:
+		f, err := strconv.ParseFloat(v, 64)
+		return f, err == nil
+	}
+	return toFloat(got)
+}
+
+type negTerm struct{ inner term }
+
+func (t negTerm) eval(n *graph.Node) (float64, bool) {
+	v, ok := t.inner.eval(n)
+	return -v, ok
+}
+
+type binaryTerm struct {
+	op          rune
+	left, right term
+}
+
+func (t binaryTerm) eval(n *graph.Node) (float64, bool) {
+	a, ok := t.left.eval(n)
+	if !ok {
+		return 0, false
+	}
+	b, ok := t.right.eval(n)
+	if !ok {
+		return 0, false
+	}
+	switch t.op {
+	case '+':
+		return a + b, true
+	case '-':
+		return a - b, true
+	case '*':
+		return a * b, true
+	case '/':
+		if b == 0 {
+			return 0, false
+		}
+		return a / b, true
+	}
+	return 0, false
+}
+
+type formulaParser struct {
+	toks []token
+	pos  int
+}
+
+func (p *formulaParser) peek() token { return p.toks[p.pos] }
+
+func (p *formulaParser) next() token {
+	t := p.toks[p.pos]
+	if t.kind != tokEOF {
+		p.pos++
+	}
+	return t
+}
+
+func (p *formulaParser) parseSum() (term, error) {
+	left, err := p.parseProduct()
+	if err != nil {
+		return nil, err
+	}
+	for t := p.peek(); t.kind == tokOp && (t.text == "+" || t.text == "-"); t = p.peek() {
+		p.next()
+		right, err := p.parseProduct()
+		if err != nil {
+			return nil, err
+		}
+		left = binaryTerm{rune(t.text[0]), left, right}
+	}
+	return left, nil
+}
+
+func (p *formulaParser) parseProduct() (term, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for t := p.peek(); t.kind == tokOp && (t.text == "*" || t.text == "/"); t = p.peek() {
+		p.next()
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = binaryTerm{rune(t.text[0]), left, right}
+	}
+	return left, nil
+}
+
+func (p *formulaParser) parseUnary() (term, error) {
+	if t := p.peek(); t.kind == tokOp && t.text == "-" {
+		p.next()
+		inner, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return negTerm{inner}, nil
+	}
+	return p.parseOperand()
+}
+
+func (p *formulaParser) parseOperand() (term, error) {
+	t := p.next()
+	switch t.kind {
+	case tokNumber:
+		f, _ := strconv.ParseFloat(t.text, 64)
+		return constTerm(f), nil
+	case tokIdent:
+		f, err := parseField(t.text)
+		if err != nil {
+			return nil, fmt.Errorf("%v at position %d", err, t.pos)
+		}
+		return fieldTerm{f}, nil
+	case tokLParen:
+		inner, err := p.parseSum()
+		if err != nil {
+			return nil, err
+		}
+		if t := p.next(); t.kind != tokRParen {
+			return nil, fmt.Errorf("expected ')' at position %d, got %s", t.pos, t)
+		}
+		return inner, nil
+	}
+	return nil, fmt.Errorf("expected number, field or '(' at position %d, got %s", t.pos, t)
+}
+
+// lexFormula splits a formula into numbers, fields, operators and parentheses.
+// Unlike query words, fields here stop at * / + and -.
+func lexFormula(src string) ([]token, error) {
+	var toks []token
+	rs := []rune(src)
+	for i := 0; i < len(rs); {
+		r := rs[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case r == '(':
+			toks = append(toks, token{tokLParen, "(", i})
+			i++
+		case r == ')':
+			toks = append(toks, token{tokRParen, ")", i})
+			i++
+		case strings.ContainsRune("+-*/", r):
+			toks = append(toks, token{tokOp, string(r), i})
+			i++
+		case unicode.IsDigit(r) || r == '.' || r == '$':
+			start := i
+			if r == '$' {
+				i++
+			}
+			j := i
+			for j < len(rs) && (unicode.IsDigit(rs[j]) || rs[j] == '.') {
+				j++
+			}
+			text := string(rs[i:j])
+			if _, err := strconv.ParseFloat(text, 64); err != nil {
+				return nil, fmt.Errorf("invalid number %q at position %d", string(rs[start:j]), start)
+			}
+			toks = append(toks, token{tokNumber, text, start})
+			i = j
+		case unicode.IsLetter(r) || r == '_':
+			start := i
+			for i < len(rs) && (unicode.IsLetter(rs[i]) || unicode.IsDigit(rs[i]) || strings.ContainsRune("_.:", rs[i])) {
+				i++
+			}
+			toks = append(toks, token{tokIdent, string(rs[start:i]), start})
+		default:
+			return nil, fmt.Errorf("unexpected %q at position %d", r, i)
+		}
+	}
+	return append(toks, token{kind: tokEOF, pos: len(rs)}), nil
+}
@@ -0,0 +1,130 @@
+package query
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+type tokenKind int
+
+const (
+	tokEOF tokenKind = iota
+	tokIdent
+	tokString
+	tokNumber
+	tokOp
+	tokLParen
+	tokRParen
+	tokComma
+)
+
+type token struct {
+	kind tokenKind
+	text string
+	pos  int
+}
+
+func (t token) String() string {
+	if t.kind == tokEOF {
+		return "end of query"
+	}
+	return fmt.Sprintf("%q", t.text)
+}
+
+// keyword reports whether t is the (case-insensitive) keyword kw.
+func (t token) keyword(kw string) bool {
+	return t.kind == tokIdent && strings.EqualFold(t.text, kw)
+}
+
+func lex(src string) ([]token, error) {
+	var toks []token
+	rs := []rune(src)
+	for i := 0; i < len(rs); {
+		r := rs[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case r == '(':
+			toks = append(toks, token{tokLParen, "(", i})
+			i++
+		case r == ')':
+			toks = append(toks, token{tokRParen, ")", i})
+			i++
+		case r == ',':
+			toks = append(toks, token{tokComma, ",", i})
+			i++
+		case r == '"' || r == '\'':
+			start := i
+			var sb strings.Builder
+			i++
+			for i < len(rs) && rs[i] != r {
+				if rs[i] == '\\' && i+1 < len(rs) {
+					i++
+				}
+				sb.WriteRune(rs[i])
+				i++
+			}
+			if i >= len(rs) {
+				return nil, fmt.Errorf("unterminated string at position %d", start)
+			}
+			i++
+			toks = append(toks, token{tokString, sb.String(), start})
+		case strings.ContainsRune("=!<>~", r):
+			start := i
+			op := string(r)
+			if i+1 < len(rs) {
+				switch two := string(rs[i : i+2]); two {
+				case "==", "!=", "<=", ">=", "!~":
+					op = two
+				}
+			}
+			if op == "!" {
+				return nil, fmt.Errorf("unexpected '!' at position %d", start)
+			}
+			i += len(op)
+			if op == "==" {
+				op = "="
+			}
+			toks = append(toks, token{tokOp, op, start})
+		case unicode.IsDigit(r) || r == '$' || (r == '-' && i+1 < len(rs) && unicode.IsDigit(rs[i+1])):
+			// Numbers may carry a currency sign ($20). Runs that don't parse as a
+			// number (2024-01-02, 2xlarge) are bare words.
+			start := i
+			if r == '$' {
+				i++
+				if i >= len(rs) {
+					return nil, fmt.Errorf("expected number after '$' at position %d", start)
+				}
+			}
+			j := i + 1
+			for j < len(rs) && isWordRune(rs[j]) {
+				j++
+			}
+			text := string(rs[i:j])
+			kind := tokIdent
+			if _, err := strconv.ParseFloat(text, 64); err == nil {
+				kind = tokNumber
+			} else if r == '$' {
+				return nil, fmt.Errorf("expected number after '$' at position %d", start)
+			}
+			toks = append(toks, token{kind, text, start})
+			i = j
+		case isWordRune(r):
+			start := i
+			for i < len(rs) && isWordRune(rs[i]) {
+				i++
+			}
+			toks = append(toks, token{tokIdent, string(rs[start:i]), start})
+		default:
+			return nil, fmt.Errorf("unexpected %q at position %d", r, i)
+		}
+	}
+	return append(toks, token{kind: tokEOF, pos: len(rs)}), nil
+}
+
+// isWordRune allows bare words like props.VolumeType, AWS::EC2::Volume or gp*.
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || strings.ContainsRune("_.:-*?/", r)
+}
@@ -0,0 +1,293 @@
+// Package query implements a small filter language over graph nodes.
+//
+// A query is a boolean expression evaluated against each node:
+//
+//	type = AWS::EC2::Volume and props.Size > 500 and tags.Team = "data" and cost > $20
+//	type = AWS::EC2::Volume and out AttachedTo (props.State = stopped)
+//	waste and not justified and props.VolumeType in (gp2, io1)
+//
+// Fields are id, type, cost, savings, risk, waste, justified, justification,
+// source, age, props.<Property> and tags.<Key>. Operators are = != < <= > >=
+// (numbers, times, durations, strings), ~ !~ (regular expressions), in (...)
+// and not in (...).
+// age is the time since the resource was created or launched; compare it
+// with durations such as 36h or 14d.
+// String equality accepts * and ? wildcards. A bare field is true when it is
+// set and not false, zero or empty; "has field" is true when it is set at all.
+//
+// "out [EdgeType] (expr)" is true when an outgoing edge leads to a node
+// matching expr; "in [EdgeType] (expr)" does the same for incoming edges.
+package query
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/DrSkyle/cloudslash/internal/graph"
+)
+
+// Query is a compiled filter expression.
+type Query struct {
+	src  string
+	root expr
+}
+
+// Parse compiles a query expression.
+func Parse(src string) (*Query, error) {
+	toks, err := lex(src)
+	if err != nil {
+		return nil, err
+	}
+	p := &parser{toks: toks}
+	root, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if t := p.peek(); t.kind != tokEOF {
+		return nil, fmt.Errorf("unexpected %s at position %d", t, t.pos)
+	}
+	return &Query{src: src, root: root}, nil
+}
+
+// String returns the source of the query.
+func (q *Query) String() string { return q.src }
+
+// Match reports whether n satisfies the query. Edges are followed through v.
+func (q *Query) Match(v *graph.View, n *graph.Node) bool {
+	return q.root.eval(v, n)
+}
+
+// Select returns every matching node, sorted by ID.
+func (q *Query) Select(g *graph.Graph) []*graph.Node {
+	var out []*graph.Node
+	g.Read(func(v *graph.View) {
+		v.Each(func(n *graph.Node) {
+			if q.Match(v, n) {
+				out = append(out, n)
+			}
+		})
+	})
+	sort.Slice(out, func(i, j int) bool { return out[i].ID < out[j].ID })
+	return out
+}
+
+// Run parses src and selects the matching nodes of g.
+func Run(g *graph.Graph, src string) ([]*graph.Node, error) {
+	q, err := Parse(src)
+	if err != nil {
+		return nil, err
+	}
+	return q.Select(g), nil
+}
+
+type parser struct {
+	toks []token
+	pos  int
+}
+
+func (p *parser) peek() token { return p.toks[p.pos] }
+
+func (p *parser) next() token {
+	t := p.toks[p.pos]
+	if t.kind != tokEOF {
+		p.pos++
+	}
+	return t
+}
+
+func (p *parser) expect(kind tokenKind, what string) error {
+	if t := p.next(); t.kind != kind {
+		return fmt.Errorf("expected %s at position %d, got %s", what, t.pos, t)
+	}
+	return nil
+}
+
+func (p *parser) parseOr() (expr, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.peek().keyword("or") {
+		p.next()
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = orExpr{left, right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseAnd() (expr, error) {
+	left, err := p.parseNot()
+	if err != nil {
+		return nil, err
+	}
+	for p.peek().keyword("and") {
+		p.next()
+		right, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		left = andExpr{left, right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseNot() (expr, error) {
+	if p.peek().keyword("not") {
+		p.next()
+		inner, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		return notExpr{inner}, nil
+	}
+	return p.parsePrimary()
+}
+
+func (p *parser) parsePrimary() (expr, error) {
+	t := p.peek()
+	switch {
+	case t.kind == tokLParen:
+		p.next()
+		inner, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expect(tokRParen, "')'"); err != nil {
+			return nil, err
+		}
+		return inner, nil
+
+	case t.keyword("has"):
+		p.next()
+		f, err := p.parseField()
+		if err != nil {
+			return nil, err
+		}
+		return hasExpr{f}, nil
+
+	case t.keyword("out") || t.keyword("in"):
+		p.next()
+		return p.parseTraversal(t.keyword("out"))
+
+	case t.kind == tokIdent:
+		f, err := p.parseField()
+		if err != nil {
+			return nil, err
+		}
+		return p.parseComparison(f)
+	}
+	return nil, fmt.Errorf("expected field or '(' at position %d, got %s", t.pos, t)
+}
+
+func (p *parser) parseTraversal(outgoing bool) (expr, error) {
+	tr := traverseExpr{outgoing: outgoing}
+	if t := p.peek(); t.kind == tokIdent {
+		p.next()
+		et, err := parseEdgeType(t.text)
+		if err != nil {
+			return nil, fmt.Errorf("%v at position %d", err, t.pos)
+		}
+		tr.edgeType = et
+	}
+	if err := p.expect(tokLParen, "'(' after traversal"); err != nil {
+		return nil, err
+	}
+	inner, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if err := p.expect(tokRParen, "')'"); err != nil {
+		return nil, err
+	}
+	tr.inner = inner
+	return tr, nil
+}
+
+func (p *parser) parseComparison(f field) (expr, error) {
+	t := p.peek()
+	switch {
+	case t.kind == tokOp:
+		p.next()
+		v, err := p.parseValue()
+		if err != nil {
+			return nil, err
+		}
+		return newCompare(f, t.text, v)
+
+	case t.keyword("in"):
+		p.next()
+		return p.parseIn(f, false)
+
+	case t.keyword("not") && p.toks[p.pos+1].keyword("in"):
+		p.next()
+		p.next()
+		return p.parseIn(f, true)
+	}
+	return truthyExpr{f}, nil
+}
+
+func (p *parser) parseIn(f field, negate bool) (expr, error) {
+	if err := p.expect(tokLParen, "'(' after in"); err != nil {
+		return nil, err
+	}
+	var values []value
+	for {
+		v, err := p.parseValue()
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, v)
+		if p.peek().kind != tokComma {
+			break
+		}
+		p.next()
+	}
+	if err := p.expect(tokRParen, "')'"); err != nil {
+		return nil, err
+	}
+	var e expr = newIn(f, values)
+	if negate {
+		e = notExpr{e}
+	}
+	return e, nil
+}
+
+func (p *parser) parseValue() (value, error) {
+	t := p.next()
+	switch t.kind {
+	case tokString, tokIdent, tokNumber:
+		return newValue(t.text), nil
+	}
+	return value{}, fmt.Errorf("expected value at position %d, got %s", t.pos, t)
+}
+
+func (p *parser) parseField() (field, error) {
+	t := p.next()
+	if t.kind != tokIdent {
+		return field{}, fmt.Errorf("expected field at position %d, got %s", t.pos, t)
+	}
+	f, err := parseField(t.text)
+	if err != nil {
+		return field{}, fmt.Errorf("%v at position %d", err, t.pos)
+	}
+	return f, nil
+}
+
+func parseEdgeType(s string) (graph.EdgeType, error) {
+	for _, et := range []graph.EdgeType{
+		graph.EdgeTypeAttachedTo,
+		graph.EdgeTypeSecuredBy,
+		graph.EdgeTypeContains,
+		graph.EdgeTypeFlowsTo,
+		graph.EdgeTypeUnknown,
+	} {
+		if strings.EqualFold(s, string(et)) {
+			return et, nil
+		}
+	}
+	return "", fmt.Errorf("unknown edge type %q", s)
+}
@@ -0,0 +1,140 @@
+package query
+
+import (
+	"testing"
+	"time"
+
+	"github.com/DrSkyle/cloudslash/internal/graph"
+)
+
+func testGraph() *graph.Graph {
+	g := graph.NewGraph()
+	g.AddNode("vol-big", "AWS::EC2::Volume", map[string]interface{}{
+		"Size":       int32(800),
+		"VolumeType": "gp2",
+		"Tags":       map[string]string{"Team": "data"},
+		"CreateTime": time.Date(2023, 5, 1, 0, 0, 0, 0, time.UTC),
+	})
+	g.AddNode("vol-small", "AWS::EC2::Volume", map[string]interface{}{
+		"Size":       int32(100),
+		"VolumeType": "gp3",
+		"Tags":       map[string]string{"Team": "web"},
+	})
+	g.AddNode("i-stopped", "AWS::EC2::Instance", map[string]interface{}{"State": "stopped"})
+	g.AddNode("i-running", "AWS::EC2::Instance", map[string]interface{}{"State": "running"})
+	g.AddTypedEdge("vol-big", "i-stopped", graph.EdgeTypeAttachedTo, 100)
+	g.AddTypedEdge("vol-small", "i-running", graph.EdgeTypeAttachedTo, 100)
+
+	nodeByID(g, "vol-big").IsWaste = true
+	nodeByID(g, "vol-big").Cost = 80
+	nodeByID(g, "vol-big").RiskScore = 70
+	nodeByID(g, "vol-small").Cost = 10
+	return g
+}
+
+func TestRun(t *testing.T) {
+	g := testGraph()
+
+	tests := []struct {
+		query string
+		want  []string
+	}{
+		{`type = AWS::EC2::Volume and props.VolumeType = gp2 and props.Size > 500 and tags.Team = "data" and cost > $20`, []string{"vol-big"}},
+		{`type = AWS::EC2::Volume and out AttachedTo (props.State = stopped)`, []string{"vol-big"}},
+		{`type = "AWS::EC2::Instance" and in (risk >= 70)`, []string{"i-stopped"}},
+		{`waste and not justified`, []string{"vol-big"}},
+		{`props.VolumeType in (gp2, io1) or props.State not in (stopped)`, []string{"i-running", "vol-big", "vol-small"}},
+		{`has props.CreateTime and props.CreateTime < 2024-01-01`, []string{"vol-big"}},
+		{`id ~ "^vol-" and not (cost >= 50)`, []string{"vol-small"}},
+		{`type = AWS::EC2::* and tags.team != data`, []string{"i-running", "i-stopped", "vol-small"}},
+		{`out SecuredBy (type = AWS::EC2::Instance)`, nil},
+		{`age > 30d and age < 876000h`, []string{"vol-big"}},
+		{`type = AWS::EC2::Volume and not (age > 30d)`, []string{"vol-small"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			nodes, err := Run(g, tt.query)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			var got []string
+			for _, n := range nodes {
+				got = append(got, n.ID)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("got %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Fatalf("got %v, want %v", got, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	for _, src := range []string{
+		`cost >`,
+		`colour = red`,
+		`(waste`,
+		`out Reaches (waste)`,
+		`id ~ "["`,
+		`cost > $`,
+		`waste waste`,
+		`name = "unterminated`,
+	} {
+		if _, err := Parse(src); err == nil {
+			t.Errorf("expected error for %q", src)
+		}
+	}
+}
+
+func TestFormula(t *testing.T) {
+	g := testGraph()
+	n := nodeByID(g, "vol-big")
+
+	tests := []struct {
+		src  string
+		want float64
+		ok   bool
+	}{
+		{`props.Size * 0.08`, 64, true},
+		{`(cost - $20) / 2 + -1`, 29, true},
+		{`props.Size*0.1+risk`, 150, true},
+		{`props.Missing * 2`, 0, false},
+		{`cost / (risk - 70)`, 0, false},
+	}
+	for _, tt := range tests {
+		got, ok := mustFormula(t, tt.src).Eval(n)
+		if ok != tt.ok || got != tt.want {
+			t.Errorf("%s = %v, %v; want %v, %v", tt.src, got, ok, tt.want, tt.ok)
+		}
+	}
+
+	if days, ok := mustFormula(t, `age`).Eval(n); !ok || days < 365 {
+		t.Errorf("age = %v, %v; want at least 365 days", days, ok)
+	}
+
+	for _, src := range []string{`props.Size *`, `colour * 2`, `(cost`, `cost % 2`, `1.2.3`} {
+		if _, err := ParseFormula(src); err == nil {
+			t.Errorf("expected error for %q", src)
+		}
+	}
+}
+
+func mustFormula(t *testing.T, src string) *Formula {
+	t.Helper()
+	f, err := ParseFormula(src)
+	if err != nil {
+		t.Fatalf("ParseFormula(%q): %v", src, err)
+	}
+	return f
+}
+
+// nodeByID returns the node with id, or nil when the graph has none.
+func nodeByID(g *graph.Graph, id string) *graph.Node {
+	node, _ := g.Node(id)
+	return node
+}
@@ -2,13 +2,15 @@ package remediation
 
 import (
 	"fmt"
+	"io"
 	"os"
 	"sort"
 	"strings"
 	"time"
 
-	"github.com/aws/aws-sdk-go-v2/aws/arn"
 	"github.com/DrSkyle/cloudslash/internal/graph"
+	"github.com/DrSkyle/cloudslash/internal/heuristics"
+	"github.com/DrSkyle/cloudslash/internal/resource"
 )
 
 // Generator handles creates remediation scripts.
@@ -29,57 +31,49 @@ func (g *Generator) GenerateSafeDeleteScript(path string) error {
 	}
 	defer f.Close()
 
-	g.Graph.Mu.RLock()
-	defer g.Graph.Mu.RUnlock()
+	// Plan before Read; PlanWasteDeletion locks on its own.
+	plan := g.Graph.PlanWasteDeletion()
 
 	fmt.Fprintf(f, "#!/bin/bash\n")
 	fmt.Fprintf(f, "# CloudSlash Safe Remediation Script\n")
-	fmt.Fprintf(f, "# Generated: %s\n\n", time.Now().Format(time.RFC3339))
+	fmt.Fprintf(f, "# Generated: %s\n", time.Now().Format(time.RFC3339))
+	fmt.Fprintf(f, "# Resources are deleted in dependency order (nothing is removed while still in use).\n\n")
 	fmt.Fprintf(f, "set -e\n\n") // Exit on error
 
 	wasteCount := 0
 
-	for _, node := range g.Graph.Nodes {
-		if !node.IsWaste {
-			continue
+	g.Graph.Read(func(v *graph.View) {
+		for _, id := range plan.Order {
+			node, _ := v.Node(id)
+			if writeDeleteCommands(f, node, "") {
+				wasteCount++
+			}
 		}
 
-		// Resource ID extraction using robust ARN parsing
-		resourceID := extractResourceID(node.ID)
-
-		switch node.Type {
-		case "AWS::EC2::Volume":
-			fmt.Fprintf(f, "echo \"Processing Volume: %s\"\n", resourceID)
-			// Safety Snapshot
-			desc := fmt.Sprintf("CloudSlash-Archive-%s", resourceID)
-			fmt.Fprintf(f, "aws ec2 create-snapshot --volume-id %s --description \"%s\" --tag-specifications 'ResourceType=snapshot,Tags=[{Key=CloudSlash,Value=Archive}]'\n", resourceID, desc)
-			// Delete
-			fmt.Fprintf(f, "aws ec2 delete-volume --volume-id %s\n\n", resourceID)
-			wasteCount++
-
-		case "AWS::RDS::DBInstance":
-			fmt.Fprintf(f, "echo \"Processing RDS: %s\"\n", resourceID)
-			// Safety Snapshot
-			snapID := fmt.Sprintf("cloudslash-snap-%s-%d", resourceID, time.Now().Unix())
-			fmt.Fprintf(f, "aws rds create-db-snapshot --db-instance-identifier %s --db-snapshot-identifier %s\n", resourceID, snapID)
-			// Delete (Skip final snapshot since we just took one, or force skip)
-			fmt.Fprintf(f, "aws rds delete-db-instance --db-instance-identifier %s --skip-final-snapshot\n\n", resourceID)
-			wasteCount++
-
-		case "AWS::EC2::NatGateway":
-			fmt.Fprintf(f, "echo \"Processing NAT Gateway: %s\"\n", resourceID)
-			// NAT Gateways don't have snapshots, but maybe log it?
-			// Just delete.
-			fmt.Fprintf(f, "aws ec2 delete-nat-gateway --nat-gateway-id %s\n\n", resourceID)
-			wasteCount++
-
-		case "AWS::EC2::EIP":
-			fmt.Fprintf(f, "echo \"Processing EIP: %s\"\n", resourceID)
-			// Release
-			fmt.Fprintf(f, "aws ec2 release-address --allocation-id %s\n\n", resourceID)
-			wasteCount++
+		// Cycles have no safe order; leave the commands commented out for a human to sequence.
+		if manual := plan.All()[len(plan.Order):]; len(manual) > 0 {
+			fmt.Fprintf(f, "# ⚠️ MANUAL ORDER REQUIRED: %v\n", plan.CycleError())
+			fmt.Fprintf(f, "# The following commands are disabled until the cycle is broken.\n\n")
+			for _, id := range manual {
+				node, _ := v.Node(id)
+				writeDeleteCommands(f, node, "# ")
+			}
 		}
-	}
+
+		// Volumes kept in place but worth modernizing are converted last.
+		var modernize []*graph.Node
+		v.Each(func(node *graph.Node) {
+			if _, ok := node.FindingBy("ModernizationHeuristic"); ok && !node.HasWasteFinding() && !node.Justified {
+				modernize = append(modernize, node)
+			}
+		})
+		sort.Slice(modernize, func(i, j int) bool { return modernize[i].ID < modernize[j].ID })
+		for _, node := range modernize {
+			if writeModernizeCommands(f, node, "") {
+				wasteCount++
+			}
+		}
+	})
 
 	if wasteCount == 0 {
 		fmt.Fprintf(f, "echo \"No waste found to remediate.\"\n")
@@ -98,9 +92,6 @@ func (g *Generator) GenerateIgnoreScript(path string) error {
 	}
 	defer f.Close()
 
-	g.Graph.Mu.RLock()
-	defer g.Graph.Mu.RUnlock()
-
 	fmt.Fprintf(f, "#!/bin/bash\n")
 	fmt.Fprintf(f, "# CloudSlash Ignore Tagging Script\n")
 	fmt.Fprintf(f, "# Generated: %s\n\n", time.Now().Format(time.RFC3339))
@@ -114,11 +105,13 @@ func (g *Generator) GenerateIgnoreScript(path string) error {
 	}
 	var items []wasteItem
 
-	for _, node := range g.Graph.Nodes {
-		if node.IsWaste && !node.Justified {
-			items = append(items, wasteItem{ID: node.ID, Type: node.Type})
-		}
-	}
+	g.Graph.Read(func(v *graph.View) {
+		v.Each(func(node *graph.Node) {
+			if node.HasWasteFinding() && !node.Justified {
+				items = append(items, wasteItem{ID: node.ID, Type: node.Type})
+			}
+		})
+	})
 
 	// Sort by ID
 	sort.Slice(items, func(i, j int) bool {
@@ -136,7 +129,7 @@ func (g *Generator) GenerateIgnoreScript(path string) error {
 		}
 
 		fmt.Fprintf(f, "echo \"Ignoring: %s\"\n", resourceID)
-		fmt.Fprintf(f, "aws resourcegroupstaggingapi tag-resources --resource-arn-list %s --tags cloudslash:ignore=true\n", arg)
+		fmt.Fprintf(f, "aws resourcegroupstaggingapi tag-resources --resource-arn-list %s --tags cloudslash:ignore=true%s\n", arg, regionFlag(item.ID))
 		count++
 	}
 
@@ -149,20 +142,144 @@ func (g *Generator) GenerateIgnoreScript(path string) error {
 	return nil
 }
 
-func extractResourceID(id string) string {
-	// Robust ARN parsing using official library
-	// This helps avoid fragile string splitting errors
-	if parsed, err := arn.Parse(id); err == nil {
-		// Use fields function to split by / or : safely
-		parts := strings.FieldsFunc(parsed.Resource, func(r rune) bool {
-			return r == '/' || r == ':'
-		})
-		if len(parts) > 0 {
-			return parts[len(parts)-1]
+// writeDeleteCommands emits the snapshot-then-delete commands for one node,
+// prefixing every line with prefix. Returns false for unsupported types and
+// for nodes without a waste finding.
+func writeDeleteCommands(w io.Writer, node *graph.Node, prefix string) bool {
+	if node == nil || !node.HasWasteFinding() {
+		return false
+	}
+	// Resource ID extraction using robust ARN parsing
+	resourceID := extractResourceID(node.ID)
+	region := regionFlag(node.ID)
+
+	switch node.Type {
+	case "AWS::EC2::Volume":
+		writeHeader(w, prefix, "Processing Volume", node)
+		// Safety Snapshot
+		desc := fmt.Sprintf("CloudSlash-Archive-%s", resourceID)
+		fmt.Fprintf(w, "%saws ec2 create-snapshot --volume-id %s --description \"%s\" --tag-specifications 'ResourceType=snapshot,Tags=[{Key=CloudSlash,Value=Archive}]'%s\n", prefix, resourceID, desc, region)
+		// Delete
+		fmt.Fprintf(w, "%saws ec2 delete-volume --volume-id %s%s\n\n", prefix, resourceID, region)
+
+	case "AWS::RDS::DBInstance":
+		writeHeader(w, prefix, "Processing RDS", node)
+		// Safety Snapshot
+		snapID := fmt.Sprintf("cloudslash-snap-%s-%d", resourceID, time.Now().Unix())
+		fmt.Fprintf(w, "%saws rds create-db-snapshot --db-instance-identifier %s --db-snapshot-identifier %s%s\n", prefix, resourceID, snapID, region)
+		// Delete (Skip final snapshot since we just took one, or force skip)
+		fmt.Fprintf(w, "%saws rds delete-db-instance --db-instance-identifier %s --skip-final-snapshot%s\n\n", prefix, resourceID, region)
+
+	case "AWS::RDS::DBSnapshot":
+		if _, ok := node.FindingBy("RDSSnapshotHeuristic"); !ok {
+			return false
+		}
+		// The snapshot is the archive; deleting it cannot be undone.
+		writeHeader(w, prefix, "Processing RDS Snapshot", node)
+		fmt.Fprintf(w, "%saws rds delete-db-snapshot --db-snapshot-identifier %s%s\n\n", prefix, resourceID, region)
+
+	case "AWS::RDS::DBClusterSnapshot":
+		if _, ok := node.FindingBy("RDSSnapshotHeuristic"); !ok {
+			return false
 		}
-		return parsed.Resource
+		writeHeader(w, prefix, "Processing RDS Cluster Snapshot", node)
+		fmt.Fprintf(w, "%saws rds delete-db-cluster-snapshot --db-cluster-snapshot-identifier %s%s\n\n", prefix, resourceID, region)
+
+	case "AWS::EC2::NatGateway":
+		writeHeader(w, prefix, "Processing NAT Gateway", node)
+		// NAT Gateways don't have snapshots, just delete.
+		fmt.Fprintf(w, "%saws ec2 delete-nat-gateway --nat-gateway-id %s%s\n\n", prefix, resourceID, region)
+
+	case "AWS::EC2::Instance":
+		// Only long-stopped instances are archived and terminated; other
+		// instance findings (tags, IAM, rightsizing) are not a reason to delete.
+		if _, ok := node.FindingBy("StoppedInstanceHeuristic"); !ok {
+			return false
+		}
+		writeHeader(w, prefix, "Processing Instance", node)
+		// Safety AMI of the instance and all its volumes
+		name := fmt.Sprintf("CloudSlash-Archive-%s-%d", resourceID, time.Now().Unix())
+		fmt.Fprintf(w, "%sami=$(aws ec2 create-image --instance-id %s --name \"%s\" --no-reboot --tag-specifications 'ResourceType=image,Tags=[{Key=CloudSlash,Value=Archive}]' --query ImageId --output text%s)\n", prefix, resourceID, name, region)
+		fmt.Fprintf(w, "%saws ec2 wait image-available --image-ids \"$ami\"%s\n", prefix, region)
+		// Terminate, and wait so attached volumes and EIPs are free for the next steps
+		fmt.Fprintf(w, "%saws ec2 terminate-instances --instance-ids %s%s\n", prefix, resourceID, region)
+		fmt.Fprintf(w, "%saws ec2 wait instance-terminated --instance-ids %s%s\n\n", prefix, resourceID, region)
+
+	case "AWS::EC2::NetworkInterface":
+		// Only detached interfaces can be deleted.
+		if _, ok := node.FindingBy("OrphanedENIHeuristic"); !ok {
+			return false
+		}
+		// AWS refuses to delete interfaces owned by another service.
+		if managed, _ := node.Properties["RequesterManaged"].(bool); managed {
+			fmt.Fprintf(w, "%s# Skipping Network Interface %s: requester-managed, release it through its owning service\n\n", prefix, resourceID)
+			return false
+		}
+		writeHeader(w, prefix, "Processing Network Interface", node)
+		fmt.Fprintf(w, "%saws ec2 delete-network-interface --network-interface-id %s%s\n\n", prefix, resourceID, region)
+
+	case "AWS::EC2::VPCEndpoint":
+		// Only idle endpoints are deleted; a tag finding is not a reason to.
+		if _, ok := node.FindingBy("IdleVPCEndpointHeuristic"); !ok {
+			return false
+		}
+		writeHeader(w, prefix, "Processing VPC Endpoint", node)
+		// Deleting the endpoint also removes its network interfaces.
+		fmt.Fprintf(w, "%saws ec2 delete-vpc-endpoints --vpc-endpoint-ids %s%s\n\n", prefix, resourceID, region)
+
+	case "AWS::EC2::EIP":
+		writeHeader(w, prefix, "Processing EIP", node)
+		// Release
+		fmt.Fprintf(w, "%saws ec2 release-address --allocation-id %s%s\n\n", prefix, resourceID, region)
+
+	default:
+		return false
 	}
+	return true
+}
 
-	// Fallback for non-ARN inputs (e.g. raw IDs)
-	return id
+// writeModernizeCommands emits the in-place gp3 conversion for a gp2 volume
+// that is worth keeping. Returns false for anything else.
+func writeModernizeCommands(w io.Writer, node *graph.Node, prefix string) bool {
+	if node.Type != "AWS::EC2::Volume" {
+		return false
+	}
+	if _, ok := node.FindingBy("ModernizationHeuristic"); !ok {
+		return false
+	}
+	resourceID := extractResourceID(node.ID)
+	size := 0
+	switch s := node.Properties["Size"].(type) {
+	case int32:
+		size = int(s)
+	case int:
+		size = s
+	}
+	iops, throughput := heuristics.GP3Equivalent(size)
+	writeHeader(w, prefix, "Modernizing Volume", node)
+	fmt.Fprintf(w, "%saws ec2 modify-volume --volume-id %s --volume-type gp3 --iops %d --throughput %d%s\n\n", prefix, resourceID, iops, throughput, regionFlag(node.ID))
+	return true
+}
+
+// writeHeader announces the commands for node. A scan can span several
+// accounts, so it also notes the account the commands must run as.
+func writeHeader(w io.Writer, prefix, action string, node *graph.Node) {
+	if a, err := resource.ParseARN(node.ID); err == nil && a.AccountID != "" {
+		fmt.Fprintf(w, "%s# Account %s\n", prefix, a.AccountID)
+	}
+	fmt.Fprintf(w, "%secho \"%s: %s\"\n", prefix, action, extractResourceID(node.ID))
+}
+
+// regionFlag returns the --region option for the commands on the resource
+// id, or "" when the ID names no region and the CLI default applies.
+func regionFlag(id string) string {
+	if region := resource.Region(id, ""); region != "" {
+		return " --region " + region
+	}
+	return ""
+}
+
+func extractResourceID(id string) string {
+	// Non-ARN inputs (e.g. raw IDs) are returned unchanged.
+	return resource.ResourceID(id)
 }
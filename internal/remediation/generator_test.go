@@ -1,6 +1,13 @@
 package remediation
 
-import "testing"
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/DrSkyle/cloudslash/internal/graph"
+)
 
 func TestExtractResourceID(t *testing.T) {
 	tests := []struct {
@@ -43,3 +50,270 @@ func TestExtractResourceID(t *testing.T) {
 		})
 	}
 }
+
+func TestWriteDeleteCommands_StoppedInstance(t *testing.T) {
+	g := graph.NewGraph()
+	const id = "arn:aws:ec2:us-east-1:123456789012:instance/i-0abc"
+	g.AddNode(id, "AWS::EC2::Instance", map[string]interface{}{"State": "stopped"})
+
+	// A tag finding alone must never terminate an instance.
+	g.AddFinding(id, graph.Finding{Heuristic: "TagComplianceHeuristic", Category: graph.CategoryCompliance, RiskScore: 40})
+	var buf strings.Builder
+	if writeDeleteCommands(&buf, nodeByID(g, id), "") || buf.Len() > 0 {
+		t.Fatalf("expected no commands, got %q", buf.String())
+	}
+
+	g.AddFinding(id, graph.Finding{Heuristic: "StoppedInstanceHeuristic", Category: graph.CategoryWaste, RiskScore: 60})
+	if !writeDeleteCommands(&buf, nodeByID(g, id), "") {
+		t.Fatal("expected commands for a long-stopped instance")
+	}
+	script := buf.String()
+	image := strings.Index(script, "aws ec2 create-image --instance-id i-0abc")
+	wait := strings.Index(script, "aws ec2 wait image-available")
+	terminate := strings.Index(script, "aws ec2 terminate-instances --instance-ids i-0abc")
+	terminated := strings.Index(script, "aws ec2 wait instance-terminated --instance-ids i-0abc")
+	if image < 0 || wait < image || terminate < wait || terminated < terminate {
+		t.Errorf("expected AMI, wait, terminate, then wait for termination, got:\n%s", script)
+	}
+}
+
+func TestWriteDeleteCommands_ScopesEachResource(t *testing.T) {
+	g := graph.NewGraph()
+	const (
+		east = "arn:aws:ec2:us-east-1:111111111111:instance/i-0east"
+		west = "arn:aws:ec2:eu-west-1:222222222222:volume/vol-0west"
+		raw  = "vol-0raw"
+	)
+	g.AddNode(east, "AWS::EC2::Instance", map[string]interface{}{"State": "stopped"})
+	g.AddNode(west, "AWS::EC2::Volume", map[string]interface{}{"VolumeType": "gp2", "Size": int32(500)})
+	g.AddNode(raw, "AWS::EC2::Volume", nil)
+	g.AddFinding(east, graph.Finding{Heuristic: "StoppedInstanceHeuristic", Category: graph.CategoryWaste, RiskScore: 60})
+	g.AddFinding(west, graph.Finding{Heuristic: "ModernizationHeuristic", Category: graph.CategoryRightsizing, RiskScore: 20})
+	g.AddFinding(raw, graph.Finding{Heuristic: "ZombieEBSHeuristic", Category: graph.CategoryWaste, RiskScore: 70})
+
+	var buf strings.Builder
+	writeDeleteCommands(&buf, nodeByID(g, east), "# ")
+	writeModernizeCommands(&buf, nodeByID(g, west), "")
+	writeDeleteCommands(&buf, nodeByID(g, raw), "")
+	script := buf.String()
+
+	for _, want := range []string{"# # Account 111111111111\n", "# Account 222222222222\n"} {
+		if !strings.Contains(script, want) {
+			t.Errorf("missing %q in:\n%s", want, script)
+		}
+	}
+	// Every command names the region of its resource; IDs without one use the CLI default.
+	for _, line := range strings.Split(script, "\n") {
+		var want string
+		switch {
+		case !strings.Contains(line, "aws "):
+			continue
+		case strings.Contains(line, "i-0east"), strings.Contains(line, "image-available"):
+			want = "us-east-1"
+		case strings.Contains(line, "vol-0west"):
+			want = "eu-west-1"
+		}
+		if want == "" {
+			if strings.Contains(line, "--region") {
+				t.Errorf("expected no --region on %q", line)
+			}
+		} else if !strings.Contains(line, "--region "+want) {
+			t.Errorf("expected --region %s on %q", want, line)
+		}
+	}
+}
+
+func TestWriteDeleteCommands_Network(t *testing.T) {
+	g := graph.NewGraph()
+	const (
+		eni = "arn:aws:ec2:us-east-1:123456789012:network-interface/eni-0abc"
+		ep  = "arn:aws:ec2:us-east-1:123456789012:vpc-endpoint/vpce-0abc"
+	)
+	g.AddNode(eni, "AWS::EC2::NetworkInterface", map[string]interface{}{"Status": "available"})
+	g.AddNode(ep, "AWS::EC2::VPCEndpoint", nil)
+
+	var buf strings.Builder
+	if writeDeleteCommands(&buf, nodeByID(g, eni), "") {
+		t.Fatalf("interfaces without an orphan finding must be kept, got %q", buf.String())
+	}
+	g.AddFinding(ep, graph.Finding{Heuristic: "TagComplianceHeuristic", Category: graph.CategoryCompliance, RiskScore: 40})
+	if writeDeleteCommands(&buf, nodeByID(g, ep), "") {
+		t.Fatalf("endpoints without an idle finding must be kept, got %q", buf.String())
+	}
+	g.AddFinding(eni, graph.Finding{Heuristic: "OrphanedENIHeuristic", Category: graph.CategoryWaste, RiskScore: 30})
+	g.AddFinding(ep, graph.Finding{Heuristic: "IdleVPCEndpointHeuristic", Category: graph.CategoryWaste, RiskScore: 30})
+	if !writeDeleteCommands(&buf, nodeByID(g, eni), "") || !writeDeleteCommands(&buf, nodeByID(g, ep), "") {
+		t.Fatal("expected commands for an orphaned interface and an endpoint")
+	}
+	for _, want := range []string{
+		"aws ec2 delete-network-interface --network-interface-id eni-0abc",
+		"aws ec2 delete-vpc-endpoints --vpc-endpoint-ids vpce-0abc",
+	} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("missing %q in:\n%s", want, buf.String())
+		}
+	}
+}
+
+func TestWriteDeleteCommands_RequesterManagedENI(t *testing.T) {
+	g := graph.NewGraph()
+	const id = "arn:aws:ec2:us-east-1:123456789012:network-interface/eni-0lambda"
+	g.AddNode(id, "AWS::EC2::NetworkInterface", map[string]interface{}{
+		"Status": "available", "RequesterManaged": true, "RequesterId": "AWSLambda",
+	})
+	g.AddFinding(id, graph.Finding{Heuristic: "OrphanedENIHeuristic", Category: graph.CategoryWaste, RiskScore: 30})
+
+	var buf strings.Builder
+	if writeDeleteCommands(&buf, nodeByID(g, id), "") {
+		t.Fatal("requester-managed interfaces must not be deleted")
+	}
+	if strings.Contains(buf.String(), "delete-network-interface") {
+		t.Errorf("AWS rejects deleting requester-managed interfaces, got:\n%s", buf.String())
+	}
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		if line != "" && !strings.HasPrefix(line, "#") {
+			t.Errorf("expected only comments, got %q", line)
+		}
+	}
+}
+
+func TestWriteDeleteCommands_ModernizedVolume(t *testing.T) {
+	g := graph.NewGraph()
+	const id = "arn:aws:ec2:us-east-1:123456789012:volume/vol-0gp2"
+	g.AddNode(id, "AWS::EC2::Volume", map[string]interface{}{"VolumeType": "gp2", "Size": int32(2000)})
+	g.AddFinding(id, graph.Finding{Heuristic: "ModernizationHeuristic", Category: graph.CategoryRightsizing, RiskScore: 20})
+
+	var buf strings.Builder
+	if writeDeleteCommands(&buf, nodeByID(g, id), "") {
+		t.Fatalf("a rightsizing finding must not delete a volume, got %q", buf.String())
+	}
+	if !writeModernizeCommands(&buf, nodeByID(g, id), "") {
+		t.Fatal("expected commands for a gp2 volume")
+	}
+	script := buf.String()
+	if !strings.Contains(script, "aws ec2 modify-volume --volume-id vol-0gp2 --volume-type gp3 --iops 6000 --throughput 250") {
+		t.Errorf("expected a gp3 conversion, got:\n%s", script)
+	}
+	if strings.Contains(script, "delete-volume") {
+		t.Errorf("a volume worth modernizing must not be deleted:\n%s", script)
+	}
+}
+
+func TestWriteDeleteCommands_Database(t *testing.T) {
+	g := graph.NewGraph()
+	const id = "arn:aws:rds:us-east-1:123456789012:db:orders"
+	g.AddNode(id, "AWS::RDS::DBInstance", map[string]interface{}{"Status": "available"})
+
+	// A Graviton recommendation alone must never delete a database.
+	g.AddFinding(id, graph.Finding{Heuristic: "GravitonHeuristic", Category: graph.CategoryRightsizing, RiskScore: 20})
+	var buf strings.Builder
+	if writeDeleteCommands(&buf, nodeByID(g, id), "") || buf.Len() > 0 {
+		t.Fatalf("expected no commands, got %q", buf.String())
+	}
+
+	g.AddFinding(id, graph.Finding{Heuristic: "RDSHeuristic", Category: graph.CategoryWaste, RiskScore: 50})
+	if !writeDeleteCommands(&buf, nodeByID(g, id), "") || !strings.Contains(buf.String(), "aws rds delete-db-instance --db-instance-identifier orders") {
+		t.Errorf("expected snapshot and delete for a stopped database, got:\n%s", buf.String())
+	}
+}
+
+func TestWriteDeleteCommands_RDSSnapshots(t *testing.T) {
+	g := graph.NewGraph()
+	const snap = "arn:aws:rds:us-east-1:123456789012:snapshot:orders-final"
+	const clusterSnap = "arn:aws:rds:us-east-1:123456789012:cluster-snapshot:ledger-final"
+	g.AddNode(snap, "AWS::RDS::DBSnapshot", nil)
+	g.AddNode(clusterSnap, "AWS::RDS::DBClusterSnapshot", nil)
+
+	var buf strings.Builder
+	if writeDeleteCommands(&buf, nodeByID(g, snap), "") {
+		t.Fatal("expected no commands without an RDSSnapshotHeuristic finding")
+	}
+	for _, id := range []string{snap, clusterSnap} {
+		g.AddFinding(id, graph.Finding{Heuristic: "RDSSnapshotHeuristic", Category: graph.CategoryWaste, RiskScore: 60})
+		if !writeDeleteCommands(&buf, nodeByID(g, id), "") {
+			t.Fatalf("expected commands for %s", id)
+		}
+	}
+	script := buf.String()
+	for _, want := range []string{
+		"aws rds delete-db-snapshot --db-snapshot-identifier orders-final",
+		"aws rds delete-db-cluster-snapshot --db-cluster-snapshot-identifier ledger-final",
+	} {
+		if !strings.Contains(script, want) {
+			t.Errorf("expected %q in:\n%s", want, script)
+		}
+	}
+}
+
+func TestGenerateSafeDeleteScript_WasteOnly(t *testing.T) {
+	g := graph.NewGraph()
+	const (
+		nat = "arn:aws:ec2:us-east-1:123456789012:natgateway/nat-0tags"
+		eip = "arn:aws:ec2:us-east-1:123456789012:elastic-ip/eipalloc-0idle"
+		gp2 = "arn:aws:ec2:us-east-1:123456789012:volume/vol-0gp2"
+	)
+	g.AddNode(nat, "AWS::EC2::NatGateway", nil)
+	g.AddNode(eip, "AWS::EC2::EIP", nil)
+	g.AddNode(gp2, "AWS::EC2::Volume", map[string]interface{}{"VolumeType": "gp2", "Size": int32(100)})
+	g.AddFinding(nat, graph.Finding{Heuristic: "TagComplianceHeuristic", Category: graph.CategoryCompliance, RiskScore: 40})
+	g.AddFinding(eip, graph.Finding{Heuristic: "ElasticIPHeuristic", Category: graph.CategoryWaste, RiskScore: 30})
+	g.AddFinding(gp2, graph.Finding{Heuristic: "ModernizationHeuristic", Category: graph.CategoryRightsizing, RiskScore: 20})
+
+	path := filepath.Join(t.TempDir(), "safe_cleanup.sh")
+	if err := NewGenerator(g).GenerateSafeDeleteScript(path); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	script := string(data)
+	if strings.Contains(script, "nat-0tags") {
+		t.Errorf("a compliance finding must not delete a NAT gateway:\n%s", script)
+	}
+	for _, want := range []string{
+		"aws ec2 release-address --allocation-id eipalloc-0idle",
+		"aws ec2 modify-volume --volume-id vol-0gp2 --volume-type gp3",
+		"2 resources processed",
+	} {
+		if !strings.Contains(script, want) {
+			t.Errorf("missing %q in:\n%s", want, script)
+		}
+	}
+}
+
+func TestGenerateSafeDeleteScript_SkipsJustified(t *testing.T) {
+	g := graph.NewGraph()
+	const (
+		kept  = "arn:aws:ec2:us-east-1:123456789012:volume/vol-0dr"
+		waste = "arn:aws:ec2:us-east-1:123456789012:volume/vol-0zombie"
+	)
+	g.AddNode(kept, "AWS::EC2::Volume", map[string]interface{}{"State": "available"})
+	g.AddNode(waste, "AWS::EC2::Volume", map[string]interface{}{"State": "available"})
+	g.AddFinding(kept, graph.Finding{Heuristic: "ZombieEBSHeuristic", Category: graph.CategoryWaste, RiskScore: 50})
+	g.AddFinding(waste, graph.Finding{Heuristic: "ZombieEBSHeuristic", Category: graph.CategoryWaste, RiskScore: 50})
+	nodeByID(g, kept).Justified = true
+	nodeByID(g, kept).Justification = "Disaster recovery copy"
+
+	path := filepath.Join(t.TempDir(), "safe_cleanup.sh")
+	if err := NewGenerator(g).GenerateSafeDeleteScript(path); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	script := string(data)
+	if strings.Contains(script, "vol-0dr") {
+		t.Errorf("justified volume must be excluded from remediation:\n%s", script)
+	}
+	if !strings.Contains(script, "aws ec2 delete-volume --volume-id vol-0zombie") {
+		t.Errorf("expected the unjustified volume to be deleted:\n%s", script)
+	}
+}
+
+// nodeByID returns the node with id, or nil when the graph has none.
+func nodeByID(g *graph.Graph, id string) *graph.Node {
+	node, _ := g.Node(id)
+	return node
+}
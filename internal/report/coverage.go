@@ -0,0 +1,62 @@
+package report
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/DrSkyle/cloudslash/internal/graph"
+)
+
+// WriteCoverage lists each heuristic's status, duration and coverage, followed
+// by the resources it skipped, so an empty report can be told apart from one
+// that could not look.
+func WriteCoverage(w io.Writer, runs []graph.HeuristicRun) error {
+	if len(runs) == 0 {
+		_, err := fmt.Fprintln(w, "No heuristics ran.")
+		return err
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "HEURISTIC\tSTATUS\tDURATION\tEXAMINED\tSKIPPED\tFINDINGS\tERROR")
+	incomplete := 0
+	for _, r := range runs {
+		if r.Status != graph.RunOK {
+			incomplete++
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%d\t%d\t%d\t%s\n", r.Heuristic, r.Status, r.Duration.Round(time.Millisecond), r.Examined, r.Skipped, r.Findings, r.Error)
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+
+	for _, r := range runs {
+		for _, s := range r.Skips {
+			fmt.Fprintf(w, "  %s skipped %s: %s\n", r.Heuristic, s.ResourceID, s.Reason)
+		}
+		if more := r.Skipped - len(r.Skips); more > 0 {
+			fmt.Fprintf(w, "  %s skipped %d more\n", r.Heuristic, more)
+		}
+	}
+
+	_, err := fmt.Fprintf(w, "\n%d heuristics, %d incomplete\n", len(runs), incomplete)
+	return err
+}
+
+// GenerateCoverageJSON writes the heuristic coverage of the scan to a JSON file.
+func GenerateCoverageJSON(g *graph.Graph, path string) error {
+	runs := g.HeuristicRuns()
+	if runs == nil {
+		runs = []graph.HeuristicRun{}
+	}
+
+	data, err := json.MarshalIndent(runs, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(path, data, 0644)
+}
@@ -0,0 +1,117 @@
+package report
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/DrSkyle/cloudslash/internal/graph"
+)
+
+// WriteDiff renders a scan-to-scan comparison as "text", "json" or "markdown".
+func WriteDiff(w io.Writer, d *graph.DiffReport, format string) error {
+	switch strings.ToLower(format) {
+	case "", "text":
+		return writeDiffText(w, d)
+	case "json":
+		data, err := json.MarshalIndent(d, "", "  ")
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintln(w, string(data))
+		return err
+	case "markdown", "md":
+		return writeDiffMarkdown(w, d)
+	default:
+		return fmt.Errorf("unknown diff format %q (use text, json or markdown)", format)
+	}
+}
+
+func writeDiffText(w io.Writer, d *graph.DiffReport) error {
+	fmt.Fprintf(w, "CloudSlash Scan Diff\n")
+	fmt.Fprintf(w, "====================\n")
+	fmt.Fprintf(w, "Old scan: %s\n", scanLabel(d.Old))
+	fmt.Fprintf(w, "New scan: %s\n\n", scanLabel(d.New))
+
+	fmt.Fprintf(w, "Monthly waste: $%.2f -> $%.2f (%s)\n\n", d.OldMonthlyCost, d.NewMonthlyCost, signedMoney(d.NetMonthlyDelta))
+
+	fmt.Fprintf(w, "New waste (%d):\n", len(d.NewWaste))
+	for _, c := range d.NewWaste {
+		fmt.Fprintf(w, "  + %s (%s) $%.2f/mo risk %d\n", c.ID, c.Type, c.NewCost, c.NewRisk)
+		if c.Reason != "" {
+			fmt.Fprintf(w, "      %s\n", c.Reason)
+		}
+	}
+
+	fmt.Fprintf(w, "\nResolved waste (%d):\n", len(d.ResolvedWaste))
+	for _, c := range d.ResolvedWaste {
+		fmt.Fprintf(w, "  - %s (%s) $%.2f/mo [%s]\n", c.ID, c.Type, c.OldCost, c.Resolution)
+	}
+
+	fmt.Fprintf(w, "\nChanged (%d):\n", len(d.Changed))
+	for _, c := range d.Changed {
+		fmt.Fprintf(w, "  ~ %s (%s) $%.2f -> $%.2f, risk %d -> %d\n", c.ID, c.Type, c.OldCost, c.NewCost, c.OldRisk, c.NewRisk)
+	}
+	return nil
+}
+
+func writeDiffMarkdown(w io.Writer, d *graph.DiffReport) error {
+	fmt.Fprintf(w, "# CloudSlash Scan Diff\n\n")
+	fmt.Fprintf(w, "| | Scan | Monthly Waste |\n|---|---|---|\n")
+	fmt.Fprintf(w, "| Old | %s | $%.2f |\n", scanLabel(d.Old), d.OldMonthlyCost)
+	fmt.Fprintf(w, "| New | %s | $%.2f |\n", scanLabel(d.New), d.NewMonthlyCost)
+	fmt.Fprintf(w, "| **Net** | | **%s** |\n\n", signedMoney(d.NetMonthlyDelta))
+
+	fmt.Fprintf(w, "## New Waste (%d)\n\n", len(d.NewWaste))
+	if len(d.NewWaste) > 0 {
+		fmt.Fprintf(w, "| Resource | Type | Monthly Cost | Risk | Reason |\n|---|---|---|---|---|\n")
+		for _, c := range d.NewWaste {
+			fmt.Fprintf(w, "| `%s` | %s | $%.2f | %d | %s |\n", c.ID, c.Type, c.NewCost, c.NewRisk, markdownCell(c.Reason))
+		}
+		fmt.Fprintln(w)
+	}
+
+	fmt.Fprintf(w, "## Resolved Waste (%d)\n\n", len(d.ResolvedWaste))
+	if len(d.ResolvedWaste) > 0 {
+		fmt.Fprintf(w, "| Resource | Type | Monthly Cost | Resolution |\n|---|---|---|---|\n")
+		for _, c := range d.ResolvedWaste {
+			fmt.Fprintf(w, "| `%s` | %s | $%.2f | %s |\n", c.ID, c.Type, c.OldCost, c.Resolution)
+		}
+		fmt.Fprintln(w)
+	}
+
+	fmt.Fprintf(w, "## Changed (%d)\n\n", len(d.Changed))
+	if len(d.Changed) > 0 {
+		fmt.Fprintf(w, "| Resource | Type | Cost | Risk |\n|---|---|---|---|\n")
+		for _, c := range d.Changed {
+			fmt.Fprintf(w, "| `%s` | %s | $%.2f → $%.2f | %d → %d |\n", c.ID, c.Type, c.OldCost, c.NewCost, c.OldRisk, c.NewRisk)
+		}
+		fmt.Fprintln(w)
+	}
+	return nil
+}
+
+func scanLabel(m graph.ScanMetadata) string {
+	label := "unknown time"
+	if !m.ScannedAt.IsZero() {
+		label = m.ScannedAt.Format(time.RFC822)
+	}
+	if len(m.Accounts) > 0 {
+		label += fmt.Sprintf(" (%s)", strings.Join(m.Accounts, ", "))
+	}
+	return label
+}
+
+func signedMoney(v float64) string {
+	if v < 0 {
+		return fmt.Sprintf("-$%.2f/mo", -v)
+	}
+	return fmt.Sprintf("+$%.2f/mo", v)
+}
+
+func markdownCell(s string) string {
+	s = strings.ReplaceAll(s, "|", "\\|")
+	return strings.ReplaceAll(s, "\n", "<br>")
+}
@@ -5,25 +5,32 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
+	"sort"
+	"strings"
 
 	"github.com/DrSkyle/cloudslash/internal/graph"
+	"github.com/DrSkyle/cloudslash/internal/query"
 )
 
 // ExportItem matches the JSON/CSV structure.
 type ExportItem struct {
-	ID             string  `json:"id"`
-	Type           string  `json:"type"`
-	Reason         string  `json:"reason"`
-	Cost           float64 `json:"monthly_cost"`
-	RiskScore      int     `json:"risk_score"`
-	SourceLocation string  `json:"source_location,omitempty"`
-	Owner          string  `json:"owner,omitempty"`
-	Region         string  `json:"region,omitempty"`
+	ID             string          `json:"id"`
+	Type           string          `json:"type"`
+	Category       graph.Category  `json:"category"` // "waste", or the top category of a resource kept in place
+	Reason         string          `json:"reason"`
+	Cost           float64         `json:"monthly_cost"`
+	Savings        float64         `json:"monthly_savings"`
+	RiskScore      int             `json:"risk_score"`
+	SourceLocation string          `json:"source_location,omitempty"`
+	Owner          string          `json:"owner,omitempty"`
+	Region         string          `json:"region,omitempty"`
+	Findings       []graph.Finding `json:"findings"`
 }
 
-// GenerateCSV writes waste items to a CSV file.
-func GenerateCSV(g *graph.Graph, path string) error {
-	items := extractItems(g)
+// GenerateCSV writes flagged resources to a CSV file, waste first.
+// A non-nil where limits the export to resources matching the query.
+func GenerateCSV(g *graph.Graph, path string, where *query.Query) error {
+	items := extractItems(g, where)
 
 	f, err := os.Create(path)
 	if err != nil {
@@ -35,7 +42,7 @@ func GenerateCSV(g *graph.Graph, path string) error {
 	defer w.Flush()
 
 	// Header
-	header := []string{"Resource ID", "Type", "Reason", "Monthly Cost ($)", "Risk Score", "Source Code", "Owner", "Region"}
+	header := []string{"Resource ID", "Type", "Category", "Reason", "Monthly Cost ($)", "Monthly Savings ($)", "Risk Score", "Source Code", "Owner", "Region", "Findings", "Actions"}
 	if err := w.Write(header); err != nil {
 		return err
 	}
@@ -44,12 +51,18 @@ func GenerateCSV(g *graph.Graph, path string) error {
 		record := []string{
 			item.ID,
 			item.Type,
+			string(item.Category),
 			item.Reason,
 			fmt.Sprintf("%.2f", item.Cost),
+			fmt.Sprintf("%.2f", item.Savings),
 			fmt.Sprintf("%d", item.RiskScore),
 			item.SourceLocation,
 			item.Owner,
 			item.Region,
+			joinFindings(item.Findings, func(f graph.Finding) string {
+				return fmt.Sprintf("[%s/%s] %s", f.Heuristic, f.Category, f.Reason)
+			}),
+			joinFindings(item.Findings, func(f graph.Finding) string { return f.Action }),
 		}
 		if err := w.Write(record); err != nil {
 			return err
@@ -59,9 +72,9 @@ func GenerateCSV(g *graph.Graph, path string) error {
 	return nil
 }
 
-// GenerateJSON writes certain waste items to a JSON file.
-func GenerateJSON(g *graph.Graph, path string) error {
-	items := extractItems(g)
+// GenerateJSON writes the same items as GenerateCSV to a JSON file.
+func GenerateJSON(g *graph.Graph, path string, where *query.Query) error {
+	items := extractItems(g, where)
 	
 	data, err := json.MarshalIndent(items, "", "  ")
 	if err != nil {
@@ -71,28 +84,52 @@ func GenerateJSON(g *graph.Graph, path string) error {
 	return os.WriteFile(path, data, 0644)
 }
 
-func extractItems(g *graph.Graph) []ExportItem {
-	g.Mu.RLock()
-	defer g.Mu.RUnlock()
-
+// extractItems returns waste first, then resources with only rightsizing,
+// security or compliance findings, each group sorted by ID.
+func extractItems(g *graph.Graph, where *query.Query) []ExportItem {
 	var items []ExportItem
-	for _, node := range g.Nodes {
-		if node.IsWaste {
-			region, _ := node.Properties["Region"].(string)
-			owner, _ := node.Properties["Owner"].(string)
-			reason, _ := node.Properties["Reason"].(string)
+	g.Read(func(v *graph.View) {
+		v.Each(func(node *graph.Node) {
+			if len(node.Findings) > 0 && (where == nil || where.Match(v, node)) {
+				category := node.Findings[0].Category // Highest risk first
+				if node.HasWasteFinding() {
+					category = graph.CategoryWaste
+				}
+				region, _ := node.Properties["Region"].(string)
+				owner, _ := node.Properties["Owner"].(string)
+				items = append(items, ExportItem{
+					ID:             node.ID,
+					Type:           node.Type,
+					Category:       category,
+					Reason:         node.Reason(),
+					Cost:           node.Cost,
+					Savings:        node.Savings,
+					RiskScore:      node.RiskScore,
+					SourceLocation: node.SourceLocation,
+					Owner:          owner,
+					Region:         region,
+					Findings:       node.Findings,
+				})
+			}
+		})
+	})
+	sort.Slice(items, func(i, j int) bool {
+		wi, wj := items[i].Category == graph.CategoryWaste, items[j].Category == graph.CategoryWaste
+		if wi != wj {
+			return wi
+		}
+		return items[i].ID < items[j].ID
+	})
+	return items
+}
 
-			items = append(items, ExportItem{
-				ID:             node.ID,
-				Type:           node.Type,
-				Reason:         reason,
-				Cost:           node.Cost,
-				RiskScore:      node.RiskScore,
-				SourceLocation: node.SourceLocation,
-				Owner:          owner,
-				Region:         region,
-			})
+// joinFindings renders one line per finding for a single CSV cell.
+func joinFindings(findings []graph.Finding, format func(graph.Finding) string) string {
+	lines := make([]string, 0, len(findings))
+	for _, f := range findings {
+		if line := format(f); line != "" {
+			lines = append(lines, line)
 		}
 	}
-	return items
+	return strings.Join(lines, "\n")
 }
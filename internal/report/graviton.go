@@ -0,0 +1,102 @@
+package report
+
+import (
+	"encoding/csv"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/DrSkyle/cloudslash/internal/graph"
+	"github.com/DrSkyle/cloudslash/internal/heuristics"
+)
+
+// MigrationItem is one entry of the Graviton migration backlog.
+type MigrationItem struct {
+	ID      string
+	Type    string
+	From    string
+	To      string
+	Effort  heuristics.MigrationEffort
+	Savings float64
+	Notes   []string
+}
+
+// GravitonBacklog lists the resources GravitonHeuristic recommended moving,
+// easiest first and, within the same effort, largest savings first.
+func GravitonBacklog(g *graph.Graph) []MigrationItem {
+	var items []MigrationItem
+	g.Read(func(v *graph.View) {
+		v.Each(func(node *graph.Node) {
+			f, ok := node.FindingBy("GravitonHeuristic")
+			if !ok || node.Justified {
+				return
+			}
+			plan, ok := heuristics.PlanGraviton(node)
+			if !ok {
+				return
+			}
+			items = append(items, MigrationItem{
+				ID:      node.ID,
+				Type:    node.Type,
+				From:    plan.From,
+				To:      plan.To,
+				Effort:  plan.Effort,
+				Savings: f.MonthlySavings,
+				Notes:   plan.Notes,
+			})
+		})
+	})
+
+	sort.Slice(items, func(i, j int) bool {
+		if items[i].Effort != items[j].Effort {
+			return items[i].Effort < items[j].Effort
+		}
+		if items[i].Savings != items[j].Savings {
+			return items[i].Savings > items[j].Savings
+		}
+		return items[i].ID < items[j].ID
+	})
+	return items
+}
+
+// GenerateGravitonCSV writes the Graviton migration backlog to a CSV file.
+// Nothing is written when there are no candidates.
+func GenerateGravitonCSV(g *graph.Graph, path string) error {
+	items := GravitonBacklog(g)
+	if len(items) == 0 {
+		return nil
+	}
+
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	w := csv.NewWriter(f)
+	defer w.Flush()
+
+	header := []string{"Rank", "Resource ID", "Type", "Current", "Graviton", "Effort", "Monthly Savings ($)", "Notes"}
+	if err := w.Write(header); err != nil {
+		return err
+	}
+
+	for i, item := range items {
+		record := []string{
+			fmt.Sprintf("%d", i+1),
+			item.ID,
+			item.Type,
+			item.From,
+			item.To,
+			item.Effort.String(),
+			fmt.Sprintf("%.2f", item.Savings),
+			strings.Join(item.Notes, "\n"),
+		}
+		if err := w.Write(record); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
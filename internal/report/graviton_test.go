@@ -0,0 +1,37 @@
+package report
+
+import (
+	"testing"
+
+	"github.com/DrSkyle/cloudslash/internal/graph"
+)
+
+func TestGravitonBacklog_Ranks(t *testing.T) {
+	g := graph.NewGraph()
+	add := func(id, typ string, props map[string]interface{}, savings float64) {
+		g.AddNode(id, typ, props)
+		g.AddFinding(id, graph.Finding{Heuristic: "GravitonHeuristic", Category: graph.CategoryRightsizing, MonthlySavings: savings})
+	}
+	linux := func(instanceType string) map[string]interface{} {
+		return map[string]interface{}{"InstanceType": instanceType, "State": "running", "PlatformDetails": "Linux/UNIX"}
+	}
+	add("i-medium-small", "AWS::EC2::Instance", linux("m5.large"), 10)
+	add("i-medium-large", "AWS::EC2::Instance", linux("m5.4xlarge"), 80)
+	add("i-high", "AWS::EC2::Instance", map[string]interface{}{"InstanceType": "m5.large", "State": "running", "PlatformDetails": "Red Hat Enterprise Linux"}, 500)
+	add("db-low", "AWS::RDS::DBInstance", map[string]interface{}{"InstanceClass": "db.m5.large", "Engine": "postgres", "EngineVersion": "15.4"}, 5)
+	justified := linux("m5.large")
+	justified["Tags"] = map[string]string{"cloudslash:ignore": "justified:x86-only vendor agent"}
+	add("i-justified", "AWS::EC2::Instance", justified, 1000)
+
+	// Easiest first; within the same effort, largest savings first.
+	want := []string{"db-low", "i-medium-large", "i-medium-small", "i-high"}
+	items := GravitonBacklog(g)
+	if len(items) != len(want) {
+		t.Fatalf("got %d items, want %d: %+v", len(items), len(want), items)
+	}
+	for i, item := range items {
+		if item.ID != want[i] {
+			t.Errorf("rank %d = %s, want %s", i+1, item.ID, want[i])
+		}
+	}
+}
@@ -0,0 +1,24 @@
+package report
+
+import (
+	"fmt"
+	"io"
+	"text/tabwriter"
+
+	"github.com/DrSkyle/cloudslash/internal/heuristics"
+)
+
+// WriteSettings lists heuristic thresholds with their effective values and
+// where each value came from.
+func WriteSettings(w io.Writer, settings []heuristics.Setting) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "HEURISTIC\tSETTING\tVALUE\tSOURCE")
+	for _, s := range settings {
+		if s.Key == "" {
+			fmt.Fprintf(tw, "%s\t-\t-\t-\n", s.Heuristic)
+			continue
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", s.Heuristic, s.Key, s.Value, s.Source)
+	}
+	return tw.Flush()
+}
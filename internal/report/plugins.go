@@ -0,0 +1,37 @@
+package report
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/DrSkyle/cloudslash/internal/heuristics"
+)
+
+// WritePlugins lists discovered plugins with their declared capabilities.
+func WritePlugins(w io.Writer, plugins []*heuristics.Plugin) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "PLUGIN\tVERSION\tTYPES\tAFTER\tENV\tTIMEOUT\tPATH")
+	for _, p := range plugins {
+		m := p.Manifest
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\t%s\n", p.Name(), orDash(m.Version), strings.Join(m.Types, ","),
+			orDash(strings.Join(m.After, ",")), orDash(strings.Join(m.Env, ",")), p.Timeout(), p.Path)
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+	for _, p := range plugins {
+		if p.Manifest.Description != "" {
+			fmt.Fprintf(w, "  %s: %s\n", p.Name(), p.Manifest.Description)
+		}
+	}
+	return nil
+}
+
+func orDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
@@ -0,0 +1,47 @@
+package report
+
+import (
+	"fmt"
+	"io"
+	"text/tabwriter"
+	"time"
+
+	"github.com/DrSkyle/cloudslash/internal/policy"
+)
+
+// WritePolicy lists policy rules with their status. withHits adds how many
+// findings each rule matched, for policies evaluated against a scan.
+func WritePolicy(w io.Writer, p *policy.Policy, withHits bool) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	header := "ID\tACTION\tOWNER\tEXPIRES\tSTATUS\tREASON"
+	if withHits {
+		header = "ID\tACTION\tOWNER\tEXPIRES\tSTATUS\tHITS\tREASON"
+	}
+	fmt.Fprintln(tw, header)
+
+	now := time.Now()
+	for _, r := range p.Rules {
+		status := "active"
+		if r.Expired(now) {
+			status = "EXPIRED"
+		} else if days := int(r.ExpiresAt().Sub(now).Hours() / 24); days < 30 {
+			status = fmt.Sprintf("expires in %dd", days)
+		}
+
+		if withHits {
+			fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%d\t%s\n", r.ID, r.Action, r.Owner, r.Expires, status, p.Hits(r.ID), r.Reason)
+		} else {
+			fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\n", r.ID, r.Action, r.Owner, r.Expires, status, r.Reason)
+		}
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+
+	expired := len(p.Expired())
+	_, err := fmt.Fprintf(w, "\n%d rules, %d expired\n", len(p.Rules), expired)
+	if err == nil && withHits {
+		_, err = fmt.Fprintf(w, "%d unused\n", len(p.Unused()))
+	}
+	return err
+}
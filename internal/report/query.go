@@ -0,0 +1,83 @@
+package report
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/DrSkyle/cloudslash/internal/graph"
+)
+
+// QueryResult is one node in `cloudslash query` JSON output.
+type QueryResult struct {
+	ID         string                 `json:"id"`
+	Type       string                 `json:"type"`
+	IsWaste    bool                   `json:"is_waste"`
+	Justified  bool                   `json:"justified,omitempty"`
+	RiskScore  int                    `json:"risk_score"`
+	Cost       float64                `json:"monthly_cost"`
+	Savings    float64                `json:"monthly_savings,omitempty"`
+	Findings   []graph.Finding        `json:"findings,omitempty"`
+	Properties map[string]interface{} `json:"properties,omitempty"`
+}
+
+// WriteNodes renders query results as a "table" or "json".
+func WriteNodes(w io.Writer, nodes []*graph.Node, format string) error {
+	switch strings.ToLower(format) {
+	case "", "table":
+		return writeNodeTable(w, nodes)
+	case "json":
+		results := make([]QueryResult, 0, len(nodes))
+		for _, n := range nodes {
+			results = append(results, QueryResult{
+				ID:         n.ID,
+				Type:       n.Type,
+				IsWaste:    n.IsWaste,
+				Justified:  n.Justified,
+				RiskScore:  n.RiskScore,
+				Cost:       n.Cost,
+				Savings:    n.Savings,
+				Findings:   n.Findings,
+				Properties: n.Properties,
+			})
+		}
+		data, err := json.MarshalIndent(results, "", "  ")
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintln(w, string(data))
+		return err
+	default:
+		return fmt.Errorf("unknown query format %q (use table or json)", format)
+	}
+}
+
+func writeNodeTable(w io.Writer, nodes []*graph.Node) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "ID\tTYPE\tWASTE\tRISK\tCOST/MO\tREASON")
+
+	var total float64
+	for _, n := range nodes {
+		waste := "-"
+		if n.IsWaste {
+			waste = "yes"
+			if n.Justified {
+				waste = "justified"
+			}
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%d\t$%.2f\t%s\n", n.ID, n.Type, waste, n.RiskScore, n.Cost, reasonOf(n))
+		total += n.Cost
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+
+	_, err := fmt.Fprintf(w, "\n%d resources, $%.2f/mo\n", len(nodes), total)
+	return err
+}
+
+func reasonOf(n *graph.Node) string {
+	return strings.ReplaceAll(n.Reason(), "\n", " ")
+}
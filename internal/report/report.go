@@ -9,6 +9,7 @@ import (
 	"time"
 
 	"github.com/DrSkyle/cloudslash/internal/graph"
+	"github.com/DrSkyle/cloudslash/internal/resource"
 )
 
 // ReportData holds data for the HTML template.
@@ -20,6 +21,10 @@ type ReportData struct {
 	ProjectedSavings float64 // Annual
 	WasteItems       []WasteItem
 	JustifiedItems   []WasteItem // New selection for justified waste
+	Recommendations  []WasteItem // Rightsizing, security and compliance findings on resources kept in place
+	Migrations       []MigrationItem // Graviton backlog, easiest first
+	Coverage         []graph.HeuristicRun
+	Incomplete       int // Heuristics that skipped resources or failed
 
 	// Chart Data
 	ChartLabelsJSON template.JS
@@ -31,7 +36,9 @@ type WasteItem struct {
 	ID        string
 	Type      string
 	Reason    string
-	Cost      float64
+	Findings  []graph.Finding
+	Cost      float64 // As scanned
+	Savings   float64 // From acting on the findings
 	RiskScore int
 	SrcLoc    string
 }
@@ -125,6 +132,16 @@ const htmlTemplate = `
             color: var(--text-primary);
         }
         tr:hover td { background: rgba(255,255,255,0.02); }
+        .finding + .finding { margin-top: 0.5rem; }
+        .finding-evidence {
+            color: var(--text-secondary);
+            font-size: 0.8em;
+        }
+        .finding-action {
+            color: var(--text-secondary);
+            font-size: 0.8em;
+            white-space: pre-wrap;
+        }
         .badge {
             display: inline-block;
             padding: 0.25rem 0.5rem;
@@ -135,6 +152,7 @@ const htmlTemplate = `
             color: #60a5fa;
         }
         .badge.high-risk { background: rgba(239, 68, 68, 0.2); color: #f87171; }
+        .badge.partial { background: rgba(245, 158, 11, 0.2); color: #fbbf24; }
     </style>
 </head>
 <body>
@@ -193,9 +211,10 @@ const htmlTemplate = `
                         <th>Resource ID</th>
                         <th>Type</th>
                         <th>Risk Score</th>
-                        <th>Est. Monthly Cost</th>
+                        <th>Monthly Cost</th>
+                        <th>Est. Monthly Savings</th>
                         <th>Source</th>
-                        <th>Reason</th>
+                        <th>Findings</th>
                     </tr>
                 </thead>
                 <tbody>
@@ -211,17 +230,69 @@ const htmlTemplate = `
                             {{end}}
                         </td>
                         <td>${{printf "%.2f" .Cost}}</td>
+                        <td>${{printf "%.2f" .Savings}}</td>
                         <td style="font-family: monospace; font-size: 0.8em; color: var(--accent);">{{.SrcLoc}}</td>
-                        <td>{{.Reason}}</td>
+                        <td>
+                            {{range .Findings}}
+                            <div class="finding">
+                                <span class="badge">{{.Category}}</span> {{.Reason}} <span class="finding-evidence">(confidence {{printf "%.2f" .Confidence}})</span>
+                                {{range .Evidence}}<div class="finding-evidence">&middot; {{.}}</div>{{end}}
+                                {{if .Thresholds}}<div class="finding-evidence">thresholds:{{range $k, $v := .Thresholds}} {{$k}}={{$v}}{{end}}</div>{{end}}
+                                {{if .Action}}<div class="finding-action">&rarr; {{.Action}}</div>{{end}}
+                            </div>
+                            {{end}}
+                        </td>
                     </tr>
                     {{else}}
                     <tr>
-                        <td colspan="6" style="text-align: center; padding: 2rem; color: var(--text-secondary);">No waste identified. Infrastructure is optimized.</td>
+                        <td colspan="7" style="text-align: center; padding: 2rem; color: var(--text-secondary);">No waste identified. Infrastructure is optimized.</td>
+                    </tr>
+                    {{end}}
+                </tbody>
+            </table>
+        </div>
+
+        {{if .Recommendations}}
+        <div class="card" style="margin-top: 3rem;">
+            <h2 style="margin-top:0; margin-bottom:0.5rem;">Recommendations</h2>
+            <p class="subtitle" style="margin-bottom:1.5rem;">Rightsizing, security and compliance findings. These resources stay in place and are not counted as waste.</p>
+            <table>
+                <thead>
+                    <tr>
+                        <th>Resource ID</th>
+                        <th>Type</th>
+                        <th>Risk Score</th>
+                        <th>Est. Monthly Savings</th>
+                        <th>Findings</th>
+                    </tr>
+                </thead>
+                <tbody>
+                    {{range .Recommendations}}
+                    <tr>
+                        <td style="font-family: monospace;">{{.ID}}</td>
+                        <td><span class="badge">{{.Type}}</span></td>
+                        <td>
+                            {{if ge .RiskScore 80}}
+                                <span class="badge high-risk">{{.RiskScore}}</span>
+                            {{else}}
+                                {{.RiskScore}}
+                            {{end}}
+                        </td>
+                        <td>${{printf "%.2f" .Savings}}</td>
+                        <td>
+                            {{range .Findings}}
+                            <div class="finding">
+                                <span class="badge">{{.Category}}</span> {{.Reason}}
+                                {{if .Action}}<div class="finding-action">&rarr; {{.Action}}</div>{{end}}
+                            </div>
+                            {{end}}
+                        </td>
                     </tr>
                     {{end}}
                 </tbody>
             </table>
         </div>
+        {{end}}
 
         {{if .JustifiedItems}}
         <div class="card" style="margin-top: 3rem; opacity: 0.8;">
@@ -249,6 +320,75 @@ const htmlTemplate = `
         </div>
         {{end}}
 
+        {{if .Migrations}}
+        <div class="card" style="margin-top: 3rem;">
+            <h2 style="margin-top:0; margin-bottom:0.5rem;">Graviton Migration Backlog</h2>
+            <p class="subtitle" style="margin-bottom:1.5rem;">Easiest moves first, then by savings. Also written to <code>cloudslash-out/graviton_backlog.csv</code>.</p>
+            <table>
+                <thead>
+                    <tr>
+                        <th>Resource ID</th>
+                        <th>Type</th>
+                        <th>Move</th>
+                        <th>Effort</th>
+                        <th>Savings/mo</th>
+                    </tr>
+                </thead>
+                <tbody>
+                    {{range .Migrations}}
+                    <tr>
+                        <td style="font-family: monospace;">{{.ID}}</td>
+                        <td><span class="badge">{{.Type}}</span></td>
+                        <td>
+                            {{.From}} &rarr; {{.To}}
+                            {{range .Notes}}<div class="finding-evidence">&middot; {{.}}</div>{{end}}
+                        </td>
+                        <td><span class="badge {{if eq .Effort.String "high"}}high-risk{{else if eq .Effort.String "medium"}}partial{{end}}">{{.Effort}}</span></td>
+                        <td>${{printf "%.2f" .Savings}}</td>
+                    </tr>
+                    {{end}}
+                </tbody>
+            </table>
+        </div>
+        {{end}}
+
+        {{if .Coverage}}
+        <div class="card" style="margin-top: 3rem;">
+            <h2 style="margin-top:0; margin-bottom:0.5rem;">Coverage</h2>
+            <p class="subtitle" style="margin-bottom:1.5rem;">
+                {{if .Incomplete}}{{.Incomplete}} of {{len .Coverage}} heuristics could not examine everything. Missing findings below may mean "couldn't look" rather than "no waste".{{else}}Every heuristic examined all of its candidates.{{end}}
+            </p>
+            <table>
+                <thead>
+                    <tr>
+                        <th>Heuristic</th>
+                        <th>Status</th>
+                        <th>Duration</th>
+                        <th>Examined</th>
+                        <th>Skipped</th>
+                        <th>Findings</th>
+                    </tr>
+                </thead>
+                <tbody>
+                    {{range .Coverage}}
+                    <tr>
+                        <td>{{.Heuristic}}</td>
+                        <td><span class="badge {{if eq .Status "failed"}}high-risk{{else if eq .Status "partial"}}partial{{end}}">{{.Status}}</span></td>
+                        <td>{{.Duration.Round 1000000}}</td>
+                        <td>{{.Examined}}</td>
+                        <td>{{.Skipped}}</td>
+                        <td>
+                            {{.Findings}}
+                            {{if .Error}}<div class="finding-evidence">{{.Error}}</div>{{end}}
+                            {{range .Skips}}<div class="finding-evidence">&middot; {{.ResourceID}}: {{.Reason}}</div>{{end}}
+                        </td>
+                    </tr>
+                    {{end}}
+                </tbody>
+            </table>
+        </div>
+        {{end}}
+
         <div class="card" style="margin-top: 3rem;">
             <h2 style="margin-top:0; margin-bottom:1rem;">Recommended Actions</h2>
             <div class="grid" style="margin-bottom:0;">
@@ -360,46 +500,55 @@ func GenerateHTML(g *graph.Graph, outputPath string) error {
 	// Aggregate for Charts
 	costByType := make(map[string]float64)
 
-	g.Mu.RLock()
-	data.TotalResources = len(g.Nodes)
-	for _, node := range g.Nodes {
-		if node.IsWaste {
+	g.Read(func(v *graph.View) {
+		data.TotalResources = v.Len()
+		v.Each(func(node *graph.Node) {
+			if len(node.Findings) == 0 {
+				return
+			}
 			// Short Type Name
 			parts := strings.Split(node.Type, "::")
 			shortType := parts[len(parts)-1]
 
-			// Simple ID formatting
-			idShort := node.ID
-			if parts := strings.Split(node.ID, "/"); len(parts) > 1 {
-				idShort = parts[len(parts)-1] // Just the ID part of ARN
-			}
-
-			reason := ""
-			if r, ok := node.Properties["Reason"].(string); ok {
-				reason = r
-			}
-
 			item := WasteItem{
-				ID:        idShort,
+				ID:        resource.ResourceID(node.ID), // Just the ID part of ARN
 				Type:      shortType,
-				Reason:    reason, // Default reason
+				Reason:    node.Reason(), // Default reason
+				Findings:  node.Findings,
 				Cost:      node.Cost,
+				Savings:   node.Savings,
 				RiskScore: node.RiskScore,
 				SrcLoc:    node.SourceLocation, // Populate Source Location
 			}
 
-			if node.Justified {
+			switch {
+			case node.Justified:
 				item.Reason = node.Justification // Override reason with justification
 				data.JustifiedItems = append(data.JustifiedItems, item)
-			} else {
+			case node.HasWasteFinding():
 				data.TotalWaste++
-				data.TotalWasteCost += node.Cost
-				costByType[shortType] += node.Cost
+				data.TotalWasteCost += node.Savings
+				costByType[shortType] += node.Savings
 				data.WasteItems = append(data.WasteItems, item)
+			default:
+				data.Recommendations = append(data.Recommendations, item)
 			}
+		})
+		data.Coverage = v.Metadata().Heuristics
+	})
+
+	data.Migrations = GravitonBacklog(g)
+	for i := range data.Migrations {
+		data.Migrations[i].ID = resource.ResourceID(data.Migrations[i].ID)
+		parts := strings.Split(data.Migrations[i].Type, "::")
+		data.Migrations[i].Type = parts[len(parts)-1]
+	}
+
+	for _, r := range data.Coverage {
+		if r.Status != graph.RunOK {
+			data.Incomplete++
 		}
 	}
-	g.Mu.RUnlock()
 
 	data.ProjectedSavings = data.TotalWasteCost * 12
 
@@ -451,9 +600,16 @@ func GenerateHTML(g *graph.Graph, outputPath string) error {
 	data.ChartLabelsJSON = template.JS(labelsStr)
 	data.ChartValuesJSON = template.JS(valuesStr)
 
-	// Sort Items by Cost descending
+	// Sort Items by Savings descending
 	sort.Slice(data.WasteItems, func(i, j int) bool {
-		return data.WasteItems[i].Cost > data.WasteItems[j].Cost
+		return data.WasteItems[i].Savings > data.WasteItems[j].Savings
+	})
+	sort.Slice(data.Recommendations, func(i, j int) bool {
+		a, b := data.Recommendations[i], data.Recommendations[j]
+		if a.RiskScore != b.RiskScore {
+			return a.RiskScore > b.RiskScore
+		}
+		return a.ID < b.ID
 	})
 
 	t, err := template.New("report").Parse(htmlTemplate)
@@ -0,0 +1,53 @@
+package report
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"text/tabwriter"
+
+	"github.com/DrSkyle/cloudslash/internal/graph"
+)
+
+// WriteRuleMatches lists the findings the named heuristics (rules) recorded
+// on g, grouped by rule, with the monthly savings of each match.
+func WriteRuleMatches(w io.Writer, g *graph.Graph, names []string) error {
+	wanted := make(map[string]bool, len(names))
+	for _, name := range names {
+		wanted[name] = true
+	}
+
+	type match struct {
+		rule, id string
+		f        graph.Finding
+	}
+	var matches []match
+	g.Read(func(v *graph.View) {
+		v.Each(func(n *graph.Node) {
+			for _, f := range n.Findings {
+				if wanted[f.Heuristic] {
+					matches = append(matches, match{f.Heuristic, n.ID, f})
+				}
+			}
+		})
+	})
+	sort.Slice(matches, func(i, j int) bool {
+		if matches[i].rule != matches[j].rule {
+			return matches[i].rule < matches[j].rule
+		}
+		return matches[i].id < matches[j].id
+	})
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "RULE\tRESOURCE\tSAVINGS\tREASON")
+	var total float64
+	for _, m := range matches {
+		total += m.f.MonthlySavings
+		fmt.Fprintf(tw, "%s\t%s\t$%.2f/mo\t%s\n", m.rule, m.id, m.f.MonthlySavings, m.f.Reason)
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+	_, err := fmt.Fprintf(w, "\n%d rules, %d matches, $%.2f/mo\n", len(names), len(matches), total)
+	return err
+}
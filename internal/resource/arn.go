@@ -0,0 +1,147 @@
+package resource
+
+import (
+	"fmt"
+	"strings"
+
+	awsarn "github.com/aws/aws-sdk-go-v2/aws/arn"
+)
+
+// Identity is the partition, account and region a scan runs against.
+// Every node ID built from it is a real, globally unique ARN.
+type Identity struct {
+	Partition string
+	AccountID string
+	Region    string
+}
+
+// NewIdentity builds an Identity, deriving the partition from the region.
+func NewIdentity(accountID, region string) Identity {
+	return Identity{
+		Partition: PartitionForRegion(region),
+		AccountID: accountID,
+		Region:    region,
+	}
+}
+
+// PartitionForRegion maps a region code to its AWS partition.
+func PartitionForRegion(region string) string {
+	switch {
+	case strings.HasPrefix(region, "cn-"):
+		return "aws-cn"
+	case strings.HasPrefix(region, "us-gov-"):
+		return "aws-us-gov"
+	case strings.HasPrefix(region, "us-iso-"):
+		return "aws-iso"
+	case strings.HasPrefix(region, "us-isob-"):
+		return "aws-iso-b"
+	default:
+		return "aws"
+	}
+}
+
+func (id Identity) partition() string {
+	if id.Partition == "" {
+		return PartitionForRegion(id.Region)
+	}
+	return id.Partition
+}
+
+// EC2 returns the ARN of an EC2 resource, e.g. EC2("instance", "i-123").
+// Images and snapshots are shareable across accounts, so AWS leaves the
+// account out of their ARNs; their IDs are unique per region on their own.
+func (id Identity) EC2(resourceType, resourceID string) string {
+	account := id.AccountID
+	if resourceType == "image" || resourceType == "snapshot" {
+		account = ""
+	}
+	return fmt.Sprintf("arn:%s:ec2:%s:%s:%s/%s", id.partition(), id.Region, account, resourceType, resourceID)
+}
+
+// RDS returns the ARN of an RDS resource, e.g. RDS("db", "orders").
+func (id Identity) RDS(resourceType, resourceID string) string {
+	return fmt.Sprintf("arn:%s:rds:%s:%s:%s:%s", id.partition(), id.Region, id.AccountID, resourceType, resourceID)
+}
+
+// S3Bucket returns the ARN of a bucket. Bucket ARNs carry no region or account.
+func (id Identity) S3Bucket(name string) string {
+	return fmt.Sprintf("arn:%s:s3:::%s", id.partition(), name)
+}
+
+// S3MultipartUpload returns the ID used for an in-progress multipart upload.
+// S3 has no ARN for uploads, so the bucket ARN is suffixed with the upload ID,
+// which is unique within the bucket.
+func (id Identity) S3MultipartUpload(bucket, uploadID string) string {
+	return fmt.Sprintf("arn:%s:s3:::%s/multipart/%s", id.partition(), bucket, uploadID)
+}
+
+// ARN is a parsed resource ARN.
+type ARN struct {
+	Partition    string
+	Service      string
+	Region       string
+	AccountID    string
+	ResourceType string // "instance", "db", "loadbalancer"; empty for S3 buckets
+	Resource     string // Everything after the type, e.g. "i-123" or "app/my-lb/50dc6c49"
+}
+
+// ParseARN splits an ARN into its components.
+func ParseARN(s string) (ARN, error) {
+	parsed, err := awsarn.Parse(s)
+	if err != nil {
+		return ARN{}, err
+	}
+
+	a := ARN{
+		Partition: parsed.Partition,
+		Service:   parsed.Service,
+		Region:    parsed.Region,
+		AccountID: parsed.AccountID,
+		Resource:  parsed.Resource,
+	}
+
+	// S3 resources are "bucket" or "bucket/key", the bucket is not a type.
+	if a.Service == "s3" {
+		return a, nil
+	}
+
+	if i := strings.IndexAny(parsed.Resource, "/:"); i >= 0 {
+		a.ResourceType = parsed.Resource[:i]
+		a.Resource = parsed.Resource[i+1:]
+	}
+	return a, nil
+}
+
+// ID returns the short resource ID, the last segment of the resource path.
+func (a ARN) ID() string {
+	parts := strings.FieldsFunc(a.Resource, func(r rune) bool {
+		return r == '/' || r == ':'
+	})
+	if len(parts) > 0 {
+		return parts[len(parts)-1]
+	}
+	return a.Resource
+}
+
+// Identity returns the scope the resource lives in.
+func (a ARN) Identity() Identity {
+	return Identity{Partition: a.Partition, AccountID: a.AccountID, Region: a.Region}
+}
+
+// ResourceID returns the short ID of an ARN (vol-123, my-bucket, mysql-db-1).
+// Inputs that are not ARNs are returned unchanged.
+func ResourceID(id string) string {
+	a, err := ParseARN(id)
+	if err != nil {
+		return id
+	}
+	return a.ID()
+}
+
+// Region returns the region of an ARN, or fallback if it has none.
+func Region(id, fallback string) string {
+	if a, err := ParseARN(id); err == nil && a.Region != "" {
+		return a.Region
+	}
+	return fallback
+}
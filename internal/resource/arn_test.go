@@ -0,0 +1,54 @@
+package resource
+
+import "testing"
+
+func TestIdentityARNs(t *testing.T) {
+	id := NewIdentity("123456789012", "eu-west-1")
+
+	tests := []struct {
+		name string
+		got  string
+		want string
+	}{
+		{"Instance", id.EC2("instance", "i-0abc"), "arn:aws:ec2:eu-west-1:123456789012:instance/i-0abc"},
+		{"Snapshot omits account", id.EC2("snapshot", "snap-1"), "arn:aws:ec2:eu-west-1::snapshot/snap-1"},
+		{"RDS", id.RDS("db", "orders"), "arn:aws:rds:eu-west-1:123456789012:db:orders"},
+		{"Bucket", id.S3Bucket("logs"), "arn:aws:s3:::logs"},
+		{"Multipart", id.S3MultipartUpload("logs", "up-1"), "arn:aws:s3:::logs/multipart/up-1"},
+		{"GovCloud", NewIdentity("1", "us-gov-west-1").EC2("volume", "vol-1"), "arn:aws-us-gov:ec2:us-gov-west-1:1:volume/vol-1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.got != tt.want {
+				t.Errorf("got %s, want %s", tt.got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseARN(t *testing.T) {
+	a, err := ParseARN("arn:aws:elasticloadbalancing:us-east-1:123456789012:loadbalancer/app/my-lb/50dc6c49")
+	if err != nil {
+		t.Fatalf("ParseARN failed: %v", err)
+	}
+	if a.ResourceType != "loadbalancer" || a.Resource != "app/my-lb/50dc6c49" {
+		t.Errorf("unexpected resource split: %q %q", a.ResourceType, a.Resource)
+	}
+	if a.ID() != "50dc6c49" {
+		t.Errorf("ID() = %s", a.ID())
+	}
+
+	// A sibling built from the parsed identity must round-trip to the scanner's ARN.
+	vol, _ := ParseARN("arn:aws:ec2:us-east-1:123456789012:volume/vol-1")
+	if got := vol.Identity().EC2("instance", "i-1"); got != "arn:aws:ec2:us-east-1:123456789012:instance/i-1" {
+		t.Errorf("sibling ARN = %s", got)
+	}
+
+	if ResourceID("vol-raw") != "vol-raw" {
+		t.Error("non-ARN input should be returned unchanged")
+	}
+	if Region("vol-raw", "us-east-1") != "us-east-1" {
+		t.Error("Region should fall back for non-ARN input")
+	}
+}
@@ -0,0 +1,239 @@
+// Package rules loads user-defined heuristics from YAML files, so findings
+// can be added without recompiling. Every *.yaml or *.yml file in the rules
+// directory (.cloudslash-rules by default) holds a list of rules:
+//
+//	version: 1
+//	rules:
+//	  - id: old-gp2-volumes
+//	    type: [AWS::EC2::Volume]          # optional, limits the candidates
+//	    match: props.VolumeType = gp2 and age > 90d and not out AttachedTo (props.State = running)
+//	    after: [ZombieEBSHeuristic]       # optional, heuristics whose findings it reads
+//	    category: rightsizing             # waste (default), rightsizing, security or compliance
+//	    risk: 20                          # 0-100
+//	    confidence: 0.8                   # 0-1, default 1
+//	    reason: gp2 volume older than 90 days
+//	    action: Migrate to gp3
+//	    cost: props.Size * 0.02           # monthly savings, see query.Formula
+//
+// The match expression uses the query language (see package query). Each rule
+// runs as a heuristic named "rule:<id>".
+package rules
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/DrSkyle/cloudslash/internal/graph"
+	"github.com/DrSkyle/cloudslash/internal/heuristics"
+	"github.com/DrSkyle/cloudslash/internal/query"
+	"gopkg.in/yaml.v3"
+)
+
+// DefaultDir is looked up in the working directory when no directory is given.
+const DefaultDir = ".cloudslash-rules"
+
+// Rule is a user-defined heuristic. It implements heuristics.WeightedHeuristic.
+type Rule struct {
+	ID         string         `yaml:"id"`
+	Type       []string       `yaml:"type"`
+	Match      string         `yaml:"match"`
+	After      []string       `yaml:"after"`
+	Category   graph.Category `yaml:"category"`
+	Risk       int            `yaml:"risk"`
+	Confidence *float64       `yaml:"confidence"`
+	Reason     string         `yaml:"reason"`
+	Action     string         `yaml:"action"`
+	Cost       string         `yaml:"cost"`
+
+	File string `yaml:"-"` // File the rule was loaded from
+
+	match *query.Query
+	cost  *query.Formula
+}
+
+type file struct {
+	Version int     `yaml:"version"`
+	Rules   []*Rule `yaml:"rules"`
+}
+
+// Load reads and validates every rule file in dir, in file name order.
+func Load(dir string) ([]*Rule, error) {
+	if _, err := os.Stat(dir); err != nil {
+		return nil, fmt.Errorf("failed to read rules: %v", err)
+	}
+	var paths []string
+	for _, pattern := range []string{"*.yaml", "*.yml"} {
+		matches, err := filepath.Glob(filepath.Join(dir, pattern))
+		if err != nil {
+			return nil, err
+		}
+		paths = append(paths, matches...)
+	}
+	sort.Strings(paths)
+
+	var out []*Rule
+	seen := make(map[string]string)
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read rules: %v", err)
+		}
+		rs, err := Parse(data)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %v", path, err)
+		}
+		for _, r := range rs {
+			if prev, ok := seen[r.ID]; ok {
+				return nil, fmt.Errorf("%s: rule %s is already defined in %s", path, r.ID, prev)
+			}
+			seen[r.ID] = path
+			r.File = path
+		}
+		out = append(out, rs...)
+	}
+	return out, nil
+}
+
+// LoadDefault loads dir, or DefaultDir if dir is empty.
+// A missing DefaultDir is not an error and yields no rules.
+func LoadDefault(dir string) ([]*Rule, error) {
+	if dir != "" {
+		return Load(dir)
+	}
+	if _, err := os.Stat(DefaultDir); errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	return Load(DefaultDir)
+}
+
+// Parse decodes and validates one rule file.
+func Parse(data []byte) ([]*Rule, error) {
+	var f file
+	if err := yaml.Unmarshal(data, &f); err != nil {
+		return nil, fmt.Errorf("invalid rules: %v", err)
+	}
+	if f.Version > 1 {
+		return nil, fmt.Errorf("unsupported rules version %d", f.Version)
+	}
+
+	seen := make(map[string]bool)
+	for i, r := range f.Rules {
+		if r.ID == "" {
+			return nil, fmt.Errorf("rules[%d]: id is required", i)
+		}
+		if seen[r.ID] {
+			return nil, fmt.Errorf("duplicate rule id %q", r.ID)
+		}
+		seen[r.ID] = true
+		if err := r.compile(); err != nil {
+			return nil, fmt.Errorf("rule %s: %v", r.ID, err)
+		}
+	}
+	return f.Rules, nil
+}
+
+func (r *Rule) compile() error {
+	if strings.TrimSpace(r.Match) == "" {
+		return fmt.Errorf("match is required")
+	}
+	q, err := query.Parse(r.Match)
+	if err != nil {
+		return fmt.Errorf("invalid match: %v", err)
+	}
+	r.match = q
+
+	if strings.TrimSpace(r.Reason) == "" {
+		return fmt.Errorf("reason is required")
+	}
+	if r.Risk < 0 || r.Risk > 100 {
+		return fmt.Errorf("risk must be between 0 and 100, got %d", r.Risk)
+	}
+	if r.Confidence != nil && (*r.Confidence <= 0 || *r.Confidence > 1) {
+		return fmt.Errorf("confidence must be above 0 and at most 1, got %g", *r.Confidence)
+	}
+	switch r.Category {
+	case "":
+		r.Category = graph.CategoryWaste
+	case graph.CategoryWaste, graph.CategoryRightsizing, graph.CategorySecurity, graph.CategoryCompliance:
+	default:
+		return fmt.Errorf("unknown category %q", r.Category)
+	}
+
+	if strings.TrimSpace(r.Cost) != "" {
+		f, err := query.ParseFormula(r.Cost)
+		if err != nil {
+			return fmt.Errorf("invalid cost: %v", err)
+		}
+		r.cost = f
+	}
+	return nil
+}
+
+// Name implements heuristics.WeightedHeuristic.
+func (r *Rule) Name() string { return "rule:" + r.ID }
+
+// Flags implements heuristics.Flagger. A rule without types may flag anything.
+func (r *Rule) Flags() []string { return r.Type }
+
+// DependsOn implements heuristics.Dependent.
+func (r *Rule) DependsOn() heuristics.Dependencies {
+	return heuristics.Dependencies{Heuristics: r.After}
+}
+
+// Analyze implements heuristics.WeightedHeuristic. Matches whose cost formula
+// cannot be computed are reported without savings and noted in the evidence.
+func (r *Rule) Analyze(ctx context.Context, v *graph.View, cov *heuristics.Coverage) ([]heuristics.HeuristicResult, error) {
+	var candidates []*graph.Node
+	if len(r.Type) > 0 {
+		candidates = v.NodesByType(r.Type...)
+	} else {
+		v.Each(func(n *graph.Node) { candidates = append(candidates, n) })
+	}
+	cov.Examine(len(candidates))
+
+	confidence := 1.0
+	if r.Confidence != nil {
+		confidence = *r.Confidence
+	}
+
+	var results []heuristics.HeuristicResult
+	for _, n := range candidates {
+		if !r.match.Match(v, n) {
+			continue
+		}
+		res := heuristics.HeuristicResult{
+			ResourceID: n.ID,
+			Category:   r.Category,
+			Confidence: heuristics.WasteConfidence(confidence),
+			RiskScore:  r.Risk,
+			Reason:     r.Reason,
+			Evidence:   []string{"matched " + r.Match},
+			Action:     r.Action,
+		}
+		if r.cost != nil {
+			if savings, ok := r.cost.Eval(n); ok {
+				res.MonthlySavings = savings
+				res.Evidence = append(res.Evidence, fmt.Sprintf("cost %s = $%.2f", r.cost, savings))
+			} else {
+				res.Evidence = append(res.Evidence, fmt.Sprintf("cost %s could not be computed", r.cost))
+			}
+		}
+		results = append(results, res)
+	}
+	return results, nil
+}
+
+// Register adds the rules to e, after any built-ins already registered.
+func Register(e *heuristics.Engine, rs []*Rule) error {
+	for _, r := range rs {
+		if err := e.Register(r); err != nil {
+			return fmt.Errorf("rule %s: %v", r.ID, err)
+		}
+	}
+	return nil
+}
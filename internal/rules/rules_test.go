@@ -0,0 +1,165 @@
+package rules
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DrSkyle/cloudslash/internal/graph"
+	"github.com/DrSkyle/cloudslash/internal/heuristics"
+)
+
+const testRules = `
+version: 1
+rules:
+  - id: big-old-gp2
+    type: [AWS::EC2::Volume]
+    match: props.VolumeType = gp2 and props.Size >= 500 and age > 30d
+    category: rightsizing
+    risk: 20
+    confidence: 0.7
+    reason: Large gp2 volume
+    action: Migrate to gp3
+    cost: props.Size * 0.02
+  - id: flagged-on-stopped
+    match: waste and out AttachedTo (props.State = stopped)
+    after: [ZombieEBSHeuristic]
+    risk: 50
+    reason: Flagged volume on a stopped instance
+`
+
+func testGraph() *graph.Graph {
+	g := graph.NewGraph()
+	g.AddNode("vol-big", "AWS::EC2::Volume", map[string]interface{}{
+		"Size":       int32(800),
+		"VolumeType": "gp2",
+		"CreateTime": time.Now().Add(-90 * 24 * time.Hour),
+	})
+	g.AddNode("vol-new", "AWS::EC2::Volume", map[string]interface{}{
+		"Size":       int32(800),
+		"VolumeType": "gp2",
+		"CreateTime": time.Now(),
+	})
+	g.AddNode("i-stopped", "AWS::EC2::Instance", map[string]interface{}{"State": "stopped"})
+	g.AddTypedEdge("vol-big", "i-stopped", graph.EdgeTypeAttachedTo, 100)
+	return g
+}
+
+// zombie stands in for ZombieEBSHeuristic and flags every volume.
+type zombie struct{}
+
+func (zombie) Name() string    { return "ZombieEBSHeuristic" }
+func (zombie) Flags() []string { return []string{"AWS::EC2::Volume"} }
+func (zombie) Analyze(ctx context.Context, v *graph.View, cov *heuristics.Coverage) ([]heuristics.HeuristicResult, error) {
+	var out []heuristics.HeuristicResult
+	for _, n := range v.NodesByType("AWS::EC2::Volume") {
+		out = append(out, heuristics.HeuristicResult{ResourceID: n.ID, Confidence: 1, Reason: "zombie"})
+	}
+	return out, nil
+}
+
+func TestRules_RunInEngine(t *testing.T) {
+	rs, err := Parse([]byte(testRules))
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+
+	g := testGraph()
+	e := heuristics.NewEngine()
+	// Rules register before the heuristic they read; the engine still runs them after it.
+	if err := Register(e, rs); err != nil {
+		t.Fatalf("Register: %v", err)
+	}
+	if err := e.Register(zombie{}); err != nil {
+		t.Fatalf("Register: %v", err)
+	}
+	if err := e.Run(context.Background(), g); err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+
+	var got []string
+	for _, id := range []string{"vol-big", "vol-new", "i-stopped"} {
+		for _, f := range nodeByID(g, id).Findings {
+			if strings.HasPrefix(f.Heuristic, "rule:") {
+				got = append(got, f.Heuristic+" "+id)
+			}
+		}
+	}
+	sort.Strings(got)
+	want := []string{"rule:big-old-gp2 vol-big", "rule:flagged-on-stopped vol-big"}
+	if strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Fatalf("findings = %v, want %v", got, want)
+	}
+
+	for _, f := range nodeByID(g, "vol-big").Findings {
+		if f.Heuristic != "rule:big-old-gp2" {
+			continue
+		}
+		if f.Category != graph.CategoryRightsizing || f.RiskScore != 20 || f.Confidence != 0.7 || f.MonthlySavings != 16 || f.Action != "Migrate to gp3" {
+			t.Errorf("unexpected finding %+v", f)
+		}
+	}
+
+	runs := g.HeuristicRuns()
+	if runs[0].Heuristic != "rule:big-old-gp2" || runs[0].Examined != 2 || runs[1].Examined != 3 {
+		t.Errorf("unexpected runs %+v", runs)
+	}
+}
+
+func TestParse_Invalid(t *testing.T) {
+	tests := map[string]string{
+		"missing id":     `rules: [{match: waste, reason: r}]`,
+		"missing match":  `rules: [{id: a, reason: r}]`,
+		"bad match":      `rules: [{id: a, match: "colour = red", reason: r}]`,
+		"missing reason": `rules: [{id: a, match: waste}]`,
+		"risk":           `rules: [{id: a, match: waste, reason: r, risk: 101}]`,
+		"confidence":     `rules: [{id: a, match: waste, reason: r, confidence: 0}]`,
+		"category":       `rules: [{id: a, match: waste, reason: r, category: fun}]`,
+		"bad cost":       `rules: [{id: a, match: waste, reason: r, cost: "props.Size *"}]`,
+		"duplicate":      `rules: [{id: a, match: waste, reason: r}, {id: a, match: waste, reason: r}]`,
+		"future version": `version: 2`,
+	}
+	for name, src := range tests {
+		if _, err := Parse([]byte(src)); err == nil {
+			t.Errorf("%s: expected error", name)
+		}
+	}
+}
+
+func TestLoad(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, src string) {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(src), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write("a.yaml", `rules: [{id: one, match: waste, reason: r}]`)
+	write("b.yml", `rules: [{id: two, match: waste, reason: r}]`)
+	write("notes.txt", `not a rule file`)
+
+	rs, err := Load(dir)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if len(rs) != 2 || rs[0].Name() != "rule:one" || rs[1].File != filepath.Join(dir, "b.yml") {
+		t.Fatalf("unexpected rules %+v", rs)
+	}
+
+	write("c.yaml", `rules: [{id: one, match: waste, reason: r}]`)
+	if _, err := Load(dir); err == nil || !strings.Contains(err.Error(), "already defined") {
+		t.Errorf("expected duplicate id error, got %v", err)
+	}
+	if _, err := Load(filepath.Join(dir, "missing")); err == nil {
+		t.Error("expected error for missing directory")
+	}
+}
+
+// nodeByID returns the node with id, or nil when the graph has none.
+func nodeByID(g *graph.Graph, id string) *graph.Node {
+	node, _ := g.Node(id)
+	return node
+}
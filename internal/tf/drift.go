@@ -2,9 +2,9 @@ package tf
 
 import (
 	"fmt"
-	"strings"
 
 	"github.com/DrSkyle/cloudslash/internal/graph"
+	"github.com/DrSkyle/cloudslash/internal/resource"
 )
 
 // DriftDetector identifies resources that exist in the graph but not in the Terraform state.
@@ -25,45 +25,46 @@ func NewDriftDetector(g *graph.Graph, s *State) *DriftDetector {
 func (d *DriftDetector) ScanForDrift() {
 	managedIDs := d.State.GetManagedResourceIDs()
 
-	d.Graph.Mu.Lock() // Assuming we added Mu to Graph struct, or use existing mutex
-	defer d.Graph.Mu.Unlock()
+	batch := d.Graph.NewBatch()
+	defer batch.Flush()
 
-	for id, node := range d.Graph.Nodes {
-		// Skip if node is already marked as waste (optimization)
-		if node.IsWaste {
-			continue
-		}
+	d.Graph.Read(func(v *graph.View) {
+		v.Each(func(node *graph.Node) {
+			id := node.ID
+			// Skip if node is already marked as waste (optimization)
+			if node.HasWasteFinding() {
+				return
+			}
 
-		// Check if the ID or ARN exists in the managed set
-		// Note: The graph ID is usually the ARN. The state might have ID or ARN.
-		// We need robust matching.
+			// Check if the ID or ARN exists in the managed set
+			// Note: The graph ID is usually the ARN. The state might have ID or ARN.
+			// We need robust matching.
 
-		isManaged := false
+			isManaged := false
 
-		// Direct match
-		if managedIDs[id] {
-			isManaged = true
-		} else {
-			// Try to match by resource ID if the graph ID is an ARN
-			// e.g. arn:aws:ec2:region:account:instance/i-12345 -> i-12345
-			parts := strings.Split(id, "/")
-			if len(parts) > 1 {
-				resourceID := parts[len(parts)-1]
-				if managedIDs[resourceID] {
+			// Direct match
+			if managedIDs[id] {
+				isManaged = true
+			} else {
+				// Try to match by resource ID if the graph ID is an ARN
+				// e.g. arn:aws:ec2:us-east-1:123456789012:instance/i-12345 -> i-12345
+				if managedIDs[resource.ResourceID(id)] {
 					isManaged = true
 				}
 			}
-		}
 
-		if !isManaged {
-			// SHADOW IT DETECTED
-			node.IsWaste = true
-			node.RiskScore = 100
-			if node.Properties == nil {
-				node.Properties = make(map[string]interface{})
+			if !isManaged {
+				// SHADOW IT DETECTED
+				batch.AddFinding(id, graph.Finding{
+					Heuristic:  "TerraformDrift",
+					Category:   graph.CategoryCompliance,
+					Confidence: 1,
+					RiskScore:  100,
+					Reason:     "Shadow IT: Not found in Terraform State",
+					Action:     "Import the resource into Terraform or delete it",
+				})
+				fmt.Printf("Drift Detected: %s (%s)\n", id, node.Type)
 			}
-			node.Properties["Reason"] = "Shadow IT: Not found in Terraform State"
-			fmt.Printf("Drift Detected: %s (%s)\n", id, node.Type)
-		}
-	}
+		})
+	})
 }
@@ -6,6 +6,7 @@ import (
 	"strings"
 
 	"github.com/DrSkyle/cloudslash/internal/graph"
+	"github.com/DrSkyle/cloudslash/internal/resource"
 )
 
 type Generator struct {
@@ -17,6 +18,12 @@ func NewGenerator(g *graph.Graph, s *State) *Generator {
 	return &Generator{Graph: g, State: s}
 }
 
+// wastePlan returns the waste in deletion order. Every generated file lists
+// resources in that order, so review and apply follow dependencies.
+func (g *Generator) wastePlan() *graph.DeletionPlan {
+	return g.Graph.PlanWasteDeletion()
+}
+
 // GenerateWasteTF generates the waste.tf file containing resource blocks.
 func (g *Generator) GenerateWasteTF(path string) error {
 	f, err := os.Create(path)
@@ -25,11 +32,11 @@ func (g *Generator) GenerateWasteTF(path string) error {
 	}
 	defer f.Close()
 
-	g.Graph.Mu.RLock()
-	defer g.Graph.Mu.RUnlock()
+	plan := g.wastePlan()
 
-	for id, node := range g.Graph.Nodes {
-		if !node.IsWaste {
+	for _, id := range plan.All() {
+		node, ok := g.Graph.Node(id)
+		if !ok {
 			continue
 		}
 
@@ -47,8 +54,8 @@ func (g *Generator) GenerateWasteTF(path string) error {
 		// For "Reverse Terraform", we ideally want to reconstruct the config.
 		// For now, we'll write minimal config or comments.
 		fmt.Fprintf(f, "  # Imported by CloudSlash (Risk Score: %d)\n", node.RiskScore)
-		if reason, ok := node.Properties["Reason"].(string); ok {
-			fmt.Fprintf(f, "  # Reason: %s\n", reason)
+		for _, finding := range node.Findings {
+			fmt.Fprintf(f, "  # Reason: %s\n", finding.Reason)
 		}
 		fmt.Fprintf(f, "}\n\n")
 	}
@@ -66,11 +73,11 @@ func (g *Generator) GenerateImportScript(path string) error {
 	fmt.Fprintf(f, "#!/bin/bash\n")
 	fmt.Fprintf(f, "# Generated by CloudSlash\n\n")
 
-	g.Graph.Mu.RLock()
-	defer g.Graph.Mu.RUnlock()
+	plan := g.wastePlan()
 
-	for id, node := range g.Graph.Nodes {
-		if !node.IsWaste {
+	for _, id := range plan.All() {
+		node, ok := g.Graph.Node(id)
+		if !ok {
 			continue
 		}
 
@@ -98,21 +105,25 @@ func (g *Generator) GenerateDestroyPlan(path string) error {
 	defer f.Close()
 
 	fmt.Fprintf(f, "CloudSlash Destruction Plan\n")
-	fmt.Fprintf(f, "===========================\n\n")
+	fmt.Fprintf(f, "===========================\n")
+	fmt.Fprintf(f, "Resources are listed in the order they must be deleted.\n\n")
 
-	g.Graph.Mu.RLock()
-	defer g.Graph.Mu.RUnlock()
+	plan := g.wastePlan()
 
 	totalWaste := 0
-	for id, node := range g.Graph.Nodes {
-		if !node.IsWaste {
-			continue
+	writeItem := func(id string) {
+		node, ok := g.Graph.Node(id)
+		if !ok {
+			return
 		}
 		totalWaste++
 
-		fmt.Fprintf(f, "[%d] %s (%s)\n", node.RiskScore, id, node.Type)
-		if reason, ok := node.Properties["Reason"].(string); ok {
-			fmt.Fprintf(f, "    Reason: %s\n", reason)
+		fmt.Fprintf(f, "%d. [%d] %s (%s)\n", totalWaste, node.RiskScore, id, node.Type)
+		for _, finding := range node.Findings {
+			fmt.Fprintf(f, "    Reason: %s\n", finding.Reason)
+			if finding.Action != "" {
+				fmt.Fprintf(f, "    Action: %s\n", finding.Action)
+			}
 		}
 
 		// Special handling for S3 Multipart
@@ -125,7 +136,18 @@ func (g *Generator) GenerateDestroyPlan(path string) error {
 		fmt.Fprintf(f, "\n")
 	}
 
-	fmt.Fprintf(f, "Total Waste Items: %d\n", totalWaste)
+	for _, id := range plan.Order {
+		writeItem(id)
+	}
+
+	if err := plan.CycleError(); err != nil {
+		fmt.Fprintf(f, "⚠️  MANUAL ORDER REQUIRED\n")
+		fmt.Fprintf(f, "    %v\n\n", err)
+		for _, id := range plan.All()[len(plan.Order):] {
+			writeItem(id)
+		}
+	}
+
 	fmt.Fprintf(f, "Total Waste Items: %d\n", totalWaste)
 	return nil
 }
@@ -144,16 +166,16 @@ func (g *Generator) GenerateFixScript(path string) error {
 	fmt.Fprintf(f, "# SAFETY: These commands remove items from Terraform STATE only.\n")
 	fmt.Fprintf(f, "# They DO NOT delete the actual resource. You can safely delete the code after running this.\n\n")
 
-	g.Graph.Mu.RLock()
-	defer g.Graph.Mu.RUnlock()
+	plan := g.wastePlan()
 
 	var stateMap map[string]string
 	if g.State != nil {
 		stateMap = g.State.GetResourceMapping()
 	}
 
-	for id, node := range g.Graph.Nodes {
-		if !node.IsWaste {
+	for _, id := range plan.All() {
+		node, ok := g.Graph.Node(id)
+		if !ok {
 			continue
 		}
 
@@ -176,7 +198,7 @@ func (g *Generator) GenerateFixScript(path string) error {
 			}
 		}
 
-		fmt.Fprintf(f, "# Waste: %s (%s)\n", id, node.Properties["Reason"])
+		fmt.Fprintf(f, "# Waste: %s (%s)\n", id, node.Reason())
 		if resourceName != "" {
 			fmt.Fprintf(f, "terraform state rm %s\n", resourceName)
 		} else {
@@ -215,10 +237,5 @@ func sanitizeName(id string) string {
 }
 
 func extractResourceID(arn, awsType string) string {
-	// Simplified extraction
-	parts := strings.Split(arn, "/")
-	if len(parts) > 1 {
-		return parts[len(parts)-1]
-	}
-	return arn
+	return resource.ResourceID(arn)
 }
@@ -167,14 +167,14 @@ func (m Model) View() string {
     cursorStyle := lipgloss.NewStyle().Foreground(highlight).Bold(true)
 
     // STABLE SORT & FILTER
-    m.Graph.Mu.RLock()
     var items []*graph.Node
-    for _, node := range m.Graph.Nodes {
-        if node.IsWaste {
-            items = append(items, node)
-        }
-    }
-    m.Graph.Mu.RUnlock()
+    m.Graph.Read(func(v *graph.View) {
+        v.Each(func(node *graph.Node) {
+            if node.HasWasteFinding() {
+                items = append(items, node)
+            }
+        })
+    })
     
     // Sort by ID for stability
     sort.Slice(items, func(i, j int) bool {
@@ -224,13 +224,13 @@ func (m Model) View() string {
             
             if m.isTrial { ownerDisp = "HIDDEN" }
 
-            // Cost
+            // Savings
             costStr := "-"
-            if node.Cost > 0 {
-                costStr = fmt.Sprintf("$%.2f", node.Cost)
+            if node.Savings > 0 {
+                costStr = fmt.Sprintf("$%.2f", node.Savings)
             }
             costDisp := dimStyle.Render(fmt.Sprintf("%-12s", costStr))
-            if node.Cost > 50 {
+            if node.Savings > 50 {
                 costDisp = warnStyle.Render(fmt.Sprintf("%-12s", costStr))
             }
 
@@ -262,15 +262,21 @@ func (m Model) View() string {
              }
 
              details := fmt.Sprintf(
-                 "DETAILS FOR %s\n\nType:   %s\nRegion: %v\nOwner:  %v\nCost:   $%.2f/mo%s\n\n[DETECTED WASTE]\nReason: %v",
+                 "DETAILS FOR %s\n\nType:   %s\nRegion: %v\nOwner:  %v\nCost:   $%.2f/mo\nSaving: $%.2f/mo%s\n\n[DETECTED WASTE]",
                  node.ID,
                  node.Type,
                  node.Properties["Region"],
                  node.Properties["Owner"],
                  node.Cost,
+                 node.Savings,
                  srcLocDisplay,
-                 node.Properties["Reason"],
              )
+             for _, f := range node.Findings {
+                 details += fmt.Sprintf("\n[%s] %s (risk %d, %.0f%% confidence)", f.Category, f.Reason, f.RiskScore, f.Confidence*100)
+                 if f.Action != "" {
+                     details += fmt.Sprintf("\n  -> %s", f.Action)
+                 }
+             }
              
              s.WriteString("\n\n")
              s.WriteString(cardStyle.Render(details))